@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/leonelquinteros/gotext/plurals"
+)
+
+// Backend is the minimal interface to provide translations from any store (database, RPC, config service...).
+// Wrap it with NewBackendTranslator to make it available in a Locale, so call sites remain unchanged.
+// Domain implements it for translations parsed from PO and MO files.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type Backend interface {
+	// Lookup returns the Translation stored for the given context (empty for none) and msgid.
+	Lookup(ctx, id string) (*Translation, bool)
+
+	// PluralForm returns the index of the plural form to use for n.
+	PluralForm(n int) int
+}
+
+// PluralFormsFunc returns a function selecting the plural form index for n according to
+// a Plural-Forms header value (i.e. "nplurals=2; plural=(n != 1);").
+// It uses the Germanic plural rule if the value can't be parsed. It's meant to help implementing Backend.PluralForm.
+func PluralFormsFunc(header string) func(n int) int {
+	_, plural := parsePluralForms(header)
+	c := &catalog{pluralforms: compilePlural(plural)}
+
+	return c.pluralForm
+}
+
+// headerPluralForms compiles the Plural-Forms of the given catalog header entry (i.e. "Plural-Forms: nplurals=2; plural=(n != 1);\n").
+// It returns nil if there are none.
+func headerPluralForms(header string) plurals.Expression {
+	for _, line := range strings.Split(header, "\n") {
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) == 2 && strings.EqualFold(strings.TrimSpace(kv[0]), "Plural-Forms") {
+			_, plural := parsePluralForms(kv[1])
+			return compilePlural(plural)
+		}
+	}
+
+	return nil
+}
+
+// backendOf returns the Backend resolving the lookups of a Translator.
+// Translators not implementing Backend are resolved with their Domain.
+func backendOf(tr Translator) Backend {
+	if b, ok := tr.(Backend); ok {
+		return b
+	}
+	return tr.GetDomain()
+}
+
+/*
+BackendTranslator makes a Backend available as a Translator, to be added to a Locale with AddTranslator.
+
+Example:
+
+	l := gotext.NewLocale("/path/to/i18n/dir", "de")
+	l.AddTranslator("default", gotext.NewBackendTranslator(myBackend))
+
+	fmt.Println(l.Get("Translate this"))
+*/
+type BackendTranslator struct {
+	backend Backend
+	domain  *Domain
+}
+
+// NewBackendTranslator returns a Translator resolving lookups with the given Backend.
+func NewBackendTranslator(b Backend) *BackendTranslator {
+	return &BackendTranslator{
+		backend: b,
+		domain:  NewDomain(),
+	}
+}
+
+// Backend returns the wrapped Backend.
+func (bt *BackendTranslator) Backend() Backend {
+	return bt.backend
+}
+
+// Lookup returns the Translation stored in the Backend for the given context (empty for none) and msgid.
+func (bt *BackendTranslator) Lookup(ctx, id string) (*Translation, bool) {
+	return bt.backend.Lookup(ctx, id)
+}
+
+// PluralForm returns the index of the plural form to use for n, according to the Backend.
+func (bt *BackendTranslator) PluralForm(n int) int {
+	return bt.backend.PluralForm(n)
+}
+
+// GetDomain returns an empty Domain, as translations live in the Backend.
+func (bt *BackendTranslator) GetDomain() *Domain {
+	return bt.domain
+}
+
+// ParseFile does nothing, as translations live in the Backend.
+func (bt *BackendTranslator) ParseFile(f string) {}
+
+// Parse does nothing, as translations live in the Backend.
+func (bt *BackendTranslator) Parse(buf []byte) {}
+
+// Get retrieves the corresponding Translation for the given string.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (bt *BackendTranslator) Get(str string, vars ...interface{}) string {
+	if tr, ok := bt.backend.Lookup("", str); ok {
+		return Printf(tr.Get(), vars...)
+	}
+
+	return Printf(str, vars...)
+}
+
+// GetN retrieves the (N)th plural form of Translation for the given string.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (bt *BackendTranslator) GetN(str, plural string, n int, vars ...interface{}) string {
+	form := bt.backend.PluralForm(n)
+	if tr, ok := bt.backend.Lookup("", str); ok {
+		return Printf(pluralString(tr, form, str, plural), vars...)
+	}
+
+	if form == 0 {
+		return Printf(str, vars...)
+	}
+	return Printf(plural, vars...)
+}
+
+// GetC retrieves the corresponding Translation for a given string in the given context.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (bt *BackendTranslator) GetC(str, ctx string, vars ...interface{}) string {
+	if tr, ok := bt.backend.Lookup(ctx, str); ok {
+		return Printf(tr.Get(), vars...)
+	}
+
+	return Printf(str, vars...)
+}
+
+// GetNC retrieves the (N)th plural form of Translation for the given string in the given context.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (bt *BackendTranslator) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	form := bt.backend.PluralForm(n)
+	if tr, ok := bt.backend.Lookup(ctx, str); ok {
+		return Printf(pluralString(tr, form, str, plural), vars...)
+	}
+
+	if form == 0 {
+		return Printf(str, vars...)
+	}
+	return Printf(plural, vars...)
+}
+
+// pluralString returns the plural form of the translation, or the msgid or plural msgid of the lookup when the form
+// isn't translated, as Po and Mo do, backends not having to set the plural msgid of their translations.
+func pluralString(tr *Translation, form int, str, plural string) string {
+	if s := tr.Trs[form]; s != "" {
+		return s
+	}
+	if form == 0 {
+		return str
+	}
+	return plural
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+// Backends aren't serializable, so it always returns an error.
+func (bt *BackendTranslator) MarshalBinary() ([]byte, error) {
+	return nil, errors.New("backend translators can't be marshalled")
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+// Backends aren't serializable, so it always returns an error.
+func (bt *BackendTranslator) UnmarshalBinary(data []byte) error {
+	return errors.New("backend translators can't be unmarshalled")
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "testing"
+
+// mapBackend is a Backend storing translations in a map, keyed by context and msgid.
+type mapBackend struct {
+	entries    map[[2]string]*Translation
+	pluralForm func(n int) int
+}
+
+func (b *mapBackend) Lookup(ctx, id string) (*Translation, bool) {
+	tr, ok := b.entries[[2]string{ctx, id}]
+	return tr, ok
+}
+
+func (b *mapBackend) PluralForm(n int) int {
+	return b.pluralForm(n)
+}
+
+func TestBackendTranslator(t *testing.T) {
+	apple := NewTranslation()
+	apple.ID = "%d apple"
+	apple.PluralID = "%d apples"
+	apple.Trs[0] = "%d jabłko"
+	apple.Trs[1] = "%d jabłka"
+	apple.Trs[2] = "%d jabłek"
+
+	open := NewTranslation()
+	open.ID = "Open"
+	open.Trs[0] = "Otwórz"
+
+	b := &mapBackend{
+		entries: map[[2]string]*Translation{
+			{"", apple.ID}:    apple,
+			{"Menu", open.ID}: open,
+		},
+		pluralForm: PluralFormsFunc("nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);"),
+	}
+
+	l := NewLocale("", "pl")
+	l.AddTranslator("default", NewBackendTranslator(b))
+
+	for _, test := range []struct{ expected, got string }{
+		{"1 jabłko", l.GetN("%d apple", "%d apples", 1, 1)},
+		{"3 jabłka", l.GetN("%d apple", "%d apples", 3, 3)},
+		{"5 jabłek", l.GetN("%d apple", "%d apples", 5, 5)},
+		{"Otwórz", l.GetC("Open", "Menu")},
+		{"Open", l.Get("Open")},
+		{"Closed", l.GetNC("Closed", "Closed ones", 1, "Menu")},
+	} {
+		if test.expected != test.got {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.got)
+		}
+	}
+}
+
+func TestBackendTranslatorMissingForm(t *testing.T) {
+	// Backends don't have to set the plural msgid, or every form
+	file := NewTranslation()
+	file.ID = "One file"
+	file.Trs[0] = "Un fichier"
+
+	b := &mapBackend{
+		entries: map[[2]string]*Translation{
+			{"", file.ID}:     file,
+			{"Menu", file.ID}: file,
+		},
+		pluralForm: PluralFormsFunc("nplurals=2; plural=(n > 1);"),
+	}
+	bt := NewBackendTranslator(b)
+	l := NewLocale("", "fr")
+	l.AddTranslator("default", bt)
+
+	for _, test := range []struct{ expected, got string }{
+		{"Un fichier", bt.GetN("One file", "%d files", 1)},
+		{"5 files", bt.GetN("One file", "%d files", 5, 5)},
+		{"5 files", bt.GetNC("One file", "%d files", 5, "Menu", 5)},
+		{"Un fichier", bt.GetNC("One file", "%d files", 0, "Menu")},
+		{"5 files", l.GetN("One file", "%d files", 5, 5)},
+		{"5 files", l.GetNC("One file", "%d files", 5, "Menu", 5)},
+	} {
+		if test.expected != test.got {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.got)
+		}
+	}
+}
+
+func TestDomainBackend(t *testing.T) {
+	po := NewPo()
+	po.ParseFile("fixtures/en_US/default.po")
+
+	var b Backend = po.GetDomain()
+
+	tr, ok := b.Lookup("", "My text")
+	if !ok || tr.Get() != translatedText {
+		t.Errorf("Expected '%s' but got '%v'", translatedText, tr)
+	}
+
+	if _, ok = b.Lookup("Ctx", "My text"); ok {
+		t.Error("Expected no translation in context")
+	}
+
+	if b.PluralForm(1) != 0 || b.PluralForm(2) != 1 {
+		t.Errorf("Expected Germanic plural forms but got %d and %d", b.PluralForm(1), b.PluralForm(2))
+	}
+}
+
+func TestPluralFormsFunc(t *testing.T) {
+	f := PluralFormsFunc("nplurals=1; plural=0;")
+	if f(5) != 0 {
+		t.Errorf("Expected 0 but got %d", f(5))
+	}
+
+	f = PluralFormsFunc("invalid")
+	if f(1) != 0 || f(3) != 1 {
+		t.Errorf("Expected Germanic plural forms but got %d and %d", f(1), f(3))
+	}
+}
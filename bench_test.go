@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "testing"
+
+func newBenchLocale() *Locale {
+	l := NewLocale("fixtures/", "en_US")
+	l.AddDomain("default")
+	return l
+}
+
+func TestLookupAllocations(t *testing.T) {
+	l := newBenchLocale()
+
+	for name, f := range map[string]func(){
+		"Get":          func() { l.Get("My text") },
+		"GetN":         func() { l.GetN("One with var: %s", "Several with vars: %s", 2) },
+		"GetC":         func() { l.GetC("Some random in a context", "Ctx") },
+		"GetNC":        func() { l.GetNC("One with var: %s", "Several with vars: %s", 2, "Ctx") },
+		"Untranslated": func() { l.Get("Untranslated text") },
+		"MissingDomain": func() {
+			l.GetD("missing", "My text")
+		},
+	} {
+		if n := testing.AllocsPerRun(100, f); n != 0 {
+			t.Errorf("Expected %s to be allocation free but got %v allocations", name, n)
+		}
+	}
+}
+
+func BenchmarkLocaleGet(b *testing.B) {
+	l := newBenchLocale()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		l.Get("My text")
+	}
+}
+
+func BenchmarkLocaleGetParallel(b *testing.B) {
+	l := newBenchLocale()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Get("My text")
+		}
+	})
+}
+
+func BenchmarkLocaleGetN(b *testing.B) {
+	l := newBenchLocale()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		l.GetN("One with var: %s", "Several with vars: %s", i)
+	}
+}
+
+func BenchmarkLocaleGetC(b *testing.B) {
+	l := newBenchLocale()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		l.GetC("Some random in a context", "Ctx")
+	}
+}
+
+func BenchmarkLocaleGetFormatted(b *testing.B) {
+	l := newBenchLocale()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		l.Get("One with var: %s", "value")
+	}
+}
+
+func BenchmarkPrintf(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		Printf("This one is the plural: %s (%d)", "value", i)
+	}
+}
+
+func BenchmarkSprintfNamed(b *testing.B) {
+	params := map[string]interface{}{"name": "Gotext", "type": "struct"}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Sprintf("%(name)s is Type %(type)s", params)
+	}
+}
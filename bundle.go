@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"io/ioutil"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/*
+Bundle loads the catalogs of a set of domains in every language available in a library directory once, and hands out
+the Locale of each language, so servers answering requests in many languages don't load catalogs per request or per
+Locale. Its Locales are shared and must not be modified, i.e. with AddDomain.
+
+Example:
+
+	bundle, err := gotext.NewBundle("/path/to/i18n/dir", "en", "default", "emails")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	func handler(w http.ResponseWriter, r *http.Request) {
+		l := bundle.Match(gotext.ParseAcceptLanguage(r.Header.Get("Accept-Language"))...)
+		fmt.Fprintln(w, l.Get("Welcome"))
+	}
+*/
+type Bundle struct {
+	// Locales by language, with catalogs for at least one of the domains, and the default one.
+	locales map[string]*Locale
+	langs   []string
+
+	fallback *Locale
+}
+
+// NewBundle loads the catalogs of the given domains for every language directory of the library path, in parallel.
+// The first domain is the default one of the Locales. The Locale of the fallback language is returned for languages
+// without catalogs, and is created even if it has none.
+func NewBundle(path, fallback string, doms ...string) (*Bundle, error) {
+	dirs, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback = SimplifiedLocale(fallback)
+	langs := []string{fallback}
+	for _, dir := range dirs {
+		lang := SimplifiedLocale(dir.Name())
+		if dir.IsDir() && lang != fallback && isLanguageCode(lang) {
+			langs = append(langs, lang)
+		}
+	}
+
+	locales := make([]*Locale, len(langs))
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for i, lang := range langs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, lang string) {
+			defer wg.Done()
+			l := NewLocale(path, lang)
+			for _, dom := range doms {
+				l.AddDomain(dom)
+			}
+			if len(doms) > 0 {
+				l.SetDomain(doms[0])
+			}
+			locales[i] = l
+			<-sem
+		}(i, lang)
+	}
+	wg.Wait()
+
+	b := &Bundle{
+		locales:  make(map[string]*Locale, len(langs)),
+		fallback: locales[0],
+	}
+	for i, l := range locales {
+		if i == 0 || len(l.GetDomains()) > 0 {
+			b.locales[langs[i]] = l
+			b.langs = append(b.langs, langs[i])
+		}
+	}
+	sort.Strings(b.langs)
+
+	return b, nil
+}
+
+// Languages returns the sorted list of languages of the bundle.
+func (b *Bundle) Languages() []string {
+	return append([]string(nil), b.langs...)
+}
+
+// lookup returns the Locale of the language, falling back from regional variants to their base language.
+func (b *Bundle) lookup(lang string) (*Locale, bool) {
+	for candidate := SimplifiedLocale(lang); candidate != ""; {
+		if l, ok := b.locales[candidate]; ok {
+			return l, true
+		}
+
+		idx := strings.LastIndex(candidate, "_")
+		if idx == -1 {
+			break
+		}
+		candidate = candidate[:idx]
+	}
+
+	return nil, false
+}
+
+// Locale returns the Locale of the given language, falling back from regional variants to their base language ("pt_BR"
+// to "pt"), or the Locale of the fallback language if the bundle has none of them.
+func (b *Bundle) Locale(lang string) *Locale {
+	if l, ok := b.lookup(lang); ok {
+		return l
+	}
+	return b.fallback
+}
+
+// Match returns the Locale of the first of the given languages (i.e. from ParseAcceptLanguage) of the bundle, falling
+// back from regional variants to their base language, or the Locale of the fallback language if none matches.
+func (b *Bundle) Match(langs ...string) *Locale {
+	for _, lang := range langs {
+		if l, ok := b.lookup(lang); ok {
+			return l
+		}
+	}
+	return b.fallback
+}
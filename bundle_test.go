@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBundle(t *testing.T) {
+	b, err := NewBundle("fixtures", "es", "default", "categories")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"ar", "de", "de_DE", "en_AU", "en_GB", "en_US", "es", "fr"}
+	if langs := b.Languages(); !reflect.DeepEqual(langs, expected) {
+		t.Errorf("Expected %v but got %v", expected, langs)
+	}
+
+	de := b.Locale("de-DE")
+	if de.GetLanguage() != "de_DE" {
+		t.Errorf("Expected '%s' but got '%s'", "de_DE", de.GetLanguage())
+	}
+	if tr := de.Get("My text"); tr != "Translated text" {
+		t.Errorf("Expected '%s' but got '%s'", "Translated text", tr)
+	}
+	if b.Locale("de_DE") != de {
+		t.Error("Expected the same Locale for the same language")
+	}
+	if l := b.Locale("de_AT"); l.GetLanguage() != "de" {
+		t.Errorf("Expected '%s' but got '%s'", "de", l.GetLanguage())
+	}
+	if l := b.Locale("ja"); l.GetLanguage() != "es" || l.Get("My text") != "My text" {
+		t.Errorf("Expected the fallback Locale but got the '%s' one", l.GetLanguage())
+	}
+
+	ar := b.Match("ja", "ar_EG", "fr")
+	if ar.GetLanguage() != "ar" || ar.GetDomain() != "default" {
+		t.Errorf("Expected the '%s' Locale with the '%s' domain but got '%s' and '%s'", "ar", "default", ar.GetLanguage(), ar.GetDomain())
+	}
+	if tr := ar.GetD("categories", "Alcohol & Tobacco"); tr != "الكحول والتبغ" {
+		t.Errorf("Expected 'الكحول والتبغ' but got '%s'", tr)
+	}
+	if l := b.Match(); l.GetLanguage() != "es" {
+		t.Errorf("Expected '%s' but got '%s'", "es", l.GetLanguage())
+	}
+
+	if _, err = NewBundle("fixtures/missing", "en"); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
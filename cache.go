@@ -0,0 +1,154 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// cacheMagic identifies Locale cache files and their format version.
+const cacheMagic = "GOTEXTC3"
+
+var (
+	// ErrInvalidCache is returned when reading a file that isn't a valid Locale cache.
+	ErrInvalidCache = errors.New("invalid locale cache file")
+
+	// ErrStaleCache is returned when any of the catalog files a Locale cache was built from changed since it was written,
+	// or a catalog file that was missing then, and would have been loaded, was created.
+	ErrStaleCache = errors.New("stale locale cache file")
+)
+
+// cacheSource records the state of a catalog file at the time a cache was written.
+type cacheSource struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// cacheEncoding is the content of a cache file, after the magic header.
+type cacheEncoding struct {
+	Sources []cacheSource
+	Absent  []string
+	Locale  []byte
+
+	// Languages catalogs are looked up for, and the catalog files and missing candidates of each domain,
+	// so the restored Locale is saved again with them
+	Languages []string
+	Files     map[string][]string
+	Missing   map[string][]string
+}
+
+// SaveCache writes the parsed catalogs of the Locale to a binary cache file,
+// which LoadLocaleCache reads back on subsequent startups without parsing any PO/MO file.
+// The file is replaced atomically, so concurrent readers never see a partial cache.
+func (l *Locale) SaveCache(file string) error {
+	data, err := l.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	obj := cacheEncoding{Locale: data}
+
+	l.RLock()
+	obj.Languages = append([]string(nil), l.languages...)
+	obj.Files = make(map[string][]string, len(l.sources))
+	for dom, files := range l.sources {
+		obj.Files[dom] = files
+	}
+	obj.Missing = make(map[string][]string, len(l.absent))
+	for dom, files := range l.absent {
+		obj.Missing[dom] = files
+	}
+	for _, files := range l.sources {
+		for _, f := range files {
+			info, err := os.Stat(f)
+			if err != nil {
+				l.RUnlock()
+				return err
+			}
+			obj.Sources = append(obj.Sources, cacheSource{Path: f, Size: info.Size(), ModTime: info.ModTime()})
+		}
+	}
+	for _, files := range l.absent {
+		obj.Absent = append(obj.Absent, files...)
+	}
+	l.RUnlock()
+
+	sort.Strings(obj.Absent)
+	sort.Slice(obj.Sources, func(i, j int) bool {
+		return obj.Sources[i].Path < obj.Sources[j].Path
+	})
+
+	buff := bytes.NewBufferString(cacheMagic)
+	if err := gob.NewEncoder(buff).Encode(obj); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(file), filepath.Base(file)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(buff.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
+
+// LoadLocaleCache reads a Locale from a cache file written by SaveCache.
+// It returns ErrStaleCache if any of the catalog files the cache was built from was modified or removed since then,
+// or if a catalog file that was looked up but missing then exists now (i.e. a regional or preferred language catalog was added),
+// in which case the Locale should be loaded from the catalogs (and the cache saved) again.
+func LoadLocaleCache(file string) (*Locale, error) {
+	data, err := getFileData(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(data, []byte(cacheMagic)) {
+		return nil, ErrInvalidCache
+	}
+
+	var obj cacheEncoding
+	if err := gob.NewDecoder(bytes.NewReader(data[len(cacheMagic):])).Decode(&obj); err != nil {
+		return nil, ErrInvalidCache
+	}
+
+	for _, src := range obj.Sources {
+		info, err := os.Stat(src.Path)
+		if err != nil || info.Size() != src.Size || !info.ModTime().Equal(src.ModTime) {
+			return nil, ErrStaleCache
+		}
+	}
+	for _, path := range obj.Absent {
+		if _, err := os.Stat(path); err == nil {
+			return nil, ErrStaleCache
+		}
+	}
+
+	l := new(Locale)
+	if err := l.UnmarshalBinary(obj.Locale); err != nil {
+		return nil, ErrInvalidCache
+	}
+	l.languages = obj.Languages
+	l.sources = obj.Files
+	l.absent = obj.Missing
+
+	return l, nil
+}
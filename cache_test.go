@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLocaleCache(t *testing.T) {
+	// Copy a catalog to a temporary library
+	dirname := path.Join("/tmp", "gotextcache", "en_US")
+	err := os.MkdirAll(dirname, os.ModePerm)
+	if err != nil {
+		t.Fatalf("Can't create test directory: %s", err.Error())
+	}
+	data, err := ioutil.ReadFile("fixtures/en_US/default.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog := path.Join(dirname, "default.po")
+	if err := ioutil.WriteFile(catalog, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLocale("/tmp/gotextcache", "en_US")
+	l.AddDomain("default")
+
+	cache := path.Join("/tmp", "gotextcache", "en_US.cache")
+	if err := l.SaveCache(cache); err != nil {
+		t.Fatal(err)
+	}
+
+	l2, err := LoadLocaleCache(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr := l2.Get("My text")
+	if tr != translatedText {
+		t.Errorf("Expected '%s' but got '%s'", translatedText, tr)
+	}
+	tr = l2.GetN("One with var: %s", "Several with vars: %s", 3, "VALUE")
+	if tr != l.GetN("One with var: %s", "Several with vars: %s", 3, "VALUE") {
+		t.Errorf("Expected '%s' but got '%s'", l.GetN("One with var: %s", "Several with vars: %s", 3, "VALUE"), tr)
+	}
+
+	// Modify catalog to invalidate the cache
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(catalog, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLocaleCache(cache); err != ErrStaleCache {
+		t.Errorf("Expected ErrStaleCache but got '%v'", err)
+	}
+
+	// Not a cache file
+	if _, err := LoadLocaleCache(catalog); err != ErrInvalidCache {
+		t.Errorf("Expected ErrInvalidCache but got '%v'", err)
+	}
+}
+
+func TestLocaleCacheAddedCatalog(t *testing.T) {
+	dir := path.Join("/tmp", "gotextcacheadded")
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(path.Join(dir, "pt"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(dir, "pt", "default.po"), []byte("msgid \"Hello\"\nmsgstr \"Olá\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLocale(dir, "pt_BR")
+	l.AddDomain("default")
+
+	cache := path.Join(dir, "pt_BR.cache")
+	if err := l.SaveCache(cache); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLocaleCache(cache); err != nil {
+		t.Fatal(err)
+	}
+
+	// A regional catalog that would now be overlaid invalidates the cache
+	if err := os.MkdirAll(path.Join(dir, "pt_BR", "LC_MESSAGES"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(dir, "pt_BR", "LC_MESSAGES", "default.mo"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLocaleCache(cache); err != ErrStaleCache {
+		t.Errorf("Expected ErrStaleCache but got '%v'", err)
+	}
+}
+
+func TestLocaleCacheRoundTrip(t *testing.T) {
+	dir := path.Join("/tmp", "gotextcacheroundtrip")
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(path.Join(dir, "pt"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	catalog := path.Join(dir, "pt", "default.po")
+	if err := ioutil.WriteFile(catalog, []byte("msgid \"Hello\"\nmsgstr \"Olá\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLocale(dir, "pt_BR")
+	l.languages = []string{"pt_BR", "en"}
+	l.AddDomain("default")
+
+	cache := path.Join(dir, "pt_BR.cache")
+	if err := l.SaveCache(cache); err != nil {
+		t.Fatal(err)
+	}
+	l2, err := LoadLocaleCache(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(l2.languages, l.languages) {
+		t.Errorf("Expected languages %v but got %v", l.languages, l2.languages)
+	}
+	if !reflect.DeepEqual(l2.sources, l.sources) {
+		t.Errorf("Expected sources %v but got %v", l.sources, l2.sources)
+	}
+
+	// The restored Locale is cached again with its sources, so changes to the catalogs still invalidate it
+	l2.Refresh()
+	if tr := l2.Get("Hello"); tr != "Olá" {
+		t.Errorf("Expected 'Olá' but got '%s'", tr)
+	}
+	if err := l2.SaveCache(cache); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLocaleCache(cache); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(catalog, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLocaleCache(cache); err != ErrStaleCache {
+		t.Errorf("Expected ErrStaleCache but got '%v'", err)
+	}
+}
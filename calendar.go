@@ -0,0 +1,144 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoCalendarName is returned along with the English calendar names missing from the catalogs of a Locale in another
+// language than English, as no calendar data is bundled.
+var ErrNoCalendarName = errors.New("gotext: calendar name missing from the catalogs")
+
+// CalendarWidth selects the form of calendar names: "January", "Jan" or "J".
+type CalendarWidth int
+
+const (
+	// CalendarWide selects the full names, "January" or "Monday".
+	CalendarWide CalendarWidth = iota
+
+	// CalendarAbbreviated selects the abbreviated names, "Jan" or "Mon".
+	CalendarAbbreviated
+
+	// CalendarNarrow selects the narrow names, "J" or "M", which aren't unique.
+	CalendarNarrow
+)
+
+// String returns the name of the width, as used in the semantic keys of calendar names.
+func (w CalendarWidth) String() string {
+	switch w {
+	case CalendarAbbreviated:
+		return "abbreviated"
+	case CalendarNarrow:
+		return "narrow"
+	}
+
+	return "wide"
+}
+
+// calendarName returns the English name of a calendar width.
+func calendarName(wide string, w CalendarWidth) string {
+	switch w {
+	case CalendarAbbreviated:
+		return wide[:3]
+	case CalendarNarrow:
+		return wide[:1]
+	}
+
+	return wide
+}
+
+// calendarTranslation returns the translation of the key of a calendar name in the default domain, or else its English
+// name, with ErrNoCalendarName unless the Locale is in English (or has no language).
+func (l *Locale) calendarTranslation(key, english string) (string, error) {
+	if name := l.TKey(key, ""); name != "" {
+		return name, nil
+	}
+	if lang := strings.SplitN(l.GetLanguage(), "_", 2)[0]; lang != "" && lang != "en" {
+		return english, ErrNoCalendarName
+	}
+
+	return english, nil
+}
+
+/*
+MonthName returns the name of the month provided by the catalogs of the Locale, in the given width.
+No calendar data is bundled: names are semantic keys (see TKey) of the default domain that each application
+translates, "calendar.month.<width>.<n>" from 1 for January to 12 for December, as their narrow forms aren't
+unique. Names missing from the catalogs are returned in English, with ErrNoCalendarName when the Locale is in another
+language, so callers can tell them apart from translated names.
+
+Example catalog entries:
+
+	msgid "calendar.month.wide.5"
+	msgstr "Mai"
+
+	msgid "calendar.month.abbreviated.5"
+	msgstr "Mai"
+*/
+func (l *Locale) MonthName(m time.Month, w CalendarWidth) (string, error) {
+	return l.calendarTranslation("calendar.month."+w.String()+"."+strconv.Itoa(int(m)), calendarName(m.String(), w))
+}
+
+// WeekdayName returns the name of the weekday provided by the catalogs of the Locale, in the given width, as MonthName
+// with the keys "calendar.weekday.<width>.<n>", from 0 for Sunday to 6 for Saturday as time.Weekday.
+func (l *Locale) WeekdayName(d time.Weekday, w CalendarWidth) (string, error) {
+	return l.calendarTranslation("calendar.weekday."+w.String()+"."+strconv.Itoa(int(d)), calendarName(d.String(), w))
+}
+
+// eras are the English names of the eras of the Gregorian calendar, in their widths.
+var eras = [2][3]string{
+	{"Before Christ", "BC", "B"},
+	{"Anno Domini", "AD", "A"},
+}
+
+// EraName returns the name of the Gregorian era of the year provided by the catalogs of the Locale, in the given width,
+// as MonthName with the keys "calendar.era.<width>.<n>", 0 before Christ and 1 after.
+// Years are numbered as by time.Time, so year 0 is 1 BC.
+func (l *Locale) EraName(year int, w CalendarWidth) (string, error) {
+	era := 1
+	if year <= 0 {
+		era = 0
+	}
+	if w < CalendarWide || w > CalendarNarrow {
+		w = CalendarWide
+	}
+
+	return l.calendarTranslation("calendar.era."+w.String()+"."+strconv.Itoa(era), eras[era][w])
+}
+
+// MonthNames returns the names of the months from January to December, i.e. for date pickers, as MonthName.
+// The error is ErrNoCalendarName if any of them is missing from the catalogs.
+func (l *Locale) MonthNames(w CalendarWidth) ([]string, error) {
+	var err error
+	names := make([]string, 12)
+	for i := range names {
+		var e error
+		if names[i], e = l.MonthName(time.Month(i+1), w); e != nil {
+			err = e
+		}
+	}
+
+	return names, err
+}
+
+// WeekdayNames returns the names of the weekdays from Sunday to Saturday, as WeekdayName.
+// The error is ErrNoCalendarName if any of them is missing from the catalogs.
+func (l *Locale) WeekdayNames(w CalendarWidth) ([]string, error) {
+	var err error
+	names := make([]string, 7)
+	for i := range names {
+		var e error
+		if names[i], e = l.WeekdayName(time.Weekday(i), w); e != nil {
+			err = e
+		}
+	}
+
+	return names, err
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCalendarNames(t *testing.T) {
+	dirname := path.Join("/tmp", "calendarnames")
+	if err := os.MkdirAll(path.Join(dirname, "de", "LC_MESSAGES"), os.ModePerm); err != nil {
+		t.Fatalf("Can't create test directory: %s", err.Error())
+	}
+	defer os.RemoveAll(dirname)
+
+	str := `msgid ""
+msgstr ""
+"Language: de\n"
+
+msgid "calendar.month.wide.3"
+msgstr "März"
+
+msgid "calendar.month.abbreviated.3"
+msgstr "Mär"
+
+msgid "calendar.weekday.wide.0"
+msgstr "Sonntag"
+
+msgid "calendar.weekday.narrow.0"
+msgstr "S"
+
+msgid "calendar.weekday.narrow.4"
+msgstr "D"
+
+msgid "calendar.era.abbreviated.1"
+msgstr "n. Chr."
+`
+	err := ioutil.WriteFile(path.Join(dirname, "de", "LC_MESSAGES", "default.po"), []byte(str), os.ModePerm)
+	if err != nil {
+		t.Fatalf("Can't write test file: %s", err.Error())
+	}
+
+	de := NewLocale(dirname, "de")
+	de.AddDomain("default")
+
+	name := func(name string, err error) string {
+		if err != nil {
+			return name + " (" + err.Error() + ")"
+		}
+		return name
+	}
+	missing := " (" + ErrNoCalendarName.Error() + ")"
+
+	tests := []struct {
+		tr, expected string
+	}{
+		{name(de.MonthName(time.March, CalendarWide)), "März"},
+		{name(de.MonthName(time.March, CalendarAbbreviated)), "Mär"},
+		{name(de.MonthName(time.March, CalendarNarrow)), "M" + missing},
+		{name(de.MonthName(time.September, CalendarAbbreviated)), "Sep" + missing},
+		{name(de.WeekdayName(time.Sunday, CalendarWide)), "Sonntag"},
+		{name(de.WeekdayName(time.Thursday, CalendarNarrow)), "D"},
+		{name(de.WeekdayName(time.Friday, CalendarWide)), "Friday" + missing},
+		{name(de.EraName(2024, CalendarAbbreviated)), "n. Chr."},
+		{name(de.EraName(0, CalendarAbbreviated)), "BC" + missing},
+		{name(de.EraName(-44, CalendarWide)), "Before Christ" + missing},
+	}
+	for _, test := range tests {
+		if test.tr != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.tr)
+		}
+	}
+
+	expected := []string{"S", "M", "T", "W", "D", "F", "S"}
+	names, err := de.WeekdayNames(CalendarNarrow)
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v but got %v", expected, names)
+	}
+	if err != ErrNoCalendarName {
+		t.Errorf("Expected ErrNoCalendarName but got %v", err)
+	}
+	if names, _ := de.MonthNames(CalendarWide); len(names) != 12 || names[2] != "März" || names[11] != "December" {
+		t.Errorf("Unexpected names %v", names)
+	}
+
+	// English names are the bundled ones
+	en := NewLocale(dirname, "en_GB")
+	names, err = en.MonthNames(CalendarAbbreviated)
+	if err != nil || names[8] != "Sep" {
+		t.Errorf("Expected 'Sep' without error but got '%s', %v", names[8], err)
+	}
+	if tr, err := en.EraName(2024, CalendarWide); tr != "Anno Domini" || err != nil {
+		t.Errorf("Expected 'Anno Domini' without error but got '%s', %v", tr, err)
+	}
+}
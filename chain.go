@@ -0,0 +1,192 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "errors"
+
+/*
+ChainTranslator combines several Translators, resolving each lookup with the first one holding a translation for it,
+so callers don't have to implement fallback logic themselves.
+
+Example:
+
+	chain := gotext.NewChainTranslator(tenantOverrides, productCatalog, baseCatalog)
+
+	l := gotext.NewLocale("/path/to/i18n/dir", "de")
+	l.AddTranslator("default", chain)
+
+	fmt.Println(l.Get("Project"))
+*/
+type ChainTranslator struct {
+	links    []Translator
+	backends []Backend
+}
+
+// NewChainTranslator returns a Translator resolving lookups with the given Translators, in order.
+func NewChainTranslator(trs ...Translator) *ChainTranslator {
+	c := &ChainTranslator{
+		links:    trs,
+		backends: make([]Backend, len(trs)),
+	}
+	for i, tr := range trs {
+		c.backends[i] = backendOf(tr)
+	}
+
+	return c
+}
+
+// Translators returns the chained Translators, in lookup order.
+func (c *ChainTranslator) Translators() []Translator {
+	return append([]Translator(nil), c.links...)
+}
+
+// resolve returns the index of the first chained Translator holding the given plural form of a translation, or -1.
+func (c *ChainTranslator) resolve(ctx, id string, n int, plural bool) (int, string) {
+	for i, b := range c.backends {
+		tr, ok := b.Lookup(ctx, id)
+		if !ok {
+			continue
+		}
+
+		form := 0
+		if plural {
+			form = b.PluralForm(n)
+		}
+		if str := tr.Trs[form]; str != "" {
+			return i, str
+		}
+	}
+
+	return -1, ""
+}
+
+// Which returns the index of the first chained Translator holding a translation for the given context (empty for none)
+// and msgid, or -1 if there's none.
+func (c *ChainTranslator) Which(ctx, id string) int {
+	i, _ := c.resolve(ctx, id, 1, false)
+	return i
+}
+
+// Lookup returns the first translation found for the given context (empty for none) and msgid.
+// It implements the Backend interface, so chains can be nested.
+func (c *ChainTranslator) Lookup(ctx, id string) (*Translation, bool) {
+	for _, b := range c.backends {
+		if tr, ok := b.Lookup(ctx, id); ok && tr.Trs[0] != "" {
+			return tr, true
+		}
+	}
+
+	return nil, false
+}
+
+// PluralForm returns the index of the plural form to use for n, according to the first chained Translator.
+func (c *ChainTranslator) PluralForm(n int) int {
+	if len(c.backends) == 0 {
+		return emptyCatalog.pluralForm(n)
+	}
+
+	return c.backends[0].PluralForm(n)
+}
+
+// GetDomain returns the Domain of the first chained Translator, holding the catalog headers.
+func (c *ChainTranslator) GetDomain() *Domain {
+	if len(c.links) == 0 {
+		return NewDomain()
+	}
+
+	return c.links[0].GetDomain()
+}
+
+// ParseFile does nothing, chained Translators have to be parsed on their own.
+func (c *ChainTranslator) ParseFile(f string) {}
+
+// Parse does nothing, chained Translators have to be parsed on their own.
+func (c *ChainTranslator) Parse(buf []byte) {}
+
+// Get retrieves the corresponding Translation for the given string.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (c *ChainTranslator) Get(str string, vars ...interface{}) string {
+	if i, tr := c.resolve("", str, 1, false); i >= 0 {
+		return Printf(tr, vars...)
+	}
+
+	return Printf(str, vars...)
+}
+
+// GetN retrieves the (N)th plural form of Translation for the given string.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (c *ChainTranslator) GetN(str, plural string, n int, vars ...interface{}) string {
+	if i, tr := c.resolve("", str, n, true); i >= 0 {
+		return Printf(tr, vars...)
+	}
+
+	if c.PluralForm(n) == 0 {
+		return Printf(str, vars...)
+	}
+	return Printf(plural, vars...)
+}
+
+// GetC retrieves the corresponding Translation for a given string in the given context.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (c *ChainTranslator) GetC(str, ctx string, vars ...interface{}) string {
+	if i, tr := c.resolve(ctx, str, 1, false); i >= 0 {
+		return Printf(tr, vars...)
+	}
+
+	return Printf(str, vars...)
+}
+
+// GetNC retrieves the (N)th plural form of Translation for the given string in the given context.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (c *ChainTranslator) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	if i, tr := c.resolve(ctx, str, n, true); i >= 0 {
+		return Printf(tr, vars...)
+	}
+
+	if c.PluralForm(n) == 0 {
+		return Printf(str, vars...)
+	}
+	return Printf(plural, vars...)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+// The chain is flattened into a single catalog, with the headers of the first chained Translator.
+func (c *ChainTranslator) MarshalBinary() ([]byte, error) {
+	merged := NewDomain()
+	for i := len(c.links) - 1; i >= 0; i-- {
+		data, err := c.links[i].MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		do := NewDomain()
+		if err = do.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+
+		for k, tr := range do.load().entries {
+			if tr.Trs[0] != "" || k == (entryKey{}) {
+				merged.entries[k] = tr
+			}
+		}
+		if i == 0 {
+			merged.Headers = do.Headers
+			merged.Language = do.Language
+			merged.PluralForms = do.PluralForms
+			merged.nplurals = do.nplurals
+			merged.plural = do.plural
+		}
+	}
+	merged.publish()
+
+	return merged.MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+// Chains are restored as a single catalog by TranslatorEncoding.GetTranslator instead.
+func (c *ChainTranslator) UnmarshalBinary(data []byte) error {
+	return errors.New("chain translators can't be unmarshalled")
+}
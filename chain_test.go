@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "testing"
+
+func TestChainTranslator(t *testing.T) {
+	tenant := NewPo()
+	tenant.Parse([]byte(`
+msgid "Project"
+msgstr "Arbeitsbereich"
+
+msgctxt "Menu"
+msgid "Open"
+msgstr ""
+`))
+
+	product := NewPo()
+	product.Parse([]byte(`
+msgid ""
+msgstr "Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Project"
+msgstr "Projekt"
+
+msgctxt "Menu"
+msgid "Open"
+msgstr "Öffnen"
+
+msgid "%d project"
+msgid_plural "%d projects"
+msgstr[0] "%d Projekt"
+msgstr[1] "%d Projekte"
+`))
+
+	base := NewPo()
+	base.Parse([]byte(`
+msgid "Save"
+msgstr "Speichern"
+`))
+
+	l := NewLocale("", "de")
+	l.AddTranslator("default", NewChainTranslator(tenant, product, base))
+
+	for _, test := range []struct{ expected, got string }{
+		{"Arbeitsbereich", l.Get("Project")},
+		{"Öffnen", l.GetC("Open", "Menu")},
+		{"Speichern", l.Get("Save")},
+		{"3 Projekte", l.GetN("%d project", "%d projects", 3, 3)},
+		{"Missing", l.Get("Missing")},
+		{"Missing ones", l.GetN("Missing", "Missing ones", 2)},
+	} {
+		if test.expected != test.got {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.got)
+		}
+	}
+
+	// Chains are flattened when serialized
+	data, err := l.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := new(Locale)
+	if err = restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if tr := restored.Get("Project"); tr != "Arbeitsbereich" {
+		t.Errorf("Expected 'Arbeitsbereich' but got '%s'", tr)
+	}
+	if tr := restored.GetC("Open", "Menu"); tr != "Öffnen" {
+		t.Errorf("Expected 'Öffnen' but got '%s'", tr)
+	}
+	if tr := restored.Get("Save"); tr != "Speichern" {
+		t.Errorf("Expected 'Speichern' but got '%s'", tr)
+	}
+}
+
+func TestChainTranslatorMissingPlural(t *testing.T) {
+	fr := NewPo()
+	fr.Parse([]byte(`
+msgid ""
+msgstr "Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+msgid "Save"
+msgstr "Enregistrer"
+`))
+	chain := NewChainTranslator(fr, NewPo())
+
+	// the untranslated strings follow the plural rule of the chain, 0 being singular in French
+	for _, test := range []struct{ expected, got string }{
+		{"0 file", chain.GetN("%d file", "%d files", 0, 0)},
+		{"0 file", chain.GetNC("%d file", "%d files", 0, "Menu", 0)},
+		{"2 files", chain.GetN("%d file", "%d files", 2, 2)},
+		{"2 files", chain.GetNC("%d file", "%d files", 2, "Menu", 2)},
+	} {
+		if test.expected != test.got {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.got)
+		}
+	}
+}
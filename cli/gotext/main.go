@@ -0,0 +1,11 @@
+package main
+
+import (
+	"os"
+
+	"github.com/leonelquinteros/gotext/cli/internal/command"
+)
+
+func main() {
+	command.Main("gotext", os.Args[1:], "")
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// mainArgs is the environment variable running the test binary as the tool, with the newline separated arguments it
+// holds, see runMain.
+const mainArgs = "GOTEXT_MAIN_ARGS"
+
+func TestMain(m *testing.M) {
+	if args, ok := os.LookupEnv(mainArgs); ok {
+		os.Args = append([]string{"gotext"}, strings.Split(args, "\n")...)
+		if args == "" {
+			os.Args = os.Args[:1]
+		}
+		main()
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// runMain runs the tool with the arguments in dir, in a process of its own as commands exit on errors, and returns
+// its output and exit code.
+func runMain(t *testing.T, dir string, args ...string) (string, string, int) {
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), mainArgs+"="+strings.Join(args, "\n"))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	code := 0
+	if err := cmd.Run(); err != nil {
+		e, ok := err.(*exec.ExitError)
+		if !ok {
+			t.Fatal(err)
+		}
+		code = e.ExitCode()
+	}
+
+	return stdout.String(), stderr.String(), code
+}
+
+func TestUsage(t *testing.T) {
+	for _, test := range []struct {
+		args []string
+		code int
+	}{
+		{nil, 2},
+		{[]string{"help"}, 0},
+		{[]string{"-h"}, 0},
+	} {
+		_, stderr, code := runMain(t, ".", test.args...)
+		if code != test.code || !strings.HasPrefix(stderr, "Usage of gotext: [-config file] command [flags] [arguments]\n") {
+			t.Errorf("Expected the usage and exit code %d for %v but got '%s' and %d", test.code, test.args, stderr, code)
+		}
+		// The commands of the former xgotext binary are listed with the gotext ones
+		for _, name := range []string{"extract", "merge", "fmt", "convert", "sync", "gen", "completion"} {
+			if !strings.Contains(stderr, "\n  "+name+" ") {
+				t.Errorf("Expected the %s command in the usage for %v but got '%s'", name, test.args, stderr)
+			}
+		}
+	}
+
+	_, stderr, code := runMain(t, ".", "frobnicate")
+	if code != 2 || !strings.HasPrefix(stderr, "gotext: unknown command \"frobnicate\"\n\nUsage of gotext:") {
+		t.Errorf("Expected the unknown command to be reported but got '%s' and %d", stderr, code)
+	}
+	_, stderr, code = runMain(t, ".", "help", "diff")
+	if code != 0 || !strings.HasPrefix(stderr, "Usage of gotext diff: [flags] old.po new.po\n") {
+		t.Errorf("Expected the usage of diff but got '%s' and %d", stderr, code)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	dir := "/tmp/gotext_main"
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	po := "msgid \"\"\nmsgstr \"\"\n\"Language: de\\n\"\n\nmsgid \"Hello\"\nmsgstr \"Hallo\"\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "de.po"), []byte(po), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "xgotext.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Commands are run with their arguments, after the global flags
+	stdout, stderr, code := runMain(t, dir, "-config", "xgotext.json", "grep", "-msgid", "Hello", "de.po")
+	if expected := "de.po: \"Hello\" => \"Hallo\"\n"; code != 0 || stdout != expected {
+		t.Errorf("Expected '%s' but got '%s%s'", expected, stdout, stderr)
+	}
+	stdout, stderr, code = runMain(t, dir, "convert", "de.po", "de.mo")
+	if code != 0 {
+		t.Errorf("Expected the conversion to succeed but got '%s%s'", stdout, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "de.mo")); err != nil {
+		t.Error(err)
+	}
+	if _, stderr, code := runMain(t, dir, "extract"); code == 0 || !strings.Contains(stderr, "No input directory given") {
+		t.Errorf("Expected extract to require an input dir but got '%s'", stderr)
+	}
+
+	stdout, _, _ = runMain(t, dir, "__complete", "-config", "xgotext.json", "m")
+	if expected := "merge\nmsgfmt\nmsgunfmt\nmsgcat\nmsguniq\n"; stdout != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, stdout)
+	}
+}
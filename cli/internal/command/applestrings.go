@@ -0,0 +1,184 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// appleStringsEscaper escapes the strings of .strings files.
+var appleStringsEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+// writeAppleStrings writes the catalog in the .strings format of Apple platforms: keys are msgids, and the header is the value
+// of the empty key. Untranslated entries are left out, unless the catalog is in the source language or has none (i.e. templates), where they're translated
+// by their msgid as genstrings does. Contexts and plurals, held by .stringsdict files, are left out.
+func writeAppleStrings(f *gotext.PoFile, opts convertOptions) ([]byte, error) {
+	var b strings.Builder
+	if h := headerText(f); h != "" {
+		fmt.Fprintf(&b, "\"\" = \"%s\";\n", appleStringsEscaper.Replace(h))
+	}
+
+	lang := headerLanguage(f)
+	source := lang == "" || gotext.SimplifiedLocale(lang) == gotext.SimplifiedLocale(opts.sourceLanguage)
+	for _, e := range translatable(f) {
+		switch {
+		case e.HasContext:
+			skipped(e, ".strings files can't hold contexts")
+			continue
+		case e.PluralID != "":
+			skipped(e, ".strings files can't hold plural forms")
+			continue
+		case !e.Translated() && !source:
+			skipped(e, "untranslated")
+			continue
+		}
+
+		value := e.ID
+		if e.Translated() {
+			value = e.Str[0]
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		if comments := append(append([]string(nil), e.ExtractedComments...), e.TranslatorComments...); len(comments) > 0 {
+			fmt.Fprintf(&b, "/* %s */\n", strings.Replace(strings.Join(comments, "\n"), "*/", "* /", -1))
+		}
+		fmt.Fprintf(&b, "\"%s\" = \"%s\";\n", appleStringsEscaper.Replace(e.ID), appleStringsEscaper.Replace(value))
+	}
+
+	return []byte(b.String()), nil
+}
+
+// stringsScanner reads the tokens of .strings files.
+type stringsScanner struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+// skip skips spaces and comments, returning the last comment found.
+func (s *stringsScanner) skip() (comment string, err error) {
+	for s.pos < len(s.src) {
+		switch {
+		case s.src[s.pos] == '\n':
+			s.line++
+			s.pos++
+		case s.src[s.pos] == ' ' || s.src[s.pos] == '\t' || s.src[s.pos] == '\r' || s.src[s.pos] == '\uFEFF':
+			s.pos++
+		case s.hasPrefix("/*"):
+			end := strings.Index(string(s.src[s.pos+2:]), "*/")
+			if end < 0 {
+				return "", fmt.Errorf("strings: line %d: unterminated comment", s.line)
+			}
+			body := []rune(string(s.src[s.pos+2:])[:end])
+			s.line += strings.Count(string(body), "\n")
+			comment = strings.TrimSpace(string(body))
+			s.pos += 2 + len(body) + 2
+		case s.hasPrefix("//"):
+			for s.pos < len(s.src) && s.src[s.pos] != '\n' {
+				s.pos++
+			}
+		default:
+			return comment, nil
+		}
+	}
+
+	return comment, nil
+}
+
+// expect consumes the given rune.
+func (s *stringsScanner) expect(r rune) error {
+	if _, err := s.skip(); err != nil {
+		return err
+	}
+	if s.pos >= len(s.src) || s.src[s.pos] != r {
+		return fmt.Errorf("strings: line %d: expected %q", s.line, r)
+	}
+	s.pos++
+
+	return nil
+}
+
+// quoted reads a quoted string.
+func (s *stringsScanner) quoted() (string, error) {
+	if err := s.expect('"'); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for s.pos < len(s.src) {
+		r := s.src[s.pos]
+		s.pos++
+		switch r {
+		case '"':
+			return b.String(), nil
+		case '\n':
+			s.line++
+		case '\\':
+			if s.pos >= len(s.src) {
+				break
+			}
+			r = s.src[s.pos]
+			s.pos++
+			switch r {
+			case 'n':
+				r = '\n'
+			case 't':
+				r = '\t'
+			case 'r':
+				r = '\r'
+			}
+		}
+		b.WriteRune(r)
+	}
+
+	return "", fmt.Errorf("strings: line %d: unterminated string", s.line)
+}
+
+// hasPrefix reports if the remaining source starts with p.
+func (s *stringsScanner) hasPrefix(p string) bool {
+	return strings.HasPrefix(string(s.src[s.pos:]), p)
+}
+
+// readAppleStrings reads .strings files, keys being msgids and comments extracted comments.
+// The value of the empty key is the header, as written by writeAppleStrings.
+func readAppleStrings(data []byte, _ convertOptions) (*gotext.PoFile, error) {
+	s := &stringsScanner{src: []rune(string(data)), line: 1}
+
+	f := &gotext.PoFile{Entries: []*gotext.PoEntry{catalogHeader("")}}
+	for {
+		comment, err := s.skip()
+		if err != nil {
+			return nil, err
+		}
+		if s.pos >= len(s.src) {
+			return f, nil
+		}
+
+		key, err := s.quoted()
+		if err != nil {
+			return nil, err
+		}
+		if err = s.expect('='); err != nil {
+			return nil, err
+		}
+		value, err := s.quoted()
+		if err != nil {
+			return nil, err
+		}
+		if err = s.expect(';'); err != nil {
+			return nil, err
+		}
+
+		if key == "" {
+			setHeaderText(f, value)
+			continue
+		}
+		e := &gotext.PoEntry{ID: key, Str: []string{value}}
+		if comment != "" {
+			e.ExtractedComments = strings.Split(comment, "\n")
+		}
+		f.Entries = append(f.Entries, e)
+	}
+}
@@ -0,0 +1,164 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// arbKey returns the ARB resource id of an entry: its msgid, prefixed by its context.
+func arbKey(e *gotext.PoEntry) string {
+	if e.HasContext {
+		return e.Context + "." + e.ID
+	}
+
+	return e.ID
+}
+
+// writeARB writes the catalog in the Application Resource Bundle format of Flutter. Resource ids are msgids, kept with their
+// contexts, comments and plurals in the "@id" attributes. Plural entries of languages with 2 forms are ICU plural messages
+// on the "count" placeholder, the plural categories of other languages can't be mapped to gettext forms.
+// The PO header is kept in the "@@x-po-header" global attribute.
+func writeARB(f *gotext.PoFile, opts convertOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	// Resources are written in catalog order, with their attributes following them.
+	var b strings.Builder
+	b.WriteString("{\n")
+	field := func(key string, v interface{}) {
+		buf.Reset()
+		enc.Encode(key)
+		k := strings.TrimSpace(buf.String())
+		buf.Reset()
+		enc.Encode(v)
+		if b.Len() > 2 {
+			b.WriteString(",\n")
+		}
+		fmt.Fprintf(&b, "  %s: %s", k, strings.TrimSpace(buf.String()))
+	}
+
+	if lang := headerLanguage(f); lang != "" {
+		field("@@locale", lang)
+	}
+	if h := headerText(f); h != "" {
+		field("@@x-po-header", h)
+	}
+	for _, e := range translatable(f) {
+		key := arbKey(e)
+		attrs := map[string]interface{}{}
+		if e.HasContext {
+			attrs["context"] = e.Context
+			attrs["x-msgid"] = e.ID
+		}
+		if comments := append(append([]string(nil), e.ExtractedComments...), e.TranslatorComments...); len(comments) > 0 {
+			attrs["description"] = strings.Join(comments, "\n")
+		}
+		if e.HasFlag("fuzzy") {
+			attrs["x-fuzzy"] = true
+		}
+
+		value := ""
+		if len(e.Str) > 0 {
+			value = e.Str[0]
+		}
+		if e.PluralID != "" {
+			if len(e.Str) != 2 {
+				skipped(e, "ARB plural messages can't hold other than 2 plural forms")
+				continue
+			}
+			attrs["x-msgid_plural"] = e.PluralID
+			attrs["placeholders"] = map[string]interface{}{"count": map[string]string{"type": "int"}}
+			value = ""
+			if e.Str[0] != "" || e.Str[1] != "" {
+				value = fmt.Sprintf("{count, plural, one{%s} other{%s}}", e.Str[0], e.Str[1])
+			}
+		}
+
+		field(key, value)
+		if len(attrs) > 0 {
+			field("@"+key, attrs)
+		}
+	}
+	b.WriteString("\n}\n")
+
+	return []byte(b.String()), nil
+}
+
+// arbPluralRe matches the ICU plural messages written by writeARB.
+var arbPluralRe = regexp.MustCompile(`^\{\s*\w+\s*,\s*plural\s*,\s*one\s*\{(.*)\}\s*other\s*\{(.*)\}\s*\}$`)
+
+// readARB reads Application Resource Bundle files, as written by writeARB.
+func readARB(data []byte, _ convertOptions) (*gotext.PoFile, error) {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	var lang string
+	if raw, ok := m["@@locale"]; ok {
+		json.Unmarshal(raw, &lang)
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if !strings.HasPrefix(k, "@") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	f := &gotext.PoFile{Entries: []*gotext.PoEntry{catalogHeader(lang)}}
+	if raw, ok := m["@@x-po-header"]; ok {
+		var h string
+		if err := json.Unmarshal(raw, &h); err != nil {
+			return nil, fmt.Errorf("arb: @@x-po-header: %v", err)
+		}
+		setHeaderText(f, h)
+	}
+	for _, k := range keys {
+		var value string
+		if err := json.Unmarshal(m[k], &value); err != nil {
+			return nil, fmt.Errorf("arb: %s: %v", k, err)
+		}
+		var attrs struct {
+			Context     string `json:"context"`
+			Description string `json:"description"`
+			Msgid       string `json:"x-msgid"`
+			PluralID    string `json:"x-msgid_plural"`
+			Fuzzy       bool   `json:"x-fuzzy"`
+		}
+		if raw, ok := m["@"+k]; ok {
+			if err := json.Unmarshal(raw, &attrs); err != nil {
+				return nil, fmt.Errorf("arb: @%s: %v", k, err)
+			}
+		}
+
+		e := &gotext.PoEntry{ID: k, Context: attrs.Context, HasContext: attrs.Context != "", Str: []string{value}}
+		if attrs.Msgid != "" {
+			e.ID = attrs.Msgid
+		}
+		if attrs.Description != "" {
+			e.ExtractedComments = strings.Split(attrs.Description, "\n")
+		}
+		if attrs.Fuzzy {
+			e.SetFlag("fuzzy", true)
+		}
+		if attrs.PluralID != "" {
+			e.PluralID = attrs.PluralID
+			e.Str = []string{"", ""}
+			if sm := arbPluralRe.FindStringSubmatch(value); sm != nil {
+				e.Str = []string{sm[1], sm[2]}
+			}
+		}
+		f.Entries = append(f.Entries, e)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/leonelquinteros/gotext/cli/xgotext/parser"
+)
+
+// callPattern is a call whose string arguments are extracted, as configured in the "calls" of the "extract" section or
+// in call manifests. Argument positions start at 0, and the arguments without position aren't taken.
+type callPattern struct {
+	// Package is the import path of the function, or of the type of the method.
+	Package string `json:"package"`
+	// Function is the function name, or "Type.Method" for methods.
+	Function string `json:"function"`
+	ID       int    `json:"id"`
+	Plural   *int   `json:"plural"`
+	Context  *int   `json:"context"`
+	Domain   *int   `json:"domain"`
+	Default  *int   `json:"default"`
+}
+
+// getterDef returns the parser definition of the call.
+func (p callPattern) getterDef() parser.GetterDef {
+	position := func(i *int) int {
+		if i == nil {
+			return -1
+		}
+		return *i
+	}
+
+	return parser.GetterDef{
+		Id:      p.ID,
+		Plural:  position(p.Plural),
+		Context: position(p.Context),
+		Domain:  position(p.Domain),
+		Default: position(p.Default),
+	}
+}
+
+// readManifest reads a call manifest, a JSON array of call patterns shipped by frameworks wrapping gotext.
+func readManifest(file string) ([]callPattern, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []callPattern
+	if err = json.Unmarshal(data, &calls); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+
+	return calls, nil
+}
+
+// setCallPatterns sets the calls extracted in addition to the gotext getters: the ones of the configuration, then the
+// ones of its manifests.
+func setCallPatterns(conf extractConfig) error {
+	calls := conf.Calls
+	for _, file := range conf.Manifests {
+		if file == "" {
+			continue
+		}
+		manifest, err := readManifest(file)
+		if err != nil {
+			return err
+		}
+		calls = append(calls, manifest...)
+	}
+
+	patterns := make([]parser.CallPattern, 0, len(calls))
+	for _, c := range calls {
+		if c.Package == "" || c.Function == "" {
+			return fmt.Errorf("call pattern without package or function: %+v", c)
+		}
+		def := c.getterDef()
+		if def.Id < 0 || def.Plural < -1 || def.Context < -1 || def.Domain < -1 || def.Default < -1 {
+			return fmt.Errorf("negative argument position in the call pattern of %s.%s", c.Package, c.Function)
+		}
+		patterns = append(patterns, parser.CallPattern{Package: c.Package, Name: c.Function, GetterDef: def})
+	}
+	parser.SetCallPatterns(patterns)
+
+	return nil
+}
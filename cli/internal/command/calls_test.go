@@ -0,0 +1,108 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leonelquinteros/gotext/cli/xgotext/parser"
+)
+
+func TestSetCallPatterns(t *testing.T) {
+	dir := "/tmp/gotext_call_patterns"
+	writeFixture(t, dir, map[string]string{
+		"manifest.json": `[{"package": "example.com/i18n", "function": "Translator.N", "id": 1, "plural": 2, "domain": 0}]`,
+		"invalid.json":  `{"package": "example.com/i18n"}`,
+	})
+	defer os.RemoveAll(dir)
+	defer parser.SetCallPatterns(nil)
+
+	def := func(i int) *int {
+		return &i
+	}
+	conf := extractConfig{
+		Calls:     []callPattern{{Package: "example.com/i18n", Function: "T", ID: 0, Default: def(1)}},
+		Manifests: []string{filepath.Join(dir, "manifest.json")},
+	}
+	if err := setCallPatterns(conf); err != nil {
+		t.Fatal(err)
+	}
+
+	calls, err := readManifest(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := parser.GetterDef{Id: 1, Plural: 2, Context: -1, Domain: 0, Default: -1}
+	if len(calls) != 1 || calls[0].Function != "Translator.N" || calls[0].getterDef() != expected {
+		t.Errorf("Expected the Translator.N call with %v but got %v", expected, calls)
+	}
+	expected = parser.GetterDef{Id: 0, Plural: -1, Context: -1, Domain: -1, Default: 1}
+	if def := conf.Calls[0].getterDef(); def != expected {
+		t.Errorf("Expected %v but got %v", expected, def)
+	}
+
+	for _, test := range []struct {
+		conf     extractConfig
+		expected string
+	}{
+		{extractConfig{Calls: []callPattern{{Function: "T"}}}, "call pattern without package or function"},
+		{extractConfig{Calls: []callPattern{{Package: "example.com/i18n", Function: "T", ID: -1}}}, "negative argument position"},
+		{extractConfig{Calls: []callPattern{{Package: "example.com/i18n", Function: "T", Context: def(-2)}}}, "negative argument position"},
+		{extractConfig{Manifests: []string{filepath.Join(dir, "invalid.json")}}, "invalid.json: "},
+		{extractConfig{Manifests: []string{filepath.Join(dir, "missing.json")}}, "no such file"},
+	} {
+		if err := setCallPatterns(test.conf); err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("Expected '%s' but got '%v'", test.expected, err)
+		}
+	}
+}
+
+func TestExtractCalls(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_extract_calls"
+	writeFixture(t, dir, map[string]string{
+		"go.mod":        goMod("example.com/app"),
+		"xgotext.json":  `{"extract": {"calls": [{"package": "example.com/app/i18n", "function": "T", "id": 0, "default": 1}]}}`,
+		"manifest.json": `[{"package": "example.com/app/i18n", "function": "Translator.N", "id": 1, "plural": 2, "domain": 0}]`,
+		"i18n/i18n.go": `package i18n
+
+type Translator struct{}
+
+func T(key, def string) string { return def }
+
+func (t *Translator) N(domain, one, other string, n int) string { return one }
+`,
+		"main.go": `package main
+
+import (
+	"example.com/app/i18n"
+	"github.com/leonelquinteros/gotext"
+)
+
+func main() {
+	i18n.T("home.title", "Welcome")
+	tr := &i18n.Translator{}
+	tr.N("admin", "One user", "%d users", 2)
+	gotext.Get("Hello")
+}
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	// The calls of the configuration and of the manifests are extracted with the gotext getters
+	if r := runCommand(t, dir, "extract", "-in", ".", "-out", "locales", "-manifest", "manifest.json"); r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	checkReferences(t, filepath.Join(dir, "locales", "default.pot"), map[string]string{
+		"home.title": "main.go:9",
+		"Hello":      "main.go:12",
+	})
+	checkReferences(t, filepath.Join(dir, "locales", "admin.pot"), map[string]string{"One user": "main.go:11"})
+
+	r := runCommand(t, dir, "extract", "-in", ".", "-out", "locales", "-manifest", "missing.json")
+	if r.code == 0 || !strings.Contains(r.stderr, "missing.json") {
+		t.Errorf("Expected an error for the missing manifest but got '%s'", r.stderr)
+	}
+}
@@ -0,0 +1,77 @@
+package command
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// catalogEntry is a translation of a catalog with its context.
+type catalogEntry struct {
+	ctx string
+	*gotext.Translation
+}
+
+// loadCatalog loads a .po or .mo file.
+func loadCatalog(file string) (gotext.Translator, error) {
+	if _, err := os.Stat(file); err != nil {
+		return nil, err
+	}
+
+	var tr gotext.Translator = gotext.NewPo()
+	if filepath.Ext(file) == ".mo" {
+		tr = gotext.NewMo()
+	}
+	tr.ParseFile(file)
+
+	return tr, nil
+}
+
+// readCatalog loads a .po or .mo file and returns its encoding, holding the headers and all of the entries.
+func readCatalog(file string) (*gotext.TranslatorEncoding, error) {
+	tr, err := loadCatalog(file)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := tr.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s: %v", file, err)
+	}
+
+	enc := new(gotext.TranslatorEncoding)
+	if err = gob.NewDecoder(bytes.NewReader(data)).Decode(enc); err != nil {
+		return nil, fmt.Errorf("decoding %s: %v", file, err)
+	}
+
+	return enc, nil
+}
+
+// sortedEntries returns the entries of a catalog sorted by context and msgid, skipping the header entry.
+func sortedEntries(enc *gotext.TranslatorEncoding) []catalogEntry {
+	var entries []catalogEntry
+	for id, tr := range enc.Translations {
+		if id != "" {
+			entries = append(entries, catalogEntry{"", tr})
+		}
+	}
+	for ctx, trs := range enc.Contexts {
+		for _, tr := range trs {
+			entries = append(entries, catalogEntry{ctx, tr})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].ctx != entries[j].ctx {
+			return entries[i].ctx < entries[j].ctx
+		}
+		return entries[i].ID < entries[j].ID
+	})
+
+	return entries
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// check runs the check command, verifying that the translated catalogs are consistent with their template
+// with gotext.CheckConsistency, and failing when they aren't.
+func check(args []string) {
+	fs := newFlagSet("check", "[flags] template.pot [path ...]")
+	jsonOutput := fs.Bool("json", false, "print the issues as a JSON array")
+	disable := fs.String("disable", "", "comma separated list of issue kinds to ignore, i.e. \"extra,plural-count\"")
+	parseFlags(fs, args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	ignored := make(map[gotext.IssueKind]bool)
+	for _, kind := range strings.Split(*disable, ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			ignored[gotext.IssueKind(kind)] = true
+		}
+	}
+
+	template := fs.Arg(0)
+	pot, err := readPoFile(template)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Catalogs are looked for next to the template by default.
+	paths := fs.Args()[1:]
+	if len(paths) == 0 {
+		paths = []string{filepath.Dir(template)}
+	}
+	files, err := poFiles(paths)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	issues := []lintIssue{}
+	for _, file := range files {
+		if filepath.Ext(file) != ".po" {
+			continue
+		}
+		po, err := readPoFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, issue := range gotext.CheckConsistency(pot, po) {
+			if ignored[issue.Kind] {
+				continue
+			}
+			issues = append(issues, lintIssue{file, issue.Line, issue.Kind, issue.Context, issue.Msgid, issue.Message})
+			if *jsonOutput {
+				continue
+			}
+			fmt.Printf("%s: %s: %s", file, issue.Kind, issue.Message)
+			if issue.Context != "" {
+				fmt.Printf(" (msgid %q, msgctxt %q)\n", issue.Msgid, issue.Context)
+			} else {
+				fmt.Printf(" (msgid %q)\n", issue.Msgid)
+			}
+		}
+	}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err = enc.Encode(issues); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if len(issues) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,261 @@
+// Package command implements the commands of the gotext tool, shared by the gotext and xgotext binaries.
+package command
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+)
+
+// command is a subcommand of the tool.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string)
+}
+
+// commands are the subcommands, in the order they're listed by help.
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{"extract", "extract the strings of Go packages to .pot files", extract},
+		{"merge", "update a translated catalog to a new template", merge},
+		{"fmt", "format catalogs in canonical style", func(args []string) { fmtCatalogs("fmt", "", args) }},
+		{"sort", "sort the entries of catalogs", func(args []string) { fmtCatalogs("sort", "id", args) }},
+		{"lint", "check catalogs for mistakes", lint},
+		{"check", "check that translated catalogs match their template", check},
+		{"stats", "report the translation completion of catalogs", stats},
+		{"diff", "compare the translations of two catalogs", diff},
+		{"grep", "search the entries of catalogs", grep},
+		{"convert", "convert catalogs between file formats", convert},
+		{"pseudo", "pseudo-localize a template", pseudo},
+		{"compendium", "build compendiums and translate templates with them", compendium},
+		{"sync", "synchronize catalogs with translation management systems", syncCatalogs},
+		{"msgfmt", "compile a catalog to a .mo file", msgfmt},
+		{"msgunfmt", "decompile a .mo file to a catalog", msgunfmt},
+		{"msgcat", "concatenate catalogs", msgcat},
+		{"msguniq", "merge the duplicate entries of a catalog", msguniq},
+		{"export-js", "export a catalog to JavaScript formats", exportJS},
+		{"gen", "generate typed accessors of the messages of a catalog", gen},
+		{"po2go", "embed catalogs in Go source files", po2go},
+		{"help", "show the help of a command", help},
+		{"completion", "print the shell completion script of bash, zsh or fish", completion},
+	}
+}
+
+var (
+	// prog is the name of the running binary, shown in usages.
+	prog = "gotext"
+
+	// configFile is the project configuration file, set with the global -config flag.
+	configFile = defaultConfigFile
+
+	// completedFlag is the prefix of the flag being completed: parseFlags lists the matching ones instead of parsing.
+	completedFlag string
+)
+
+// findCommand returns the command with the given name, nil if there's none.
+func findCommand(name string) *command {
+	for _, c := range commands {
+		if c.name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// Main runs the tool named name with the arguments of the command line.
+// When defaultCommand is set, it's run with the arguments not starting with a command name, as xgotext does with extract.
+func Main(name string, args []string, defaultCommand string) {
+	log.SetFlags(0)
+	prog = name
+
+	global := flag.NewFlagSet(name, flag.ContinueOnError)
+	global.StringVar(&configFile, "config", defaultConfigFile, "project configuration file")
+	global.Usage = usage
+	if defaultCommand != "" {
+		// Flags of the default command are left to it.
+		global.Usage = func() {}
+		global.SetOutput(ioutil.Discard)
+	}
+
+	err := global.Parse(args)
+	if err != nil && defaultCommand != "" {
+		// The flag failing to parse is the first one of the default command, the global ones before it are left out.
+		findCommand(defaultCommand).run(args[len(args)-len(global.Args())-1:])
+		return
+	}
+	if err == flag.ErrHelp {
+		os.Exit(0)
+	}
+	if err != nil {
+		os.Exit(2)
+	}
+
+	args = global.Args()
+	if len(args) > 0 && args[0] == "__complete" {
+		complete(args[1:])
+		return
+	}
+	if len(args) > 0 {
+		if c := findCommand(args[0]); c != nil {
+			c.run(args[1:])
+			return
+		}
+	}
+	if defaultCommand != "" {
+		findCommand(defaultCommand).run(args)
+		return
+	}
+
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "%s: unknown command %q\n\n", prog, args[0])
+	}
+	usage()
+	os.Exit(2)
+}
+
+// usage prints the global usage, listing the commands.
+func usage() {
+	out := os.Stderr
+	fmt.Fprintf(out, "Usage of %s: [-config file] command [flags] [arguments]\n\nCommands:\n", prog)
+	for _, c := range commands {
+		fmt.Fprintf(out, "  %-12s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(out, "\nRun '%s help command' for the flags of a command.\n\n", prog)
+	fmt.Fprintf(out, "  -config string\n    \tproject configuration file (default %q)\n", defaultConfigFile)
+}
+
+// newFlagSet returns the flag set of a command, its usage showing the synopsis of the arguments.
+func newFlagSet(name, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage of %s %s: %s\n", prog, name, synopsis)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parseFlags parses the flags of a command, or lists them when completing.
+func parseFlags(fs *flag.FlagSet, args []string) {
+	if completedFlag != "" {
+		fs.VisitAll(func(f *flag.Flag) {
+			if name := "-" + f.Name; strings.HasPrefix(name, completedFlag) {
+				fmt.Println(name)
+			}
+		})
+		os.Exit(0)
+	}
+
+	fs.Parse(args)
+}
+
+// help runs the help command, showing the usage of a command or the global one.
+func help(args []string) {
+	if len(args) == 0 {
+		usage()
+		return
+	}
+
+	c := findCommand(args[0])
+	if c == nil || c.name == "help" {
+		usage()
+		os.Exit(2)
+	}
+	c.run([]string{"-h"})
+}
+
+// complete prints the completions of the last word of the command line, the previous ones being the arguments of the tool.
+// Nothing is printed for arguments, so shells complete them as files.
+func complete(words []string) {
+	cur := ""
+	if len(words) > 0 {
+		cur, words = words[len(words)-1], words[:len(words)-1]
+	}
+
+	// Global flags are skipped.
+	for len(words) > 0 && strings.HasPrefix(words[0], "-") {
+		if words[0] == "-config" || words[0] == "--config" {
+			words = words[1:]
+		}
+		if len(words) > 0 {
+			words = words[1:]
+		}
+	}
+
+	if len(words) == 0 {
+		if strings.HasPrefix(cur, "-") {
+			if strings.HasPrefix("-config", "-"+strings.TrimLeft(cur, "-")) {
+				fmt.Println("-config")
+			}
+			return
+		}
+		for _, c := range commands {
+			if strings.HasPrefix(c.name, cur) {
+				fmt.Println(c.name)
+			}
+		}
+		return
+	}
+
+	if words[0] == "help" && len(words) == 1 {
+		for _, c := range commands {
+			if strings.HasPrefix(c.name, cur) {
+				fmt.Println(c.name)
+			}
+		}
+		return
+	}
+
+	c := findCommand(words[0])
+	if c == nil || c.name == "help" || c.name == "completion" || !strings.HasPrefix(cur, "-") {
+		return
+	}
+	// Commands list their flags when parsing them, past their own subcommands.
+	completedFlag = "-" + strings.TrimLeft(cur, "-")
+	c.run(words[1:])
+}
+
+// completionScripts are the completion scripts of the shells, "{prog}" being replaced by the name of the binary.
+var completionScripts = map[string]string{
+	"bash": `_{prog}_complete() {
+	local IFS=$'\n'
+	COMPREPLY=($({prog} __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _{prog}_complete {prog}
+`,
+	"zsh": `#compdef {prog}
+
+_{prog}_complete() {
+	local -a completions
+	completions=(${(f)"$({prog} __complete "${(@)words[2,CURRENT]}" 2>/dev/null)"})
+	if (( ${#completions} )); then
+		compadd -a completions
+	else
+		_files
+	fi
+}
+compdef _{prog}_complete {prog}
+`,
+	"fish": `complete -c {prog} -a '({prog} __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)'
+`,
+}
+
+// completion runs the completion command, printing the completion script of a shell.
+func completion(args []string) {
+	fs := newFlagSet("completion", "bash|zsh|fish")
+	parseFlags(fs, args)
+
+	script, ok := completionScripts[fs.Arg(0)]
+	if fs.NArg() != 1 || !ok {
+		fs.Usage()
+		os.Exit(2)
+	}
+	fmt.Print(strings.Replace(script, "{prog}", prog, -1))
+}
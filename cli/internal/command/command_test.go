@@ -0,0 +1,147 @@
+package command
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// commandArgs is the environment variable running the test binary as the gotext tool, with the newline separated
+// arguments it holds, see runCommand.
+const commandArgs = "GOTEXT_COMMAND_ARGS"
+
+func TestMain(m *testing.M) {
+	if args, ok := os.LookupEnv(commandArgs); ok {
+		Main("gotext", strings.Split(args, "\n"), "")
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// commandResult is the outcome of a command run by runCommand.
+type commandResult struct {
+	stdout string
+	stderr string
+	code   int
+}
+
+// runCommand runs the gotext tool with the arguments in dir, in a process of its own as commands exit on errors.
+func runCommand(t *testing.T, dir string, args ...string) commandResult {
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), commandArgs+"="+strings.Join(args, "\n"))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	r := commandResult{}
+	if err := cmd.Run(); err != nil {
+		e, ok := err.(*exec.ExitError)
+		if !ok {
+			t.Fatal(err)
+		}
+		r.code = e.ExitCode()
+	}
+	r.stdout, r.stderr = stdout.String(), stderr.String()
+
+	return r
+}
+
+var (
+	loaderOnce sync.Once
+	loaderErr  string
+)
+
+// requireLoader skips the test when go/packages can't load the packages with the Go toolchain installed, as it exits the
+// process on the internal errors of toolchains newer than it supports. A package is extracted to find out.
+// On CI, with the CI environment variable set, the test fails instead, as the toolchain pinned by the workflow has to
+// load them.
+func requireLoader(t *testing.T) {
+	loaderOnce.Do(func() {
+		dir := "/tmp/gotext_command_probe"
+		writeFixture(t, dir, map[string]string{
+			"go.mod":  goMod("example.com/probe"),
+			"main.go": "package main\n\nimport \"github.com/leonelquinteros/gotext\"\n\nfunc main() {\n\tgotext.Get(\"Probe\")\n}\n",
+		})
+		defer os.RemoveAll(dir)
+
+		if r := runCommand(t, dir, "extract", "-in", ".", "-out", "."); r.code != 0 {
+			loaderErr = strings.TrimSpace(r.stderr)
+		}
+	})
+	if loaderErr != "" {
+		if os.Getenv("CI") != "" {
+			t.Fatalf("go/packages can't load packages with this Go toolchain: %s", loaderErr)
+		}
+		t.Skipf("go/packages can't load packages with this Go toolchain: %s", loaderErr)
+	}
+}
+
+// repoRoot is the root of the gotext module, the tests being run in the package directory.
+const repoRoot = "../../.."
+
+// goMod returns the go.mod file of a fixture module requiring gotext, replaced by the repository. Go 1.16 is the
+// first version embedding files.
+func goMod(module string) string {
+	root, _ := filepath.Abs(repoRoot)
+	return "module " + module + "\n\ngo 1.16\n\nrequire github.com/leonelquinteros/gotext v0.0.0\n\n" +
+		"replace github.com/leonelquinteros/gotext => " + filepath.ToSlash(root) + "\n"
+}
+
+// writeFixture writes the files of a fixture to dir, by path relative to it, replacing its previous content.
+// Modules get the go.sum of the repository, so their requirements are verified.
+func writeFixture(t *testing.T, dir string, files map[string]string) {
+	os.RemoveAll(dir)
+	sum, err := ioutil.ReadFile(filepath.Join(repoRoot, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Base(name) == "go.mod" {
+			if err := ioutil.WriteFile(filepath.Join(filepath.Dir(path), "go.sum"), sum, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+// readReferences returns the source references of the entries of the template, by msgid, prefixed with their context
+// and "|" when they have one.
+func readReferences(t *testing.T, path string) map[string]string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := gotext.ParsePoFile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs := make(map[string]string)
+	for _, e := range f.Entries {
+		if e.IsHeader() {
+			continue
+		}
+		key := e.ID
+		if e.HasContext {
+			key = e.Context + "|" + key
+		}
+		refs[key] = strings.Join(e.References, " ")
+	}
+	return refs
+}
@@ -0,0 +1,134 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// compendium runs the compendium build and apply commands.
+func compendium(args []string) {
+	if len(args) > 0 {
+		switch args[0] {
+		case "build":
+			buildCompendium(args[1:])
+			return
+		case "apply":
+			applyCompendium(args[1:])
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Usage of %s compendium: build|apply [flags] ...\n", prog)
+	os.Exit(2)
+}
+
+// writeOutput writes the catalog to the output file, or to the standard output when not set.
+func writeOutput(out string, f *gotext.PoFile) {
+	src := f.Format(gotext.FormatOptions{})
+	if out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := ioutil.WriteFile(out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// buildCompendium combines the translations of the catalogs of a language into a compendium.
+func buildCompendium(args []string) {
+	fs := newFlagSet("compendium build", "[flags] path ...")
+	out := fs.String("o", "", "output file: /path/to/compendium.fr.po (default standard output)")
+	report := fs.Bool("report", false, "print the conflicting translations, whose first one is kept")
+	parseFlags(fs, args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	files, err := poFiles(fs.Args())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var (
+		catalogs []*gotext.PoFile
+		names    []string
+		lang     string
+	)
+	for _, file := range files {
+		if filepath.Ext(file) != ".po" {
+			continue
+		}
+		f, err := readPoFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// Translations of a compendium must be in the same language.
+		l := catalogLanguage(file, f)
+		if lang == "" {
+			lang = l
+		} else if l != lang {
+			log.Fatalf("%s: language %q differs from %q, build a compendium per language", file, l, lang)
+		}
+		catalogs = append(catalogs, f)
+		names = append(names, file)
+	}
+
+	c, conflicts := gotext.BuildCompendium(catalogs...)
+	if *report {
+		printConflicts(names, conflicts)
+	}
+	if lang != "" {
+		h := c.Header()
+		if h == nil {
+			h = catalogHeader("")
+			c.Entries = append([]*gotext.PoEntry{h}, c.Entries...)
+		}
+		h.SetHeaderValue("Language", lang)
+	}
+
+	writeOutput(*out, c)
+}
+
+// applyCompendium translates a template with a compendium.
+func applyCompendium(args []string) {
+	fs := newFlagSet("compendium apply", "[flags] compendium.po new.pot")
+	out := fs.String("o", "", "output file: /path/to/default.po (default standard output)")
+	noFuzzy := fs.Bool("N", false, "translate exact matches only, without fuzzy translations of similar msgids")
+	parseFlags(fs, args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	c, err := readPoFile(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	pot, err := readPoFile(fs.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	po := gotext.MergeTemplate(&gotext.PoFile{}, pot, gotext.MergeOptions{NoFuzzyMatching: *noFuzzy, Compendium: c})
+	if h, ch := po.Header(), c.Header(); h != nil && ch != nil {
+		for _, key := range []string{"Language", "Plural-Forms"} {
+			if v := ch.HeaderValue(key); v != "" {
+				h.SetHeaderValue(key, v)
+			}
+		}
+	}
+
+	stats := po.Stats()
+	log.Printf("%d translated, %d fuzzy, %d untranslated", stats.Translated, stats.Fuzzy, stats.Untranslated)
+
+	writeOutput(*out, po)
+}
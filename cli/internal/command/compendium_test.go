@@ -0,0 +1,75 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompendium(t *testing.T) {
+	dir := "/tmp/gotext_compendium"
+	writeFixture(t, dir, map[string]string{
+		"i18n/default.pot": mergeTemplate,
+		"i18n/de/web.po":   "msgid \"Hello\"\nmsgstr \"Hallo\"\n\nmsgid \"Hello world\"\nmsgstr \"Hallo Welt\"\n",
+		"i18n/de/api.po":   "msgid \"Hello\"\nmsgstr \"Servus\"\n\nmsgid \"Open\"\nmsgstr \"\"\n",
+		"i18n/fr/web.po":   "msgid \"Hello\"\nmsgstr \"Bonjour\"\n",
+		"i18n/fr/admin.po": "msgid \"Users\"\nmsgstr \"Utilisateurs\"\n",
+	})
+	defer os.RemoveAll(dir)
+
+	if r := runCommand(t, dir, "compendium"); r.code != 2 || !strings.Contains(r.stderr, "compendium: build|apply") {
+		t.Errorf("Expected the usage but got %d '%s'", r.code, r.stderr)
+	}
+
+	// The translations of a language are combined, the first of conflicting ones being kept
+	r := runCommand(t, dir, "compendium", "build", "i18n/de")
+	for _, expected := range []string{"\"Language: de\\n\"", "msgid \"Hello\"\nmsgstr \"Servus\"\n", "msgid \"Hello world\"\nmsgstr \"Hallo Welt\"\n"} {
+		if r.code != 0 || !strings.Contains(r.stdout, expected) {
+			t.Errorf("Expected '%s' in '%s%s'", expected, r.stdout, r.stderr)
+		}
+	}
+	if strings.Contains(r.stdout, "Open") || r.stderr != "" {
+		t.Errorf("Expected only the translated strings but got '%s%s'", r.stdout, r.stderr)
+	}
+	r = runCommand(t, dir, "compendium", "build", "-report", "i18n/de")
+	if expected := "i18n/de/web.po: msgid \"Hello\": translation \"Hallo\" conflicts with \"Servus\"\n"; r.code != 0 || r.stderr != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, r.stderr)
+	}
+	r = runCommand(t, dir, "compendium", "build", "i18n")
+	if r.code != 1 || !strings.Contains(r.stderr, "build a compendium per language") {
+		t.Errorf("Expected a language error but got %d '%s'", r.code, r.stderr)
+	}
+	if r := runCommand(t, dir, "compendium", "build", "-o", "de.po", "i18n/de"); r.code != 0 || r.stdout != "" {
+		t.Fatalf("Expected success but got '%s%s'", r.stdout, r.stderr)
+	}
+
+	// Templates are translated with the exact and similar msgids of the compendium
+	r = runCommand(t, dir, "compendium", "apply", "de.po", "i18n/default.pot")
+	for _, expected := range []string{
+		"\"Language: de\\n\"",
+		"#: main.go:10\nmsgid \"Hello\"\nmsgstr \"Servus\"\n",
+		"#: main.go:11\n#, fuzzy\nmsgid \"Hello world!\"\nmsgstr \"Hallo Welt\"\n",
+		"#: main.go:12\nmsgid \"Save\"\nmsgstr \"\"\n",
+	} {
+		if r.code != 0 || !strings.Contains(r.stdout, expected) {
+			t.Errorf("Expected '%s' in '%s%s'", expected, r.stdout, r.stderr)
+		}
+	}
+	if expected := "1 translated, 1 fuzzy, 1 untranslated\n"; r.stderr != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, r.stderr)
+	}
+
+	r = runCommand(t, dir, "compendium", "apply", "-N", "-o", "i18n/de/default.po", "de.po", "i18n/default.pot")
+	if expected := "1 translated, 0 fuzzy, 2 untranslated\n"; r.code != 0 || r.stderr != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, r.stderr)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "i18n", "de", "default.po"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "#: main.go:11\nmsgid \"Hello world!\"\nmsgstr \"\"\n"; !strings.Contains(string(data), expected) {
+		t.Errorf("Expected '%s' in '%s'", expected, data)
+	}
+}
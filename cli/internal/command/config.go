@@ -0,0 +1,46 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+)
+
+// defaultConfigFile is the project configuration file read from the current directory.
+const defaultConfigFile = "xgotext.json"
+
+// projectConfig is the project configuration, read from a JSON file.
+type projectConfig struct {
+	Extract extractConfig `json:"extract"`
+	Sync    syncConfig    `json:"sync"`
+}
+
+// loadConfig reads the project configuration file. A missing default file is an empty configuration.
+func loadConfig(file string) (*projectConfig, error) {
+	conf := new(projectConfig)
+
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) && file == defaultConfigFile {
+		return conf, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, conf); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+
+	return conf, nil
+}
+
+// loadProjectConfig reads the configuration file given with the global -config flag, exiting on errors.
+func loadProjectConfig() *projectConfig {
+	conf, err := loadConfig(configFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return conf
+}
@@ -0,0 +1,431 @@
+package command
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// catalogFormat reads and writes catalogs in a file format, using gotext.PoFile as the common model.
+// A nil read or write function means the format can't be read or written.
+// Formats without a header of their own keep the PO header in an entry with an empty key, as PO files do.
+type catalogFormat struct {
+	read  func(data []byte, opts convertOptions) (*gotext.PoFile, error)
+	write func(f *gotext.PoFile, opts convertOptions) ([]byte, error)
+}
+
+// convertOptions configures how catalogs are read and written.
+type convertOptions struct {
+	sourceLanguage   string
+	contextSeparator string
+}
+
+// catalogFormats are the formats supported by the convert command, by name.
+// Names are the file extensions detected, "json" being the i18next format.
+var catalogFormats = map[string]catalogFormat{
+	"po":        {readPo, writePo},
+	"pot":       {readPo, writePot},
+	"mo":        {readMo, writeMo},
+	"json":      {readI18next, writeI18next},
+	"gettextjs": {nil, writeGettextJS},
+	"csv":       {readCSV, writeCSV},
+	"xliff":     {readXLIFF, writeXLIFF},
+	"xlf":       {readXLIFF, writeXLIFF},
+	"arb":       {readARB, writeARB},
+	"strings":   {readAppleStrings, writeAppleStrings},
+}
+
+// formatNames returns the sorted names of the formats.
+func formatNames() string {
+	names := make([]string, 0, len(catalogFormats))
+	for name := range catalogFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
+
+// skipped warns about an entry the output format can't hold.
+func skipped(e *gotext.PoEntry, reason string) {
+	log.Printf("Skipping msgid %q: %s", e.ID, reason)
+}
+
+// translatable returns the entries to convert, without header nor obsolete entries.
+func translatable(f *gotext.PoFile) []*gotext.PoEntry {
+	var entries []*gotext.PoEntry
+	for _, e := range f.Entries {
+		if !e.IsHeader() && !e.Obsolete {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+// catalogHeader returns the header of a new catalog in the given language.
+func catalogHeader(lang string) *gotext.PoEntry {
+	h := &gotext.PoEntry{}
+	h.SetHeaderValue("Content-Type", "text/plain; charset=UTF-8")
+	if lang != "" {
+		h.SetHeaderValue("Language", lang)
+	}
+
+	return h
+}
+
+// headerLanguage returns the Language header of a catalog.
+func headerLanguage(f *gotext.PoFile) string {
+	if h := f.Header(); h != nil {
+		return h.HeaderValue("Language")
+	}
+
+	return ""
+}
+
+// headerText returns the header of a catalog, as the msgstr of its header entry.
+func headerText(f *gotext.PoFile) string {
+	if h := f.Header(); h != nil && len(h.Str) > 0 {
+		return h.Str[0]
+	}
+
+	return ""
+}
+
+// setHeaderText replaces the header of a catalog read with catalogHeader by the given one, keeping its Language if it's unset.
+func setHeaderText(f *gotext.PoFile, text string) {
+	h := f.Header()
+	lang := h.HeaderValue("Language")
+	h.Str = []string{text}
+	if lang != "" && h.HeaderValue("Language") == "" {
+		h.SetHeaderValue("Language", lang)
+	}
+}
+
+func readPo(data []byte, _ convertOptions) (*gotext.PoFile, error) {
+	return gotext.ParsePoFile(data)
+}
+
+func readMo(data []byte, _ convertOptions) (*gotext.PoFile, error) {
+	return gotext.UnmarshalMo(data)
+}
+
+func writePo(f *gotext.PoFile, _ convertOptions) ([]byte, error) {
+	return f.Format(gotext.FormatOptions{}), nil
+}
+
+// writePot writes the catalog as a template, without translations.
+func writePot(f *gotext.PoFile, _ convertOptions) ([]byte, error) {
+	tmpl := &gotext.PoFile{}
+	for _, e := range f.Entries {
+		if e.Obsolete {
+			continue
+		}
+		t := *e
+		t.TranslatorComments, t.Previous = nil, nil
+		t.SetFlag("fuzzy", false)
+		if !e.IsHeader() {
+			t.Str = make([]string, len(e.Str))
+		}
+		tmpl.Entries = append(tmpl.Entries, &t)
+	}
+
+	return tmpl.Format(gotext.FormatOptions{}), nil
+}
+
+func writeMo(f *gotext.PoFile, _ convertOptions) ([]byte, error) {
+	return f.MarshalMo(false), nil
+}
+
+// translator returns the catalog loaded as a Translator, for the library exports.
+func translator(f *gotext.PoFile) gotext.Translator {
+	po := gotext.NewPo()
+	po.Parse(f.Format(gotext.FormatOptions{NoWrap: true}))
+
+	return po
+}
+
+// writeI18next writes the translations in the i18next JSON format, with the header under the empty key.
+func writeI18next(f *gotext.PoFile, _ convertOptions) ([]byte, error) {
+	data, err := gotext.ExportI18next(translator(f))
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]string)
+	if err = json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	if h := headerText(f); h != "" {
+		m[""] = h
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(m)
+
+	return buf.Bytes(), err
+}
+
+func writeGettextJS(f *gotext.PoFile, _ convertOptions) ([]byte, error) {
+	data, err := gotext.ExportGettextJS(translator(f))
+	return append(data, '\n'), err
+}
+
+// flattenI18next adds the strings of an i18next JSON object to m, the keys of nested objects being joined with dots.
+func flattenI18next(m map[string]string, prefix string, obj map[string]interface{}) error {
+	for k, v := range obj {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case string:
+			m[key] = v
+		case map[string]interface{}:
+			if err := flattenI18next(m, key, v); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("json: %s: expected a string or an object", key)
+		}
+	}
+
+	return nil
+}
+
+// i18nextForm returns the key of the entry an i18next key holds a plural form of, and the index of the form:
+// "key_plural" holds the second form of "key" for languages with 2 forms, and "key_0", "key_1"... the forms of the others.
+func i18nextForm(k string, m map[string]string) (string, int) {
+	if base := strings.TrimSuffix(k, "_plural"); base != k {
+		if _, ok := m[base]; ok {
+			return base, 1
+		}
+	}
+	if i := strings.LastIndex(k, "_"); i > 0 {
+		if n, err := strconv.Atoi(k[i+1:]); err == nil && n >= 0 {
+			if _, ok := m[k[:i]+"_0"]; ok {
+				return k[:i], n
+			}
+		}
+	}
+
+	return k, 0
+}
+
+// readI18next reads i18next JSON files, as written by writeI18next. Keys are msgids, followed by their context after the
+// context separator, and plural forms are read as i18nextForm describes. i18next doesn't record plural msgids, so the
+// last plural form is used as msgid_plural, which is the right one for catalogs in the source language.
+func readI18next(data []byte, opts convertOptions) (*gotext.PoFile, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	m := make(map[string]string)
+	if err := flattenI18next(m, "", obj); err != nil {
+		return nil, err
+	}
+
+	f := &gotext.PoFile{Entries: []*gotext.PoEntry{catalogHeader("")}}
+	if h, ok := m[""]; ok {
+		setHeaderText(f, h)
+		delete(m, "")
+	}
+
+	forms := make(map[string]map[int]string)
+	for k, v := range m {
+		base, n := i18nextForm(k, m)
+		if forms[base] == nil {
+			forms[base] = make(map[int]string)
+		}
+		forms[base][n] = v
+	}
+
+	keys := make([]string, 0, len(forms))
+	for k := range forms {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		e := &gotext.PoEntry{ID: k}
+		if sep := opts.contextSeparator; sep != "" {
+			if i := strings.LastIndex(k, sep); i > 0 && i+len(sep) < len(k) {
+				e.ID, e.Context, e.HasContext = k[:i], k[i+len(sep):], true
+			}
+		}
+
+		n := 0
+		for i := range forms[k] {
+			if i >= n {
+				n = i + 1
+			}
+		}
+		e.Str = make([]string, n)
+		for i, str := range forms[k] {
+			e.Str[i] = str
+		}
+		if n > 1 {
+			e.PluralID = e.Str[n-1]
+			if e.PluralID == "" {
+				e.PluralID = e.ID
+			}
+		}
+		f.Entries = append(f.Entries, e)
+	}
+
+	return f, nil
+}
+
+// writeCSV writes a row per entry: msgctxt, msgid, msgid_plural, flags and the msgstr forms, after a header row.
+// The catalog header comes first, as the msgstr of a row with an empty msgid.
+func writeCSV(f *gotext.PoFile, _ convertOptions) ([]byte, error) {
+	entries := translatable(f)
+	forms := 1
+	for _, e := range entries {
+		if len(e.Str) > forms {
+			forms = len(e.Str)
+		}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	row := []string{"msgctxt", "msgid", "msgid_plural", "flags"}
+	for i := 0; i < forms; i++ {
+		row = append(row, fmt.Sprintf("msgstr[%d]", i))
+	}
+	w.Write(row)
+	if h := headerText(f); h != "" {
+		row = []string{"", "", "", "", h}
+		for len(row) < forms+4 {
+			row = append(row, "")
+		}
+		w.Write(row)
+	}
+	for _, e := range entries {
+		row = append([]string{e.Context, e.ID, e.PluralID, strings.Join(e.Flags, ",")}, e.Str...)
+		for len(row) < forms+4 {
+			row = append(row, "")
+		}
+		w.Write(row)
+	}
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+// readCSV reads the files written by writeCSV.
+func readCSV(data []byte, _ convertOptions) (*gotext.PoFile, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 || len(rows[0]) < 5 || rows[0][1] != "msgid" {
+		return nil, fmt.Errorf("csv: missing msgctxt, msgid, msgid_plural, flags, msgstr[0]... header row")
+	}
+
+	f := &gotext.PoFile{Entries: []*gotext.PoEntry{catalogHeader("")}}
+	for i, row := range rows[1:] {
+		if len(row) < 5 {
+			return nil, fmt.Errorf("csv: row %d: expected at least 5 fields", i+2)
+		}
+		e := &gotext.PoEntry{Context: row[0], HasContext: row[0] != "", ID: row[1], PluralID: row[2]}
+		if e.IsHeader() {
+			setHeaderText(f, row[4])
+			continue
+		}
+		for _, flag := range strings.Split(row[3], ",") {
+			if flag = strings.TrimSpace(flag); flag != "" {
+				e.Flags = append(e.Flags, flag)
+			}
+		}
+		e.Str = row[4:]
+		if e.PluralID == "" {
+			e.Str = row[4:5]
+		}
+		f.Entries = append(f.Entries, e)
+	}
+
+	return f, nil
+}
+
+// convert runs the convert command, converting catalogs between file formats detected by extension.
+func convert(args []string) {
+	fs := newFlagSet("convert", "[flags] input output\n\nFormats: "+formatNames()+"\n")
+	from := fs.String("from", "", "input format (default detected from the input file extension)")
+	to := fs.String("to", "", "output format (default detected from the output file extension)")
+	sourceLang := fs.String("source-lang", "en", "language of the msgids, for the formats recording it")
+	lang := fs.String("lang", "", "Language header of the catalog, for input formats not recording it")
+	pluralForms := fs.String("plural-forms", "", "Plural-Forms header of the catalog, for input formats not recording it")
+	contextSeparator := fs.String("context-separator", "_", "separator of the contexts in the keys of i18next files, empty to keep them in msgids")
+	parseFlags(fs, args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	in, out := fs.Arg(0), fs.Arg(1)
+	if *from == "" {
+		*from = strings.TrimPrefix(filepath.Ext(in), ".")
+	}
+	if *to == "" {
+		*to = strings.TrimPrefix(filepath.Ext(out), ".")
+	}
+
+	inFormat, ok := catalogFormats[*from]
+	if !ok || inFormat.read == nil {
+		log.Fatalf("Unknown input format %q, use -from with one of: %s", *from, formatNames())
+	}
+	outFormat, ok := catalogFormats[*to]
+	if !ok || outFormat.write == nil {
+		log.Fatalf("Unknown output format %q, use -to with one of: %s", *to, formatNames())
+	}
+
+	data, err := ioutil.ReadFile(in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts := convertOptions{sourceLanguage: *sourceLang, contextSeparator: *contextSeparator}
+	f, err := inFormat.read(data, opts)
+	if err != nil {
+		log.Fatalf("%s: %v", in, err)
+	}
+	if h := f.Header(); h != nil {
+		if *lang != "" {
+			h.SetHeaderValue("Language", *lang)
+		} else if h.HeaderValue("Language") == "" {
+			log.Printf("%s: no Language header, use -lang to set it", in)
+		}
+		if *pluralForms != "" {
+			h.SetHeaderValue("Plural-Forms", *pluralForms)
+		} else if h.HeaderValue("Plural-Forms") == "" {
+			log.Printf("%s: no Plural-Forms header, use -plural-forms to set it", in)
+		}
+	}
+
+	data, err = outFormat.write(f, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if out == "-" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err = ioutil.WriteFile(out, data, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,296 @@
+package command
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+const convertCatalog = `msgid ""
+msgstr ""
+"Project-Id-Version: convert\n"
+"Language: de\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+#. Greeting
+msgid "Hello"
+msgstr "Hallo"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Öffnen"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d Datei"
+msgstr[1] "%d Dateien"
+
+#, fuzzy
+msgid "Save"
+msgstr "Speichern"
+
+msgid "Untranslated"
+msgstr ""
+`
+
+func TestConvertRoundTrip(t *testing.T) {
+	// Entries each format can't hold, by msgid
+	losses := map[string]struct{ removed, changed []string }{
+		"po":      {},
+		"pot":     {changed: []string{"%d file", "Hello", "Open", "Save"}},
+		"mo":      {removed: []string{"Save", "Untranslated"}},
+		"json":    {removed: []string{"Untranslated"}, changed: []string{"%d file", "Save"}},
+		"csv":     {},
+		"xliff":   {},
+		"xlf":     {},
+		"arb":     {},
+		"strings": {removed: []string{"%d file", "Open", "Untranslated"}, changed: []string{"Save"}},
+	}
+
+	src, err := gotext.ParsePoFile([]byte(convertCatalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := convertOptions{sourceLanguage: "en", contextSeparator: "_"}
+
+	for _, name := range sortedFormats() {
+		format := catalogFormats[name]
+		data, err := format.write(src, opts)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if format.read == nil {
+			checkGettextJS(t, data)
+			continue
+		}
+
+		f, err := format.read(data, opts)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		d, err := gotext.DiffCatalogs(translator(src), translator(f), gotext.DiffOptions{IgnoreHeaders: gotext.VolatileHeaders})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		loss, ok := losses[name]
+		if !ok {
+			t.Errorf("%s: no expected round trip", name)
+			continue
+		}
+		if len(d.Headers) > 0 {
+			t.Errorf("%s: expected the same headers but got %v", name, d.Headers)
+		}
+		if got := diffIDs(d.Added); len(got) > 0 {
+			t.Errorf("%s: expected no added entries but got %v", name, got)
+		}
+		if got := diffIDs(d.Removed); !reflect.DeepEqual(got, loss.removed) {
+			t.Errorf("%s: expected removed entries %v but got %v", name, loss.removed, got)
+		}
+		if got := diffIDs(d.Changed); !reflect.DeepEqual(got, loss.changed) {
+			t.Errorf("%s: expected changed entries %v but got %v", name, loss.changed, got)
+		}
+	}
+}
+
+// sortedFormats returns the names of the catalog formats, sorted.
+func sortedFormats() []string {
+	names := make([]string, 0, len(catalogFormats))
+	for name := range catalogFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// diffIDs returns the sorted msgids of the changes, nil for none.
+func diffIDs(changes []gotext.EntryChange) []string {
+	var ids []string
+	for _, c := range changes {
+		ids = append(ids, c.Msgid)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// checkGettextJS checks the output of the write only gettext.js format.
+func checkGettextJS(t *testing.T, data []byte) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("gettextjs: %v", err)
+	}
+
+	header, _ := m[""].(map[string]interface{})
+	if header["language"] != "de" || header["plural-forms"] != "nplurals=2; plural=(n != 1);" {
+		t.Errorf("gettextjs: unexpected header %v", header)
+	}
+	for _, test := range []struct {
+		key      string
+		expected interface{}
+	}{
+		{"Hello", "Hallo"},
+		{"menu\u0004Open", "Öffnen"},
+		{"%d file", []interface{}{"%d Datei", "%d Dateien"}},
+	} {
+		if !reflect.DeepEqual(m[test.key], test.expected) {
+			t.Errorf("gettextjs: expected '%v' but got '%v'", test.expected, m[test.key])
+		}
+	}
+}
+
+func TestReadI18next(t *testing.T) {
+	data := []byte(`{
+  "": "Language: ru\nPlural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n",
+  "menu": {
+    "open": "Открыть"
+  },
+  "Save_toolbar": "Сохранить",
+  "Underscore_key": "Ключ",
+  "%d file_0": "%d файл",
+  "%d file_1": "%d файла",
+  "%d file_2": "%d файлов",
+  "%d item": "%d item",
+  "%d item_plural": "%d items"
+}`)
+
+	f, err := readI18next(data, convertOptions{contextSeparator: "_"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lang := headerLanguage(f); lang != "ru" {
+		t.Errorf("Expected 'ru' but got '%s'", lang)
+	}
+
+	for _, test := range []struct {
+		ctx, id, pluralID string
+		str               []string
+	}{
+		{"", "menu.open", "", []string{"Открыть"}},
+		{"toolbar", "Save", "", []string{"Сохранить"}},
+		{"key", "Underscore", "", []string{"Ключ"}},
+		{"", "%d file", "%d файлов", []string{"%d файл", "%d файла", "%d файлов"}},
+		{"", "%d item", "%d items", []string{"%d item", "%d items"}},
+	} {
+		e := f.Find(test.ctx, test.id)
+		if e == nil {
+			t.Errorf("Expected an entry for '%s' in context '%s'", test.id, test.ctx)
+			continue
+		}
+		if e.PluralID != test.pluralID || !reflect.DeepEqual(e.Str, test.str) {
+			t.Errorf("Expected '%s' %v but got '%s' %v", test.pluralID, test.str, e.PluralID, e.Str)
+		}
+	}
+
+	// Without a context separator underscores are part of the msgids
+	f, err = readI18next(data, convertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Find("", "Underscore_key") == nil {
+		t.Error("Expected an entry for 'Underscore_key'")
+	}
+
+	// Arrays aren't catalogs
+	if _, err = readI18next([]byte(`{"list": ["a", "b"]}`), convertOptions{}); err == nil {
+		t.Error("Expected an error for an array value")
+	}
+}
+
+func TestWriteAppleStringsSourceLanguage(t *testing.T) {
+	src, err := gotext.ParsePoFile([]byte(convertCatalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.Header().SetHeaderValue("Language", "en")
+
+	data, err := writeAppleStrings(src, convertOptions{sourceLanguage: "en"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := readAppleStrings(data, convertOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Untranslated entries of the source language are translated by their msgid, as genstrings does
+	e := f.Find("", "Untranslated")
+	if e == nil || e.Str[0] != "Untranslated" {
+		t.Errorf("Expected 'Untranslated' but got %v", e)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	dir := "/tmp/gotext_convert"
+	writeFixture(t, dir, map[string]string{
+		"de.po":   msgfmtCatalog,
+		"de.json": `{"Hello": "Hallo", "Open_menu": "Öffnen"}`,
+	})
+	defer os.RemoveAll(dir)
+
+	// Formats are detected from the extensions, or given by flags
+	if r := runCommand(t, dir, "convert", "de.po", "de.csv"); r.code != 0 {
+		t.Fatalf("Expected success but got '%s%s'", r.stdout, r.stderr)
+	}
+	r := runCommand(t, dir, "convert", "-from", "csv", "-to", "po", "de.csv", "-")
+	for _, expected := range []string{"\"Language: de\\n\"", "#, fuzzy\nmsgid \"Bye\"\nmsgstr \"Tschüss\"\n", "msgid \"Hello\"\nmsgstr \"Hallo\"\n"} {
+		if r.code != 0 || !strings.Contains(r.stdout, expected) {
+			t.Errorf("Expected '%s' in '%s%s'", expected, r.stdout, r.stderr)
+		}
+	}
+	r = runCommand(t, dir, "convert", "de.csv", "de.txt")
+	if r.code != 1 || !strings.Contains(r.stderr, "Unknown output format \"txt\", use -to") {
+		t.Errorf("Expected an unknown format error but got %d '%s'", r.code, r.stderr)
+	}
+	r = runCommand(t, dir, "convert", "-from", "gettextjs", "de.csv", "de.po")
+	if r.code != 1 || !strings.Contains(r.stderr, "Unknown input format \"gettextjs\", use -from") {
+		t.Errorf("Expected an unknown format error but got %d '%s'", r.code, r.stderr)
+	}
+
+	// Headers missing from the input format are set by flags, or reported
+	r = runCommand(t, dir, "convert", "de.json", "-")
+	if r.code != 1 || !strings.Contains(r.stderr, "Unknown output format") {
+		t.Errorf("Expected an unknown format error but got %d '%s'", r.code, r.stderr)
+	}
+	r = runCommand(t, dir, "convert", "-to", "po", "de.json", "-")
+	if r.code != 0 || !strings.Contains(r.stderr, "de.json: no Language header, use -lang to set it") ||
+		!strings.Contains(r.stderr, "de.json: no Plural-Forms header, use -plural-forms to set it") {
+		t.Errorf("Expected missing headers warnings but got '%s'", r.stderr)
+	}
+	r = runCommand(t, dir, "convert", "-to", "po", "-lang", "de_AT", "-plural-forms", "nplurals=2; plural=(n != 1);", "de.json", "-")
+	for _, expected := range []string{"\"Language: de_AT\\n\"", "\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"", "msgctxt \"menu\"\nmsgid \"Open\"\nmsgstr \"Öffnen\"\n"} {
+		if r.code != 0 || r.stderr != "" || !strings.Contains(r.stdout, expected) {
+			t.Errorf("Expected '%s' in '%s%s'", expected, r.stdout, r.stderr)
+		}
+	}
+
+	// Contexts are kept in the i18next keys without separator
+	r = runCommand(t, dir, "convert", "-to", "po", "-context-separator", "", "de.json", "-")
+	if expected := "msgid \"Open_menu\"\nmsgstr \"Öffnen\"\n"; r.code != 0 || !strings.Contains(r.stdout, expected) {
+		t.Errorf("Expected '%s' in '%s%s'", expected, r.stdout, r.stderr)
+	}
+
+	// The source language is recorded by XLIFF files
+	r = runCommand(t, dir, "convert", "-source-lang", "fr", "de.po", "de.xliff")
+	if r.code != 0 {
+		t.Fatalf("Expected success but got '%s%s'", r.stdout, r.stderr)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "de.xliff"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `source-language="fr" target-language="de"`) {
+		t.Errorf("Expected the fr source language in '%s'", data)
+	}
+}
@@ -0,0 +1,207 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// crowdinConfig configures the synchronization with Crowdin.
+type crowdinConfig struct {
+	ProjectID int    `json:"project_id"`
+	Branch    string `json:"branch"`
+
+	// Token is the personal access token, read from the CROWDIN_PERSONAL_TOKEN environment variable when not set.
+	Token string `json:"token"`
+
+	// Organization is the organization of Crowdin Enterprise projects.
+	Organization string `json:"organization"`
+
+	// BaseURL overrides the API URL, derived from the organization.
+	BaseURL string `json:"base_url"`
+}
+
+// crowdin synchronizes catalogs with Crowdin, through its v2 API.
+type crowdin struct {
+	conf     crowdinConfig
+	token    string
+	base     string
+	branchID int
+}
+
+func newCrowdin(sc *syncConfig) (syncProvider, error) {
+	if sc.Crowdin == nil || sc.Crowdin.ProjectID == 0 {
+		return nil, fmt.Errorf("no crowdin project_id configured")
+	}
+
+	c := &crowdin{conf: *sc.Crowdin, base: sc.Crowdin.BaseURL}
+	var err error
+	if c.token, err = providerToken(c.conf.Token, "CROWDIN_PERSONAL_TOKEN"); err != nil {
+		return nil, err
+	}
+	if c.base == "" {
+		c.base = "https://api.crowdin.com/api/v2"
+		if c.conf.Organization != "" {
+			c.base = "https://" + c.conf.Organization + ".api.crowdin.com/api/v2"
+		}
+	}
+	c.base = strings.TrimSuffix(c.base, "/")
+
+	return c, nil
+}
+
+// crowdinItem is an item of the lists and single item responses of the API.
+type crowdinItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// do sends a JSON request to the API.
+func (c *crowdin) do(method, path string, body, out interface{}) error {
+	req, err := newJSONRequest(method, c.base+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	return apiRequest(req, out)
+}
+
+// list returns all of the items of a list endpoint, querying its pages.
+func (c *crowdin) list(path string, query url.Values) ([]crowdinItem, error) {
+	const limit = 500
+
+	var items []crowdinItem
+	for offset := 0; ; offset += limit {
+		query.Set("limit", fmt.Sprint(limit))
+		query.Set("offset", fmt.Sprint(offset))
+
+		var resp struct {
+			Data []struct {
+				Data crowdinItem `json:"data"`
+			} `json:"data"`
+		}
+		if err := c.do(http.MethodGet, path+"?"+query.Encode(), nil, &resp); err != nil {
+			return nil, err
+		}
+		for _, d := range resp.Data {
+			items = append(items, d.Data)
+		}
+		if len(resp.Data) < limit {
+			return items, nil
+		}
+	}
+}
+
+// branch returns the id of the configured branch, creating it when missing. It's 0 without branch.
+func (c *crowdin) branch(create bool) (int, error) {
+	if c.conf.Branch == "" || c.branchID != 0 {
+		return c.branchID, nil
+	}
+
+	branches, err := c.list(fmt.Sprintf("/projects/%d/branches", c.conf.ProjectID), url.Values{"name": {c.conf.Branch}})
+	if err != nil {
+		return 0, err
+	}
+	for _, b := range branches {
+		if b.Name == c.conf.Branch {
+			c.branchID = b.ID
+			return c.branchID, nil
+		}
+	}
+	if !create {
+		return 0, fmt.Errorf("branch %q not found", c.conf.Branch)
+	}
+
+	var resp struct {
+		Data crowdinItem `json:"data"`
+	}
+	err = c.do(http.MethodPost, fmt.Sprintf("/projects/%d/branches", c.conf.ProjectID), map[string]string{"name": c.conf.Branch}, &resp)
+	c.branchID = resp.Data.ID
+
+	return c.branchID, err
+}
+
+// file returns the id of the project file with the given name, 0 if there's none.
+func (c *crowdin) file(branchID int, name string) (int, error) {
+	query := url.Values{}
+	if branchID != 0 {
+		query.Set("branchId", fmt.Sprint(branchID))
+	}
+	files, err := c.list(fmt.Sprintf("/projects/%d/files", c.conf.ProjectID), query)
+	if err != nil {
+		return 0, err
+	}
+	for _, f := range files {
+		if f.Name == name {
+			return f.ID, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// Push uploads the template to the storage, then adds or updates the project file with it.
+func (c *crowdin) Push(name string, pot []byte) error {
+	branchID, err := c.branch(true)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.base+"/storages", bytes.NewReader(pot))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Crowdin-API-FileName", name)
+	var storage struct {
+		Data crowdinItem `json:"data"`
+	}
+	if err = apiRequest(req, &storage); err != nil {
+		return err
+	}
+
+	fileID, err := c.file(branchID, name)
+	if err != nil {
+		return err
+	}
+	if fileID != 0 {
+		return c.do(http.MethodPut, fmt.Sprintf("/projects/%d/files/%d", c.conf.ProjectID, fileID), map[string]int{"storageId": storage.Data.ID}, nil)
+	}
+
+	body := map[string]interface{}{"storageId": storage.Data.ID, "name": name}
+	if branchID != 0 {
+		body["branchId"] = branchID
+	}
+	return c.do(http.MethodPost, fmt.Sprintf("/projects/%d/files", c.conf.ProjectID), body, nil)
+}
+
+// Pull builds the translations of the project file, then downloads them.
+func (c *crowdin) Pull(name, lang string) ([]byte, error) {
+	branchID, err := c.branch(false)
+	if err != nil {
+		return nil, err
+	}
+	fileID, err := c.file(branchID, name)
+	if err != nil {
+		return nil, err
+	}
+	if fileID == 0 {
+		return nil, fmt.Errorf("file %q not found, push it first", name)
+	}
+
+	var build struct {
+		Data crowdinItem `json:"data"`
+	}
+	err = c.do(http.MethodPost, fmt.Sprintf("/projects/%d/translations/builds/files/%d", c.conf.ProjectID, fileID),
+		map[string]string{"targetLanguageId": lang}, &build)
+	if err != nil {
+		return nil, err
+	}
+
+	return download(build.Data.URL)
+}
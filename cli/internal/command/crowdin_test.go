@@ -0,0 +1,158 @@
+package command
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// newTestCrowdin returns a crowdin provider of the project 1 using the fake API.
+func newTestCrowdin(t *testing.T, api *fakeAPI, branch string) *crowdin {
+	p, err := newCrowdin(&syncConfig{Crowdin: &crowdinConfig{ProjectID: 1, Branch: branch, Token: "secret", BaseURL: api.URL + "/"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p.(*crowdin)
+}
+
+// crowdinList returns the response of a list endpoint holding the given items.
+func crowdinList(items ...crowdinItem) map[string]interface{} {
+	data := make([]map[string]crowdinItem, len(items))
+	for i, item := range items {
+		data[i] = map[string]crowdinItem{"data": item}
+	}
+
+	return map[string]interface{}{"data": data}
+}
+
+func TestCrowdinPushNewFile(t *testing.T) {
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"GET /projects/1/branches":  reply(crowdinList()),
+		"POST /projects/1/branches": reply(map[string]crowdinItem{"data": {ID: 3, Name: "release"}}),
+		"POST /storages":            reply(map[string]crowdinItem{"data": {ID: 7}}),
+		"GET /projects/1/files":     reply(crowdinList(crowdinItem{ID: 8, Name: "other.pot"})),
+		"POST /projects/1/files":    reply(map[string]crowdinItem{"data": {ID: 9}}),
+	})
+	defer api.Close()
+
+	if err := newTestCrowdin(t, api, "release").Push("default.pot", []byte(syncTemplate)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"GET /projects/1/branches", "POST /projects/1/branches", "POST /storages", "GET /projects/1/files", "POST /projects/1/files"}
+	if routes := api.routes(); !reflect.DeepEqual(routes, expected) {
+		t.Errorf("Expected requests %v but got %v", expected, routes)
+	}
+
+	storage := api.call(t, "POST /storages")
+	if string(storage.Body) != syncTemplate {
+		t.Errorf("Expected the template but got '%s'", storage.Body)
+	}
+	if name := storage.Header.Get("Crowdin-API-FileName"); name != "default.pot" {
+		t.Errorf("Expected 'default.pot' but got '%s'", name)
+	}
+	if auth := storage.Header.Get("Authorization"); auth != "Bearer secret" {
+		t.Errorf("Expected 'Bearer secret' but got '%s'", auth)
+	}
+	if branchID := api.call(t, "GET /projects/1/files").Query.Get("branchId"); branchID != "3" {
+		t.Errorf("Expected files of the branch '3' but got '%s'", branchID)
+	}
+
+	body := decodeBody(t, api.call(t, "POST /projects/1/files"))
+	expectedBody := map[string]interface{}{"storageId": 7.0, "name": "default.pot", "branchId": 3.0}
+	if !reflect.DeepEqual(body, expectedBody) {
+		t.Errorf("Expected %v but got %v", expectedBody, body)
+	}
+}
+
+func TestCrowdinPushExistingFile(t *testing.T) {
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /storages":          reply(map[string]crowdinItem{"data": {ID: 7}}),
+		"GET /projects/1/files":   reply(crowdinList(crowdinItem{ID: 9, Name: "default.pot"})),
+		"PUT /projects/1/files/9": reply(map[string]crowdinItem{"data": {ID: 9}}),
+	})
+	defer api.Close()
+
+	if err := newTestCrowdin(t, api, "").Push("default.pot", []byte(syncTemplate)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"POST /storages", "GET /projects/1/files", "PUT /projects/1/files/9"}
+	if routes := api.routes(); !reflect.DeepEqual(routes, expected) {
+		t.Errorf("Expected requests %v but got %v", expected, routes)
+	}
+	body := decodeBody(t, api.call(t, "PUT /projects/1/files/9"))
+	if !reflect.DeepEqual(body, map[string]interface{}{"storageId": 7.0}) {
+		t.Errorf("Expected the storage 7 but got %v", body)
+	}
+}
+
+func TestCrowdinPull(t *testing.T) {
+	var api *fakeAPI
+	api = newFakeAPI(t, map[string]http.HandlerFunc{
+		"GET /projects/1/branches": reply(crowdinList(crowdinItem{ID: 3, Name: "release"})),
+		"GET /projects/1/files":    reply(crowdinList(crowdinItem{ID: 9, Name: "default.pot"})),
+		"POST /projects/1/translations/builds/files/9": func(w http.ResponseWriter, r *http.Request) {
+			reply(map[string]crowdinItem{"data": {URL: api.URL + "/download/de.po"}})(w, r)
+		},
+		"GET /download/de.po": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, syncTranslation)
+		},
+	})
+	defer api.Close()
+
+	p := newTestCrowdin(t, api, "release")
+	data, err := p.Pull("default.pot", "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != syncTranslation {
+		t.Errorf("Expected the translation but got '%s'", data)
+	}
+	body := decodeBody(t, api.call(t, "POST /projects/1/translations/builds/files/9"))
+	if body["targetLanguageId"] != "de" {
+		t.Errorf("Expected 'de' but got '%v'", body["targetLanguageId"])
+	}
+
+	// Files missing from the project must be pushed first
+	if _, err = p.Pull("missing.pot", "de"); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestCrowdinList(t *testing.T) {
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"GET /projects/1/files": func(w http.ResponseWriter, r *http.Request) {
+			n := 500
+			if r.URL.Query().Get("offset") != "0" {
+				n = 2
+			}
+			var items []crowdinItem
+			for i := 0; i < n; i++ {
+				items = append(items, crowdinItem{ID: len(items) + 1, Name: fmt.Sprintf("file%d.pot", i)})
+			}
+			reply(crowdinList(items...))(w, r)
+		},
+	})
+	defer api.Close()
+
+	items, err := newTestCrowdin(t, api, "").list("/projects/1/files", map[string][]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 502 {
+		t.Errorf("Expected 502 items but got %d", len(items))
+	}
+
+	var offsets []string
+	api.mu.Lock()
+	for _, c := range api.calls {
+		offsets = append(offsets, c.Query.Get("offset"))
+	}
+	api.mu.Unlock()
+	if !reflect.DeepEqual(offsets, []string{"0", "500"}) {
+		t.Errorf("Expected the offsets 0 and 500 but got %v", offsets)
+	}
+}
@@ -0,0 +1,45 @@
+package command
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// diff runs the diff command, printing the translations added, removed and changed between two versions of a catalog.
+func diff(args []string) {
+	fs := newFlagSet("diff", "[flags] old.po new.po")
+	exitCode := fs.Bool("exit-code", false, "exit with status 1 when the catalogs differ")
+	stat := fs.Bool("stat", false, "print the number of added, removed and changed entries only")
+	parseFlags(fs, args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	oldTr, err := loadCatalog(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	newTr, err := loadCatalog(fs.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	d, err := gotext.DiffCatalogs(oldTr, newTr, gotext.DiffOptions{IgnoreHeaders: gotext.VolatileHeaders})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *stat {
+		fmt.Printf("%d added, %d removed, %d changed, %d headers changed\n", len(d.Added), len(d.Removed), len(d.Changed), len(d.Headers))
+	} else {
+		fmt.Print(d)
+	}
+	if *exitCode && !d.Empty() {
+		os.Exit(1)
+	}
+}
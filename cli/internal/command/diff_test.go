@@ -0,0 +1,86 @@
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+const diffOld = `msgid ""
+msgstr ""
+"Language: de\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"PO-Revision-Date: 2024-01-01 10:00+0000\n"
+
+msgid "Hello"
+msgstr "Hallo"
+
+msgid "Bye"
+msgstr "Tschüss"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Öffnen"
+`
+
+const diffNew = `msgid ""
+msgstr ""
+"Language: de\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"PO-Revision-Date: 2024-02-01 10:00+0000\n"
+
+msgid "Hello"
+msgstr "Guten Tag"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Öffnen"
+
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "Eine Datei"
+msgstr[1] "%d Dateien"
+`
+
+func TestDiff(t *testing.T) {
+	dir := "/tmp/gotext_diff"
+	writeFixture(t, dir, map[string]string{
+		"old.po": diffOld,
+		"new.po": diffNew,
+	})
+	defer os.RemoveAll(dir)
+
+	r := runCommand(t, dir, "diff", "old.po", "new.po")
+	expected := "- \"Bye\"\n+ \"One file\"\n~ \"Hello\": msgstr[0] \"Hallo\" => msgstr[0] \"Guten Tag\"\n"
+	if r.code != 0 || r.stdout != expected {
+		t.Errorf("Expected '%s' but got '%s%s'", expected, r.stdout, r.stderr)
+	}
+
+	r = runCommand(t, dir, "diff", "-stat", "-exit-code", "old.po", "new.po")
+	expected = "1 added, 1 removed, 1 changed, 0 headers changed\n"
+	if r.code != 1 || r.stdout != expected {
+		t.Errorf("Expected '%s' and exit code 1 but got '%s' and %d", expected, r.stdout, r.code)
+	}
+
+	// The volatile headers are ignored, the others compared
+	writeFile(t, dir, "revised.po", strings.Replace(diffOld, "2024-01-01", "2024-03-01", 1))
+	if r := runCommand(t, dir, "diff", "-exit-code", "old.po", "revised.po"); r.code != 0 || r.stdout != "" {
+		t.Errorf("Expected no differences but got '%s%s'", r.stdout, r.stderr)
+	}
+	writeFile(t, dir, "revised.po", strings.Replace(diffOld, "Language: de", "Language: de_AT", 1))
+	r = runCommand(t, dir, "diff", "-stat", "old.po", "revised.po")
+	if expected = "0 added, 0 removed, 0 changed, 1 headers changed\n"; r.stdout != expected {
+		t.Errorf("Expected '%s' but got '%s%s'", expected, r.stdout, r.stderr)
+	}
+	r = runCommand(t, dir, "diff", "old.po", "revised.po")
+	if expected = "~ header Language: \"de\" => \"de_AT\"\n"; r.stdout != expected {
+		t.Errorf("Expected '%s' but got '%s%s'", expected, r.stdout, r.stderr)
+	}
+
+	if r := runCommand(t, dir, "diff", "old.po"); r.code != 2 || !strings.Contains(r.stderr, "old.po new.po") {
+		t.Errorf("Expected the usage and exit code 2 but got '%s' and %d", r.stderr, r.code)
+	}
+	if r := runCommand(t, dir, "diff", "old.po", "missing.po"); r.code == 0 || !strings.Contains(r.stderr, "missing.po") {
+		t.Errorf("Expected an error for the missing catalog but got '%s'", r.stderr)
+	}
+}
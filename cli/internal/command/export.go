@@ -0,0 +1,52 @@
+package command
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// exportJS runs the export-js command, converting a .po or .mo file to the JSON formats of JavaScript libraries.
+func exportJS(args []string) {
+	fs := newFlagSet("export-js", "[flags]")
+	format := fs.String("format", "i18next", "output format: i18next or gettextjs")
+	in := fs.String("in", "", "input file: /path/to/default.po")
+	out := fs.String("out", "", "output file: /path/to/default.json (default standard output)")
+	parseFlags(fs, args)
+
+	if *in == "" {
+		log.Fatal("No input file given")
+	}
+
+	var tr gotext.Translator = gotext.NewPo()
+	if filepath.Ext(*in) == ".mo" {
+		tr = gotext.NewMo()
+	}
+	tr.ParseFile(*in)
+
+	var data []byte
+	var err error
+	switch *format {
+	case "i18next":
+		data, err = gotext.ExportI18next(tr)
+	case "gettextjs":
+		data, err = gotext.ExportGettextJS(tr)
+	default:
+		log.Fatalf("Unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	data = append(data, '\n')
+
+	if *out == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err = ioutil.WriteFile(*out, data, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,294 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/leonelquinteros/gotext/cli/xgotext/parser"
+)
+
+// extract runs the extract command, the default one of xgotext, writing the strings of Go packages to .pot files.
+func extract(args []string) {
+	fs := newFlagSet("extract", "[flags]")
+	dirName := fs.String("in", "", "input dir: /path/to/go/pkg")
+	outputDir := fs.String("out", "", "output dir: /path/to/i18n/files")
+	defaultDomain := fs.String("default", "default", "Name of default domain")
+	excludeDirs := fs.String("exclude", ".git", "Comma separated list of directories to exclude")
+	currentPackage := fs.Bool("current-package", false, "extract only the package in the current directory, as run by go:generate")
+	since := fs.String("since", "", "re-extract only the packages whose Go files changed since the git ref, updating the existing templates")
+	staged := fs.Bool("staged", false, "re-extract only the packages with staged Go files, updating and staging the existing templates, as a pre-commit hook")
+	refRoot := fs.String("ref-root", "", "directory the source references are relative to instead of the input dir, \"module\" for the root of its Go module")
+	refPrefix := fs.String("ref-prefix", "", "prefix of the source references")
+	manifests := fs.String("manifest", "", "comma separated list of JSON manifests of the calls to extract in addition to the gotext getters")
+	header := make(headerFlag)
+	fs.Var(header, "header", "`field` of the header of the templates, as \"Name: value\", i.e. \"Project-Id-Version: shop 1.2\", can be given several times")
+	banner := fs.Bool("banner", false, "write the standard gettext prologue, with its placeholders unless configured, before the header of the templates")
+	modules := fs.String("modules", "", "extract each Go module found under the input dir with its own configuration, writing \"split\" templates per module or \"merged\" ones")
+	verbose := fs.Bool("v", false, "print currently handled directory")
+	parseFlags(fs, args)
+
+	// The flags given take precedence over the "extract" section of the configuration, which takes precedence over the
+	// defaults of the other flags.
+	var flags extractConfig
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "out":
+			flags.Out = *outputDir
+		case "default":
+			flags.Default = *defaultDomain
+		case "exclude":
+			flags.Exclude = strings.Split(*excludeDirs, ",")
+		case "ref-root":
+			flags.RefRoot = *refRoot
+		case "ref-prefix":
+			flags.RefPrefix = *refPrefix
+		case "header":
+			flags.Header = header
+		case "banner":
+			if *banner {
+				flags.Banner = new(bannerConfig)
+			}
+		case "manifest":
+			flags.Manifests = strings.Split(*manifests, ",")
+		}
+	})
+	base := extractConfig{Default: *defaultDomain, Exclude: strings.Split(*excludeDirs, ",")}.override(loadProjectConfig().Extract)
+	conf := base.override(flags)
+	*outputDir, *defaultDomain, *excludeDirs = conf.Out, conf.Default, strings.Join(conf.Exclude, ",")
+
+	if *modules != "" {
+		if *modules != modulesSplit && *modules != modulesMerged {
+			log.Fatalf("Unknown -modules mode %q, \"split\" or \"merged\" expected", *modules)
+		}
+		if *currentPackage || *since != "" || *staged {
+			log.Fatal("-modules can't be used with -current-package, -since or -staged")
+		}
+		if *dirName == "" {
+			log.Fatal("No input directory given")
+		}
+		policyIssues, err := extractModules(*dirName, *modules, base, flags, *verbose)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if policyIssues > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *currentPackage && (*since != "" || *staged) {
+		log.Fatal("-since and -staged can't be used with -current-package")
+	}
+	if *since != "" && *staged {
+		log.Fatal("-since can't be used with -staged")
+	}
+	if *currentPackage {
+		if *dirName != "" {
+			log.Fatal("No input directory can be given with -current-package")
+		}
+		*dirName = "."
+		// go:generate runs in the package directory, so its domain file is kept next to it by default.
+		if *outputDir == "" {
+			*outputDir = "."
+		}
+	}
+
+	if *dirName == "" {
+		log.Fatal("No input directory given")
+	}
+	if *outputDir == "" {
+		log.Fatal("No output directory given")
+	}
+
+	data := &parser.DomainMap{
+		Default:    *defaultDomain,
+		Header:     conf.headerFields(),
+		Banner:     conf.Banner.lines(),
+		OutputDirs: conf.DomainOut,
+	}
+
+	prefix, err := referencePrefix(*dirName, conf.RefRoot, conf.RefPrefix)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = setCallPatterns(conf); err != nil {
+		log.Fatal(err)
+	}
+
+	if *currentPackage {
+		if *verbose {
+			log.Printf("%s (package %s)", os.Getenv("GOFILE"), os.Getenv("GOPACKAGE"))
+		}
+		err = parser.ParseDir(*dirName, *dirName, data)
+	} else if *since != "" || *staged {
+		var files []string
+		if *staged {
+			files, err = stagedSources(*dirName)
+		} else {
+			files, err = changedSources(*dirName, *since)
+		}
+		if err == nil {
+			err = extractChanged(*dirName, *outputDir, prefix, files, strings.Split(*excludeDirs, ","), data, *verbose)
+		}
+	} else {
+		err = parser.ParseDirRec(*dirName, strings.Split(*excludeDirs, ","), data, *verbose)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	issues := conf.Policy.check(*dirName, data)
+	printPolicyIssues(issues)
+	prefixLocations(data, prefix)
+
+	err = data.Save(*outputDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *staged {
+		if err = stageTemplates(*outputDir, data); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if len(issues) > 0 {
+		os.Exit(1)
+	}
+}
+
+// extractChanged updates the templates of the output directory with the strings of the packages of the changed Go and
+// embedded template files, relative to dirName, parsing these packages only. The strings of packages that were removed are removed.
+// The source references of the templates are made relative to dirName again by removing their prefix.
+// Without templates to update, all of the packages are parsed.
+func extractChanged(dirName, outputDir, prefix string, files, exclude []string, data *parser.DomainMap, verbose bool) error {
+	if err := data.Load(outputDir); err != nil {
+		return err
+	}
+	data.MapLocations(func(location string) string {
+		return strings.TrimPrefix(location, prefix)
+	})
+	if len(data.Domains) == 0 {
+		log.Printf("No templates in %s, extracting all packages", outputDir)
+		return parser.ParseDirRec(dirName, exclude, data, verbose)
+	}
+
+	// Templates are parsed with the package embedding them.
+	packageDir := func(file string) string {
+		dir := filepath.Dir(filepath.FromSlash(file))
+		if parser.IsTemplate(file) {
+			return parser.EmbeddingDir(dirName, dir)
+		}
+		return dir
+	}
+
+	changed := make(map[string]bool)
+	var dirs []string
+	for _, file := range files {
+		dir := packageDir(file)
+		if !changed[dir] && !parser.IsExcluded(dir, exclude) {
+			changed[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+
+	data.RemoveLocations(func(file string) bool {
+		return changed[packageDir(file)]
+	})
+	if err := parser.ParseDirs(dirs, dirName, data, verbose); err != nil {
+		return err
+	}
+	data.SortLocations()
+
+	return nil
+}
+
+// sourcePathspecs are the git pathspecs of the files extracted: Go files and the templates they may embed.
+func sourcePathspecs() []string {
+	pathspecs := []string{"--", "*.go"}
+	for _, ext := range parser.TemplateExtensions {
+		pathspecs = append(pathspecs, "*"+ext)
+	}
+	return pathspecs
+}
+
+// changedSources returns the source files of the directory changed since the git ref, including uncommitted and
+// untracked ones, relative to the directory.
+func changedSources(dir, ref string) ([]string, error) {
+	changed, err := gitFiles(dir, append([]string{"diff", "--name-only", "-z", "--relative", ref}, sourcePathspecs()...)...)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := gitFiles(dir, append([]string{"ls-files", "-z", "--others", "--exclude-standard"}, sourcePathspecs()...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(changed, untracked...), nil
+}
+
+// stagedSources returns the staged source files of the directory, relative to it.
+// Packages whose staged files have unstaged changes too are parsed with them, which is reported.
+func stagedSources(dir string) ([]string, error) {
+	staged, err := gitFiles(dir, append([]string{"diff", "--cached", "--name-only", "-z", "--relative"}, sourcePathspecs()...)...)
+	if err != nil {
+		return nil, err
+	}
+	unstaged, err := gitFiles(dir, append([]string{"diff", "--name-only", "-z", "--relative"}, sourcePathspecs()...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	modified := make(map[string]bool)
+	for _, file := range unstaged {
+		modified[file] = true
+	}
+	for _, file := range staged {
+		if modified[file] {
+			log.Printf("Warning: %s has unstaged changes, extracted too", file)
+		}
+	}
+
+	return staged, nil
+}
+
+// stageTemplates stages the templates of the domains, so they're committed with the sources they were extracted from.
+func stageTemplates(outputDir string, data *parser.DomainMap) error {
+	args := []string{"add", "--"}
+	for name := range data.Domains {
+		args = append(args, data.DomainPath(outputDir, name))
+	}
+	if len(args) == 2 {
+		return nil
+	}
+
+	_, err := gitFiles("", args...)
+	return err
+}
+
+// gitFiles runs git in the directory, returning the NUL separated file names it prints.
+func gitFiles(dir string, args ...string) ([]string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if e, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(e.Stderr)))
+		}
+		return nil, err
+	}
+
+	var files []string
+	for _, file := range strings.Split(string(out), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
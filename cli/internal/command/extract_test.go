@@ -0,0 +1,314 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// appFixture is a module calling the gotext getters from two packages.
+var appFixture = map[string]string{
+	"go.mod": goMod("example.com/app"),
+	"main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/app/pkg"
+	"github.com/leonelquinteros/gotext"
+)
+
+func main() {
+	fmt.Println(gotext.Get("Hello"))
+	fmt.Println(pkg.Greet())
+}
+`,
+	"pkg/pkg.go": `package pkg
+
+import "github.com/leonelquinteros/gotext"
+
+func Greet() string {
+	return gotext.Get("Hello") + gotext.Get("Bye")
+}
+`,
+}
+
+// git runs git in the directory, failing the test on errors.
+func git(t *testing.T, dir string, args ...string) {
+	args = append([]string{"-c", "user.name=gotext", "-c", "user.email=gotext@example.com"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v: %s", args[4], err, out)
+	}
+}
+
+// writeGitFixture writes the fixture to dir as a git repository, committing its files.
+func writeGitFixture(t *testing.T, dir string, files map[string]string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	writeFixture(t, dir, files)
+	git(t, dir, "init", "-q")
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "fixture")
+}
+
+// writeFile writes a file of the fixture in dir.
+func writeFile(t *testing.T, dir, name, content string) {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExtractSince(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_extract_since"
+	writeGitFixture(t, dir, appFixture)
+	defer os.RemoveAll(dir)
+
+	if r := runCommand(t, dir, "extract", "-in", ".", "-out", "i18n"); r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "templates")
+
+	// The package main isn't parsed again, so its reference edited by hand is kept
+	template := filepath.Join(dir, "i18n", "default.pot")
+	data, err := ioutil.ReadFile(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "i18n/default.pot", strings.Replace(string(data), "#: main.go:11", "#: main.go:99", 1))
+	writeFile(t, dir, "pkg/pkg.go", "package pkg\n\nimport \"github.com/leonelquinteros/gotext\"\n\n"+
+		"func Greet() string {\n\treturn gotext.Get(\"Welcome\")\n}\n")
+	writeFile(t, dir, "pkg/sub/sub.go", "package sub\n\nimport \"github.com/leonelquinteros/gotext\"\n\n"+
+		"var Title = gotext.Get(\"Hello\")\n")
+
+	if r := runCommand(t, dir, "extract", "-in", ".", "-out", "i18n", "-since", "HEAD"); r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+
+	refs := readReferences(t, template)
+	expected := map[string]string{
+		"Hello":   "main.go:99 pkg/sub/sub.go:5",
+		"Welcome": "pkg/pkg.go:6",
+	}
+	for id, ref := range expected {
+		if refs[id] != ref {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", ref, id, refs[id])
+		}
+	}
+	if len(refs) != len(expected) {
+		t.Errorf("Expected the strings of the changed packages only to be updated but got %v", refs)
+	}
+
+	if r := runCommand(t, dir, "extract", "-in", ".", "-out", "i18n", "-since", "unknown"); r.code == 0 {
+		t.Error("Expected an error for an unknown git ref")
+	}
+}
+
+func TestExtractSinceWithoutTemplates(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_extract_since_new"
+	writeGitFixture(t, dir, appFixture)
+	defer os.RemoveAll(dir)
+
+	// All of the packages are extracted when there's no template to update
+	r := runCommand(t, dir, "extract", "-in", ".", "-out", "i18n", "-since", "HEAD")
+	if r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	if !strings.Contains(r.stderr, "extracting all packages") {
+		t.Errorf("Expected the full extraction to be reported but got '%s'", r.stderr)
+	}
+	if refs := readReferences(t, filepath.Join(dir, "i18n", "default.pot")); refs["Hello"] != "main.go:11 pkg/pkg.go:6" {
+		t.Errorf("Expected 'main.go:11 pkg/pkg.go:6' but got %v", refs)
+	}
+}
+
+func TestChangedSources(t *testing.T) {
+	dir := "/tmp/gotext_changed_sources"
+	writeGitFixture(t, dir, map[string]string{
+		"main.go":            "package main\n",
+		"pkg/pkg.go":         "package pkg\n",
+		"views/index.tmpl":   "{{ .Loc.Get \"Hello\" }}\n",
+		"views/README.md":    "views\n",
+		".gitignore":         "ignored/\n",
+		"ignored/ignored.go": "package ignored\n",
+	})
+	defer os.RemoveAll(dir)
+
+	writeFile(t, dir, "pkg/pkg.go", "package pkg\n\nvar V int\n")
+	writeFile(t, dir, "views/index.tmpl", "{{ .Loc.Get \"Bye\" }}\n")
+	writeFile(t, dir, "views/README.md", "changed\n")
+	writeFile(t, dir, "new/new.go", "package new\n")
+	writeFile(t, dir, "ignored/other.go", "package ignored\n")
+
+	files, err := changedSources(dir, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "pkg/pkg.go views/index.tmpl new/new.go"
+	if got := strings.Join(files, " "); got != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, got)
+	}
+}
+
+func TestExtractStaged(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_extract_staged"
+	writeGitFixture(t, dir, appFixture)
+	defer os.RemoveAll(dir)
+
+	if r := runCommand(t, dir, "extract", "-in", ".", "-out", "i18n"); r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "templates")
+
+	// The package main has unstaged changes only, so it isn't parsed again
+	writeFile(t, dir, "main.go", strings.Replace(appFixture["main.go"], "Hello", "Hi", 1))
+	writeFile(t, dir, "pkg/pkg.go", "package pkg\n\nimport \"github.com/leonelquinteros/gotext\"\n\n"+
+		"func Greet() string {\n\treturn gotext.Get(\"Welcome\")\n}\n")
+	git(t, dir, "add", "pkg/pkg.go")
+	// Staged files with unstaged changes are extracted as they are in the working tree
+	writeFile(t, dir, "pkg/pkg.go", "package pkg\n\nimport \"github.com/leonelquinteros/gotext\"\n\n"+
+		"func Greet() string {\n\treturn gotext.Get(\"Welcome\") + gotext.Get(\"Later\")\n}\n")
+
+	r := runCommand(t, dir, "extract", "-in", ".", "-out", "i18n", "-staged")
+	if r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	if !strings.Contains(r.stderr, "Warning: pkg/pkg.go has unstaged changes") {
+		t.Errorf("Expected a warning about the unstaged changes but got '%s'", r.stderr)
+	}
+
+	refs := readReferences(t, filepath.Join(dir, "i18n", "default.pot"))
+	expected := map[string]string{
+		"Hello":   "main.go:11",
+		"Welcome": "pkg/pkg.go:6",
+		"Later":   "pkg/pkg.go:6",
+	}
+	for id, ref := range expected {
+		if refs[id] != ref {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", ref, id, refs[id])
+		}
+	}
+	if len(refs) != len(expected) {
+		t.Errorf("Expected the strings of the staged packages only to be updated but got %v", refs)
+	}
+
+	// The updated templates are staged with the sources
+	staged, err := gitFiles(dir, "diff", "--cached", "--name-only", "-z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(staged, " "); got != "i18n/default.pot pkg/pkg.go" {
+		t.Errorf("Expected 'i18n/default.pot pkg/pkg.go' to be staged but got '%s'", got)
+	}
+}
+
+func TestExtractStagedFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"-in", ".", "-out", "i18n", "-staged", "-since", "HEAD"},
+		{"-out", "i18n", "-staged", "-current-package"},
+	} {
+		r := runCommand(t, ".", append([]string{"extract"}, args...)...)
+		if r.code == 0 || !strings.Contains(r.stderr, "can't be used with") {
+			t.Errorf("Expected %v to be refused but got '%s'", args, r.stderr)
+		}
+	}
+}
+
+func TestExtractSinceTemplates(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_extract_since_templates"
+	writeGitFixture(t, dir, map[string]string{
+		"go.mod": goMod("example.com/app"),
+		"web/web.go": `package web
+
+import (
+	"embed"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+//go:embed views
+var views embed.FS
+
+var Title = gotext.Get("Title")
+`,
+		"web/views/admin/list.tmpl": `<h1>{{ .Loc.Get "Users" }}</h1>`,
+	})
+	defer os.RemoveAll(dir)
+
+	if r := runCommand(t, dir, "extract", "-in", ".", "-out", "i18n"); r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "templates")
+
+	// Changed templates are extracted with the package embedding them
+	writeFile(t, dir, "web/views/admin/list.tmpl", "<h1>{{ .Loc.Get \"Accounts\" }}</h1>\n")
+	r := runCommand(t, dir, "extract", "-in", ".", "-out", "i18n", "-since", "HEAD")
+	if r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	checkReferences(t, filepath.Join(dir, "i18n", "default.pot"), map[string]string{
+		"Title":    "web/web.go:12",
+		"Accounts": "web/views/admin/list.tmpl:1",
+	})
+}
+
+func TestExtractCurrentPackage(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_extract_current"
+	files := map[string]string{
+		"pkg/sub/sub.go": "package sub\n\nimport \"github.com/leonelquinteros/gotext\"\n\nvar Title = gotext.Get(\"Title\")\n",
+	}
+	for name, content := range appFixture {
+		files[name] = content
+	}
+	writeFixture(t, dir, files)
+	defer os.RemoveAll(dir)
+
+	// As run by go:generate, the package only is extracted, next to it
+	pkg := filepath.Join(dir, "pkg")
+	if r := runCommand(t, pkg, "extract", "-current-package"); r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	checkReferences(t, filepath.Join(pkg, "default.pot"), map[string]string{
+		"Hello": "pkg.go:6",
+		"Bye":   "pkg.go:6",
+	})
+
+	// The output dir and the reference root can still be given
+	r := runCommand(t, pkg, "extract", "-current-package", "-out", "../i18n", "-ref-root", "module")
+	if r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	checkReferences(t, filepath.Join(dir, "i18n", "default.pot"), map[string]string{
+		"Hello": "pkg/pkg.go:6",
+		"Bye":   "pkg/pkg.go:6",
+	})
+}
+
+func TestExtractCurrentPackageInput(t *testing.T) {
+	r := runCommand(t, ".", "extract", "-current-package", "-in", ".")
+	if r.code == 0 || !strings.Contains(r.stderr, "No input directory can be given with -current-package") {
+		t.Errorf("Expected the input dir to be refused but got '%s'", r.stderr)
+	}
+}
@@ -0,0 +1,136 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// poFiles returns the .po and .pot files of the given paths, walking directories.
+func poFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		err := filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if path == p && !info.IsDir() {
+				files = append(files, path)
+				return nil
+			}
+			if info.IsDir() {
+				if path != p && strings.HasPrefix(info.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if ext := filepath.Ext(path); ext == ".po" || ext == ".pot" {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// sortOrder returns the order of the entries selected by the -sort flag.
+func sortOrder(name string) gotext.PoSort {
+	switch name {
+	case "":
+		return gotext.SortNone
+	case "id":
+		return gotext.SortByID
+	case "file":
+		return gotext.SortByFile
+	}
+	log.Fatalf("Unknown sort order %q", name)
+	return gotext.SortNone
+}
+
+// fmtCatalogs runs the fmt command, rewriting catalogs in canonical gettext style as gofmt does for Go source.
+// The sort command runs it too, sorting entries by default so they're in canonical order as well.
+func fmtCatalogs(name, defaultSort string, args []string) {
+	fs := newFlagSet(name, "[flags] [path ...]")
+	list := fs.Bool("l", false, "list files whose formatting differs from canonical style")
+	write := fs.Bool("w", false, "write result to (source) file instead of standard output")
+	width := fs.Int("width", gotext.DefaultPoWidth, "width of the lines")
+	noWrap := fs.Bool("nowrap", false, "do not wrap long strings, only split them after newlines")
+	sortUsage := "sort entries: \"id\" or \"file\""
+	if defaultSort == "" {
+		sortUsage += " (default keep their order)"
+	}
+	sortBy := fs.String("sort", defaultSort, sortUsage)
+	parseFlags(fs, args)
+
+	opts := gotext.FormatOptions{Width: *width, NoWrap: *noWrap, Sort: sortOrder(*sortBy)}
+
+	if fs.NArg() == 0 {
+		if *write {
+			log.Fatal("Cannot use -w with standard input")
+		}
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			log.Fatal(err)
+		}
+		out, err := gotext.FormatPo(data, opts)
+		if err != nil {
+			log.Fatalf("<standard input>: %v", err)
+		}
+		if *list {
+			if !bytes.Equal(data, out) {
+				fmt.Println("<standard input>")
+			}
+			return
+		}
+		os.Stdout.Write(out)
+		return
+	}
+
+	files, err := poFiles(fs.Args())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	failed := false
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			log.Print(err)
+			failed = true
+			continue
+		}
+		out, err := gotext.FormatPo(data, opts)
+		if err != nil {
+			log.Printf("%s: %v", file, err)
+			failed = true
+			continue
+		}
+
+		changed := !bytes.Equal(data, out)
+		if *list && changed {
+			fmt.Println(file)
+		}
+		if *write {
+			if changed {
+				if err = ioutil.WriteFile(file, out, 0644); err != nil {
+					log.Print(err)
+					failed = true
+				}
+			}
+		} else if !*list {
+			os.Stdout.Write(out)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,126 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sortCatalog = `msgid ""
+msgstr ""
+"Language: de\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#: web/views.go:20
+msgid "Zebra"
+msgstr "Zebra"
+
+#: main.go:12
+msgid "Bye"
+msgstr "Tschüss"
+
+#: web/menu.go:8
+msgctxt "menu"
+msgid "Apple"
+msgstr "Apfel"
+
+#: main.go:30
+msgid "Apple"
+msgstr "Apfel"
+`
+
+// msgids returns the msgids of the entries of a catalog, in order, prefixed with their context and "|" when they
+// have one.
+func msgids(po string) string {
+	var ids []string
+	ctx := ""
+	for _, line := range strings.Split(po, "\n") {
+		if strings.HasPrefix(line, "msgctxt ") {
+			ctx = strings.Trim(strings.TrimPrefix(line, "msgctxt "), `"`) + "|"
+		}
+		if strings.HasPrefix(line, "msgid ") {
+			if id := strings.Trim(strings.TrimPrefix(line, "msgid "), `"`); id != "" {
+				ids = append(ids, ctx+id)
+			}
+			ctx = ""
+		}
+	}
+	return strings.Join(ids, " ")
+}
+
+func TestSort(t *testing.T) {
+	dir := "/tmp/gotext_sort"
+	writeFixture(t, dir, map[string]string{
+		"i18n/de/default.po": sortCatalog,
+		"i18n/default.pot":   sortCatalog,
+		"i18n/.cache/old.po": sortCatalog,
+		"i18n/README.md":     "catalogs\n",
+	})
+	defer os.RemoveAll(dir)
+
+	// Entries are sorted by msgid by default, then context
+	r := runCommand(t, dir, "sort", "i18n/de/default.po")
+	if expected := "Apple menu|Apple Bye Zebra"; r.code != 0 || msgids(r.stdout) != expected {
+		t.Errorf("Expected '%s' but got '%s%s'", expected, r.stdout, r.stderr)
+	}
+	r = runCommand(t, dir, "sort", "-sort", "file", "i18n/de/default.po")
+	if expected := "Bye Apple menu|Apple Zebra"; r.code != 0 || msgids(r.stdout) != expected {
+		t.Errorf("Expected '%s' but got '%s%s'", expected, r.stdout, r.stderr)
+	}
+	// The fmt command keeps their order
+	if r := runCommand(t, dir, "fmt", "-l", "i18n/de/default.po"); r.code != 0 || r.stdout != "" {
+		t.Errorf("Expected the catalog to be formatted but got '%s%s'", r.stdout, r.stderr)
+	}
+
+	// Directories are walked for catalogs, skipping hidden ones
+	r = runCommand(t, dir, "sort", "-l", "i18n")
+	if expected := "i18n/de/default.po\ni18n/default.pot\n"; r.code != 0 || r.stdout != expected {
+		t.Errorf("Expected '%s' but got '%s%s'", expected, r.stdout, r.stderr)
+	}
+	if r := runCommand(t, dir, "sort", "-w", "i18n"); r.code != 0 || r.stdout != "" {
+		t.Fatalf("Expected the catalogs to be sorted but got '%s%s'", r.stdout, r.stderr)
+	}
+	if r := runCommand(t, dir, "sort", "-l", "i18n"); r.code != 0 || r.stdout != "" {
+		t.Errorf("Expected the catalogs to be sorted but got '%s%s'", r.stdout, r.stderr)
+	}
+	for _, test := range []struct {
+		file, expected string
+	}{
+		{"i18n/default.pot", "Apple menu|Apple Bye Zebra"},
+		{"i18n/.cache/old.po", "Zebra Bye menu|Apple Apple"},
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, test.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ids := msgids(string(data)); ids != test.expected {
+			t.Errorf("Expected '%s' in %s but got '%s'", test.expected, test.file, ids)
+		}
+	}
+}
+
+func TestSortErrors(t *testing.T) {
+	dir := "/tmp/gotext_sort_errors"
+	writeFixture(t, dir, map[string]string{
+		"default.po": sortCatalog,
+		"broken.po":  "msgid \"Hello\"\nmsgstr \"Hallo\n",
+	})
+	defer os.RemoveAll(dir)
+
+	for _, test := range []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-w"}, "Cannot use -w with standard input"},
+		{[]string{"-sort", "size", "default.po"}, "Unknown sort order \"size\""},
+		{[]string{"-l", "broken.po", "default.po"}, "broken.po: "},
+		{[]string{"missing.po"}, "missing.po"},
+	} {
+		r := runCommand(t, dir, append([]string{"sort"}, test.args...)...)
+		if r.code == 0 || !strings.Contains(r.stderr, test.expected) {
+			t.Errorf("Expected '%s' for %v but got '%s'", test.expected, test.args, r.stderr)
+		}
+	}
+}
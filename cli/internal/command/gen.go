@@ -0,0 +1,301 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// placeholderRe matches fmt verbs with their optional explicit argument index, flags, width and precision.
+	placeholderRe = regexp.MustCompile(`%(?:\[(\d+)\])?[-+# 0]*(?:\d+)?(?:\.\d+)?([a-zA-Z%])`)
+
+	// namedPlaceholderRe matches the named verbs supported by gotext.Sprintf.
+	namedPlaceholderRe = regexp.MustCompile(`%\(([a-zA-Z0-9_]+)\)[.0-9]*([svTtbcdoqXxUeEfFgGp])`)
+)
+
+// genParam is a parameter of a generated accessor.
+type genParam struct {
+	name, typ string
+
+	// key is the name of the placeholder, for named verbs.
+	key string
+}
+
+// genFunc is a generated accessor.
+type genFunc struct {
+	name   string
+	entry  catalogEntry
+	params []genParam
+	named  bool
+}
+
+// verbType returns the Go type of the argument expected by a fmt verb.
+func verbType(verb byte) string {
+	switch verb {
+	case 's', 'q':
+		return "string"
+	case 'd', 'c', 'o', 'O', 'b', 'U':
+		return "int"
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		return "float64"
+	case 't':
+		return "bool"
+	}
+
+	return "interface{}"
+}
+
+// mergeType returns the type of a parameter used by placeholders of types a and b.
+func mergeType(a, b string) string {
+	if a == "" || a == b {
+		return b
+	}
+
+	return "interface{}"
+}
+
+// positionalParams returns the parameters expected by the fmt verbs of the given formats.
+func positionalParams(formats ...string) []genParam {
+	var types []string
+	for _, f := range formats {
+		next := 0
+		for _, m := range placeholderRe.FindAllStringSubmatch(f, -1) {
+			if m[2] == "%" {
+				continue
+			}
+			if m[1] != "" {
+				if i, err := strconv.Atoi(m[1]); err == nil && i > 0 {
+					next = i - 1
+				}
+			}
+			for len(types) <= next {
+				types = append(types, "")
+			}
+			types[next] = mergeType(types[next], verbType(m[2][0]))
+			next++
+		}
+	}
+
+	params := make([]genParam, len(types))
+	for i, typ := range types {
+		if typ == "" {
+			typ = "interface{}"
+		}
+		params[i] = genParam{name: fmt.Sprintf("arg%d", i+1), typ: typ}
+	}
+
+	return params
+}
+
+// namedParams returns the parameters expected by the named verbs of the given formats.
+func namedParams(formats ...string) []genParam {
+	var params []genParam
+	index := make(map[string]int)
+	for _, f := range formats {
+		for _, m := range namedPlaceholderRe.FindAllStringSubmatch(f, -1) {
+			typ := verbType(m[2][0])
+			if i, ok := index[m[1]]; ok {
+				params[i].typ = mergeType(params[i].typ, typ)
+				continue
+			}
+			index[m[1]] = len(params)
+			params = append(params, genParam{name: paramName(m[1]), typ: typ, key: m[1]})
+		}
+	}
+
+	return params
+}
+
+// paramName returns a valid Go parameter name for a placeholder name, not clashing with the generated code.
+func paramName(key string) string {
+	name := identifier(key, false)
+	switch {
+	case token.Lookup(name).IsKeyword():
+		name += "Arg"
+	case name == "l", name == "n", name == "id", name == "plural", name == "gotext":
+		name += "Arg"
+	}
+
+	return name
+}
+
+// identifier turns the words of s into a CamelCase Go identifier, exported or not.
+func identifier(s string, exported bool) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for i, w := range words {
+		r := []rune(w)
+		if i == 0 && !exported {
+			r[0] = unicode.ToLower(r[0])
+		} else {
+			r[0] = unicode.ToUpper(r[0])
+		}
+		b.WriteString(string(r))
+	}
+
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		if exported {
+			name = "Msg" + name
+		} else {
+			name = "arg" + name
+		}
+	}
+
+	return name
+}
+
+// funcName returns the accessor name for an entry: the first words of its msgid, followed by its context.
+func funcName(e catalogEntry) string {
+	text := namedPlaceholderRe.ReplaceAllString(e.ID, " ")
+	text = placeholderRe.ReplaceAllString(text, " ")
+
+	words := strings.Fields(text)
+	if len(words) > 6 {
+		words = words[:6]
+	}
+	name := identifier(strings.Join(words, " "), true)
+	if e.ctx != "" {
+		name += identifier(e.ctx, true)
+	}
+
+	return name
+}
+
+// buildFuncs returns the accessors of the given entries, with unique names.
+func buildFuncs(entries []catalogEntry) []genFunc {
+	funcs := make([]genFunc, 0, len(entries))
+	used := map[string]bool{"Domain": true}
+	for _, e := range entries {
+		name := funcName(e)
+		for i := 2; used[name]; i++ {
+			name = fmt.Sprintf("%s%d", funcName(e), i)
+		}
+		used[name] = true
+
+		fn := genFunc{name: name, entry: e}
+		if fn.params = namedParams(e.ID, e.PluralID); len(fn.params) > 0 {
+			fn.named = true
+		} else {
+			fn.params = positionalParams(e.ID, e.PluralID)
+		}
+		funcs = append(funcs, fn)
+	}
+
+	return funcs
+}
+
+// generateAccessors returns the Go source of the typed accessors of the given entries.
+func generateAccessors(pkg, dom string, entries []catalogEntry) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by xgotext gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import \"github.com/leonelquinteros/gotext\"\n\n")
+	fmt.Fprintf(&b, "// Domain is the gettext domain of the messages.\nconst Domain = %q\n", dom)
+
+	for _, fn := range buildFuncs(entries) {
+		e := fn.entry
+		params := []string{"l *gotext.Locale"}
+		if e.PluralID != "" {
+			params = append(params, "n int")
+		}
+		var args []string
+		for _, p := range fn.params {
+			params = append(params, p.name+" "+p.typ)
+			args = append(args, p.name)
+		}
+
+		fmt.Fprintf(&b, "\n// %s translates %q", fn.name, e.ID)
+		if e.ctx != "" {
+			fmt.Fprintf(&b, " in the %q context", e.ctx)
+		}
+		fmt.Fprintf(&b, ".\nfunc %s(%s) string {\n", fn.name, strings.Join(params, ", "))
+
+		id, plural := strconv.Quote(e.ID), strconv.Quote(e.PluralID)
+		if fn.named {
+			// Named verbs aren't understood by vet, so the msgids aren't passed as constants to the printf-like lookups.
+			fmt.Fprintf(&b, "\tid := %s\n", id)
+			id = "id"
+			if e.PluralID != "" {
+				fmt.Fprintf(&b, "\tplural := %s\n", plural)
+				plural = "plural"
+			}
+		}
+
+		var call string
+		switch {
+		case e.PluralID != "" && e.ctx != "":
+			call = fmt.Sprintf("l.GetNDC(Domain, %s, %s, n, %q", id, plural, e.ctx)
+		case e.PluralID != "":
+			call = fmt.Sprintf("l.GetND(Domain, %s, %s, n", id, plural)
+		case e.ctx != "":
+			call = fmt.Sprintf("l.GetDC(Domain, %s, %q", id, e.ctx)
+		default:
+			call = fmt.Sprintf("l.GetD(Domain, %s", id)
+		}
+
+		if fn.named {
+			var kv []string
+			for _, p := range fn.params {
+				kv = append(kv, fmt.Sprintf("%q: %s", p.key, p.name))
+			}
+			fmt.Fprintf(&b, "\treturn gotext.Sprintf(%s), map[string]interface{}{%s})\n}\n", call, strings.Join(kv, ", "))
+			continue
+		}
+
+		if len(args) > 0 {
+			call += ", " + strings.Join(args, ", ")
+		}
+		fmt.Fprintf(&b, "\treturn %s)\n}\n", call)
+	}
+
+	return format.Source(b.Bytes())
+}
+
+// gen runs the gen command, generating typed accessor functions for the messages of a catalog.
+func gen(args []string) {
+	fs := newFlagSet("gen", "[flags]")
+	in := fs.String("in", "", "input file: /path/to/default.po")
+	out := fs.String("out", "", "output file: /path/to/msg/messages.go (default standard output)")
+	pkg := fs.String("package", "msg", "package name of the generated code")
+	dom := fs.String("domain", "", "domain of the messages (default input file name)")
+	parseFlags(fs, args)
+
+	if *in == "" {
+		log.Fatal("No input file given")
+	}
+	if *dom == "" {
+		*dom = strings.TrimSuffix(filepath.Base(*in), filepath.Ext(*in))
+	}
+
+	enc, err := readCatalog(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := generateAccessors(*pkg, *dom, sortedEntries(enc))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err = ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,177 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+func TestFuncName(t *testing.T) {
+	for _, test := range []struct {
+		entry    catalogEntry
+		expected string
+	}{
+		{catalogEntry{Translation: &gotext.Translation{ID: "Hello, %s!"}}, "Hello"},
+		{catalogEntry{Translation: &gotext.Translation{ID: "Open"}, ctx: "menu"}, "OpenMenu"},
+		{catalogEntry{Translation: &gotext.Translation{ID: "Welcome %(name)s, you have %(count)d messages"}}, "WelcomeYouHaveMessages"},
+		{catalogEntry{Translation: &gotext.Translation{ID: "one two three four five six seven"}}, "OneTwoThreeFourFiveSix"},
+		{catalogEntry{Translation: &gotext.Translation{ID: "%d"}}, "Msg"},
+		{catalogEntry{Translation: &gotext.Translation{ID: "404 not found"}}, "Msg404NotFound"},
+	} {
+		if name := funcName(test.entry); name != test.expected {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", test.expected, test.entry.ID, name)
+		}
+	}
+}
+
+func TestBuildFuncs(t *testing.T) {
+	var entries []catalogEntry
+	for _, id := range []string{"Hello", "Hello!", "Domain", "Hello %s"} {
+		entries = append(entries, catalogEntry{Translation: &gotext.Translation{ID: id}})
+	}
+
+	// Names are unique, Domain being the constant of the generated package
+	var names []string
+	for _, fn := range buildFuncs(entries) {
+		names = append(names, fn.name)
+	}
+	expected := "Hello Hello2 Domain2 Hello3"
+	if got := strings.Join(names, " "); got != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, got)
+	}
+}
+
+func TestGenParams(t *testing.T) {
+	for _, test := range []struct {
+		formats  []string
+		expected string
+	}{
+		{[]string{"%s has %d items at %.2f%%"}, "arg1 string, arg2 int, arg3 float64"},
+		{[]string{"%[2]s sent %[1]d files"}, "arg1 int, arg2 string"},
+		{[]string{"One file", "%d files"}, "arg1 int"},
+		{[]string{"%v and %t", "%s and %t"}, "arg1 interface{}, arg2 bool"},
+		{[]string{"%[3]d"}, "arg1 interface{}, arg2 interface{}, arg3 int"},
+	} {
+		var params []string
+		for _, p := range positionalParams(test.formats...) {
+			params = append(params, p.name+" "+p.typ)
+		}
+		if got := strings.Join(params, ", "); got != test.expected {
+			t.Errorf("Expected '%s' for %q but got '%s'", test.expected, test.formats, got)
+		}
+	}
+
+	var params []string
+	for _, p := range namedParams("%(name)s has %(count)d %(type)s", "%(count)s and %(n)d") {
+		params = append(params, p.key+"="+p.name+" "+p.typ)
+	}
+	expected := "name=name string, count=count interface{}, type=typeArg string, n=nArg int"
+	if got := strings.Join(params, ", "); got != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, got)
+	}
+}
+
+const genCatalog = `msgid ""
+msgstr ""
+"Language: es\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Hello, %s!"
+msgstr "¡Hola, %s!"
+
+msgid "%[2]s sent %[1]d files"
+msgstr "%[2]s envió %[1]d archivos"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Abrir"
+
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "Un archivo"
+msgstr[1] "%d archivos"
+
+msgid "Welcome %(name)s, you have %(count)d messages"
+msgstr "Bienvenida %(name)s, tienes %(count)d mensajes"
+`
+
+func TestGen(t *testing.T) {
+	dir := "/tmp/gotext_gen"
+	writeFixture(t, dir, map[string]string{
+		"go.mod":          goMod("example.com/app"),
+		"i18n/es/shop.po": genCatalog,
+		"msg/doc.go":      "// Package msg holds the generated accessors.\npackage msg\n",
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/app/msg"
+	"github.com/leonelquinteros/gotext"
+)
+
+func main() {
+	l := gotext.NewLocale("i18n", "es")
+	l.AddDomain(msg.Domain)
+	fmt.Println(msg.Hello(l, "Ana"))
+	fmt.Println(msg.SentFiles(l, 3, "Ana"))
+	fmt.Println(msg.OpenMenu(l))
+	fmt.Println(msg.OneFile(l, 2, 2))
+	fmt.Println(msg.WelcomeYouHaveMessages(l, "Ana", 4))
+}
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	r := runCommand(t, dir, "gen", "-in", "i18n/es/shop.po", "-out", "msg/messages.go")
+	if r.code != 0 {
+		t.Fatalf("Expected the generation to succeed but got '%s'", r.stderr)
+	}
+	src, err := ioutil.ReadFile(filepath.Join(dir, "msg", "messages.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"// Code generated by xgotext gen; DO NOT EDIT.\n\npackage msg\n",
+		"const Domain = \"shop\"\n",
+		"func Hello(l *gotext.Locale, arg1 string) string {\n\treturn l.GetD(Domain, \"Hello, %s!\", arg1)\n}\n",
+		"func SentFiles(l *gotext.Locale, arg1 int, arg2 string) string {\n",
+		"// OpenMenu translates \"Open\" in the \"menu\" context.\nfunc OpenMenu(l *gotext.Locale) string {\n\treturn l.GetDC(Domain, \"Open\", \"menu\")\n}\n",
+		"func OneFile(l *gotext.Locale, n int, arg1 int) string {\n\treturn l.GetND(Domain, \"One file\", \"%d files\", n, arg1)\n}\n",
+		"func WelcomeYouHaveMessages(l *gotext.Locale, name string, count int) string {\n",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("Expected '%s' in '%s'", expected, src)
+		}
+	}
+
+	// The accessors type check, and translate with their arguments
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Expected the accessors to build but got '%v': %s", err, out)
+	}
+	expected := "¡Hola, Ana!\nAna envió 3 archivos\nAbrir\n2 archivos\nBienvenida Ana, tienes 4 mensajes\n"
+	if string(out) != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, out)
+	}
+
+	// The output defaults to stdout, and the domain to the file name
+	r = runCommand(t, dir, "gen", "-in", "i18n/es/shop.po", "-package", "shopmsg", "-domain", "store")
+	if r.code != 0 || !strings.Contains(r.stdout, "package shopmsg\n") || !strings.Contains(r.stdout, "const Domain = \"store\"\n") {
+		t.Errorf("Expected the accessors of the store domain on stdout but got '%s%s'", r.stdout, r.stderr)
+	}
+	if r := runCommand(t, dir, "gen"); r.code == 0 || !strings.Contains(r.stderr, "No input file given") {
+		t.Errorf("Expected an error without input file but got '%s'", r.stderr)
+	}
+}
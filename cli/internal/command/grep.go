@@ -0,0 +1,142 @@
+package command
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// entryFilter selects catalog entries, all of its set criteria must match.
+type entryFilter struct {
+	msgid, msgstr, msgctxt, comment, reference *regexp.Regexp
+	flag                                       string
+	untranslated                               bool
+}
+
+// anyMatch reports if the expression matches any of the strings.
+func anyMatch(re *regexp.Regexp, list ...string) bool {
+	for _, s := range list {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// match reports if the entry is selected by the filter.
+func (f *entryFilter) match(e *gotext.PoEntry) bool {
+	switch {
+	case e.IsHeader() || e.Obsolete:
+		return false
+	case f.msgid != nil && !anyMatch(f.msgid, e.ID, e.PluralID):
+		return false
+	case f.msgstr != nil && !anyMatch(f.msgstr, e.Str...):
+		return false
+	case f.msgctxt != nil && !(e.HasContext && f.msgctxt.MatchString(e.Context)):
+		return false
+	case f.comment != nil && !anyMatch(f.comment, append(e.TranslatorComments, e.ExtractedComments...)...):
+		return false
+	case f.reference != nil && !anyMatch(f.reference, e.References...):
+		return false
+	case f.flag != "" && !e.HasFlag(f.flag):
+		return false
+	case f.untranslated && e.Translated():
+		return false
+	}
+
+	return true
+}
+
+// grep runs the grep command, searching the entries of catalogs.
+func grep(args []string) {
+	fs := newFlagSet("grep", "[flags] path ...")
+	msgid := fs.String("msgid", "", "regular expression matching the msgid or msgid_plural")
+	msgstr := fs.String("msgstr", "", "regular expression matching a translation")
+	msgctxt := fs.String("msgctxt", "", "regular expression matching the context")
+	comment := fs.String("comment", "", "regular expression matching a translator or extracted comment")
+	reference := fs.String("ref", "", "regular expression matching a source reference")
+	flagName := fs.String("flag", "", "flag of the entries, i.e. \"fuzzy\" or \"c-format\"")
+	untranslated := fs.Bool("untranslated", false, "select entries with missing translations")
+	ignoreCase := fs.Bool("i", false, "case insensitive regular expressions")
+	list := fs.Bool("l", false, "list files with matching entries only")
+	count := fs.Bool("c", false, "print the number of matching entries of each file only")
+	parseFlags(fs, args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	compile := func(expr string) *regexp.Regexp {
+		if expr == "" {
+			return nil
+		}
+		if *ignoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return re
+	}
+	filter := &entryFilter{
+		msgid:        compile(*msgid),
+		msgstr:       compile(*msgstr),
+		msgctxt:      compile(*msgctxt),
+		comment:      compile(*comment),
+		reference:    compile(*reference),
+		flag:         *flagName,
+		untranslated: *untranslated,
+	}
+
+	files, err := poFiles(fs.Args())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	found := false
+	for _, file := range files {
+		f, err := readPoFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		matches := 0
+		for _, e := range f.Entries {
+			if !filter.match(e) {
+				continue
+			}
+			matches++
+			if *list || *count {
+				continue
+			}
+
+			line := fmt.Sprintf("%s: %q", file, e.ID)
+			if e.HasContext {
+				line += fmt.Sprintf(" (context %q)", e.Context)
+			}
+			strs := make([]string, len(e.Str))
+			for i, s := range e.Str {
+				strs[i] = fmt.Sprintf("%q", s)
+			}
+			fmt.Printf("%s => %s\n", line, strings.Join(strs, " | "))
+		}
+
+		found = found || matches > 0
+		switch {
+		case *count:
+			fmt.Printf("%s: %d\n", file, matches)
+		case *list && matches > 0:
+			fmt.Println(file)
+		}
+	}
+	if !found {
+		os.Exit(1)
+	}
+}
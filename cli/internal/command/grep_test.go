@@ -0,0 +1,82 @@
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+const grepCatalog = `msgid ""
+msgstr ""
+"Language: de\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+# Shown on the home page
+#: main.go:12
+msgid "Hello"
+msgstr "Hallo"
+
+#. The menu entry
+#: web/menu.go:8
+msgctxt "menu"
+msgid "Open"
+msgstr "Öffnen"
+
+#: web/files.go:20
+#, fuzzy, c-format
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "Eine Datei"
+msgstr[1] "%d Dateien"
+
+#: main.go:30
+msgid "Bye"
+msgstr ""
+
+#~ msgid "Old"
+#~ msgstr "Alt"
+`
+
+func TestGrep(t *testing.T) {
+	dir := "/tmp/gotext_grep"
+	writeFixture(t, dir, map[string]string{
+		"i18n/de.po":  grepCatalog,
+		"i18n/fr.pot": strings.Replace(grepCatalog, "\"Hallo\"", "\"\"", 1),
+	})
+	defer os.RemoveAll(dir)
+
+	for _, test := range []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-msgid", "^Hel", "i18n/de.po"}, "i18n/de.po: \"Hello\" => \"Hallo\"\n"},
+		{[]string{"-msgid", "%d files", "i18n/de.po"}, "i18n/de.po: \"One file\" => \"Eine Datei\" | \"%d Dateien\"\n"},
+		{[]string{"-msgstr", "datei", "-i", "i18n/de.po"}, "i18n/de.po: \"One file\" => \"Eine Datei\" | \"%d Dateien\"\n"},
+		{[]string{"-msgctxt", "menu", "i18n/de.po"}, "i18n/de.po: \"Open\" (context \"menu\") => \"Öffnen\"\n"},
+		{[]string{"-comment", "home|menu", "i18n/de.po"}, "i18n/de.po: \"Hello\" => \"Hallo\"\ni18n/de.po: \"Open\" (context \"menu\") => \"Öffnen\"\n"},
+		{[]string{"-ref", "^web/", "-flag", "fuzzy", "i18n/de.po"}, "i18n/de.po: \"One file\" => \"Eine Datei\" | \"%d Dateien\"\n"},
+		{[]string{"-ref", "^main.go", "-untranslated", "i18n"}, "i18n/de.po: \"Bye\" => \"\"\ni18n/fr.pot: \"Hello\" => \"\"\ni18n/fr.pot: \"Bye\" => \"\"\n"},
+		{[]string{"-msgid", "Hello", "-l", "i18n"}, "i18n/de.po\ni18n/fr.pot\n"},
+		{[]string{"-untranslated", "-c", "i18n"}, "i18n/de.po: 1\ni18n/fr.pot: 2\n"},
+	} {
+		r := runCommand(t, dir, append([]string{"grep"}, test.args...)...)
+		if r.code != 0 || r.stdout != test.expected {
+			t.Errorf("Expected '%s' for %v but got '%s%s'", test.expected, test.args, r.stdout, r.stderr)
+		}
+	}
+
+	// Obsolete entries are never selected, and the status is 1 without matches
+	if r := runCommand(t, dir, "grep", "-msgid", "Old", "i18n"); r.code != 1 || r.stdout != "" {
+		t.Errorf("Expected no matches but got '%s%s'", r.stdout, r.stderr)
+	}
+	if r := runCommand(t, dir, "grep", "-msgid", "Old", "-c", "i18n/de.po"); r.code != 1 || r.stdout != "i18n/de.po: 0\n" {
+		t.Errorf("Expected no matches to be counted but got '%s%s'", r.stdout, r.stderr)
+	}
+	if r := runCommand(t, dir, "grep", "-msgid", "Hello"); r.code != 2 || !strings.Contains(r.stderr, "path ...") {
+		t.Errorf("Expected the usage and exit code 2 but got '%s' and %d", r.stderr, r.code)
+	}
+	if r := runCommand(t, dir, "grep", "-msgid", "(", "i18n"); r.code == 0 || !strings.Contains(r.stderr, "missing closing )") {
+		t.Errorf("Expected an error for the invalid expression but got '%s'", r.stderr)
+	}
+}
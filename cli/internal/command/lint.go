@@ -0,0 +1,78 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// lintIssue is an issue of a catalog, as written by the JSON output of the lint command.
+type lintIssue struct {
+	File    string           `json:"file"`
+	Line    int              `json:"line"`
+	Kind    gotext.IssueKind `json:"kind"`
+	Context string           `json:"msgctxt,omitempty"`
+	Msgid   string           `json:"msgid,omitempty"`
+	Message string           `json:"message"`
+}
+
+// lint runs the lint command, validating catalogs with Po.Validate and failing when issues are found.
+func lint(args []string) {
+	fs := newFlagSet("lint", "[flags] path ...")
+	jsonOutput := fs.Bool("json", false, "print the issues as a JSON array")
+	disable := fs.String("disable", "", "comma separated list of issue kinds to ignore, i.e. \"placeholders,duplicate\"")
+	parseFlags(fs, args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	ignored := make(map[gotext.IssueKind]bool)
+	for _, kind := range strings.Split(*disable, ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			ignored[gotext.IssueKind(kind)] = true
+		}
+	}
+
+	files, err := poFiles(fs.Args())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	issues := []lintIssue{}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		po := gotext.NewPo()
+		po.Parse(data)
+		for _, issue := range po.Validate() {
+			if ignored[issue.Kind] {
+				continue
+			}
+			issues = append(issues, lintIssue{file, issue.Line, issue.Kind, issue.Context, issue.Msgid, issue.Message})
+			if !*jsonOutput {
+				fmt.Printf("%s:%s\n", file, issue)
+			}
+		}
+	}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err = enc.Encode(issues); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if len(issues) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,65 @@
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	dir := "/tmp/gotext_lint"
+	writeFixture(t, dir, map[string]string{
+		"i18n/de/default.po": msgfmtCatalog,
+		"i18n/fr/default.po": `msgid ""
+msgstr ""
+"Language: fr\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+msgid "%d files"
+msgstr "fichiers"
+
+msgid "Hello"
+msgstr "Bonjour"
+
+msgid "Hello"
+msgstr "Salut"
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	if r := runCommand(t, dir, "lint", "i18n/de"); r.code != 0 || r.stdout != "" {
+		t.Errorf("Expected no issues but got '%s%s'", r.stdout, r.stderr)
+	}
+
+	// Directories are walked, each issue printed on a line of its own
+	r := runCommand(t, dir, "lint", "i18n")
+	lines := strings.Split(strings.TrimSpace(r.stdout), "\n")
+	if r.code != 1 || len(lines) != 2 {
+		t.Fatalf("Expected 2 issues but got %d '%s%s'", r.code, r.stdout, r.stderr)
+	}
+	for i, kind := range []string{"placeholders", "duplicate"} {
+		if !strings.HasPrefix(lines[i], "i18n/fr/default.po:") || !strings.Contains(lines[i], ": "+kind+": ") {
+			t.Errorf("Expected a %s issue but got '%s'", kind, lines[i])
+		}
+	}
+
+	r = runCommand(t, dir, "lint", "-json", "i18n/fr/default.po")
+	var issues []lintIssue
+	if err := json.Unmarshal([]byte(r.stdout), &issues); err != nil {
+		t.Fatalf("Expected JSON issues but got '%s%s'", r.stdout, r.stderr)
+	}
+	if r.code != 1 || len(issues) != 2 || issues[0].Kind != "placeholders" || issues[0].Msgid != "%d files" || issues[0].Line != 6 {
+		t.Errorf("Expected the placeholders issue of '%%d files' but got %+v", issues)
+	}
+
+	// Disabled kinds are ignored
+	r = runCommand(t, dir, "lint", "-disable", "duplicate", "i18n/fr/default.po")
+	if r.code != 1 || strings.Count(r.stdout, "\n") != 1 || !strings.Contains(r.stdout, ": placeholders: ") {
+		t.Errorf("Expected the placeholders issue only but got '%s%s'", r.stdout, r.stderr)
+	}
+	r = runCommand(t, dir, "lint", "-json", "-disable", "placeholders, duplicate", "i18n")
+	if r.code != 0 || strings.TrimSpace(r.stdout) != "[]" {
+		t.Errorf("Expected no issues but got '%s%s'", r.stdout, r.stderr)
+	}
+}
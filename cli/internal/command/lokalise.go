@@ -0,0 +1,232 @@
+package command
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// lokaliseConfig configures the synchronization with Lokalise.
+type lokaliseConfig struct {
+	ProjectID string `json:"project_id"`
+
+	// SourceLanguage is the base language of the project, "en" when not set.
+	SourceLanguage string `json:"source_language"`
+
+	// Token is the API token, read from the LOKALISE_API_TOKEN environment variable when not set.
+	Token string `json:"token"`
+
+	// BaseURL overrides the API URL.
+	BaseURL string `json:"base_url"`
+}
+
+// lokaliseBatch is the number of keys listed or updated by request.
+const lokaliseBatch = 500
+
+// lokalise synchronizes catalogs with Lokalise, through its v2 API.
+type lokalise struct {
+	conf  lokaliseConfig
+	token string
+	base  string
+}
+
+func newLokalise(sc *syncConfig) (syncProvider, error) {
+	if sc.Lokalise == nil || sc.Lokalise.ProjectID == "" {
+		return nil, fmt.Errorf("no lokalise project_id configured")
+	}
+
+	l := &lokalise{conf: *sc.Lokalise, base: sc.Lokalise.BaseURL}
+	var err error
+	if l.token, err = providerToken(l.conf.Token, "LOKALISE_API_TOKEN"); err != nil {
+		return nil, err
+	}
+	if l.conf.SourceLanguage == "" {
+		l.conf.SourceLanguage = "en"
+	}
+	if l.base == "" {
+		l.base = "https://api.lokalise.com/api2"
+	}
+	l.base = strings.TrimSuffix(l.base, "/") + "/projects/" + url.PathEscape(l.conf.ProjectID)
+
+	return l, nil
+}
+
+// do sends a JSON request to the API of the project.
+func (l *lokalise) do(method, path string, body, out interface{}) error {
+	req, err := newJSONRequest(method, l.base+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Api-Token", l.token)
+
+	return apiRequest(req, out)
+}
+
+// lokaliseProcess is the state of an asynchronous upload.
+type lokaliseProcess struct {
+	Process struct {
+		ProcessID string `json:"process_id"`
+		Status    string `json:"status"`
+		Message   string `json:"message"`
+	} `json:"process"`
+}
+
+// Push uploads the template as the base language file, waiting for Lokalise to import it,
+// then describes the keys with the extracted comments of their entries.
+func (l *lokalise) Push(name string, pot []byte) error {
+	f, err := gotext.ParsePoFile(pot)
+	if err != nil {
+		return err
+	}
+
+	// Base language translations are the source strings.
+	for _, e := range f.Entries {
+		if e.IsHeader() {
+			continue
+		}
+		if len(e.Str) == 0 {
+			e.Str = []string{""}
+		}
+		if e.Str[0] == "" {
+			e.Str[0] = e.ID
+		}
+		if e.PluralID != "" && len(e.Str) > 1 && e.Str[1] == "" {
+			e.Str[1] = e.PluralID
+		}
+	}
+
+	var proc lokaliseProcess
+	err = l.do(http.MethodPost, "/files/upload", map[string]interface{}{
+		"data":                   base64.StdEncoding.EncodeToString(f.Format(gotext.FormatOptions{NoWrap: true})),
+		"filename":               name,
+		"lang_iso":               l.conf.SourceLanguage,
+		"distinguish_by_context": true,
+		"replace_modified":       true,
+	}, &proc)
+	if err != nil {
+		return err
+	}
+
+	err = poll("upload", func() (bool, error) {
+		switch proc.Process.Status {
+		case "finished":
+			return true, nil
+		case "failed", "cancelled":
+			return false, fmt.Errorf("upload %s: %s", proc.Process.Status, proc.Process.Message)
+		}
+		return false, l.do(http.MethodGet, "/processes/"+proc.Process.ProcessID, nil, &proc)
+	})
+	if err != nil {
+		return err
+	}
+
+	return l.describeKeys(name, f)
+}
+
+// lokaliseKey is a key of the project, as listed and updated.
+type lokaliseKey struct {
+	KeyID   int64 `json:"key_id"`
+	KeyName *struct {
+		Other string `json:"other"`
+	} `json:"key_name,omitempty"`
+	Context     *string `json:"context,omitempty"`
+	Description string  `json:"description"`
+}
+
+// describeKeys sets the descriptions of the keys of the file to the extracted comments of their entries.
+func (l *lokalise) describeKeys(name string, f *gotext.PoFile) error {
+	descriptions := make(map[string]string)
+	for _, e := range f.Entries {
+		if !e.IsHeader() && !e.Obsolete {
+			descriptions[e.Context+"\x04"+e.ID] = strings.Join(e.ExtractedComments, "\n")
+		}
+	}
+
+	var updates []lokaliseKey
+	for page := 1; ; page++ {
+		var resp struct {
+			Keys []lokaliseKey `json:"keys"`
+		}
+		query := url.Values{"filter_filenames": {name}, "limit": {fmt.Sprint(lokaliseBatch)}, "page": {fmt.Sprint(page)}}
+		if err := l.do(http.MethodGet, "/keys?"+query.Encode(), nil, &resp); err != nil {
+			return err
+		}
+		for _, k := range resp.Keys {
+			if k.KeyName == nil {
+				continue
+			}
+			ctx := ""
+			if k.Context != nil {
+				ctx = *k.Context
+			}
+			if desc, ok := descriptions[ctx+"\x04"+k.KeyName.Other]; ok && desc != k.Description {
+				updates = append(updates, lokaliseKey{KeyID: k.KeyID, Description: desc})
+			}
+		}
+		if len(resp.Keys) < lokaliseBatch {
+			break
+		}
+	}
+
+	for len(updates) > 0 {
+		n := len(updates)
+		if n > lokaliseBatch {
+			n = lokaliseBatch
+		}
+		if err := l.do(http.MethodPut, "/keys", map[string]interface{}{"keys": updates[:n]}, nil); err != nil {
+			return err
+		}
+		updates = updates[n:]
+	}
+
+	return nil
+}
+
+// Pull exports the translations of the file in a language, then extracts them from the downloaded bundle.
+func (l *lokalise) Pull(name, lang string) ([]byte, error) {
+	var bundle struct {
+		BundleURL string `json:"bundle_url"`
+	}
+	err := l.do(http.MethodPost, "/files/download", map[string]interface{}{
+		"format":             "po",
+		"filter_langs":       []string{lang},
+		"filter_filenames":   []string{name},
+		"original_filenames": false,
+		"bundle_structure":   "%LANG_ISO%.po",
+		"export_empty_as":    "empty",
+	}, &bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := download(bundle.BundleURL)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	for _, zf := range zr.File {
+		if path.Ext(zf.Name) != ".po" {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		return ioutil.ReadAll(rc)
+	}
+
+	return nil, fmt.Errorf("no translations of %s in %s", lang, bundle.BundleURL)
+}
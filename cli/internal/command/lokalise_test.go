@@ -0,0 +1,114 @@
+package command
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestLokalise returns a lokalise provider of the project "p1" using the fake API.
+func newTestLokalise(t *testing.T, api *fakeAPI) syncProvider {
+	p, err := newLokalise(&syncConfig{Lokalise: &lokaliseConfig{ProjectID: "p1", Token: "secret", BaseURL: api.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestLokalisePush(t *testing.T) {
+	defer fastPoll(time.Minute)()
+
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /projects/p1/files/upload": reply(map[string]interface{}{"process": map[string]string{"process_id": "pr1", "status": "queued"}}),
+		"GET /projects/p1/processes/pr1": reply(map[string]interface{}{"process": map[string]string{"process_id": "pr1", "status": "finished"}}),
+		"GET /projects/p1/keys": reply(map[string]interface{}{"keys": []map[string]interface{}{
+			{"key_id": 1, "key_name": map[string]string{"other": "Hello"}, "description": ""},
+			{"key_id": 2, "key_name": map[string]string{"other": "Removed"}, "description": ""},
+		}}),
+		"PUT /projects/p1/keys": reply(map[string]interface{}{"keys": []interface{}{}}),
+	})
+	defer api.Close()
+
+	if err := newTestLokalise(t, api).Push("default.pot", []byte(syncTemplate)); err != nil {
+		t.Fatal(err)
+	}
+
+	upload := api.call(t, "POST /projects/p1/files/upload")
+	if token := upload.Header.Get("X-Api-Token"); token != "secret" {
+		t.Errorf("Expected 'secret' but got '%s'", token)
+	}
+	body := decodeBody(t, upload)
+	if body["lang_iso"] != "en" || body["filename"] != "default.pot" {
+		t.Errorf("Expected an 'en' upload of 'default.pot' but got '%v' and '%v'", body["lang_iso"], body["filename"])
+	}
+	data, err := base64.StdEncoding.DecodeString(body["data"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The source strings are the translations of the base language
+	if !strings.Contains(string(data), "msgid \"Hello\"\nmsgstr \"Hello\"\n") {
+		t.Errorf("Expected the source strings as translations but got '%s'", data)
+	}
+
+	if files := api.call(t, "GET /projects/p1/keys").Query.Get("filter_filenames"); files != "default.pot" {
+		t.Errorf("Expected the keys of 'default.pot' but got '%s'", files)
+	}
+	update := decodeBody(t, api.call(t, "PUT /projects/p1/keys"))
+	expected := []interface{}{map[string]interface{}{"key_id": 1.0, "description": "The greeting"}}
+	if !reflect.DeepEqual(update["keys"], expected) {
+		t.Errorf("Expected %v but got %v", expected, update["keys"])
+	}
+}
+
+func TestLokalisePushFailed(t *testing.T) {
+	defer fastPoll(time.Minute)()
+
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /projects/p1/files/upload": reply(map[string]interface{}{"process": map[string]string{"process_id": "pr1", "status": "queued"}}),
+		"GET /projects/p1/processes/pr1": reply(map[string]interface{}{"process": map[string]string{"process_id": "pr1", "status": "failed", "message": "Invalid file"}}),
+	})
+	defer api.Close()
+
+	err := newTestLokalise(t, api).Push("default.pot", []byte(syncTemplate))
+	if err == nil || err.Error() != "upload failed: Invalid file" {
+		t.Errorf("Expected the failed upload but got '%v'", err)
+	}
+}
+
+func TestLokalisePull(t *testing.T) {
+	var bundle bytes.Buffer
+	zw := zip.NewWriter(&bundle)
+	w, _ := zw.Create("locale/de.po")
+	w.Write([]byte(syncTranslation))
+	zw.Close()
+
+	var api *fakeAPI
+	api = newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /projects/p1/files/download": func(w http.ResponseWriter, r *http.Request) {
+			reply(map[string]string{"bundle_url": api.URL + "/bundle.zip"})(w, r)
+		},
+		"GET /bundle.zip": func(w http.ResponseWriter, r *http.Request) {
+			w.Write(bundle.Bytes())
+		},
+	})
+	defer api.Close()
+
+	data, err := newTestLokalise(t, api).Pull("default.pot", "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != syncTranslation {
+		t.Errorf("Expected the translation but got '%s'", data)
+	}
+
+	body := decodeBody(t, api.call(t, "POST /projects/p1/files/download"))
+	if !reflect.DeepEqual(body["filter_langs"], []interface{}{"de"}) || !reflect.DeepEqual(body["filter_filenames"], []interface{}{"default.pot"}) {
+		t.Errorf("Expected the 'de' translations of 'default.pot' but got %v", body)
+	}
+}
@@ -0,0 +1,74 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// readPoFile loads a .po or .pot file keeping all of its comments.
+func readPoFile(file string) (*gotext.PoFile, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	f, err := gotext.ParsePoFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%v", file, err)
+	}
+
+	return f, nil
+}
+
+// merge runs the merge command, updating a translated catalog to a new template as GNU msgmerge does.
+func merge(args []string) {
+	fs := newFlagSet("merge", "[flags] new.pot existing.po")
+	out := fs.String("o", "", "output file: /path/to/default.po (default standard output)")
+	update := fs.Bool("U", false, "update the existing catalog in place")
+	noFuzzy := fs.Bool("N", false, "do not use fuzzy matching for changed msgids")
+	previous := fs.Bool("previous", false, "keep the previous msgids of fuzzy matched entries")
+	compendium := fs.String("C", "", "compendium of translations reused for the entries missing in the existing catalog: /path/to/compendium.po")
+	width := fs.Int("width", gotext.DefaultPoWidth, "width of the lines")
+	parseFlags(fs, args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *update && *out != "" {
+		log.Fatal("Cannot use -U with -o")
+	}
+
+	pot, err := readPoFile(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	po, err := readPoFile(fs.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := gotext.MergeOptions{NoFuzzyMatching: *noFuzzy, Previous: *previous}
+	if *compendium != "" {
+		if opts.Compendium, err = readPoFile(*compendium); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	merged := gotext.MergeTemplate(po, pot, opts)
+	src := merged.Format(gotext.FormatOptions{Width: *width})
+
+	if *update {
+		*out = fs.Arg(1)
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err = ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,103 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const mergeTemplate = `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#: main.go:10
+msgid "Hello"
+msgstr ""
+
+#: main.go:11
+msgid "Hello world!"
+msgstr ""
+
+#: main.go:12
+msgid "Save"
+msgstr ""
+`
+
+const mergeCatalog = `msgid ""
+msgstr ""
+"Language: de\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#: main.go:8
+msgid "Hello"
+msgstr "Hallo"
+
+#: main.go:9
+msgid "Hello world"
+msgstr "Hallo Welt"
+`
+
+func TestMerge(t *testing.T) {
+	dir := "/tmp/gotext_merge"
+	writeFixture(t, dir, map[string]string{
+		"default.pot":   mergeTemplate,
+		"de/default.po": mergeCatalog,
+		"compendium.po": "msgid \"Save\"\nmsgstr \"Speichern\"\n",
+	})
+	defer os.RemoveAll(dir)
+
+	// Changed msgids are matched fuzzily, with the references of the template
+	r := runCommand(t, dir, "merge", "default.pot", "de/default.po")
+	for _, expected := range []string{
+		"#: main.go:10\nmsgid \"Hello\"\nmsgstr \"Hallo\"\n",
+		"#: main.go:11\n#, fuzzy\nmsgid \"Hello world!\"\nmsgstr \"Hallo Welt\"\n",
+		"#: main.go:12\nmsgid \"Save\"\nmsgstr \"\"\n",
+	} {
+		if r.code != 0 || !strings.Contains(r.stdout, expected) {
+			t.Errorf("Expected '%s' in '%s%s'", expected, r.stdout, r.stderr)
+		}
+	}
+	if strings.Contains(r.stdout, "#|") {
+		t.Errorf("Expected no previous msgids but got '%s'", r.stdout)
+	}
+
+	r = runCommand(t, dir, "merge", "-previous", "default.pot", "de/default.po")
+	if expected := "#, fuzzy\n#| msgid \"Hello world\"\nmsgid \"Hello world!\"\n"; r.code != 0 || !strings.Contains(r.stdout, expected) {
+		t.Errorf("Expected '%s' in '%s%s'", expected, r.stdout, r.stderr)
+	}
+
+	r = runCommand(t, dir, "merge", "-N", "default.pot", "de/default.po")
+	if expected := "#: main.go:11\nmsgid \"Hello world!\"\nmsgstr \"\"\n"; r.code != 0 || !strings.Contains(r.stdout, expected) {
+		t.Errorf("Expected '%s' in '%s%s'", expected, r.stdout, r.stderr)
+	}
+
+	// Missing translations are taken from the compendium
+	r = runCommand(t, dir, "merge", "-C", "compendium.po", "default.pot", "de/default.po")
+	if expected := "msgid \"Save\"\nmsgstr \"Speichern\"\n"; r.code != 0 || !strings.Contains(r.stdout, expected) {
+		t.Errorf("Expected '%s' in '%s%s'", expected, r.stdout, r.stderr)
+	}
+
+	// The catalog is updated in place, or written to the output file
+	if r := runCommand(t, dir, "merge", "-U", "-o", "out.po", "default.pot", "de/default.po"); r.code != 1 {
+		t.Errorf("Expected -U and -o to conflict but got '%s%s'", r.stdout, r.stderr)
+	}
+	if r := runCommand(t, dir, "merge", "-o", "out.po", "default.pot", "de/default.po"); r.code != 0 || r.stdout != "" {
+		t.Fatalf("Expected success but got '%s%s'", r.stdout, r.stderr)
+	}
+	merged, err := ioutil.ReadFile(filepath.Join(dir, "out.po"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := runCommand(t, dir, "merge", "-U", "default.pot", "de/default.po"); r.code != 0 || r.stdout != "" {
+		t.Fatalf("Expected success but got '%s%s'", r.stdout, r.stderr)
+	}
+	updated, err := ioutil.ReadFile(filepath.Join(dir, "de", "default.po"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(updated) != string(merged) {
+		t.Errorf("Expected '%s' but got '%s'", merged, updated)
+	}
+}
@@ -0,0 +1,360 @@
+package command
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/leonelquinteros/gotext/cli/xgotext/parser"
+)
+
+// extractConfig is the "extract" section of the project configuration. The flags of the extract command take precedence
+// over it.
+type extractConfig struct {
+	// Out is the output directory of the templates, relative to the module in split monorepo extractions.
+	Out string `json:"out"`
+	// Default is the name of the default domain.
+	Default string `json:"default"`
+	// Exclude lists the directories to exclude, relative to the input directory or the module.
+	Exclude []string `json:"exclude"`
+	// RefRoot is the directory the source references are relative to, "module" for the root of the Go module.
+	RefRoot string `json:"ref_root"`
+	// RefPrefix is prepended to the source references.
+	RefPrefix string `json:"ref_prefix"`
+	// DomainOut maps domains to their own output directories, relative to the module in split monorepo extractions.
+	DomainOut map[string]string `json:"domain_out"`
+	// Header sets fields of the header of the templates, i.e. "Project-Id-Version".
+	Header map[string]string `json:"header"`
+	// Banner is the prologue of the templates, none when unset.
+	Banner *bannerConfig `json:"banner"`
+	// Policy checks the extracted strings, none when unset.
+	Policy *policyConfig `json:"policy"`
+	// Calls are extracted in addition to the gotext getters.
+	Calls []callPattern `json:"calls"`
+	// Manifests are JSON files listing calls to extract, relative to the current directory.
+	Manifests []string `json:"manifests"`
+}
+
+// override returns the configuration with the fields set in o replacing its own.
+func (c extractConfig) override(o extractConfig) extractConfig {
+	if o.Out != "" {
+		c.Out = o.Out
+	}
+	if o.Default != "" {
+		c.Default = o.Default
+	}
+	if o.Exclude != nil {
+		c.Exclude = o.Exclude
+	}
+	if o.RefRoot != "" {
+		c.RefRoot = o.RefRoot
+	}
+	if o.RefPrefix != "" {
+		c.RefPrefix = o.RefPrefix
+	}
+	if o.DomainOut != nil {
+		c.DomainOut = o.DomainOut
+	}
+	if o.Header != nil {
+		// Fields are set over the ones of the overridden configuration.
+		header := make(map[string]string, len(c.Header)+len(o.Header))
+		for name, value := range c.Header {
+			header[name] = value
+		}
+		for name, value := range o.Header {
+			header[name] = value
+		}
+		c.Header = header
+	}
+	if o.Banner != nil {
+		c.Banner = c.Banner.override(o.Banner)
+	}
+	if o.Policy != nil {
+		c.Policy = o.Policy
+	}
+	if o.Calls != nil {
+		c.Calls = o.Calls
+	}
+	if o.Manifests != nil {
+		c.Manifests = o.Manifests
+	}
+
+	return c
+}
+
+// headerFields returns the header fields of the configuration, sorted by name.
+func (c extractConfig) headerFields() []parser.HeaderField {
+	fields := make([]parser.HeaderField, 0, len(c.Header))
+	for name, value := range c.Header {
+		fields = append(fields, parser.HeaderField{Name: name, Value: value})
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+
+	return fields
+}
+
+// bannerConfig is the prologue of the templates, the standard one of GNU gettext. Its placeholders are kept unless
+// configured.
+type bannerConfig struct {
+	// Title is the description of the templates.
+	Title string `json:"title"`
+	// Copyright lists the holders of the copyright, with its years.
+	Copyright []string `json:"copyright"`
+	// Package is the name of the package whose license the templates are distributed under.
+	Package string `json:"package"`
+	// Authors lists the authors of the templates, with their email address and years.
+	Authors []string `json:"authors"`
+}
+
+// override returns the banner with the fields set in o replacing its own.
+func (b *bannerConfig) override(o *bannerConfig) *bannerConfig {
+	if b == nil {
+		return o
+	}
+
+	c := *b
+	if o.Title != "" {
+		c.Title = o.Title
+	}
+	if o.Copyright != nil {
+		c.Copyright = o.Copyright
+	}
+	if o.Package != "" {
+		c.Package = o.Package
+	}
+	if o.Authors != nil {
+		c.Authors = o.Authors
+	}
+
+	return &c
+}
+
+// lines returns the lines of the banner, with the placeholders of GNU xgettext for the fields not set.
+func (b *bannerConfig) lines() []string {
+	if b == nil {
+		return nil
+	}
+
+	title, copyright, pkg, authors := b.Title, b.Copyright, b.Package, b.Authors
+	if title == "" {
+		title = "SOME DESCRIPTIVE TITLE."
+	}
+	if len(copyright) == 0 {
+		copyright = []string{"YEAR THE PACKAGE'S COPYRIGHT HOLDER"}
+	}
+	if pkg == "" {
+		pkg = "PACKAGE"
+	}
+	if len(authors) == 0 {
+		authors = []string{"FIRST AUTHOR <EMAIL@ADDRESS>, YEAR."}
+	}
+
+	lines := []string{title}
+	for _, holder := range copyright {
+		lines = append(lines, "Copyright (C) "+holder)
+	}
+	lines = append(lines, "This file is distributed under the same license as the "+pkg+" package.")
+	lines = append(lines, authors...)
+
+	return append(lines, "")
+}
+
+// headerFlag is the -header flag of the extract command, which can be given several times.
+type headerFlag map[string]string
+
+func (h headerFlag) String() string {
+	return ""
+}
+
+func (h headerFlag) Set(field string) error {
+	i := strings.Index(field, ":")
+	if i <= 0 {
+		return fmt.Errorf("%q is not a \"Name: value\" header field", field)
+	}
+	h[strings.TrimSpace(field[:i])] = strings.TrimSpace(field[i+1:])
+
+	return nil
+}
+
+// refRootModule is the -ref-root value making source references relative to the root of the Go module.
+const refRootModule = "module"
+
+// referencePrefix returns the prefix of the source references of the strings of dir, which are relative to it, making
+// them relative to the root directory instead, prefixed with prefix. The root is dir when empty.
+func referencePrefix(dir, root, prefix string) (string, error) {
+	if root == "" {
+		return prefix, nil
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if root == refRootModule {
+		if root, err = moduleRoot(dir); err != nil {
+			return "", err
+		}
+	} else if root, err = filepath.Abs(root); err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is not under the reference root %s", dir, root)
+	}
+	if rel == "." {
+		return prefix, nil
+	}
+
+	return prefix + filepath.ToSlash(rel) + "/", nil
+}
+
+// moduleRoot returns the root directory of the Go module holding the absolute directory dir.
+func moduleRoot(dir string) (string, error) {
+	for d := dir; ; {
+		if _, err := os.Stat(filepath.Join(d, "go.mod")); err == nil {
+			return d, nil
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return "", fmt.Errorf("no go.mod found above %s", dir)
+		}
+		d = parent
+	}
+}
+
+// prefixLocations prepends prefix to the source locations of the strings.
+func prefixLocations(data *parser.DomainMap, prefix string) {
+	if prefix != "" {
+		data.MapLocations(func(location string) string {
+			return prefix + location
+		})
+	}
+}
+
+// Modes of the -modules flag of the extract command.
+const (
+	modulesSplit  = "split"
+	modulesMerged = "merged"
+)
+
+// moduleConfig returns the extraction configuration of the module in dir: the one of its own configuration file, if
+// any, over the shared one in base, under the flags given on the command line.
+func moduleConfig(dir string, base, flags extractConfig) (extractConfig, error) {
+	file := filepath.Join(dir, defaultConfigFile)
+	if _, err := os.Stat(file); err != nil {
+		return base.override(flags), nil
+	}
+
+	conf, err := loadConfig(file)
+	if err != nil {
+		return extractConfig{}, err
+	}
+
+	return base.override(conf.Extract).override(flags), nil
+}
+
+// extractModules extracts the strings of each Go module found under dirName with its own configuration. Split templates
+// are written per module, to its output directory, with source locations relative to the module. Merged templates are
+// written to the shared output directory, with source locations relative to dirName.
+// The strings are checked by the policy of each module, or of the shared configuration when merged, returning the number
+// of issues printed.
+func extractModules(dirName, mode string, base, flags extractConfig, verbose bool) (int, error) {
+	shared := base.override(flags)
+	modules, err := parser.FindModules(dirName, shared.Exclude)
+	if err != nil {
+		return 0, err
+	}
+	if len(modules) == 0 {
+		return 0, fmt.Errorf("no go.mod found under %s", dirName)
+	}
+	if mode == modulesMerged && shared.Out == "" {
+		return 0, fmt.Errorf("no output directory given")
+	}
+
+	var policyIssues int
+	merged := &parser.DomainMap{Header: shared.headerFields(), Banner: shared.Banner.lines(), OutputDirs: shared.DomainOut}
+	for _, dir := range modules {
+		conf, err := moduleConfig(dir, base, flags)
+		if err != nil {
+			return 0, err
+		}
+		if verbose {
+			log.Printf("module %s", dir)
+		}
+		if err = setCallPatterns(conf); err != nil {
+			return 0, err
+		}
+
+		if mode == modulesMerged {
+			// Strings without domain go to the default one of their module.
+			merged.Default = conf.Default
+			if err = parser.ParseModule(dir, dirName, conf.Exclude, merged, verbose); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		if conf.Out == "" {
+			return 0, fmt.Errorf("no output directory configured for module %s", dir)
+		}
+		out := moduleDir(dir, conf.Out)
+		outputDirs := make(map[string]string, len(conf.DomainOut))
+		for domain, domainOut := range conf.DomainOut {
+			outputDirs[domain] = moduleDir(dir, domainOut)
+		}
+
+		prefix, err := referencePrefix(dir, conf.RefRoot, conf.RefPrefix)
+		if err != nil {
+			return 0, err
+		}
+		data := &parser.DomainMap{
+			Default:    conf.Default,
+			Header:     conf.headerFields(),
+			Banner:     conf.Banner.lines(),
+			OutputDirs: outputDirs,
+		}
+		if err = parser.ParseModule(dir, dir, conf.Exclude, data, verbose); err != nil {
+			return 0, err
+		}
+		issues := conf.Policy.check(dir, data)
+		printPolicyIssues(issues)
+		policyIssues += len(issues)
+
+		prefixLocations(data, prefix)
+		if err = data.Save(out); err != nil {
+			return 0, err
+		}
+	}
+
+	if mode == modulesMerged {
+		prefix, err := referencePrefix(dirName, shared.RefRoot, shared.RefPrefix)
+		if err != nil {
+			return 0, err
+		}
+		issues := shared.Policy.check(dirName, merged)
+		printPolicyIssues(issues)
+		policyIssues += len(issues)
+
+		prefixLocations(merged, prefix)
+		if err = merged.Save(shared.Out); err != nil {
+			return 0, err
+		}
+	}
+
+	return policyIssues, nil
+}
+
+// moduleDir returns the path of the directory relative to the module in dir, unless absolute.
+func moduleDir(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
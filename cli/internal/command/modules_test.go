@@ -0,0 +1,479 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/leonelquinteros/gotext/cli/xgotext/parser"
+)
+
+// monorepoFixture is a module holding a nested one with its own configuration, both calling the gotext getters.
+var monorepoFixture = map[string]string{
+	"go.mod": goMod("example.com/mono"),
+	"main.go": `package main
+
+import "github.com/leonelquinteros/gotext"
+
+func main() {
+	gotext.Get("Hello")
+}
+`,
+	"services/api/go.mod":       goMod("example.com/api"),
+	"services/api/xgotext.json": `{"extract": {"default": "api"}}`,
+	"services/api/main.go": `package main
+
+import "github.com/leonelquinteros/gotext"
+
+func main() {
+	gotext.Get("Hello")
+	gotext.Get("Not found")
+}
+`,
+}
+
+// checkReferences checks the source references of the strings of the template.
+func checkReferences(t *testing.T, template string, expected map[string]string) {
+	refs := readReferences(t, template)
+	for id, ref := range expected {
+		if refs[id] != ref {
+			t.Errorf("%s: Expected '%s' for '%s' but got '%s'", template, ref, id, refs[id])
+		}
+	}
+	if len(refs) != len(expected) {
+		t.Errorf("%s: Expected %d strings but got %v", template, len(expected), refs)
+	}
+}
+
+func TestExtractModulesSplit(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_extract_split"
+	writeFixture(t, dir, monorepoFixture)
+	defer os.RemoveAll(dir)
+
+	if r := runCommand(t, dir, "extract", "-in", ".", "-modules", "split", "-out", "i18n"); r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+
+	// Each module has its own templates, with references relative to it
+	checkReferences(t, filepath.Join(dir, "i18n", "default.pot"), map[string]string{"Hello": "main.go:6"})
+	checkReferences(t, filepath.Join(dir, "services", "api", "i18n", "api.pot"), map[string]string{
+		"Hello":     "main.go:6",
+		"Not found": "main.go:7",
+	})
+	if _, err := os.Stat(filepath.Join(dir, "i18n", "api.pot")); !os.IsNotExist(err) {
+		t.Errorf("Expected no template of the nested module in the root one but got '%v'", err)
+	}
+}
+
+func TestExtractModulesMerged(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_extract_merged"
+	writeFixture(t, dir, monorepoFixture)
+	defer os.RemoveAll(dir)
+
+	if r := runCommand(t, dir, "extract", "-in", ".", "-modules", "merged", "-out", "i18n"); r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+
+	// Strings go to the default domain of their module, with references relative to the input dir
+	checkReferences(t, filepath.Join(dir, "i18n", "default.pot"), map[string]string{"Hello": "main.go:6"})
+	checkReferences(t, filepath.Join(dir, "i18n", "api.pot"), map[string]string{
+		"Hello":     "services/api/main.go:6",
+		"Not found": "services/api/main.go:7",
+	})
+}
+
+func TestExtractModulesErrors(t *testing.T) {
+	dir := "/tmp/gotext_extract_modules_errors"
+	writeFixture(t, dir, map[string]string{"pkg/pkg.go": "package pkg\n"})
+	defer os.RemoveAll(dir)
+
+	for _, test := range []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-in", ".", "-modules", "all"}, `Unknown -modules mode "all"`},
+		{[]string{"-in", ".", "-modules", "split", "-since", "HEAD"}, "-modules can't be used with"},
+		{[]string{"-modules", "split"}, "No input directory given"},
+		{[]string{"-in", ".", "-modules", "split", "-out", "i18n"}, "no go.mod found under ."},
+	} {
+		r := runCommand(t, dir, append([]string{"extract"}, test.args...)...)
+		if r.code == 0 || !strings.Contains(r.stderr, test.expected) {
+			t.Errorf("Expected '%s' for %v but got '%s'", test.expected, test.args, r.stderr)
+		}
+	}
+
+	// Split modules need an output directory, and merged ones a shared one
+	writeFixture(t, dir, map[string]string{"go.mod": "module example.com/empty\n"})
+	for _, mode := range []string{"split", "merged"} {
+		r := runCommand(t, dir, "extract", "-in", ".", "-modules", mode)
+		if r.code == 0 || !strings.Contains(r.stderr, "output directory") {
+			t.Errorf("Expected a missing output directory error for %s modules but got '%s'", mode, r.stderr)
+		}
+	}
+}
+
+func TestReferencePrefix(t *testing.T) {
+	dir := "/tmp/gotext_reference_prefix"
+	writeFixture(t, dir, map[string]string{
+		"go.mod":              "module example.com/app\n",
+		"internal/web/web.go": "package web\n",
+	})
+	defer os.RemoveAll(dir)
+	web := filepath.Join(dir, "internal", "web")
+
+	for _, test := range []struct {
+		root, prefix, expected string
+	}{
+		{"", "", ""},
+		{"", "backend/", "backend/"},
+		{"module", "", "internal/web/"},
+		{"module", "backend/", "backend/internal/web/"},
+		{filepath.Join(dir, "internal"), "", "web/"},
+		{web, "backend/", "backend/"},
+	} {
+		prefix, err := referencePrefix(web, test.root, test.prefix)
+		if err != nil {
+			t.Error(err)
+		} else if prefix != test.expected {
+			t.Errorf("Expected '%s' for the root '%s' but got '%s'", test.expected, test.root, prefix)
+		}
+	}
+
+	if _, err := referencePrefix(dir, web, ""); err == nil {
+		t.Error("Expected an error for a directory outside of the reference root")
+	}
+	if _, err := referencePrefix("/tmp", "module", ""); err == nil {
+		t.Error("Expected an error for a directory outside of Go modules")
+	}
+}
+
+func TestExtractReferences(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_extract_references"
+	writeGitFixture(t, dir, map[string]string{
+		"go.mod": goMod("example.com/app"),
+		"internal/web/web.go": `package web
+
+import "github.com/leonelquinteros/gotext"
+
+var Title = gotext.Get("Hello")
+`,
+	})
+	defer os.RemoveAll(dir)
+	web := filepath.Join(dir, "internal", "web")
+	template := filepath.Join(dir, "locales", "default.pot")
+
+	// References are relative to the module wherever the tool is run from
+	r := runCommand(t, web, "extract", "-in", ".", "-out", "../../locales", "-ref-root", "module", "-ref-prefix", "backend/")
+	if r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	checkReferences(t, template, map[string]string{"Hello": "backend/internal/web/web.go:5"})
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "templates")
+
+	// The prefix is kept when updating the templates
+	writeFile(t, dir, "internal/web/web.go", "package web\n\nimport \"github.com/leonelquinteros/gotext\"\n\n"+
+		"var Title = gotext.Get(\"Welcome\")\n")
+	r = runCommand(t, dir, "extract", "-in", "internal/web", "-out", "locales", "-ref-root", "module", "-ref-prefix", "backend/", "-since", "HEAD")
+	if r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	checkReferences(t, template, map[string]string{"Welcome": "backend/internal/web/web.go:5"})
+}
+
+func TestModuleConfig(t *testing.T) {
+	dir := "/tmp/gotext_module_config"
+	writeFixture(t, dir, map[string]string{
+		"api/xgotext.json": `{"extract": {"out": "locales", "default": "api", "domain_out": {"admin": "admin/locales"}}}`,
+		"web/web.go":       "package web\n",
+		"bad/xgotext.json": `{"extract": `,
+	})
+	defer os.RemoveAll(dir)
+
+	base := extractConfig{Out: "i18n", Default: "default", Exclude: []string{".git"}}
+	flags := extractConfig{Default: "app"}
+
+	// The configuration of the module overrides the shared one, under the flags
+	conf, err := moduleConfig(filepath.Join(dir, "api"), base, flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Out != "locales" || conf.Default != "app" || conf.DomainOut["admin"] != "admin/locales" || len(conf.Exclude) != 1 {
+		t.Errorf("Expected the module configuration under the flags but got %+v", conf)
+	}
+
+	conf, err = moduleConfig(filepath.Join(dir, "web"), base, flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Out != "i18n" || conf.Default != "app" || conf.DomainOut != nil {
+		t.Errorf("Expected the shared configuration under the flags but got %+v", conf)
+	}
+
+	if _, err = moduleConfig(filepath.Join(dir, "bad"), base, flags); err == nil {
+		t.Error("Expected an error for an invalid module configuration")
+	}
+
+	if got := moduleDir(dir, "locales"); got != filepath.Join(dir, "locales") {
+		t.Errorf("Expected '%s' but got '%s'", filepath.Join(dir, "locales"), got)
+	}
+	if got := moduleDir(dir, "/srv/locales"); got != "/srv/locales" {
+		t.Errorf("Expected '/srv/locales' but got '%s'", got)
+	}
+}
+
+func TestExtractModulesDomainOut(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_extract_domain_out"
+	files := map[string]string{
+		"services/api/xgotext.json": `{"extract": {"out": "locales", "domain_out": {"admin": "admin/locales"}}}`,
+		"services/api/admin/admin.go": `package admin
+
+import "github.com/leonelquinteros/gotext"
+
+var Title = gotext.GetD("admin", "Dashboard")
+`,
+	}
+	for name, content := range monorepoFixture {
+		if name != "services/api/xgotext.json" {
+			files[name] = content
+		}
+	}
+	writeFixture(t, dir, files)
+	defer os.RemoveAll(dir)
+
+	// Output directories are relative to the module, the flags applying to all of them
+	if r := runCommand(t, dir, "extract", "-in", ".", "-modules", "split", "-default", "app"); r.code == 0 || !strings.Contains(r.stderr, "no output directory configured for module .") {
+		t.Fatalf("Expected an error for the root module without output directory but got '%s'", r.stderr)
+	}
+	writeFile(t, dir, "xgotext.json", `{"extract": {"out": "i18n"}}`)
+	if r := runCommand(t, dir, "extract", "-in", ".", "-modules", "split", "-default", "app"); r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+
+	checkReferences(t, filepath.Join(dir, "i18n", "app.pot"), map[string]string{"Hello": "main.go:6"})
+	api := filepath.Join(dir, "services", "api")
+	checkReferences(t, filepath.Join(api, "locales", "app.pot"), map[string]string{
+		"Hello":     "main.go:6",
+		"Not found": "main.go:7",
+	})
+	checkReferences(t, filepath.Join(api, "admin", "locales", "admin.pot"), map[string]string{"Dashboard": "admin/admin.go:5"})
+}
+
+func TestExtractDomainOut(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_extract_domain_out_single"
+	writeGitFixture(t, dir, map[string]string{
+		"go.mod":       goMod("example.com/app"),
+		"xgotext.json": `{"extract": {"domain_out": {"admin": "admin/locales"}}}`,
+		"main.go": `package main
+
+import "github.com/leonelquinteros/gotext"
+
+func main() {
+	gotext.Get("Hello")
+	gotext.GetD("admin", "Dashboard")
+}
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	if r := runCommand(t, dir, "extract", "-in", ".", "-out", "i18n"); r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	admin := filepath.Join(dir, "admin", "locales", "admin.pot")
+	checkReferences(t, admin, map[string]string{"Dashboard": "main.go:7"})
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "templates")
+
+	// Mapped domains are updated in their output directory
+	writeFile(t, dir, "main.go", "package main\n\nimport \"github.com/leonelquinteros/gotext\"\n\n"+
+		"func main() {\n\tgotext.Get(\"Hello\")\n\tgotext.GetD(\"admin\", \"Users\")\n}\n")
+	if r := runCommand(t, dir, "extract", "-in", ".", "-out", "i18n", "-since", "HEAD"); r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	checkReferences(t, admin, map[string]string{"Users": "main.go:7"})
+	if _, err := os.Stat(filepath.Join(dir, "i18n", "admin.pot")); !os.IsNotExist(err) {
+		t.Errorf("Expected no admin domain in the output directory but got '%v'", err)
+	}
+}
+
+func TestHeaderFlag(t *testing.T) {
+	h := make(headerFlag)
+	for _, field := range []string{"Project-Id-Version: shop 1.2", " X-Team :web ", "Report-Msgid-Bugs-To: https://example.com/bugs"} {
+		if err := h.Set(field); err != nil {
+			t.Error(err)
+		}
+	}
+	expected := headerFlag{"Project-Id-Version": "shop 1.2", "X-Team": "web", "Report-Msgid-Bugs-To": "https://example.com/bugs"}
+	if len(h) != len(expected) {
+		t.Errorf("Expected %v but got %v", expected, h)
+	}
+	for name, value := range expected {
+		if h[name] != value {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", value, name, h[name])
+		}
+	}
+
+	for _, field := range []string{"Project-Id-Version", ": value"} {
+		if err := h.Set(field); err == nil {
+			t.Errorf("Expected an error for '%s'", field)
+		}
+	}
+}
+
+func TestExtractConfigHeader(t *testing.T) {
+	base := extractConfig{Header: map[string]string{"Project-Id-Version": "shop", "X-Team": "web"}}
+	conf := base.override(extractConfig{Header: map[string]string{"Project-Id-Version": "shop 1.2", "Language-Team": "French"}})
+
+	// Fields are set over the ones of the overridden configuration, which is left unchanged
+	fields := conf.headerFields()
+	expected := []parser.HeaderField{
+		{Name: "Language-Team", Value: "French"},
+		{Name: "Project-Id-Version", Value: "shop 1.2"},
+		{Name: "X-Team", Value: "web"},
+	}
+	if fmt.Sprint(fields) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v but got %v", expected, fields)
+	}
+	if base.Header["Project-Id-Version"] != "shop" {
+		t.Errorf("Expected the base configuration to be unchanged but got %v", base.Header)
+	}
+	if conf := base.override(extractConfig{}); len(conf.Header) != 2 {
+		t.Errorf("Expected the base header but got %v", conf.Header)
+	}
+}
+
+func TestExtractHeader(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_extract_header"
+	files := map[string]string{
+		"xgotext.json":              `{"extract": {"header": {"Project-Id-Version": "shop", "X-Team": "web"}}}`,
+		"services/api/xgotext.json": `{"extract": {"header": {"X-Team": "api"}}}`,
+	}
+	for name, content := range monorepoFixture {
+		if name != "services/api/xgotext.json" {
+			files[name] = content
+		}
+	}
+	writeFixture(t, dir, files)
+	defer os.RemoveAll(dir)
+
+	// The flags override the configured fields, and modules set theirs over the shared ones
+	r := runCommand(t, dir, "extract", "-in", ".", "-out", "i18n", "-modules", "split", "-header", "Project-Id-Version: shop 1.2")
+	if r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	for _, test := range []struct {
+		template string
+		expected map[string]string
+	}{
+		{filepath.Join(dir, "i18n", "default.pot"), map[string]string{"Project-Id-Version": "shop 1.2", "X-Team": "web"}},
+		{filepath.Join(dir, "services", "api", "i18n", "default.pot"), map[string]string{"Project-Id-Version": "shop 1.2", "X-Team": "api"}},
+	} {
+		data, err := ioutil.ReadFile(test.template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := gotext.ParsePoFile(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, value := range test.expected {
+			if got := f.Header().HeaderValue(name); got != value {
+				t.Errorf("%s: Expected '%s' for '%s' but got '%s'", test.template, value, name, got)
+			}
+		}
+	}
+
+	r = runCommand(t, dir, "extract", "-in", ".", "-out", "i18n", "-header", "Project-Id-Version")
+	if r.code == 0 || !strings.Contains(r.stderr, "is not a \"Name: value\" header field") {
+		t.Errorf("Expected an error for the invalid header field but got '%s'", r.stderr)
+	}
+}
+
+func TestBannerLines(t *testing.T) {
+	var none *bannerConfig
+	if lines := none.lines(); lines != nil {
+		t.Errorf("Expected no banner but got %q", lines)
+	}
+
+	// Fields not set keep the placeholders of GNU xgettext
+	expected := []string{
+		"SOME DESCRIPTIVE TITLE.",
+		"Copyright (C) YEAR THE PACKAGE'S COPYRIGHT HOLDER",
+		"This file is distributed under the same license as the PACKAGE package.",
+		"FIRST AUTHOR <EMAIL@ADDRESS>, YEAR.",
+		"",
+	}
+	if lines := new(bannerConfig).lines(); fmt.Sprint(lines) != fmt.Sprint(expected) {
+		t.Errorf("Expected %q but got %q", expected, lines)
+	}
+
+	b := &bannerConfig{Title: "Shop translations.", Copyright: []string{"2023 ACME", "2024 Shop Inc."}, Package: "shop"}
+	expected = []string{
+		"Shop translations.",
+		"Copyright (C) 2023 ACME",
+		"Copyright (C) 2024 Shop Inc.",
+		"This file is distributed under the same license as the shop package.",
+		"FIRST AUTHOR <EMAIL@ADDRESS>, YEAR.",
+		"",
+	}
+	if lines := b.lines(); fmt.Sprint(lines) != fmt.Sprint(expected) {
+		t.Errorf("Expected %q but got %q", expected, lines)
+	}
+
+	// Overrides set their fields over the banner, which is left unchanged
+	o := b.override(&bannerConfig{Package: "api", Authors: []string{"Jane Doe <jane@example.com>, 2024."}})
+	if o.Title != b.Title || o.Package != "api" || len(o.Authors) != 1 || b.Package != "shop" {
+		t.Errorf("Expected the banner with the package and authors overridden but got %+v", o)
+	}
+	if o := none.override(b); o != b {
+		t.Errorf("Expected the overriding banner but got %+v", o)
+	}
+}
+
+func TestExtractBanner(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_extract_banner"
+	files := map[string]string{
+		"xgotext.json": `{"extract": {"banner": {"title": "Shop translations.", "package": "shop"}}}`,
+	}
+	for name, content := range appFixture {
+		files[name] = content
+	}
+	writeFixture(t, dir, files)
+	defer os.RemoveAll(dir)
+
+	prologue := "# Shop translations.\n# Copyright (C) YEAR THE PACKAGE'S COPYRIGHT HOLDER\n" +
+		"# This file is distributed under the same license as the shop package.\n# FIRST AUTHOR <EMAIL@ADDRESS>, YEAR.\n#\n#, fuzzy\nmsgid \"\"\n"
+	if r := runCommand(t, dir, "extract", "-in", ".", "-out", "i18n"); r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	if content, _ := ioutil.ReadFile(filepath.Join(dir, "i18n", "default.pot")); !strings.HasPrefix(string(content), prologue) {
+		t.Errorf("Expected the configured banner but got '%s'", content)
+	}
+
+	// The -banner flag writes the placeholders without configuration
+	os.Remove(filepath.Join(dir, "xgotext.json"))
+	if r := runCommand(t, dir, "extract", "-in", ".", "-out", "locales", "-banner"); r.code != 0 {
+		t.Fatalf("Expected the extraction to succeed but got '%s'", r.stderr)
+	}
+	if content, _ := ioutil.ReadFile(filepath.Join(dir, "locales", "default.pot")); !strings.HasPrefix(string(content), "# SOME DESCRIPTIVE TITLE.\n") {
+		t.Errorf("Expected the placeholder banner but got '%s'", content)
+	}
+}
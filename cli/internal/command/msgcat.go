@@ -0,0 +1,72 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// printConflicts reports the translations dropped combining catalogs.
+func printConflicts(files []string, conflicts []gotext.PoConflict) {
+	for _, c := range conflicts {
+		id := fmt.Sprintf("msgid %q", c.Kept.ID)
+		if c.Kept.HasContext {
+			id += fmt.Sprintf(", msgctxt %q", c.Kept.Context)
+		}
+		fmt.Fprintf(os.Stderr, "%s: %s: translation %q conflicts with %q\n",
+			files[c.File], id, strings.Join(c.Dropped.Str, " | "), strings.Join(c.Kept.Str, " | "))
+	}
+}
+
+// msgcat runs the msgcat command, combining catalogs into one as GNU msgcat does.
+func msgcat(args []string) {
+	fs := newFlagSet("msgcat", "[flags] file.po ...")
+	out := fs.String("o", "", "output file: /path/to/default.po (default standard output)")
+	duplicates := fs.String("duplicates", "use-first", "handling of duplicates with different translations: \"use-first\", \"require-identical\" or \"report\"")
+	sortBy := fs.String("sort", "", "sort entries: \"id\" or \"file\" (default keep their order)")
+	width := fs.Int("width", gotext.DefaultPoWidth, "width of the lines")
+	parseFlags(fs, args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	opts := gotext.FormatOptions{Width: *width, Sort: sortOrder(*sortBy)}
+
+	files := make([]*gotext.PoFile, fs.NArg())
+	for i, file := range fs.Args() {
+		f, err := readPoFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		files[i] = f
+	}
+
+	merged, conflicts := gotext.ConcatPo(files...)
+	switch *duplicates {
+	case "use-first":
+	case "require-identical":
+		if len(conflicts) > 0 {
+			printConflicts(fs.Args(), conflicts)
+			os.Exit(1)
+		}
+	case "report":
+		printConflicts(fs.Args(), conflicts)
+	default:
+		log.Fatalf("Unknown duplicates handling %q", *duplicates)
+	}
+
+	src := merged.Format(opts)
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
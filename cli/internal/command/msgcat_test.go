@@ -0,0 +1,61 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMsgcat(t *testing.T) {
+	dir := "/tmp/gotext_msgcat"
+	writeFixture(t, dir, map[string]string{
+		"web.po": "msgid \"Zebra\"\nmsgstr \"Zebra\"\n\nmsgid \"Hello\"\nmsgstr \"Hallo\"\n",
+		"api.po": "msgid \"Bye\"\nmsgstr \"Tschüss\"\n\nmsgid \"Hello\"\nmsgstr \"Servus\"\n",
+		"cli.po": "msgid \"Hello\"\nmsgstr \"Hallo\"\n\nmsgid \"Apple\"\nmsgstr \"Apfel\"\n",
+	})
+	defer os.RemoveAll(dir)
+
+	// Entries are kept in order, the first translation of duplicates winning
+	r := runCommand(t, dir, "msgcat", "web.po", "api.po")
+	if expected := "Zebra Hello Bye"; r.code != 0 || msgids(r.stdout) != expected || r.stderr != "" {
+		t.Errorf("Expected '%s' but got '%s%s'", expected, r.stdout, r.stderr)
+	}
+	if !strings.Contains(r.stdout, "msgid \"Hello\"\nmsgstr \"Hallo\"\n") {
+		t.Errorf("Expected the first translation of 'Hello' but got '%s'", r.stdout)
+	}
+	r = runCommand(t, dir, "msgcat", "-sort", "id", "web.po", "api.po", "cli.po")
+	if expected := "Apple Bye Hello Zebra"; r.code != 0 || msgids(r.stdout) != expected {
+		t.Errorf("Expected '%s' but got '%s%s'", expected, r.stdout, r.stderr)
+	}
+
+	// Conflicting translations are reported, or fail the command
+	conflict := "api.po: msgid \"Hello\": translation \"Servus\" conflicts with \"Hallo\"\n"
+	r = runCommand(t, dir, "msgcat", "-duplicates", "report", "web.po", "api.po", "cli.po")
+	if r.code != 0 || r.stderr != conflict || msgids(r.stdout) != "Zebra Hello Bye Apple" {
+		t.Errorf("Expected '%s' but got '%s%s'", conflict, r.stdout, r.stderr)
+	}
+	r = runCommand(t, dir, "msgcat", "-duplicates", "require-identical", "web.po", "api.po")
+	if r.code != 1 || r.stderr != conflict || r.stdout != "" {
+		t.Errorf("Expected '%s' but got %d '%s%s'", conflict, r.code, r.stdout, r.stderr)
+	}
+	if r := runCommand(t, dir, "msgcat", "-duplicates", "require-identical", "web.po", "cli.po"); r.code != 0 {
+		t.Errorf("Expected identical duplicates to pass but got '%s%s'", r.stdout, r.stderr)
+	}
+	if r := runCommand(t, dir, "msgcat", "-duplicates", "newest", "web.po"); r.code != 1 || !strings.Contains(r.stderr, "Unknown duplicates handling") {
+		t.Errorf("Expected an error but got %d '%s'", r.code, r.stderr)
+	}
+
+	// The catalog is written to the output file
+	if r := runCommand(t, dir, "msgcat", "-o", "all.po", "web.po", "cli.po"); r.code != 0 || r.stdout != "" {
+		t.Fatalf("Expected success but got '%s%s'", r.stdout, r.stderr)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "all.po"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Zebra Hello Apple"; msgids(string(data)) != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, data)
+	}
+}
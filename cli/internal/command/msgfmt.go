@@ -0,0 +1,64 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// checkCatalog validates a .po file with Po.Validate, printing its issues. It reports if there were none.
+func checkCatalog(file string, data []byte) bool {
+	po := gotext.NewPo()
+	po.Parse(data)
+
+	issues := po.Validate()
+	for _, issue := range issues {
+		fmt.Fprintf(os.Stderr, "%s:%s\n", file, issue)
+	}
+
+	return len(issues) == 0
+}
+
+// msgfmt runs the msgfmt command, compiling a .po file into a .mo file as GNU msgfmt does.
+func msgfmt(args []string) {
+	fs := newFlagSet("msgfmt", "[flags] file.po")
+	out := fs.String("o", "", "output file: /path/to/default.mo (default input file with .mo extension)")
+	check := fs.Bool("check", false, "validate the catalog, failing on plural forms, placeholders, escapes and duplicate entries issues")
+	useFuzzy := fs.Bool("use-fuzzy", false, "compile fuzzy entries too")
+	statistics := fs.Bool("statistics", false, "print the number of translated, fuzzy and untranslated messages")
+	parseFlags(fs, args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	in := fs.Arg(0)
+
+	data, err := ioutil.ReadFile(in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	f, err := gotext.ParsePoFile(data)
+	if err != nil {
+		log.Fatalf("%s:%v", in, err)
+	}
+	if *check && !checkCatalog(in, data) {
+		os.Exit(1)
+	}
+
+	if *statistics {
+		s := f.Stats()
+		fmt.Fprintf(os.Stderr, "%d translated messages, %d fuzzy translations, %d untranslated messages.\n", s.Translated, s.Fuzzy, s.Untranslated)
+	}
+
+	if *out == "" {
+		*out = strings.TrimSuffix(in, ".po") + ".mo"
+	}
+	if err = ioutil.WriteFile(*out, f.MarshalMo(*useFuzzy), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,83 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+const msgfmtCatalog = `msgid ""
+msgstr ""
+"Language: de\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Hello"
+msgstr "Hallo"
+
+#, fuzzy
+msgid "Bye"
+msgstr "Tschüss"
+
+msgid "Open"
+msgstr ""
+`
+
+func TestMsgfmt(t *testing.T) {
+	dir := "/tmp/gotext_msgfmt"
+	writeFixture(t, dir, map[string]string{
+		"de/default.po": msgfmtCatalog,
+		"de/broken.po":  msgfmtCatalog + "\nmsgid \"%d files\"\nmsgstr \"Dateien\"\n",
+	})
+	defer os.RemoveAll(dir)
+
+	// The .mo file is written next to the catalog, without fuzzy entries
+	if r := runCommand(t, dir, "msgfmt", "de/default.po"); r.code != 0 {
+		t.Fatalf("Expected success but got '%s%s'", r.stdout, r.stderr)
+	}
+	mo := gotext.NewMo()
+	mo.ParseFile(filepath.Join(dir, "de", "default.mo"))
+	for _, test := range []struct{ expected, got string }{
+		{"Hallo", mo.Get("Hello")},
+		{"Bye", mo.Get("Bye")},
+		{"Open", mo.Get("Open")},
+	} {
+		if test.expected != test.got {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.got)
+		}
+	}
+
+	// Fuzzy entries are compiled on demand
+	if r := runCommand(t, dir, "msgfmt", "-use-fuzzy", "-o", "fuzzy.mo", "de/default.po"); r.code != 0 {
+		t.Fatalf("Expected success but got '%s%s'", r.stdout, r.stderr)
+	}
+	mo = gotext.NewMo()
+	mo.ParseFile(filepath.Join(dir, "fuzzy.mo"))
+	if tr := mo.Get("Bye"); tr != "Tschüss" {
+		t.Errorf("Expected 'Tschüss' but got '%s'", tr)
+	}
+
+	r := runCommand(t, dir, "msgfmt", "-statistics", "-o", "stats.mo", "de/default.po")
+	if expected := "1 translated messages, 1 fuzzy translations, 1 untranslated messages.\n"; r.code != 0 || r.stderr != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, r.stderr)
+	}
+
+	// Invalid catalogs fail the check and aren't compiled
+	if r := runCommand(t, dir, "msgfmt", "-check", "de/default.po"); r.code != 0 || r.stderr != "" {
+		t.Errorf("Expected the catalog to be valid but got '%s'", r.stderr)
+	}
+	r = runCommand(t, dir, "msgfmt", "-check", "de/broken.po")
+	if r.code != 1 || !strings.Contains(r.stderr, "de/broken.po:") || !strings.Contains(r.stderr, "placeholders") {
+		t.Errorf("Expected a placeholders issue but got %d '%s'", r.code, r.stderr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "de", "broken.mo")); !os.IsNotExist(err) {
+		t.Errorf("Expected no .mo file for the invalid catalog but got %v", err)
+	}
+	// Unchecked, it's compiled as it is
+	if r := runCommand(t, dir, "msgfmt", "de/broken.po"); r.code != 0 {
+		t.Errorf("Expected success but got '%s%s'", r.stdout, r.stderr)
+	}
+}
@@ -0,0 +1,48 @@
+package command
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// msgunfmt runs the msgunfmt command, decompiling a .mo file into a .po file as GNU msgunfmt does.
+func msgunfmt(args []string) {
+	fs := newFlagSet("msgunfmt", "[flags] file.mo")
+	out := fs.String("o", "", "output file: /path/to/default.po (default standard output)")
+	width := fs.Int("width", gotext.DefaultPoWidth, "width of the lines")
+	noWrap := fs.Bool("nowrap", false, "do not wrap long strings, only split them after newlines")
+	sortByID := fs.Bool("sort", false, "sort entries by msgid (default keep the .mo file order)")
+	parseFlags(fs, args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	in := fs.Arg(0)
+
+	data, err := ioutil.ReadFile(in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	f, err := gotext.UnmarshalMo(data)
+	if err != nil {
+		log.Fatalf("%s: %v", in, err)
+	}
+
+	opts := gotext.FormatOptions{Width: *width, NoWrap: *noWrap}
+	if *sortByID {
+		opts.Sort = gotext.SortByID
+	}
+	src := f.Format(opts)
+
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err = ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,72 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+func TestMsgunfmt(t *testing.T) {
+	po, err := gotext.ParsePoFile([]byte(sortCatalog + `
+msgid "A long message that goes on and on"
+msgstr "Eine lange Nachricht, die immer weiter und weiter geht"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := "/tmp/gotext_msgunfmt"
+	writeFixture(t, dir, map[string]string{
+		"de/default.mo": string(po.MarshalMo(false)),
+	})
+	defer os.RemoveAll(dir)
+
+	// Entries are kept in the .mo file order, by key, unless sorted
+	r := runCommand(t, dir, "msgunfmt", "de/default.mo")
+	if expected := "A long message that goes on and on Apple Bye Zebra menu|Apple"; r.code != 0 || msgids(r.stdout) != expected {
+		t.Errorf("Expected '%s' but got '%s%s'", expected, r.stdout, r.stderr)
+	}
+	r = runCommand(t, dir, "msgunfmt", "-sort", "de/default.mo")
+	if expected := "A long message that goes on and on Apple menu|Apple Bye Zebra"; r.code != 0 || msgids(r.stdout) != expected {
+		t.Errorf("Expected '%s' but got '%s%s'", expected, r.stdout, r.stderr)
+	}
+	decompiled, err := gotext.ParsePoFile([]byte(r.stdout))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := decompiled.Find("menu", "Apple"); e == nil || e.Str[0] != "Apfel" {
+		t.Errorf("Expected the 'Apfel' translation but got %v", e)
+	}
+
+	// Long strings are wrapped at the width
+	long := `msgstr "Eine lange Nachricht, die immer weiter und weiter geht"`
+	r = runCommand(t, dir, "msgunfmt", "-width", "40", "de/default.mo")
+	if r.code != 0 || strings.Contains(r.stdout, long) || !strings.Contains(r.stdout, "msgstr \"\"\n\"Eine lange ") {
+		t.Errorf("Expected the msgstr to be wrapped but got '%s%s'", r.stdout, r.stderr)
+	}
+	r = runCommand(t, dir, "msgunfmt", "-width", "40", "-nowrap", "de/default.mo")
+	if r.code != 0 || !strings.Contains(r.stdout, long) {
+		t.Errorf("Expected the msgstr not to be wrapped but got '%s%s'", r.stdout, r.stderr)
+	}
+
+	// The catalog is written to the output file
+	if r := runCommand(t, dir, "msgunfmt", "-o", "de/default.po", "de/default.mo"); r.code != 0 || r.stdout != "" {
+		t.Fatalf("Expected success but got '%s%s'", r.stdout, r.stderr)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "de", "default.po"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "msgid \"Bye\"\nmsgstr \"Tschüss\"\n") {
+		t.Errorf("Expected the decompiled catalog but got '%s'", data)
+	}
+
+	// Other files aren't decompiled
+	if r := runCommand(t, dir, "msgunfmt", "de/default.po"); r.code != 1 || !strings.Contains(r.stderr, "de/default.po: ") {
+		t.Errorf("Expected an error but got %d '%s'", r.code, r.stderr)
+	}
+}
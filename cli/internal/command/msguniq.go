@@ -0,0 +1,50 @@
+package command
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// msguniq runs the msguniq command, collapsing the entries repeated in a catalog as GNU msguniq does.
+func msguniq(args []string) {
+	fs := newFlagSet("msguniq", "[flags] file.po")
+	out := fs.String("o", "", "output file: /path/to/default.po (default standard output)")
+	write := fs.Bool("w", false, "write result to (source) file instead of standard output")
+	report := fs.Bool("report", false, "print the duplicates with different translations, whose first translation is kept")
+	sortBy := fs.String("sort", "", "sort entries: \"id\" or \"file\" (default keep their order)")
+	width := fs.Int("width", gotext.DefaultPoWidth, "width of the lines")
+	parseFlags(fs, args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *write && *out != "" {
+		log.Fatal("Cannot use -w with -o")
+	}
+
+	f, err := readPoFile(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	unique, conflicts := gotext.ConcatPo(f)
+	if *report {
+		printConflicts(fs.Args(), conflicts)
+	}
+
+	src := unique.Format(gotext.FormatOptions{Width: *width, Sort: sortOrder(*sortBy)})
+	if *write {
+		*out = fs.Arg(0)
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err = ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
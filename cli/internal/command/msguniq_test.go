@@ -0,0 +1,79 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const duplicatesCatalog = `#: web.go:1
+msgid "Zebra"
+msgstr "Zebra"
+
+#: web.go:2
+msgid "Hello"
+msgstr "Hallo"
+
+#: api.go:1
+msgid "Bye"
+msgstr "Tschüss"
+
+#: api.go:2
+msgid "Hello"
+msgstr "Servus"
+
+#: cli.go:1
+msgid "Zebra"
+msgstr "Zebra"
+`
+
+func TestMsguniq(t *testing.T) {
+	dir := "/tmp/gotext_msguniq"
+	writeFixture(t, dir, map[string]string{
+		"de.po": duplicatesCatalog,
+	})
+	defer os.RemoveAll(dir)
+
+	// Duplicates are collapsed into their first occurrence, keeping its translation
+	r := runCommand(t, dir, "msguniq", "de.po")
+	if expected := "Zebra Hello Bye"; r.code != 0 || msgids(r.stdout) != expected || r.stderr != "" {
+		t.Errorf("Expected '%s' but got '%s%s'", expected, r.stdout, r.stderr)
+	}
+	if !strings.Contains(r.stdout, "msgid \"Hello\"\nmsgstr \"Hallo\"\n") {
+		t.Errorf("Expected the first translation of 'Hello' but got '%s'", r.stdout)
+	}
+	r = runCommand(t, dir, "msguniq", "-sort", "id", "de.po")
+	if expected := "Bye Hello Zebra"; r.code != 0 || msgids(r.stdout) != expected {
+		t.Errorf("Expected '%s' but got '%s%s'", expected, r.stdout, r.stderr)
+	}
+
+	// Conflicting translations are reported on demand
+	r = runCommand(t, dir, "msguniq", "-report", "de.po")
+	if expected := "de.po: msgid \"Hello\": translation \"Servus\" conflicts with \"Hallo\"\n"; r.code != 0 || r.stderr != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, r.stderr)
+	}
+
+	// The catalog is written to the output file, or to itself
+	if r := runCommand(t, dir, "msguniq", "-w", "-o", "out.po", "de.po"); r.code != 1 {
+		t.Errorf("Expected -w and -o to conflict but got '%s%s'", r.stdout, r.stderr)
+	}
+	if r := runCommand(t, dir, "msguniq", "-o", "out.po", "de.po"); r.code != 0 || r.stdout != "" {
+		t.Fatalf("Expected success but got '%s%s'", r.stdout, r.stderr)
+	}
+	unique, err := ioutil.ReadFile(filepath.Join(dir, "out.po"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := runCommand(t, dir, "msguniq", "-w", "de.po"); r.code != 0 || r.stdout != "" {
+		t.Fatalf("Expected success but got '%s%s'", r.stdout, r.stderr)
+	}
+	written, err := ioutil.ReadFile(filepath.Join(dir, "de.po"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(written) != string(unique) || msgids(string(written)) != "Zebra Hello Bye" {
+		t.Errorf("Expected '%s' but got '%s'", unique, written)
+	}
+}
@@ -0,0 +1,133 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/leonelquinteros/gotext/plurals"
+)
+
+// generateCatalog returns the Go source of a catalog compiled into a static map and plural function,
+// exposed as a gotext.Translator by the Translator function of the generated package.
+func generateCatalog(pkg string, enc *gotext.TranslatorEncoding) ([]byte, error) {
+	plural := enc.Plural
+	if plural == "" {
+		// Germanic plural rule, as used by catalogs without Plural-Forms header.
+		plural = "n != 1"
+	}
+	pluralSrc, err := plurals.GoSource(plural)
+	if err != nil {
+		return nil, fmt.Errorf("compiling plural forms %q: %v", plural, err)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by xgotext po2go; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import \"github.com/leonelquinteros/gotext\"\n\n")
+	fmt.Fprintf(&b, "// Language is the language of the catalog.\nconst Language = %q\n\n", enc.Language)
+	fmt.Fprintf(&b, "// PluralForms is the Plural-Forms header of the catalog.\nconst PluralForms = %q\n\n", enc.PluralForms)
+
+	b.WriteString("// entries are the catalog translations, indexed by msgid, or by context and msgid joined by \"\\x04\".\n")
+	b.WriteString("var entries = map[string]*gotext.Translation{\n")
+	for _, e := range sortedEntries(enc) {
+		key := e.ID
+		if e.ctx != "" {
+			key = e.ctx + "\x04" + e.ID
+		}
+
+		idx := make([]int, 0, len(e.Trs))
+		for i := range e.Trs {
+			idx = append(idx, i)
+		}
+		sort.Ints(idx)
+
+		fmt.Fprintf(&b, "\t%q: {ID: %q", key, e.ID)
+		if e.PluralID != "" {
+			fmt.Fprintf(&b, ", PluralID: %q", e.PluralID)
+		}
+		b.WriteString(", Trs: map[int]string{")
+		for i, n := range idx {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "%d: %q", n, e.Trs[n])
+		}
+		b.WriteString("}")
+		if e.Fuzzy {
+			b.WriteString(", Fuzzy: true")
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString(`// backend implements gotext.Backend with the compiled catalog.
+type backend struct{}
+
+func (backend) Lookup(ctx, id string) (*gotext.Translation, bool) {
+	if ctx != "" {
+		id = ctx + "\x04" + id
+	}
+	tr, ok := entries[id]
+	return tr, ok
+}
+
+func (backend) PluralForm(n int) int {
+	return pluralForm(uint32(n))
+}
+
+`)
+	fmt.Fprintf(&b, "// pluralForm is the compiled plural form expression %q.\n", plural)
+	fmt.Fprintf(&b, "func pluralForm(n uint32) int {\n%s}\n\n", pluralSrc)
+	b.WriteString(`// Translator returns the compiled catalog, to be added to a Locale with AddTranslator.
+func Translator() gotext.Translator {
+	return gotext.NewBackendTranslator(backend{})
+}
+`)
+
+	return format.Source(b.Bytes())
+}
+
+// po2go runs the po2go command, compiling a .po or .mo file into Go source
+// so translations are embedded in the binary without parsing at startup.
+func po2go(args []string) {
+	fs := newFlagSet("po2go", "[flags]")
+	in := fs.String("in", "", "input file: /path/to/default.po")
+	out := fs.String("out", "", "output file: /path/to/catalog.go (default standard output)")
+	pkg := fs.String("package", "", "package name of the generated code (default catalog language)")
+	parseFlags(fs, args)
+
+	if *in == "" {
+		log.Fatal("No input file given")
+	}
+
+	enc, err := readCatalog(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *pkg == "" {
+		*pkg = identifier(enc.Language, false)
+		if enc.Language == "" {
+			*pkg = identifier(filepath.Base(filepath.Dir(*in)), false)
+		}
+	}
+
+	src, err := generateCatalog(*pkg, enc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err = ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,215 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// poeditorConfig configures the synchronization with POEditor.
+type poeditorConfig struct {
+	ProjectID int `json:"project_id"`
+
+	// Token is the API token, read from the POEDITOR_API_TOKEN environment variable when not set.
+	Token string `json:"token"`
+
+	// SyncTerms deletes the terms of the project missing from pushed templates.
+	SyncTerms bool `json:"sync_terms"`
+
+	// BaseURL overrides the API URL.
+	BaseURL string `json:"base_url"`
+}
+
+// poeditorBatch is the number of terms updated by request.
+const poeditorBatch = 500
+
+// poeditor synchronizes catalogs with POEditor, through its v2 API.
+type poeditor struct {
+	conf  poeditorConfig
+	token string
+	base  string
+}
+
+func newPOEditor(sc *syncConfig) (syncProvider, error) {
+	if sc.POEditor == nil || sc.POEditor.ProjectID == 0 {
+		return nil, fmt.Errorf("no poeditor project_id configured")
+	}
+
+	p := &poeditor{conf: *sc.POEditor, base: sc.POEditor.BaseURL}
+	var err error
+	if p.token, err = providerToken(p.conf.Token, "POEDITOR_API_TOKEN"); err != nil {
+		return nil, err
+	}
+	if p.base == "" {
+		p.base = "https://api.poeditor.com/v2"
+	}
+	p.base = strings.TrimSuffix(p.base, "/")
+
+	return p, nil
+}
+
+// poeditorResponse is the envelope of the API responses, which report errors in their status rather than in the HTTP one.
+type poeditorResponse struct {
+	Response struct {
+		Status  string `json:"status"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"response"`
+	Result json.RawMessage `json:"result"`
+}
+
+// send sends a request to the API, decoding the result of its response into out when not nil.
+func (p *poeditor) send(req *http.Request, out interface{}) error {
+	var resp poeditorResponse
+	if err := apiRequest(req, &resp); err != nil {
+		return err
+	}
+	if resp.Response.Status != "success" {
+		return fmt.Errorf("%s: %s (%s)", req.URL.Path, resp.Response.Message, resp.Response.Code)
+	}
+	if out == nil || len(resp.Result) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(resp.Result, out)
+}
+
+// call posts a form to an API endpoint, authenticated with the token and identifying the project.
+func (p *poeditor) call(path string, form url.Values, out interface{}) error {
+	form.Set("api_token", p.token)
+	form.Set("id", strconv.Itoa(p.conf.ProjectID))
+
+	req, err := http.NewRequest(http.MethodPost, p.base+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return p.send(req, out)
+}
+
+// Push uploads the terms of the template, then tags them with the files they're referenced in.
+func (p *poeditor) Push(name string, pot []byte) error {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	syncTerms := "0"
+	if p.conf.SyncTerms {
+		syncTerms = "1"
+	}
+	fields := [][2]string{
+		{"api_token", p.token},
+		{"id", strconv.Itoa(p.conf.ProjectID)},
+		{"updating", "terms"},
+		{"sync_terms", syncTerms},
+	}
+	for _, f := range fields {
+		if err := mw.WriteField(f[0], f[1]); err != nil {
+			return err
+		}
+	}
+	fw, err := mw.CreateFormFile("file", name)
+	if err != nil {
+		return err
+	}
+	if _, err = fw.Write(pot); err != nil {
+		return err
+	}
+	if err = mw.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.base+"/projects/upload", &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	if err = p.send(req, nil); err != nil {
+		return err
+	}
+
+	f, err := gotext.ParsePoFile(pot)
+	if err != nil {
+		return err
+	}
+
+	return p.tagTerms(f)
+}
+
+// poeditorTerm identifies a term to update, with its new tags.
+type poeditorTerm struct {
+	Term    string   `json:"term"`
+	Context string   `json:"context"`
+	Tags    []string `json:"tags"`
+}
+
+// tagTerms tags the terms of the template with the files of their references.
+func (p *poeditor) tagTerms(f *gotext.PoFile) error {
+	var terms []poeditorTerm
+	for _, e := range f.Entries {
+		if e.IsHeader() || e.Obsolete {
+			continue
+		}
+		if tags := referenceFiles(e); len(tags) > 0 {
+			terms = append(terms, poeditorTerm{Term: e.ID, Context: e.Context, Tags: tags})
+		}
+	}
+
+	for len(terms) > 0 {
+		n := len(terms)
+		if n > poeditorBatch {
+			n = poeditorBatch
+		}
+		data, err := json.Marshal(terms[:n])
+		if err != nil {
+			return err
+		}
+		if err = p.call("/terms/update", url.Values{"data": {string(data)}}, nil); err != nil {
+			return err
+		}
+		terms = terms[n:]
+	}
+
+	return nil
+}
+
+// referenceFiles returns the sorted files of the references of an entry, without their line numbers.
+func referenceFiles(e *gotext.PoEntry) []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, line := range e.References {
+		for _, ref := range strings.Fields(line) {
+			if i := strings.LastIndex(ref, ":"); i > 0 {
+				if _, err := strconv.Atoi(ref[i+1:]); err == nil {
+					ref = ref[:i]
+				}
+			}
+			if !seen[ref] {
+				seen[ref] = true
+				files = append(files, ref)
+			}
+		}
+	}
+	sort.Strings(files)
+
+	return files
+}
+
+// Pull exports the translations of the project in a language, then downloads them.
+func (p *poeditor) Pull(name, lang string) ([]byte, error) {
+	var export struct {
+		URL string `json:"url"`
+	}
+	if err := p.call("/projects/export", url.Values{"language": {lang}, "type": {"po"}}, &export); err != nil {
+		return nil, err
+	}
+
+	return download(export.URL)
+}
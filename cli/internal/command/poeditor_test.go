@@ -0,0 +1,122 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestPOEditor returns a poeditor provider of the project 1 using the fake API.
+func newTestPOEditor(t *testing.T, api *fakeAPI, syncTerms bool) syncProvider {
+	p, err := newPOEditor(&syncConfig{POEditor: &poeditorConfig{ProjectID: 1, Token: "secret", SyncTerms: syncTerms, BaseURL: api.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+// poeditorReply returns a handler answering with a successful response of the given result.
+func poeditorReply(result interface{}) http.HandlerFunc {
+	return reply(map[string]interface{}{
+		"response": map[string]string{"status": "success", "code": "200", "message": "OK"},
+		"result":   result,
+	})
+}
+
+func TestPOEditorPush(t *testing.T) {
+	var updates [][]poeditorTerm
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /projects/upload": poeditorReply(map[string]interface{}{"terms": map[string]int{"added": 501}}),
+		"POST /terms/update": func(w http.ResponseWriter, r *http.Request) {
+			var terms []poeditorTerm
+			if err := json.Unmarshal([]byte(r.FormValue("data")), &terms); err != nil {
+				t.Error(err)
+			}
+			if r.FormValue("api_token") != "secret" || r.FormValue("id") != "1" {
+				t.Errorf("Expected the token and project but got '%s' and '%s'", r.FormValue("api_token"), r.FormValue("id"))
+			}
+			updates = append(updates, terms)
+			poeditorReply(nil)(w, r)
+		},
+	})
+	defer api.Close()
+
+	// Enough referenced terms for two updates
+	var b strings.Builder
+	b.WriteString(syncTemplate)
+	for i := 0; i < poeditorBatch; i++ {
+		fmt.Fprintf(&b, "\n#: pkg/file%d.go:1\nmsgctxt \"batch\"\nmsgid \"Term %d\"\nmsgstr \"\"\n", i, i)
+	}
+	pot := b.String()
+
+	if err := newTestPOEditor(t, api, true).Push("default.pot", []byte(pot)); err != nil {
+		t.Fatal(err)
+	}
+
+	fields, files := multipartFields(t, api.call(t, "POST /projects/upload"))
+	expected := map[string]string{"api_token": "secret", "id": "1", "updating": "terms", "sync_terms": "1"}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("Expected %v but got %v", expected, fields)
+	}
+	if files["default.pot"] != pot {
+		t.Error("Expected the template as 'default.pot'")
+	}
+
+	if len(updates) != 2 || len(updates[0]) != poeditorBatch || len(updates[1]) != 1 {
+		t.Fatalf("Expected updates of %d and 1 terms", poeditorBatch)
+	}
+	first := poeditorTerm{Term: "Hello", Tags: []string{"main.go"}}
+	if !reflect.DeepEqual(updates[0][0], first) {
+		t.Errorf("Expected %v but got %v", first, updates[0][0])
+	}
+	last := poeditorTerm{Term: fmt.Sprintf("Term %d", poeditorBatch-1), Context: "batch", Tags: []string{fmt.Sprintf("pkg/file%d.go", poeditorBatch-1)}}
+	if !reflect.DeepEqual(updates[1][0], last) {
+		t.Errorf("Expected %v but got %v", last, updates[1][0])
+	}
+}
+
+func TestPOEditorPull(t *testing.T) {
+	var api *fakeAPI
+	api = newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /projects/export": func(w http.ResponseWriter, r *http.Request) {
+			poeditorReply(map[string]string{"url": api.URL + "/download/de.po"})(w, r)
+		},
+		"GET /download/de.po": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, syncTranslation)
+		},
+	})
+	defer api.Close()
+
+	data, err := newTestPOEditor(t, api, false).Pull("default.pot", "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != syncTranslation {
+		t.Errorf("Expected the translation but got '%s'", data)
+	}
+
+	form, _ := url.ParseQuery(string(api.call(t, "POST /projects/export").Body))
+	if form.Get("language") != "de" || form.Get("type") != "po" || form.Get("id") != "1" {
+		t.Errorf("Expected an export of the 'de' po file but got %v", form)
+	}
+}
+
+func TestPOEditorError(t *testing.T) {
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /projects/export": reply(map[string]interface{}{
+			"response": map[string]string{"status": "fail", "code": "4044", "message": "Language not found"},
+		}),
+	})
+	defer api.Close()
+
+	// Errors are reported in the response status, with a 200 HTTP one
+	_, err := newTestPOEditor(t, api, false).Pull("default.pot", "xx")
+	if err == nil || !strings.Contains(err.Error(), "Language not found (4044)") {
+		t.Errorf("Expected the API error but got '%v'", err)
+	}
+}
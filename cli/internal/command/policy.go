@@ -0,0 +1,132 @@
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/leonelquinteros/gotext/cli/xgotext/parser"
+)
+
+// Kinds of the policy issues of the extract command.
+const (
+	policyMaxLength          gotext.IssueKind = "max-length"
+	policyTrailingWhitespace gotext.IssueKind = "trailing-whitespace"
+	policyOneWordContext     gotext.IssueKind = "one-word-context"
+	policyBannedWord         gotext.IssueKind = "banned-word"
+)
+
+// policyConfig is the "policy" of the "extract" section, the checks of the extracted strings, all disabled by default.
+type policyConfig struct {
+	// MaxLength is the maximum length of the msgids in characters, unlimited when 0.
+	MaxLength int `json:"max_length"`
+	// NoTrailingWhitespace forbids msgids ending with spaces or newlines.
+	NoTrailingWhitespace bool `json:"no_trailing_whitespace"`
+	// OneWordContext requires a context for the msgids of a single word, which are ambiguous to translators.
+	OneWordContext bool `json:"one_word_context"`
+	// BannedWords lists the words and phrases msgids can't hold, regardless of case.
+	BannedWords []string `json:"banned_words"`
+}
+
+// check returns the policy issues of the extracted strings, one per source location, sorted by location.
+// Locations are joined to dir, the directory they're relative to.
+func (p *policyConfig) check(dir string, data *parser.DomainMap) []lintIssue {
+	if p == nil {
+		return nil
+	}
+
+	banned := make([]*regexp.Regexp, 0, len(p.BannedWords))
+	for _, word := range p.BannedWords {
+		banned = append(banned, regexp.MustCompile(`(?i)(^|\W)`+regexp.QuoteMeta(word)+`($|\W)`))
+	}
+
+	var issues []lintIssue
+	checkTranslation := func(t *parser.Translation) {
+		msgid, context := unquotePo(t.MsgId), unquotePo(t.Context)
+
+		var found []lintIssue
+		issue := func(kind gotext.IssueKind, format string, args ...interface{}) {
+			found = append(found, lintIssue{Kind: kind, Context: context, Msgid: msgid, Message: fmt.Sprintf(format, args...)})
+		}
+		for _, s := range []string{msgid, unquotePo(t.MsgIdPlural)} {
+			if s == "" {
+				continue
+			}
+			if n := utf8.RuneCountInString(s); p.MaxLength > 0 && n > p.MaxLength {
+				issue(policyMaxLength, "%d characters, more than the maximum of %d", n, p.MaxLength)
+			}
+			if p.NoTrailingWhitespace && strings.TrimRightFunc(s, unicode.IsSpace) != s {
+				issue(policyTrailingWhitespace, "trailing whitespace or newline")
+			}
+			for i, re := range banned {
+				if re.MatchString(s) {
+					issue(policyBannedWord, "banned word %q", p.BannedWords[i])
+				}
+			}
+		}
+		// Placeholders alone aren't words.
+		if words := strings.Fields(msgid); p.OneWordContext && t.Context == "" && len(words) == 1 &&
+			!strings.HasPrefix(words[0], "%") && strings.IndexFunc(words[0], unicode.IsLetter) >= 0 {
+			issue(policyOneWordContext, "single word without context")
+		}
+
+		for _, location := range t.SourceLocations {
+			file, line := parser.SplitLocation(location)
+			for _, i := range found {
+				i.File, i.Line = filepath.Join(dir, filepath.FromSlash(file)), line
+				issues = append(issues, i)
+			}
+		}
+	}
+
+	for _, d := range data.Domains {
+		for _, t := range d.Translations {
+			checkTranslation(t)
+		}
+		for _, tm := range d.ContextTranslations {
+			for _, t := range tm {
+				checkTranslation(t)
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Message < b.Message
+	})
+
+	return issues
+}
+
+// printPolicyIssues prints the policy issues as the lint command does.
+func printPolicyIssues(issues []lintIssue) {
+	for _, issue := range issues {
+		fmt.Printf("%s:%d: %s: %s", issue.File, issue.Line, issue.Kind, issue.Message)
+		if issue.Context != "" {
+			fmt.Printf(" (msgid %q, msgctxt %q)\n", issue.Msgid, issue.Context)
+		} else {
+			fmt.Printf(" (msgid %q)\n", issue.Msgid)
+		}
+	}
+}
+
+// unquotePo returns the string of a quoted PO string of the parser, empty for an empty literal.
+func unquotePo(lit string) string {
+	s, err := strconv.Unquote(lit)
+	if err != nil {
+		return strings.Trim(lit, `"`)
+	}
+	return s
+}
@@ -0,0 +1,129 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/leonelquinteros/gotext/cli/xgotext/parser"
+)
+
+// policyStrings returns the extracted strings of the checks of the policy.
+func policyStrings() *parser.DomainMap {
+	data := &parser.DomainMap{}
+	for _, t := range []*parser.Translation{
+		{MsgId: `"Save your changes"`, SourceLocations: []string{"main.go:3"}},
+		{MsgId: `"Open"`, Context: `"menu"`, SourceLocations: []string{"main.go:4"}},
+		{MsgId: `"%d"`, SourceLocations: []string{"main.go:5"}},
+		{MsgId: `"Close"`, SourceLocations: []string{"main.go:6", "pkg/pkg.go:2"}},
+		{MsgId: `"Press Enter "`, SourceLocations: []string{"main.go:7"}},
+		{MsgId: `"One file"`, MsgIdPlural: `"%d files\n"`, SourceLocations: []string{"main.go:8"}},
+		{MsgId: `"Click here to continue"`, SourceLocations: []string{"main.go:9"}},
+		{MsgId: `"Clicking is fine"`, SourceLocations: []string{"main.go:10"}},
+	} {
+		data.AddTranslation("", t)
+	}
+
+	return data
+}
+
+func TestPolicyCheck(t *testing.T) {
+	dir := "/src/app"
+	issue := func(file string, line int, kind gotext.IssueKind, context, msgid, message string) string {
+		return fmt.Sprintf("%s:%d %s %q %q %s", filepath.Join(dir, file), line, kind, context, msgid, message)
+	}
+
+	for _, test := range []struct {
+		name     string
+		policy   *policyConfig
+		expected []string
+	}{
+		{"none", nil, nil},
+		{"passing", &policyConfig{MaxLength: 40, BannedWords: []string{"foo"}}, nil},
+		{"max length", &policyConfig{MaxLength: 16}, []string{
+			issue("main.go", 3, policyMaxLength, "", "Save your changes", "17 characters, more than the maximum of 16"),
+			issue("main.go", 9, policyMaxLength, "", "Click here to continue", "22 characters, more than the maximum of 16"),
+		}},
+		{"trailing whitespace", &policyConfig{NoTrailingWhitespace: true}, []string{
+			issue("main.go", 7, policyTrailingWhitespace, "", "Press Enter ", "trailing whitespace or newline"),
+			issue("main.go", 8, policyTrailingWhitespace, "", "One file", "trailing whitespace or newline"),
+		}},
+		{"one word context", &policyConfig{OneWordContext: true}, []string{
+			issue("main.go", 6, policyOneWordContext, "", "Close", "single word without context"),
+			issue("pkg/pkg.go", 2, policyOneWordContext, "", "Close", "single word without context"),
+		}},
+		{"banned words", &policyConfig{BannedWords: []string{"click", "your changes"}}, []string{
+			issue("main.go", 3, policyBannedWord, "", "Save your changes", `banned word "your changes"`),
+			issue("main.go", 9, policyBannedWord, "", "Click here to continue", `banned word "click"`),
+		}},
+	} {
+		issues := test.policy.check(dir, policyStrings())
+		var got []string
+		for _, i := range issues {
+			got = append(got, fmt.Sprintf("%s:%d %s %q %q %s", i.File, i.Line, i.Kind, i.Context, i.Msgid, i.Message))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(test.expected) {
+			t.Errorf("%s: Expected %q but got %q", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestPolicyCheckContext(t *testing.T) {
+	data := &parser.DomainMap{}
+	data.AddTranslation("", &parser.Translation{MsgId: `"Open the file "`, Context: `"menu"`, SourceLocations: []string{"main.go:1"}})
+
+	issues := (&policyConfig{NoTrailingWhitespace: true, OneWordContext: true}).check(".", data)
+	if len(issues) != 1 || issues[0].Context != "menu" || issues[0].Kind != policyTrailingWhitespace || issues[0].File != "main.go" {
+		t.Errorf("Expected a trailing whitespace issue of the menu context but got %+v", issues)
+	}
+}
+
+func TestExtractPolicy(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_extract_policy"
+	files := map[string]string{
+		"xgotext.json":              `{"extract": {"policy": {"one_word_context": true}}}`,
+		"services/api/xgotext.json": `{"extract": {"policy": {"banned_words": ["not found"]}}}`,
+	}
+	for name, content := range monorepoFixture {
+		if name != "services/api/xgotext.json" {
+			files[name] = content
+		}
+	}
+	writeFixture(t, dir, files)
+	defer os.RemoveAll(dir)
+
+	// Issues are printed as the lint command does, failing the extraction once the templates are written
+	r := runCommand(t, dir, "extract", "-in", ".", "-out", "i18n")
+	if r.code != 1 {
+		t.Errorf("Expected the extraction to fail but got %d: '%s'", r.code, r.stderr)
+	}
+	expected := "main.go:6: one-word-context: single word without context (msgid \"Hello\")\n" +
+		"services/api/main.go:6: one-word-context: single word without context (msgid \"Hello\")\n"
+	if r.stdout != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, r.stdout)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "i18n", "default.pot")); err != nil {
+		t.Error(err)
+	}
+
+	// Each module is checked by its own policy
+	r = runCommand(t, dir, "extract", "-in", ".", "-out", "i18n", "-modules", "split")
+	if r.code != 1 {
+		t.Errorf("Expected the extraction to fail but got %d: '%s'", r.code, r.stderr)
+	}
+	expected = "main.go:6: one-word-context: single word without context (msgid \"Hello\")\n" +
+		filepath.Join("services", "api", "main.go") + ":7: banned-word: banned word \"not found\" (msgid \"Not found\")\n"
+	if r.stdout != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, r.stdout)
+	}
+
+	// Passing policies don't fail the extraction
+	writeFile(t, dir, "xgotext.json", `{"extract": {"policy": {"max_length": 20}}}`)
+	if r := runCommand(t, dir, "extract", "-in", ".", "-out", "i18n"); r.code != 0 || r.stdout != "" {
+		t.Errorf("Expected the extraction to succeed but got %d: '%s%s'", r.code, r.stdout, r.stderr)
+	}
+}
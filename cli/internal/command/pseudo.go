@@ -0,0 +1,53 @@
+package command
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// pseudo runs the pseudo command, filling a catalog with pseudo-localized translations.
+func pseudo(args []string) {
+	opts := gotext.DefaultPseudoOptions
+	fs := newFlagSet("pseudo", "[flags] default.pot")
+	out := fs.String("o", "", "output file: /path/to/en_XA/default.po (default standard output)")
+	lang := fs.String("lang", "en_XA", "Language header of the pseudo-localized catalog")
+	fs.Float64Var(&opts.Expansion, "expansion", opts.Expansion, "length added to strings, as a ratio of their length")
+	noAccents := fs.Bool("no-accents", false, "keep ASCII letters unaccented")
+	fs.StringVar(&opts.Open, "open", opts.Open, "marker opening strings")
+	fs.StringVar(&opts.Close, "close", opts.Close, "marker closing strings")
+	parseFlags(fs, args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	opts.Accents = !*noAccents
+
+	f, err := readPoFile(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	f.Pseudolocalize(opts)
+
+	h := f.Header()
+	if h == nil {
+		h = &gotext.PoEntry{}
+		f.Entries = append([]*gotext.PoEntry{h}, f.Entries...)
+	}
+	h.SetFlag("fuzzy", false)
+	if *lang != "" {
+		h.SetHeaderValue("Language", *lang)
+	}
+
+	src := f.Format(gotext.FormatOptions{})
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err = ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
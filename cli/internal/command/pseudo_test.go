@@ -0,0 +1,64 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const pseudoTemplate = `#, fuzzy
+msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+msgid "Hello %s"
+msgstr ""
+
+msgid "Save"
+msgstr ""
+`
+
+func TestPseudo(t *testing.T) {
+	dir := "/tmp/gotext_pseudo"
+	writeFixture(t, dir, map[string]string{
+		"default.pot": pseudoTemplate,
+	})
+	defer os.RemoveAll(dir)
+
+	// Strings are accented, expanded and marked, keeping their placeholders, in a translated en_XA catalog
+	r := runCommand(t, dir, "pseudo", "default.pot")
+	for _, expected := range []string{
+		"msgid \"\"\nmsgstr \"\"\n\"Content-Type: text/plain; charset=UTF-8\\n\"\n\"Language: en_XA\\n\"\n",
+		"msgid \"Hello %s\"\nmsgstr \"[Ĥéļļö %s~~~]\"\n",
+		"msgid \"Save\"\nmsgstr \"[Šáṽé~~]\"\n",
+	} {
+		if r.code != 0 || !strings.Contains(r.stdout, expected) {
+			t.Errorf("Expected '%s' in '%s%s'", expected, r.stdout, r.stderr)
+		}
+	}
+	if strings.Contains(r.stdout, "fuzzy") {
+		t.Errorf("Expected the header not to be fuzzy but got '%s'", r.stdout)
+	}
+
+	r = runCommand(t, dir, "pseudo", "-no-accents", "-expansion", "0", "-open", "«", "-close", "»", "-lang", "qps", "-o", "qps.po", "default.pot")
+	if r.code != 0 || r.stdout != "" {
+		t.Fatalf("Expected success but got '%s%s'", r.stdout, r.stderr)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "qps.po"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"\"Language: qps\\n\"", "msgstr \"«Hello %s»\"\n", "msgstr \"«Save»\"\n"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected '%s' in '%s'", expected, data)
+		}
+	}
+
+	// Without language, the header is left as it is
+	r = runCommand(t, dir, "pseudo", "-lang", "", "default.pot")
+	if r.code != 0 || strings.Contains(r.stdout, "Language:") {
+		t.Errorf("Expected no Language header but got '%s%s'", r.stdout, r.stderr)
+	}
+}
@@ -0,0 +1,146 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// catalogStats is the completion of a domain in a language, as written by the JSON output of the stats command.
+type catalogStats struct {
+	Language     string  `json:"language"`
+	Domain       string  `json:"domain"`
+	Translated   int     `json:"translated"`
+	Fuzzy        int     `json:"fuzzy"`
+	Untranslated int     `json:"untranslated"`
+	Total        int     `json:"total"`
+	Percent      float64 `json:"percent"`
+}
+
+// newCatalogStats returns the completion of the domain of a language.
+func newCatalogStats(lang, domain string, s gotext.PoStats) catalogStats {
+	return catalogStats{lang, domain, s.Translated, s.Fuzzy, s.Untranslated, s.Total(), math.Round(s.Percent()*10) / 10}
+}
+
+// catalogLanguage returns the language of a catalog, from its Language header or, when not set,
+// from its path: "lang/domain.po" or "lang/LC_MESSAGES/domain.po".
+func catalogLanguage(file string, f *gotext.PoFile) string {
+	if h := f.Header(); h != nil {
+		if lang := h.HeaderValue("Language"); lang != "" {
+			return lang
+		}
+	}
+
+	dir := filepath.Dir(file)
+	if filepath.Base(dir) == "LC_MESSAGES" {
+		dir = filepath.Dir(dir)
+	}
+
+	return filepath.Base(dir)
+}
+
+// badgeColor returns the shields.io color of a completion percentage.
+func badgeColor(percent float64) string {
+	switch {
+	case percent >= 90:
+		return "brightgreen"
+	case percent >= 75:
+		return "green"
+	case percent >= 50:
+		return "yellow"
+	case percent >= 25:
+		return "orange"
+	}
+
+	return "red"
+}
+
+// stats runs the stats command, printing the completion of the catalogs by language and domain.
+func stats(args []string) {
+	fs := newFlagSet("stats", "[flags] path ...")
+	format := fs.String("format", "table", "output format: \"table\", \"json\" or \"badge\"")
+	out := fs.String("o", "", "output dir of the badge format, written as one shields.io endpoint file per language: /path/to/badges")
+	parseFlags(fs, args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *format == "badge" && *out == "" {
+		log.Fatal("No output directory given for badges")
+	}
+
+	files, err := poFiles(fs.Args())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var all []catalogStats
+	languages := make(map[string]gotext.PoStats)
+	for _, file := range files {
+		// Templates have nothing translated.
+		if filepath.Ext(file) != ".po" {
+			continue
+		}
+		f, err := readPoFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		lang := catalogLanguage(file, f)
+		s := f.Stats()
+		all = append(all, newCatalogStats(lang, strings.TrimSuffix(filepath.Base(file), ".po"), s))
+		languages[lang] = languages[lang].Add(s)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].Language != all[j].Language {
+			return all[i].Language < all[j].Language
+		}
+		return all[i].Domain < all[j].Domain
+	})
+
+	switch *format {
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "LANGUAGE\tDOMAIN\tTRANSLATED\tFUZZY\tUNTRANSLATED\tCOMPLETE")
+		for _, s := range all {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%.1f%%\n", s.Language, s.Domain, s.Translated, s.Fuzzy, s.Untranslated, s.Percent)
+		}
+		w.Flush()
+
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err = enc.Encode(all); err != nil {
+			log.Fatal(err)
+		}
+
+	case "badge":
+		if err = os.MkdirAll(*out, 0755); err != nil {
+			log.Fatal(err)
+		}
+		for lang, s := range languages {
+			badge, _ := json.Marshal(map[string]interface{}{
+				"schemaVersion": 1,
+				"label":         lang,
+				"message":       fmt.Sprintf("%.0f%%", s.Percent()),
+				"color":         badgeColor(s.Percent()),
+			})
+			if err = ioutil.WriteFile(filepath.Join(*out, lang+".json"), append(badge, '\n'), 0644); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+	default:
+		log.Fatalf("Unknown format %q", *format)
+	}
+}
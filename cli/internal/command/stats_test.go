@@ -0,0 +1,64 @@
+package command
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	dir := "/tmp/gotext_stats"
+	writeFixture(t, dir, map[string]string{
+		"i18n/default.pot":               mergeTemplate,
+		"i18n/de/default.po":             msgfmtCatalog,
+		"i18n/de/admin.po":               "msgid \"Users\"\nmsgstr \"Benutzer\"\n",
+		"i18n/fr/LC_MESSAGES/default.po": "msgid \"Hello\"\nmsgstr \"Bonjour\"\n\nmsgid \"Bye\"\nmsgstr \"\"\n",
+	})
+	defer os.RemoveAll(dir)
+
+	// Catalogs are listed by language and domain, the language taken from their path without header
+	r := runCommand(t, dir, "stats", "i18n")
+	expected := "LANGUAGE  DOMAIN   TRANSLATED  FUZZY  UNTRANSLATED  COMPLETE\n" +
+		"de        admin    1           0      0             100.0%\n" +
+		"de        default  1           1      1             33.3%\n" +
+		"fr        default  1           0      1             50.0%\n"
+	if r.code != 0 || r.stdout != expected {
+		t.Errorf("Expected '%s' but got '%s%s'", expected, r.stdout, r.stderr)
+	}
+
+	r = runCommand(t, dir, "stats", "-format", "json", "i18n/de/default.po")
+	var all []catalogStats
+	if err := json.Unmarshal([]byte(r.stdout), &all); err != nil {
+		t.Fatalf("Expected JSON stats but got '%s%s'", r.stdout, r.stderr)
+	}
+	if s := (catalogStats{"de", "default", 1, 1, 1, 3, 33.3}); r.code != 0 || len(all) != 1 || all[0] != s {
+		t.Errorf("Expected %+v but got %+v", s, all)
+	}
+
+	// Badges are written by language
+	if r := runCommand(t, dir, "stats", "-format", "badge", "i18n"); r.code != 1 || !strings.Contains(r.stderr, "No output directory") {
+		t.Errorf("Expected an error but got %d '%s'", r.code, r.stderr)
+	}
+	if r := runCommand(t, dir, "stats", "-format", "badge", "-o", "badges", "i18n"); r.code != 0 {
+		t.Fatalf("Expected success but got '%s%s'", r.stdout, r.stderr)
+	}
+	for lang, expected := range map[string]string{
+		"de": `{"color":"yellow","label":"de","message":"50%","schemaVersion":1}` + "\n",
+		"fr": `{"color":"yellow","label":"fr","message":"50%","schemaVersion":1}` + "\n",
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "badges", lang+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != expected {
+			t.Errorf("Expected '%s' but got '%s'", expected, data)
+		}
+	}
+
+	if r := runCommand(t, dir, "stats", "-format", "csv", "i18n"); r.code != 1 || !strings.Contains(r.stderr, "Unknown format") {
+		t.Errorf("Expected an error but got %d '%s'", r.code, r.stderr)
+	}
+}
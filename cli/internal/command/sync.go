@@ -0,0 +1,352 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// syncConfig configures the synchronization of catalogs with translation management systems.
+type syncConfig struct {
+	// Template is the path of the .pot file holding the source strings.
+	Template string `json:"template"`
+
+	// Translations is the path of the translated catalogs, "{lang}" being replaced by their language,
+	// i.e. "locales/{lang}/LC_MESSAGES/default.po".
+	Translations string `json:"translations"`
+
+	// Languages are the languages pulled.
+	Languages []string `json:"languages"`
+
+	// LanguageMapping maps the languages of the catalogs to the codes of the translation management system, i.e. "pt_BR": "pt-BR".
+	LanguageMapping map[string]string `json:"language_mapping"`
+
+	// MinimumPerc is the minimum completion percentage of pulled catalogs, less complete ones are skipped.
+	MinimumPerc float64 `json:"minimum_perc"`
+
+	Crowdin   *crowdinConfig   `json:"crowdin"`
+	Weblate   *weblateConfig   `json:"weblate"`
+	POEditor  *poeditorConfig  `json:"poeditor"`
+	Transifex *transifexConfig `json:"transifex"`
+	Lokalise  *lokaliseConfig  `json:"lokalise"`
+}
+
+// syncProvider is a translation management system catalogs are synchronized with.
+type syncProvider interface {
+	// Push uploads the template holding the source strings, named after its file.
+	Push(name string, pot []byte) error
+
+	// Pull downloads the translations of the template in a language, as a .po file.
+	Pull(name, lang string) ([]byte, error)
+}
+
+// syncLocker is a provider which can prevent translators from working while the source strings change.
+type syncLocker interface {
+	Lock() error
+	Unlock() error
+}
+
+// syncProviders create the providers from the configuration, by name.
+var syncProviders = map[string]func(conf *syncConfig) (syncProvider, error){
+	"crowdin":   newCrowdin,
+	"weblate":   newWeblate,
+	"poeditor":  newPOEditor,
+	"transifex": newTransifex,
+	"lokalise":  newLokalise,
+}
+
+// providerToken returns the API token of a provider: the configured one, or the value of the environment variable.
+func providerToken(token, env string) (string, error) {
+	if token == "" {
+		token = os.Getenv(env)
+	}
+	if token == "" {
+		return "", fmt.Errorf("no API token configured nor set in the %s environment variable", env)
+	}
+
+	return token, nil
+}
+
+// apiClient sends the requests of the providers.
+var apiClient = &http.Client{Timeout: 5 * time.Minute}
+
+// apiError is the error of a request answered with an error status.
+type apiError struct {
+	Method, Path, Status string
+	StatusCode           int
+	Body                 string
+}
+
+func (e *apiError) Error() string {
+	msg := strings.TrimSpace(e.Body)
+	if len(msg) > 500 {
+		msg = msg[:500] + "..."
+	}
+
+	return fmt.Sprintf("%s %s: %s: %s", e.Method, e.Path, e.Status, msg)
+}
+
+// isNotFound reports if err is the error of a request answered with the 404 status.
+func isNotFound(err error) bool {
+	e, ok := err.(*apiError)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// apiRequest sends a request to a provider API, decoding its JSON response into out when not nil.
+// Responses with an error status are returned as errors.
+func apiRequest(req *http.Request, out interface{}) error {
+	resp, err := apiClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return &apiError{Method: req.Method, Path: req.URL.Path, Status: resp.Status, StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if data, ok := out.(*[]byte); ok {
+		*data = body
+		return nil
+	}
+	if err = json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%s %s: %v", req.Method, req.URL.Path, err)
+	}
+
+	return nil
+}
+
+// newJSONRequest returns a request with a JSON body, or without body when v is nil.
+func newJSONRequest(method, url string, v interface{}) (*http.Request, error) {
+	var body io.Reader
+	if v != nil {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if v != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+// download returns the content of a URL, as given by providers to download exported files.
+func download(url string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	err = apiRequest(req, &data)
+
+	return data, err
+}
+
+// pollInterval is the interval between the checks of the asynchronous jobs of the providers, and pollTimeout how long they're waited for.
+var (
+	pollInterval = 2 * time.Second
+	pollTimeout  = 10 * time.Minute
+)
+
+// poll calls check until it reports the job is done or fails, or the job times out.
+func poll(job string, check func() (done bool, err error)) error {
+	deadline := time.Now().Add(pollTimeout)
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s timed out after %s", job, pollTimeout)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// providerLanguage returns the code of a language in the translation management system.
+func (c *syncConfig) providerLanguage(lang string) string {
+	if code, ok := c.LanguageMapping[lang]; ok {
+		return code
+	}
+
+	return lang
+}
+
+// syncCatalogs runs the sync command, pushing the template to a translation management system and pulling its translations.
+func syncCatalogs(args []string) {
+	names := make([]string, 0, len(syncProviders))
+	for name := range syncProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fs := newFlagSet("sync", "[flags] provider [push|pull|lock|unlock]\n\nProviders: "+strings.Join(names, ", ")+"\n")
+	fs.StringVar(&configFile, "config", configFile, "project configuration file")
+	minimumPerc := fs.Float64("minimum-perc", 0, "minimum completion percentage of pulled translations, overriding the configured one")
+	parseFlags(fs, args)
+
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	newProvider, ok := syncProviders[fs.Arg(0)]
+	if !ok {
+		fs.Usage()
+		os.Exit(2)
+	}
+	action := fs.Arg(1)
+
+	sc := &loadProjectConfig().Sync
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "minimum-perc" {
+			sc.MinimumPerc = *minimumPerc
+		}
+	})
+	if sc.Template == "" {
+		log.Fatalf("No sync template configured in %s", configFile)
+	}
+	provider, err := newProvider(sc)
+	if err != nil {
+		log.Fatalf("%s: %v", fs.Arg(0), err)
+	}
+
+	// Remote files are named after the template, the domain of the catalogs.
+	if err = runSyncAction(action, provider, sc, filepath.Base(sc.Template)); err != nil {
+		log.Fatalf("%s: %v", fs.Arg(0), err)
+	}
+}
+
+// runSyncAction pushes the template and/or pulls the translations, both when the action is empty.
+// Providers implementing syncLocker can be locked and unlocked too, i.e. while templates are extracted.
+func runSyncAction(action string, provider syncProvider, sc *syncConfig, name string) error {
+	switch action {
+	case "", "push", "pull":
+	case "lock", "unlock":
+		return lockProvider(provider, action == "lock")
+	default:
+		return fmt.Errorf("unknown action %q", action)
+	}
+
+	if action == "" || action == "push" {
+		if err := pushTemplate(provider, sc, name); err != nil {
+			return err
+		}
+	}
+
+	if action == "" || action == "pull" {
+		if sc.Translations == "" || len(sc.Languages) == 0 {
+			return fmt.Errorf("no translations path or languages configured to pull")
+		}
+		for _, lang := range sc.Languages {
+			if err := pullLanguage(provider, sc, name, lang); err != nil {
+				return fmt.Errorf("%s: %v", lang, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// lockProvider locks or unlocks the provider.
+func lockProvider(provider syncProvider, lock bool) error {
+	locker, ok := provider.(syncLocker)
+	if !ok {
+		return fmt.Errorf("locking isn't supported")
+	}
+
+	if lock {
+		if err := locker.Lock(); err != nil {
+			return err
+		}
+		log.Print("Locked")
+		return nil
+	}
+	if err := locker.Unlock(); err != nil {
+		return err
+	}
+	log.Print("Unlocked")
+
+	return nil
+}
+
+// pushTemplate uploads the template, locking the providers supporting it while their source strings are updated.
+func pushTemplate(provider syncProvider, sc *syncConfig, name string) (err error) {
+	pot, err := ioutil.ReadFile(sc.Template)
+	if err != nil {
+		return err
+	}
+
+	if locker, ok := provider.(syncLocker); ok {
+		if err = locker.Lock(); err != nil {
+			return err
+		}
+		defer func() {
+			if uerr := locker.Unlock(); err == nil {
+				err = uerr
+			}
+		}()
+	}
+
+	if err = provider.Push(name, pot); err != nil {
+		return err
+	}
+	log.Printf("Pushed %s", sc.Template)
+
+	return nil
+}
+
+// pullLanguage downloads the translations of a language, written in canonical style unless less complete than configured.
+func pullLanguage(provider syncProvider, sc *syncConfig, name, lang string) error {
+	data, err := provider.Pull(name, sc.providerLanguage(lang))
+	if err != nil {
+		return err
+	}
+
+	f, err := gotext.ParsePoFile(data)
+	if err != nil {
+		return err
+	}
+	stats := f.Stats()
+	if stats.Percent() < sc.MinimumPerc {
+		log.Printf("Skipping %s: %.1f%% translated, less than %.1f%%", lang, stats.Percent(), sc.MinimumPerc)
+		return nil
+	}
+
+	file := strings.Replace(sc.Translations, "{lang}", lang, -1)
+	if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(file, f.Format(gotext.FormatOptions{}), 0644); err != nil {
+		return err
+	}
+	log.Printf("Pulled %s: %.1f%% translated", file, stats.Percent())
+
+	return nil
+}
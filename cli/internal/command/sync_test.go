@@ -0,0 +1,275 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// apiCall is a request received by a fake provider API.
+type apiCall struct {
+	Route  string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// fakeAPI is a provider API answering the requests with the handlers of their routes, "METHOD /path",
+// and recording them.
+type fakeAPI struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	calls []apiCall
+}
+
+func newFakeAPI(t *testing.T, routes map[string]http.HandlerFunc) *fakeAPI {
+	api := &fakeAPI{}
+	api.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		route := r.Method + " " + r.URL.Path
+
+		api.mu.Lock()
+		api.calls = append(api.calls, apiCall{Route: route, Query: r.URL.Query(), Header: r.Header, Body: body})
+		api.mu.Unlock()
+
+		h, ok := routes[route]
+		if !ok {
+			t.Errorf("Unexpected request %s", route)
+			http.NotFound(w, r)
+			return
+		}
+		h(w, r)
+	}))
+
+	return api
+}
+
+// routes returns the routes of the requests received, in order.
+func (api *fakeAPI) routes() []string {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	routes := make([]string, len(api.calls))
+	for i, c := range api.calls {
+		routes[i] = c.Route
+	}
+
+	return routes
+}
+
+// call returns the last request received on a route.
+func (api *fakeAPI) call(t *testing.T, route string) apiCall {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	for i := len(api.calls) - 1; i >= 0; i-- {
+		if api.calls[i].Route == route {
+			return api.calls[i]
+		}
+	}
+	t.Fatalf("No request %s", route)
+
+	return apiCall{}
+}
+
+// reply returns a handler answering with the JSON encoding of v.
+func reply(v interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	}
+}
+
+// decodeBody decodes the JSON body of a request.
+func decodeBody(t *testing.T, c apiCall) map[string]interface{} {
+	var m map[string]interface{}
+	if err := json.Unmarshal(c.Body, &m); err != nil {
+		t.Fatalf("%s: %v", c.Route, err)
+	}
+
+	return m
+}
+
+// multipartFields returns the fields and files of a multipart request body.
+func multipartFields(t *testing.T, c apiCall) (map[string]string, map[string]string) {
+	req, _ := http.NewRequest(http.MethodPost, "/", bytes.NewReader(c.Body))
+	req.Header.Set("Content-Type", c.Header.Get("Content-Type"))
+	mr, err := req.MultipartReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields, files := make(map[string]string), make(map[string]string)
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			break
+		}
+		data, _ := ioutil.ReadAll(part)
+		if part.FileName() != "" {
+			files[part.FileName()] = string(data)
+		} else {
+			fields[part.FormName()] = string(data)
+		}
+	}
+
+	return fields, files
+}
+
+const syncTemplate = `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#: main.go:12 main.go:20
+#. The greeting
+msgid "Hello"
+msgstr ""
+`
+
+const syncTranslation = `msgid ""
+msgstr ""
+"Language: de\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+msgid "Hello"
+msgstr "Hallo"
+`
+
+// memProvider is a provider keeping the catalogs in memory, by provider language, and recording its calls.
+type memProvider struct {
+	calls    []string
+	catalogs map[string]string
+}
+
+func (p *memProvider) Push(name string, pot []byte) error {
+	p.calls = append(p.calls, "push "+name)
+	return nil
+}
+
+func (p *memProvider) Pull(name, lang string) ([]byte, error) {
+	p.calls = append(p.calls, "pull "+lang)
+	if po, ok := p.catalogs[lang]; ok {
+		return []byte(po), nil
+	}
+
+	return nil, fmt.Errorf("no %s translations", lang)
+}
+
+func (p *memProvider) Lock() error {
+	p.calls = append(p.calls, "lock")
+	return nil
+}
+
+func (p *memProvider) Unlock() error {
+	p.calls = append(p.calls, "unlock")
+	return nil
+}
+
+func TestRunSyncAction(t *testing.T) {
+	dir := "/tmp/gotext_sync"
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	template := path.Join(dir, "default.pot")
+	if err := ioutil.WriteFile(template, []byte(syncTemplate), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sc := &syncConfig{
+		Template:        template,
+		Translations:    path.Join(dir, "{lang}", "default.po"),
+		Languages:       []string{"de", "pt_BR", "fr"},
+		LanguageMapping: map[string]string{"pt_BR": "pt-BR"},
+		MinimumPerc:     50,
+	}
+	p := &memProvider{catalogs: map[string]string{
+		"de":    syncTranslation,
+		"pt-BR": strings.Replace(syncTranslation, "Hallo", "Olá", 1),
+		"fr":    strings.Replace(syncTranslation, "Hallo", "", 1),
+	}}
+
+	if err := runSyncAction("", p, sc, "default.pot"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Templates are pushed while locked, and languages pulled with their provider codes
+	expected := []string{"lock", "push default.pot", "unlock", "pull de", "pull pt-BR", "pull fr"}
+	if !reflect.DeepEqual(p.calls, expected) {
+		t.Errorf("Expected calls %v but got %v", expected, p.calls)
+	}
+
+	for _, test := range []struct {
+		lang, expected string
+	}{
+		{"de", "Hallo"},
+		{"pt_BR", "Olá"},
+	} {
+		data, err := ioutil.ReadFile(path.Join(dir, test.lang, "default.po"))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !strings.Contains(string(data), test.expected) {
+			t.Errorf("Expected '%s' in the %s catalog but got '%s'", test.expected, test.lang, data)
+		}
+	}
+
+	// Less complete catalogs than the minimum percentage are skipped
+	if _, err := os.Stat(path.Join(dir, "fr", "default.po")); !os.IsNotExist(err) {
+		t.Errorf("Expected the fr catalog to be skipped but got '%v'", err)
+	}
+	sc.MinimumPerc = 0
+	p.calls = nil
+	if err := runSyncAction("pull", p, sc, "default.pot"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path.Join(dir, "fr", "default.po")); err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(p.calls, []string{"pull de", "pull pt-BR", "pull fr"}) {
+		t.Errorf("Expected pulls only but got %v", p.calls)
+	}
+
+	// Errors name their language
+	sc.Languages = append(sc.Languages, "it")
+	if err := runSyncAction("pull", p, sc, "default.pot"); err == nil || !strings.HasPrefix(err.Error(), "it: ") {
+		t.Errorf("Expected an error of the it catalog but got '%v'", err)
+	}
+}
+
+func TestRunSyncActionLock(t *testing.T) {
+	p := &memProvider{}
+	if err := runSyncAction("lock", p, &syncConfig{}, "default.pot"); err != nil {
+		t.Fatal(err)
+	}
+	if err := runSyncAction("unlock", p, &syncConfig{}, "default.pot"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p.calls, []string{"lock", "unlock"}) {
+		t.Errorf("Expected a lock and an unlock but got %v", p.calls)
+	}
+
+	// Providers without locks
+	unlockable := struct{ syncProvider }{p}
+	if err := runSyncAction("lock", unlockable, &syncConfig{}, "default.pot"); err == nil {
+		t.Error("Expected an error locking a provider without locks")
+	}
+
+	if err := runSyncAction("publish", p, &syncConfig{}, "default.pot"); err == nil {
+		t.Error("Expected an error for an unknown action")
+	}
+}
@@ -0,0 +1,200 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// transifexConfig configures the synchronization with Transifex.
+type transifexConfig struct {
+	Organization string `json:"organization"`
+	Project      string `json:"project"`
+
+	// Resource is the slug of the resource of the catalogs, created when pushed the first time.
+	Resource string `json:"resource"`
+
+	// Token is the API token, read from the TX_TOKEN environment variable when not set.
+	Token string `json:"token"`
+
+	// BaseURL overrides the API URL.
+	BaseURL string `json:"base_url"`
+}
+
+// transifex synchronizes catalogs with a Transifex resource, through its v3 API.
+type transifex struct {
+	conf  transifexConfig
+	token string
+	base  string
+}
+
+func newTransifex(sc *syncConfig) (syncProvider, error) {
+	if sc.Transifex == nil || sc.Transifex.Organization == "" || sc.Transifex.Project == "" || sc.Transifex.Resource == "" {
+		return nil, fmt.Errorf("no transifex organization, project and resource configured")
+	}
+
+	t := &transifex{conf: *sc.Transifex, base: sc.Transifex.BaseURL}
+	var err error
+	if t.token, err = providerToken(t.conf.Token, "TX_TOKEN"); err != nil {
+		return nil, err
+	}
+	if t.base == "" {
+		t.base = "https://rest.api.transifex.com"
+	}
+	t.base = strings.TrimSuffix(t.base, "/")
+
+	return t, nil
+}
+
+// transifexRef is a JSON:API resource identifier.
+type transifexRef struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// transifexData is a JSON:API resource object.
+type transifexData struct {
+	Type          string                 `json:"type"`
+	ID            string                 `json:"id,omitempty"`
+	Attributes    map[string]interface{} `json:"attributes,omitempty"`
+	Relationships map[string]interface{} `json:"relationships,omitempty"`
+}
+
+// transifexJob is the state of an asynchronous upload or download.
+type transifexJob struct {
+	Data struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			Status string `json:"status"`
+			Errors []struct {
+				Code   string `json:"code"`
+				Detail string `json:"detail"`
+			} `json:"errors"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// err returns the error of a failed job.
+func (j *transifexJob) err() error {
+	var details []string
+	for _, e := range j.Data.Attributes.Errors {
+		details = append(details, e.Detail)
+	}
+	if len(details) == 0 {
+		details = append(details, j.Data.Attributes.Status)
+	}
+
+	return fmt.Errorf("%s", strings.Join(details, "; "))
+}
+
+// relationship returns the JSON:API relationship to a resource.
+func relationship(typ, id string) map[string]interface{} {
+	return map[string]interface{}{"data": transifexRef{Type: typ, ID: id}}
+}
+
+// do sends a JSON:API request, decoding its response into out when not nil.
+func (t *transifex) do(method, path string, data *transifexData, out interface{}) error {
+	var body interface{}
+	if data != nil {
+		body = map[string]interface{}{"data": data}
+	}
+	req, err := newJSONRequest(method, t.base+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	if data != nil {
+		req.Header.Set("Content-Type", "application/vnd.api+json")
+	}
+
+	return apiRequest(req, out)
+}
+
+func (t *transifex) projectID() string {
+	return "o:" + t.conf.Organization + ":p:" + t.conf.Project
+}
+
+func (t *transifex) resourceID() string {
+	return t.projectID() + ":r:" + t.conf.Resource
+}
+
+// ensureResource creates the resource when missing, as a PO one.
+func (t *transifex) ensureResource() error {
+	err := t.do(http.MethodGet, "/resources/"+t.resourceID(), nil, nil)
+	if !isNotFound(err) {
+		return err
+	}
+
+	return t.do(http.MethodPost, "/resources", &transifexData{
+		Type:       "resources",
+		Attributes: map[string]interface{}{"name": t.conf.Resource, "slug": t.conf.Resource},
+		Relationships: map[string]interface{}{
+			"project":     relationship("projects", t.projectID()),
+			"i18n_format": relationship("i18n_formats", "PO"),
+		},
+	}, nil)
+}
+
+// Push uploads the template as the source strings of the resource, waiting for Transifex to process it.
+func (t *transifex) Push(name string, pot []byte) error {
+	if err := t.ensureResource(); err != nil {
+		return err
+	}
+
+	var job transifexJob
+	err := t.do(http.MethodPost, "/resource_strings_async_uploads", &transifexData{
+		Type:          "resource_strings_async_uploads",
+		Attributes:    map[string]interface{}{"content": string(pot), "content_encoding": "text"},
+		Relationships: map[string]interface{}{"resource": relationship("resources", t.resourceID())},
+	}, &job)
+	if err != nil {
+		return err
+	}
+
+	return poll("upload", func() (bool, error) {
+		switch job.Data.Attributes.Status {
+		case "succeeded":
+			return true, nil
+		case "failed":
+			return false, job.err()
+		}
+		return false, t.do(http.MethodGet, "/resource_strings_async_uploads/"+job.Data.ID, nil, &job)
+	})
+}
+
+// Pull requests the translations of the resource in a language, then downloads them once Transifex compiled them.
+// The download job redirects to the file once done.
+func (t *transifex) Pull(name, lang string) ([]byte, error) {
+	var job transifexJob
+	err := t.do(http.MethodPost, "/resource_translations_async_downloads", &transifexData{
+		Type:       "resource_translations_async_downloads",
+		Attributes: map[string]interface{}{"content_encoding": "text", "file_type": "default", "mode": "default"},
+		Relationships: map[string]interface{}{
+			"language": relationship("languages", "l:"+lang),
+			"resource": relationship("resources", t.resourceID()),
+		},
+	}, &job)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	err = poll("download", func() (bool, error) {
+		if job.Data.Attributes.Status == "failed" {
+			return false, job.err()
+		}
+		if err := t.do(http.MethodGet, "/resource_translations_async_downloads/"+job.Data.ID, nil, &data); err != nil {
+			return false, err
+		}
+		// Pending jobs are returned as JSON documents, the file otherwise.
+		var state transifexJob
+		if json.Unmarshal(data, &state) != nil || state.Data.ID == "" {
+			return true, nil
+		}
+		job = state
+		return false, nil
+	})
+
+	return data, err
+}
@@ -0,0 +1,161 @@
+package command
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const transifexResource = "o:acme:p:app:r:default"
+
+// newTestTransifex returns a transifex provider of the resource "default" using the fake API.
+func newTestTransifex(t *testing.T, api *fakeAPI) syncProvider {
+	p, err := newTransifex(&syncConfig{Transifex: &transifexConfig{Organization: "acme", Project: "app", Resource: "default", Token: "secret", BaseURL: api.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+// fastPoll makes the jobs polled every millisecond, timing out after timeout, until the returned function is called.
+func fastPoll(timeout time.Duration) func() {
+	interval, prevTimeout := pollInterval, pollTimeout
+	pollInterval, pollTimeout = time.Millisecond, timeout
+
+	return func() {
+		pollInterval, pollTimeout = interval, prevTimeout
+	}
+}
+
+// transifexState returns a handler answering with the state of a job.
+func transifexState(id, status string, errors ...string) http.HandlerFunc {
+	var details []map[string]string
+	for _, e := range errors {
+		details = append(details, map[string]string{"code": "invalid", "detail": e})
+	}
+
+	return reply(map[string]interface{}{
+		"data": map[string]interface{}{"id": id, "attributes": map[string]interface{}{"status": status, "errors": details}},
+	})
+}
+
+func TestTransifexPush(t *testing.T) {
+	defer fastPoll(time.Minute)()
+
+	var checks int32
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"GET /resources/" + transifexResource:  reply(map[string]interface{}{"data": map[string]string{"id": transifexResource}}),
+		"POST /resource_strings_async_uploads": transifexState("u1", "pending"),
+		"GET /resource_strings_async_uploads/u1": func(w http.ResponseWriter, r *http.Request) {
+			status := "processing"
+			if atomic.AddInt32(&checks, 1) > 1 {
+				status = "succeeded"
+			}
+			transifexState("u1", status)(w, r)
+		},
+	})
+	defer api.Close()
+
+	if err := newTestTransifex(t, api).Push("default.pot", []byte(syncTemplate)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"GET /resources/" + transifexResource,
+		"POST /resource_strings_async_uploads",
+		"GET /resource_strings_async_uploads/u1",
+		"GET /resource_strings_async_uploads/u1",
+	}
+	if routes := api.routes(); !reflect.DeepEqual(routes, expected) {
+		t.Errorf("Expected requests %v but got %v", expected, routes)
+	}
+
+	upload := api.call(t, "POST /resource_strings_async_uploads")
+	if ct := upload.Header.Get("Content-Type"); ct != "application/vnd.api+json" {
+		t.Errorf("Expected 'application/vnd.api+json' but got '%s'", ct)
+	}
+	data := decodeBody(t, upload)["data"].(map[string]interface{})
+	if content := data["attributes"].(map[string]interface{})["content"]; content != syncTemplate {
+		t.Errorf("Expected the template but got '%v'", content)
+	}
+}
+
+func TestTransifexPushNewResource(t *testing.T) {
+	defer fastPoll(time.Minute)()
+
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"GET /resources/" + transifexResource: func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"errors": [{"status": "404"}]}`, http.StatusNotFound)
+		},
+		"POST /resources":                        reply(map[string]interface{}{"data": map[string]string{"id": transifexResource}}),
+		"POST /resource_strings_async_uploads":   transifexState("u1", "pending"),
+		"GET /resource_strings_async_uploads/u1": transifexState("u1", "failed", "Invalid PO file", "Duplicated msgid"),
+	})
+	defer api.Close()
+
+	err := newTestTransifex(t, api).Push("default.pot", []byte(syncTemplate))
+	if err == nil || err.Error() != "Invalid PO file; Duplicated msgid" {
+		t.Errorf("Expected the errors of the upload but got '%v'", err)
+	}
+
+	data := decodeBody(t, api.call(t, "POST /resources"))["data"].(map[string]interface{})
+	format := data["relationships"].(map[string]interface{})["i18n_format"].(map[string]interface{})["data"]
+	if !reflect.DeepEqual(format, map[string]interface{}{"type": "i18n_formats", "id": "PO"}) {
+		t.Errorf("Expected a PO resource but got %v", format)
+	}
+}
+
+func TestTransifexPull(t *testing.T) {
+	defer fastPoll(time.Minute)()
+
+	var checks int32
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /resource_translations_async_downloads": transifexState("d1", "pending"),
+		"GET /resource_translations_async_downloads/d1": func(w http.ResponseWriter, r *http.Request) {
+			// The job redirects to the file once done
+			if atomic.AddInt32(&checks, 1) == 1 {
+				transifexState("d1", "processing")(w, r)
+				return
+			}
+			fmt.Fprint(w, syncTranslation)
+		},
+	})
+	defer api.Close()
+
+	data, err := newTestTransifex(t, api).Pull("default.pot", "pt_BR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != syncTranslation {
+		t.Errorf("Expected the translation but got '%s'", data)
+	}
+	if n := atomic.LoadInt32(&checks); n != 2 {
+		t.Errorf("Expected 2 checks but got %d", n)
+	}
+
+	body := decodeBody(t, api.call(t, "POST /resource_translations_async_downloads"))
+	lang := body["data"].(map[string]interface{})["relationships"].(map[string]interface{})["language"]
+	if !reflect.DeepEqual(lang, map[string]interface{}{"data": map[string]interface{}{"type": "languages", "id": "l:pt_BR"}}) {
+		t.Errorf("Expected the 'l:pt_BR' language but got %v", lang)
+	}
+}
+
+func TestTransifexPullTimeout(t *testing.T) {
+	defer fastPoll(20 * time.Millisecond)()
+
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /resource_translations_async_downloads":   transifexState("d1", "pending"),
+		"GET /resource_translations_async_downloads/d1": transifexState("d1", "processing"),
+	})
+	defer api.Close()
+
+	_, err := newTestTransifex(t, api).Pull("default.pot", "de")
+	if err == nil || !strings.HasPrefix(err.Error(), "download timed out") {
+		t.Errorf("Expected a timeout but got '%v'", err)
+	}
+}
@@ -0,0 +1,123 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// weblateConfig configures the synchronization with Weblate.
+type weblateConfig struct {
+	// URL is the URL of the Weblate server, Hosted Weblate when not set.
+	URL       string `json:"url"`
+	Project   string `json:"project"`
+	Component string `json:"component"`
+
+	// SourceLanguage is the language of the source strings of the component, "en" when not set.
+	SourceLanguage string `json:"source_language"`
+
+	// Token is the API token, read from the WEBLATE_TOKEN environment variable when not set.
+	Token string `json:"token"`
+}
+
+// weblate synchronizes catalogs with a Weblate component, through its REST API.
+type weblate struct {
+	conf  weblateConfig
+	token string
+	base  string
+}
+
+func newWeblate(sc *syncConfig) (syncProvider, error) {
+	if sc.Weblate == nil || sc.Weblate.Project == "" || sc.Weblate.Component == "" {
+		return nil, fmt.Errorf("no weblate project and component configured")
+	}
+
+	w := &weblate{conf: *sc.Weblate}
+	var err error
+	if w.token, err = providerToken(w.conf.Token, "WEBLATE_TOKEN"); err != nil {
+		return nil, err
+	}
+	if w.conf.URL == "" {
+		w.conf.URL = "https://hosted.weblate.org"
+	}
+	if w.conf.SourceLanguage == "" {
+		w.conf.SourceLanguage = "en"
+	}
+	w.base = strings.TrimSuffix(w.conf.URL, "/") + "/api"
+
+	return w, nil
+}
+
+// translationPath returns the API path of the translation of the component in a language.
+func (w *weblate) translationPath(lang string) string {
+	return fmt.Sprintf("/translations/%s/%s/%s/file/", url.PathEscape(w.conf.Project), url.PathEscape(w.conf.Component), url.PathEscape(lang))
+}
+
+// send sends a request to the API, authenticated with the token.
+func (w *weblate) send(req *http.Request, out interface{}) error {
+	req.Header.Set("Authorization", "Token "+w.token)
+	return apiRequest(req, out)
+}
+
+// Push uploads the template as the source strings of the component.
+func (w *weblate) Push(name string, pot []byte) error {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if err := mw.WriteField("method", "source"); err != nil {
+		return err
+	}
+	fw, err := mw.CreateFormFile("file", name)
+	if err != nil {
+		return err
+	}
+	if _, err = fw.Write(pot); err != nil {
+		return err
+	}
+	if err = mw.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.base+w.translationPath(w.conf.SourceLanguage), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	return w.send(req, nil)
+}
+
+// Pull downloads the translation of the component in a language.
+func (w *weblate) Pull(name, lang string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, w.base+w.translationPath(lang)+"?format=po", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	err = w.send(req, &data)
+
+	return data, err
+}
+
+// Lock locks the component, so it isn't translated while its source strings change.
+func (w *weblate) Lock() error {
+	return w.lock(true)
+}
+
+// Unlock unlocks the component.
+func (w *weblate) Unlock() error {
+	return w.lock(false)
+}
+
+func (w *weblate) lock(lock bool) error {
+	path := fmt.Sprintf("/components/%s/%s/lock/", url.PathEscape(w.conf.Project), url.PathEscape(w.conf.Component))
+	req, err := newJSONRequest(http.MethodPost, w.base+path, map[string]bool{"lock": lock})
+	if err != nil {
+		return err
+	}
+
+	return w.send(req, nil)
+}
@@ -0,0 +1,91 @@
+package command
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// newTestWeblate returns a weblate provider of the component "app/default" using the fake API.
+func newTestWeblate(t *testing.T, api *fakeAPI) syncProvider {
+	p, err := newWeblate(&syncConfig{Weblate: &weblateConfig{URL: api.URL + "/", Project: "app", Component: "default", Token: "secret"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestWeblatePush(t *testing.T) {
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /api/translations/app/default/en/file/": reply(map[string]int{"accepted": 1}),
+	})
+	defer api.Close()
+
+	if err := newTestWeblate(t, api).Push("default.pot", []byte(syncTemplate)); err != nil {
+		t.Fatal(err)
+	}
+
+	c := api.call(t, "POST /api/translations/app/default/en/file/")
+	if auth := c.Header.Get("Authorization"); auth != "Token secret" {
+		t.Errorf("Expected 'Token secret' but got '%s'", auth)
+	}
+
+	fields, files := multipartFields(t, c)
+	if fields["method"] != "source" {
+		t.Errorf("Expected 'source' but got '%s'", fields["method"])
+	}
+	if files["default.pot"] != syncTemplate {
+		t.Errorf("Expected the template as 'default.pot' but got %v", files)
+	}
+}
+
+func TestWeblatePull(t *testing.T) {
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"GET /api/translations/app/default/pt_BR/file/": func(w http.ResponseWriter, r *http.Request) {
+			if format := r.URL.Query().Get("format"); format != "po" {
+				t.Errorf("Expected the 'po' format but got '%s'", format)
+			}
+			fmt.Fprint(w, syncTranslation)
+		},
+		"GET /api/translations/app/default/fr/file/": func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"detail": "Not found."}`, http.StatusNotFound)
+		},
+	})
+	defer api.Close()
+
+	p := newTestWeblate(t, api)
+	data, err := p.Pull("default.pot", "pt_BR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != syncTranslation {
+		t.Errorf("Expected the translation but got '%s'", data)
+	}
+
+	if _, err = p.Pull("default.pot", "fr"); !isNotFound(err) {
+		t.Errorf("Expected a not found error but got '%v'", err)
+	}
+}
+
+func TestWeblateLock(t *testing.T) {
+	api := newFakeAPI(t, map[string]http.HandlerFunc{
+		"POST /api/components/app/default/lock/": reply(map[string]bool{"locked": true}),
+	})
+	defer api.Close()
+
+	p := newTestWeblate(t, api).(syncLocker)
+	if err := p.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if body := decodeBody(t, api.call(t, "POST /api/components/app/default/lock/")); !reflect.DeepEqual(body, map[string]interface{}{"lock": true}) {
+		t.Errorf("Expected a lock but got %v", body)
+	}
+	if err := p.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if body := decodeBody(t, api.call(t, "POST /api/components/app/default/lock/")); !reflect.DeepEqual(body, map[string]interface{}{"lock": false}) {
+		t.Errorf("Expected an unlock but got %v", body)
+	}
+}
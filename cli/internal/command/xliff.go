@@ -0,0 +1,245 @@
+package command
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// XLIFF 1.2 documents, written as the translate-toolkit po2xliff converter does:
+// plural entries are groups of trans-units, one per form, and contexts and references are context groups.
+// The PO header is kept in a "po-header" note of the file header.
+type xliffDoc struct {
+	XMLName xml.Name    `xml:"urn:oasis:names:tc:xliff:document:1.2 xliff"`
+	Version string      `xml:"version,attr"`
+	Files   []xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	Original       string       `xml:"original,attr"`
+	SourceLanguage string       `xml:"source-language,attr"`
+	TargetLanguage string       `xml:"target-language,attr,omitempty"`
+	Datatype       string       `xml:"datatype,attr"`
+	Header         *xliffHeader `xml:"header"`
+	Body           xliffBody    `xml:"body"`
+}
+
+type xliffHeader struct {
+	Notes []xliffNote `xml:"note"`
+}
+
+type xliffBody struct {
+	Items []xliffItem `xml:",any"`
+}
+
+// xliffItem is a trans-unit, or a group of trans-units, of the body keeping their order.
+type xliffItem struct {
+	XMLName xml.Name
+	xliffUnit
+	Restype string      `xml:"restype,attr,omitempty"`
+	Units   []xliffUnit `xml:"trans-unit"`
+}
+
+type xliffUnit struct {
+	ID       string         `xml:"id,attr"`
+	Approved string         `xml:"approved,attr,omitempty"`
+	Source   string         `xml:"source,omitempty"`
+	Target   *xliffTarget   `xml:"target"`
+	Notes    []xliffNote    `xml:"note"`
+	Contexts []xliffContext `xml:"context-group"`
+}
+
+type xliffTarget struct {
+	State string `xml:"state,attr,omitempty"`
+	Text  string `xml:",chardata"`
+}
+
+type xliffNote struct {
+	From string `xml:"from,attr,omitempty"`
+	Text string `xml:",chardata"`
+}
+
+type xliffContext struct {
+	Name     string `xml:"name,attr,omitempty"`
+	Purpose  string `xml:"purpose,attr,omitempty"`
+	Contexts []struct {
+		Type string `xml:"context-type,attr"`
+		Text string `xml:",chardata"`
+	} `xml:"context"`
+}
+
+// addContext adds a context group of a single context to the unit.
+func (u *xliffUnit) addContext(name, purpose, typ, text string) {
+	c := xliffContext{Name: name, Purpose: purpose}
+	c.Contexts = append(c.Contexts, struct {
+		Type string `xml:"context-type,attr"`
+		Text string `xml:",chardata"`
+	}{typ, text})
+	u.Contexts = append(u.Contexts, c)
+}
+
+// context returns the text of the first context of the given type.
+func (u *xliffUnit) context(typ string) (string, bool) {
+	for _, g := range u.Contexts {
+		for _, c := range g.Contexts {
+			if c.Type == typ {
+				return c.Text, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// newXLIFFUnit returns the unit of a form of an entry.
+func newXLIFFUnit(id string, e *gotext.PoEntry, source, target string) xliffUnit {
+	u := xliffUnit{ID: id, Source: source}
+	if target != "" {
+		u.Approved = "yes"
+		state := "translated"
+		if e.HasFlag("fuzzy") {
+			u.Approved, state = "no", "needs-review-translation"
+		}
+		u.Target = &xliffTarget{State: state, Text: target}
+	}
+	for _, c := range e.ExtractedComments {
+		u.Notes = append(u.Notes, xliffNote{From: "developer", Text: c})
+	}
+	for _, c := range e.TranslatorComments {
+		u.Notes = append(u.Notes, xliffNote{From: "po-translator", Text: c})
+	}
+	if e.HasContext {
+		u.addContext("po-entry", "information", "x-po-msgctxt", e.Context)
+	}
+	for _, refs := range e.References {
+		for _, ref := range strings.Fields(refs) {
+			u.addContext("po-reference", "location", "sourcefile", ref)
+		}
+	}
+
+	return u
+}
+
+func writeXLIFF(f *gotext.PoFile, opts convertOptions) ([]byte, error) {
+	file := xliffFile{
+		Original:       "messages",
+		SourceLanguage: opts.sourceLanguage,
+		TargetLanguage: headerLanguage(f),
+		Datatype:       "po",
+	}
+	if h := headerText(f); h != "" {
+		file.Header = &xliffHeader{Notes: []xliffNote{{From: "po-header", Text: h}}}
+	}
+	for i, e := range translatable(f) {
+		id := strconv.Itoa(i + 1)
+		if e.PluralID == "" {
+			str := ""
+			if len(e.Str) > 0 {
+				str = e.Str[0]
+			}
+			file.Body.Items = append(file.Body.Items, xliffItem{XMLName: xml.Name{Local: "trans-unit"}, xliffUnit: newXLIFFUnit(id, e, e.ID, str)})
+			continue
+		}
+
+		g := xliffItem{XMLName: xml.Name{Local: "group"}, xliffUnit: xliffUnit{ID: id}, Restype: "x-gettext-plurals"}
+		for n, str := range e.Str {
+			source := e.PluralID
+			if n == 0 {
+				source = e.ID
+			}
+			g.Units = append(g.Units, newXLIFFUnit(fmt.Sprintf("%s[%d]", id, n), e, source, str))
+		}
+		file.Body.Items = append(file.Body.Items, g)
+	}
+
+	data, err := xml.MarshalIndent(xliffDoc{Version: "1.2", Files: []xliffFile{file}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append([]byte(xml.Header), data...), '\n'), nil
+}
+
+// xliffEntry returns the entry of a unit, without its translations.
+func xliffEntry(u xliffUnit) *gotext.PoEntry {
+	e := &gotext.PoEntry{ID: u.Source}
+	for _, n := range u.Notes {
+		if n.From == "po-translator" {
+			e.TranslatorComments = append(e.TranslatorComments, n.Text)
+		} else {
+			e.ExtractedComments = append(e.ExtractedComments, n.Text)
+		}
+	}
+	e.Context, e.HasContext = u.context("x-po-msgctxt")
+	for _, g := range u.Contexts {
+		for _, c := range g.Contexts {
+			if c.Type == "sourcefile" {
+				e.References = append(e.References, c.Text)
+			}
+		}
+	}
+	if u.Approved == "no" || (u.Target != nil && strings.HasPrefix(u.Target.State, "needs-")) {
+		e.SetFlag("fuzzy", true)
+	}
+
+	return e
+}
+
+// target returns the translation of a unit.
+func (u *xliffUnit) target() string {
+	if u.Target == nil {
+		return ""
+	}
+
+	return u.Target.Text
+}
+
+func readXLIFF(data []byte, _ convertOptions) (*gotext.PoFile, error) {
+	var doc xliffDoc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Files) == 0 {
+		return nil, fmt.Errorf("xliff: no file element")
+	}
+
+	f := &gotext.PoFile{Entries: []*gotext.PoEntry{catalogHeader(doc.Files[0].TargetLanguage)}}
+	if h := doc.Files[0].Header; h != nil {
+		for _, n := range h.Notes {
+			if n.From == "po-header" {
+				setHeaderText(f, n.Text)
+			}
+		}
+	}
+	for _, file := range doc.Files {
+		for _, item := range file.Body.Items {
+			switch item.XMLName.Local {
+			case "trans-unit":
+				e := xliffEntry(item.xliffUnit)
+				e.Str = []string{item.target()}
+				f.Entries = append(f.Entries, e)
+
+			case "group":
+				if len(item.Units) == 0 {
+					continue
+				}
+				e := xliffEntry(item.Units[0])
+				for i, u := range item.Units {
+					if i == 1 {
+						e.PluralID = u.Source
+					}
+					e.Str = append(e.Str, u.target())
+				}
+				if e.PluralID == "" {
+					e.PluralID = e.ID
+				}
+				f.Entries = append(f.Entries, e)
+			}
+		}
+	}
+
+	return f, nil
+}
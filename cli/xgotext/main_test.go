@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// mainArgs is the environment variable running the test binary as the tool, with the newline separated arguments it
+// holds, see runMain.
+const mainArgs = "GOTEXT_MAIN_ARGS"
+
+func TestMain(m *testing.M) {
+	if args, ok := os.LookupEnv(mainArgs); ok {
+		os.Args = append([]string{"xgotext"}, strings.Split(args, "\n")...)
+		if args == "" {
+			os.Args = os.Args[:1]
+		}
+		main()
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// runMain runs the tool with the arguments in dir, in a process of its own as commands exit on errors, and returns
+// its output and exit code.
+func runMain(t *testing.T, dir string, args ...string) (string, string, int) {
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), mainArgs+"="+strings.Join(args, "\n"))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	code := 0
+	if err := cmd.Run(); err != nil {
+		e, ok := err.(*exec.ExitError)
+		if !ok {
+			t.Fatal(err)
+		}
+		code = e.ExitCode()
+	}
+
+	return stdout.String(), stderr.String(), code
+}
+
+func TestDefaultCommand(t *testing.T) {
+	dir := "/tmp/xgotext_main"
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	po := "msgid \"\"\nmsgstr \"\"\n\"Language: de\\n\"\n\nmsgid \"Hello\"\nmsgstr \"Hallo\"\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "de.po"), []byte(po), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Arguments not starting with a command are the ones of extract, as before the commands were added
+	for _, args := range [][]string{nil, {"-out", "i18n"}, {"-config", "xgotext.json", "-out", "i18n"}} {
+		if _, stderr, code := runMain(t, dir, args...); code == 0 || !strings.Contains(stderr, "No input directory given") {
+			t.Errorf("Expected extract to be run for %v but got '%s'", args, stderr)
+		}
+	}
+	if _, stderr, code := runMain(t, dir, "-h"); code != 0 || !strings.HasPrefix(stderr, "Usage of xgotext extract: [flags]\n") {
+		t.Errorf("Expected the usage of extract but got '%s' and %d", stderr, code)
+	}
+
+	stdout, stderr, code := runMain(t, dir, "grep", "-msgid", "Hello", "de.po")
+	if expected := "de.po: \"Hello\" => \"Hallo\"\n"; code != 0 || stdout != expected {
+		t.Errorf("Expected '%s' but got '%s%s'", expected, stdout, stderr)
+	}
+	if _, stderr, code := runMain(t, dir, "help"); code != 0 || !strings.HasPrefix(stderr, "Usage of xgotext: [-config file] command") {
+		t.Errorf("Expected the usage but got '%s' and %d", stderr, code)
+	}
+}
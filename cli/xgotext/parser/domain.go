@@ -2,10 +2,13 @@ package parser
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/leonelquinteros/gotext"
 )
 
 // Translation for a text to translate
@@ -14,6 +17,8 @@ type Translation struct {
 	MsgIdPlural     string
 	Context         string
 	SourceLocations []string
+	// Default text of semantic keys, as a PO string written as the translation, empty for source strings
+	Default string
 }
 
 // AddLocations to translation
@@ -25,6 +30,14 @@ func (t *Translation) AddLocations(locations []string) {
 	}
 }
 
+// merge the locations of another occurrence of the translation, and its default text if it has none yet
+func (t *Translation) merge(other *Translation) {
+	t.AddLocations(other.SourceLocations)
+	if t.Default == "" {
+		t.Default = other.Default
+	}
+}
+
 // Dump translation as string
 func (t *Translation) Dump() string {
 	data := make([]string, 0, len(t.SourceLocations)+5)
@@ -39,7 +52,9 @@ func (t *Translation) Dump() string {
 
 	data = append(data, "msgid "+t.MsgId)
 
-	if t.MsgIdPlural == "" {
+	if t.MsgIdPlural == "" && t.Default != "" {
+		data = append(data, "msgstr "+t.Default)
+	} else if t.MsgIdPlural == "" {
 		data = append(data, "msgstr \"\"")
 	} else {
 		data = append(data,
@@ -74,6 +89,10 @@ func (m TranslationMap) Dump() string {
 type Domain struct {
 	Translations        TranslationMap
 	ContextTranslations map[string]TranslationMap
+	// Header fields set in the domain file
+	Header []HeaderField
+	// Banner lines written as comments before the header, none by default
+	Banner []string
 }
 
 // AddTranslation to the domain
@@ -85,7 +104,7 @@ func (d *Domain) AddTranslation(translation *Translation) {
 
 	if translation.Context == "" {
 		if t, ok := d.Translations[translation.MsgId]; ok {
-			t.AddLocations(translation.SourceLocations)
+			t.merge(translation)
 		} else {
 			d.Translations[translation.MsgId] = translation
 		}
@@ -95,7 +114,7 @@ func (d *Domain) AddTranslation(translation *Translation) {
 		}
 
 		if t, ok := d.ContextTranslations[translation.Context][translation.MsgId]; ok {
-			t.AddLocations(translation.SourceLocations)
+			t.merge(translation)
 		} else {
 			d.ContextTranslations[translation.Context][translation.MsgId] = translation
 		}
@@ -120,38 +139,136 @@ func (d *Domain) Dump() string {
 	return strings.Join(data, "\n\n")
 }
 
-// Save domain to file
-func (d *Domain) Save(path string) error {
-	file, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("failed to domain: %v", err)
+// HeaderField is a field of the header of the domain files
+type HeaderField struct {
+	Name  string
+	Value string
+}
+
+// defaultHeader is the header of new domain files
+const defaultHeader = "Plural-Forms: nplurals=2; plural=(n != 1);\n" +
+	"MIME-Version: 1.0\n" +
+	"Content-Type: text/plain; charset=UTF-8\n" +
+	"Content-Transfer-Encoding: 8bit\n" +
+	"Language: \n" +
+	"X-Generator: xgotext\n"
+
+// leadingHeaderFields are the fields set before the others when added to a header, in the order of GNU xgettext
+var leadingHeaderFields = []string{
+	"Project-Id-Version",
+	"Report-Msgid-Bugs-To",
+	"POT-Creation-Date",
+	"PO-Revision-Date",
+	"Last-Translator",
+	"Language-Team",
+}
+
+// header returns the header of the domain file: the one of the existing file, if any, so values edited by hand are
+// kept, else the default one, with the fields of the domain set.
+func (d *Domain) header(old []byte) string {
+	header := &gotext.PoEntry{Str: []string{defaultHeader}}
+	if f, err := gotext.ParsePoFile(old); err == nil && f.Header() != nil && len(f.Header().Str) > 0 {
+		header = f.Header()
 	}
-	defer file.Close()
 
-	// write header
-	_, err = file.WriteString(`msgid ""
-msgstr ""
-"Plural-Forms: nplurals=2; plural=(n != 1);\n"
-"MIME-Version: 1.0\n"
-"Content-Type: text/plain; charset=UTF-8\n"
-"Content-Transfer-Encoding: 8bit\n"
-"Language: \n"
-"X-Generator: xgotext\n"
+	fields := make(map[string]string, len(d.Header))
+	for _, field := range d.Header {
+		fields[field.Name] = field.Value
+	}
 
-`)
-	if err != nil {
-		return err
+	// missing leading fields are inserted after the leading fields preceding them, if any, the others appended
+	text := header.Str[0]
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	lines := strings.SplitAfter(text, "\n")
+	for i, name := range leadingHeaderFields {
+		value, ok := fields[name]
+		if !ok || headerLine(lines, name) >= 0 {
+			continue
+		}
+		at := 0
+		for _, previous := range leadingHeaderFields[:i] {
+			if j := headerLine(lines, previous); j >= at {
+				at = j + 1
+			}
+		}
+		lines = append(lines[:at], append([]string{name + ": " + value + "\n"}, lines[at:]...)...)
+		delete(fields, name)
+	}
+	header.Str[0] = strings.Join(lines, "")
+	for _, field := range d.Header {
+		if value, ok := fields[field.Name]; ok {
+			header.SetHeaderValue(field.Name, value)
+		}
+	}
+
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(header.Str[0], "\n") {
+		if line != "" {
+			b.WriteString(`"` + gotext.EscapePoString(line) + "\"\n")
+		}
+	}
+	return b.String()
+}
+
+// headerLine returns the index of the line of the header field, -1 if there's none
+func headerLine(lines []string, name string) int {
+	for i, line := range lines {
+		if strings.HasPrefix(line, name+":") {
+			return i
+		}
+	}
+	return -1
+}
+
+// banner returns the comments written before the header, flagged fuzzy as in GNU xgettext templates
+func (d *Domain) banner() string {
+	if len(d.Banner) == 0 {
+		return ""
 	}
 
-	// write domain content
-	_, err = file.WriteString(d.Dump())
-	return err
+	var b strings.Builder
+	for _, line := range d.Banner {
+		if line == "" {
+			b.WriteString("#\n")
+		} else {
+			b.WriteString("# " + line + "\n")
+		}
+	}
+	b.WriteString("#, fuzzy\n")
+	return b.String()
+}
+
+// Save domain to file.
+// The file is left untouched when its content is already up to date, so regenerating unchanged packages
+// doesn't modify their files.
+func (d *Domain) Save(path string) error {
+	old, _ := ioutil.ReadFile(path)
+
+	content := d.banner() + "msgid \"\"\nmsgstr \"\"\n" + d.header(old) + "\n" + d.Dump()
+
+	if old != nil && string(old) == content {
+		return nil
+	}
+
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		return fmt.Errorf("failed to domain: %v", err)
+	}
+	return nil
 }
 
 // DomainMap contains multiple domains as map with name as key
 type DomainMap struct {
 	Domains map[string]*Domain
 	Default string
+	// Header fields set in the domain files
+	Header []HeaderField
+	// Banner lines written as comments before the header of the domain files
+	Banner []string
+	// OutputDirs maps domains to the directories they're saved to and loaded from, instead of the one given
+	OutputDirs map[string]string
 }
 
 // AddTranslation to domain map
@@ -176,17 +293,29 @@ func (m *DomainMap) AddTranslation(domain string, translation *Translation) {
 	m.Domains[domain].AddTranslation(translation)
 }
 
-// Save domains to directory
-func (m *DomainMap) Save(directory string) error {
-	// ensure output directory exist
-	err := os.MkdirAll(directory, os.ModePerm)
-	if err != nil {
-		return fmt.Errorf("failed to create output dir: %v", err)
+// DomainPath returns the path of the domain file, in the output directory of the domain if mapped, else in directory
+func (m *DomainMap) DomainPath(directory, name string) string {
+	if dir, ok := m.OutputDirs[name]; ok {
+		directory = dir
 	}
+	return filepath.Join(directory, name+".pot")
+}
 
+// Save domains to directory
+func (m *DomainMap) Save(directory string) error {
 	// save each domain in a separate po file
 	for name, domain := range m.Domains {
-		err := domain.Save(filepath.Join(directory, name+".pot"))
+		path := m.DomainPath(directory, name)
+
+		// ensure output directory exist
+		err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("failed to create output dir: %v", err)
+		}
+
+		domain.Header = m.Header
+		domain.Banner = m.Banner
+		err = domain.Save(path)
 		if err != nil {
 			return fmt.Errorf("failed to save domain %s: %v", name, err)
 		}
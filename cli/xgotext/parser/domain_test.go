@@ -0,0 +1,211 @@
+package parser
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDomainMapOutputDirs(t *testing.T) {
+	dir := "/tmp/gotext_parser_output_dirs"
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+	out, adminOut := filepath.Join(dir, "i18n"), filepath.Join(dir, "admin", "locales")
+
+	data := &DomainMap{OutputDirs: map[string]string{"admin": adminOut}}
+	data.AddTranslation("", &Translation{MsgId: `"Hello"`, SourceLocations: []string{"main.go:1"}})
+	data.AddTranslation("admin", &Translation{MsgId: `"Dashboard"`, SourceLocations: []string{"admin/admin.go:2"}})
+
+	if path := data.DomainPath(out, "admin"); path != filepath.Join(adminOut, "admin.pot") {
+		t.Errorf("Expected '%s' but got '%s'", filepath.Join(adminOut, "admin.pot"), path)
+	}
+	if path := data.DomainPath(out, "default"); path != filepath.Join(out, "default.pot") {
+		t.Errorf("Expected '%s' but got '%s'", filepath.Join(out, "default.pot"), path)
+	}
+
+	if err := data.Save(out); err != nil {
+		t.Fatal(err)
+	}
+	if refs := readReferences(t, filepath.Join(adminOut, "admin.pot")); refs["Dashboard"] != "admin/admin.go:2" {
+		t.Errorf("Expected the admin domain in its output directory but got %v", refs)
+	}
+	if _, err := os.Stat(filepath.Join(out, "admin.pot")); !os.IsNotExist(err) {
+		t.Errorf("Expected no admin domain in the output directory but got '%v'", err)
+	}
+
+	// Domain files left in the output directory are saved elsewhere, so they aren't loaded
+	stale := "msgid \"\"\nmsgstr \"\"\n\n#: old.go:1\nmsgid \"Stale\"\nmsgstr \"\"\n"
+	if err := ioutil.WriteFile(filepath.Join(out, "admin.pot"), []byte(stale), 0644); err != nil {
+		t.Fatal(err)
+	}
+	loaded := &DomainMap{OutputDirs: data.OutputDirs}
+	if err := loaded.Load(out); err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Domains) != 2 {
+		t.Fatalf("Expected the 2 domains but got %v", loaded.Domains)
+	}
+	admin := loaded.Domains["admin"].Translations
+	if _, ok := admin[`"Dashboard"`]; !ok || len(admin) != 1 {
+		t.Errorf("Expected the admin domain of its output directory but got %v", admin)
+	}
+	if _, ok := loaded.Domains["default"].Translations[`"Hello"`]; !ok {
+		t.Errorf("Expected the default domain but got %v", loaded.Domains["default"].Translations)
+	}
+
+	// Mapped domains not saved yet are skipped
+	missing := &DomainMap{OutputDirs: map[string]string{"billing": filepath.Join(dir, "billing")}}
+	if err := missing.Load(out); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := missing.Domains["billing"]; ok {
+		t.Error("Expected no billing domain")
+	}
+}
+
+func TestDomainHeader(t *testing.T) {
+	dir := "/tmp/gotext_parser_header"
+	os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d := &Domain{Header: []HeaderField{
+		{Name: "Project-Id-Version", Value: "shop 1.2"},
+		{Name: "Report-Msgid-Bugs-To", Value: "bugs@example.com"},
+		{Name: "X-Team", Value: "web"},
+	}}
+	d.AddTranslation(&Translation{MsgId: `"Hello"`, SourceLocations: []string{"main.go:1"}})
+
+	// The leading fields of new files are set before the default ones, in the order of GNU xgettext
+	path := filepath.Join(dir, "default.pot")
+	if err := d.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	expected := `msgid ""
+msgstr ""
+"Project-Id-Version: shop 1.2\n"
+"Report-Msgid-Bugs-To: bugs@example.com\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Language: \n"
+"X-Generator: xgotext\n"
+"X-Team: web\n"
+
+#: main.go:1
+msgid "Hello"
+msgstr ""`
+	if content, _ := ioutil.ReadFile(path); string(content) != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, content)
+	}
+
+	// The fields of existing files are kept, the ones set being updated in place
+	edited := `msgid ""
+msgstr ""
+"Project-Id-Version: shop 1.1\n"
+"Language-Team: French <fr@example.com>\n"
+"X-Team: mobile\n"
+
+msgid "Old"
+msgstr ""
+`
+	if err := ioutil.WriteFile(path, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	expected = `msgid ""
+msgstr ""
+"Project-Id-Version: shop 1.2\n"
+"Report-Msgid-Bugs-To: bugs@example.com\n"
+"Language-Team: French <fr@example.com>\n"
+"X-Team: web\n"
+
+#: main.go:1
+msgid "Hello"
+msgstr ""`
+	if content, _ := ioutil.ReadFile(path); string(content) != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, content)
+	}
+}
+
+func TestDomainSaveUnchanged(t *testing.T) {
+	dir := "/tmp/gotext_parser_unchanged"
+	os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d := &Domain{Header: []HeaderField{{Name: "Project-Id-Version", Value: "shop 1.2"}}}
+	d.AddTranslation(&Translation{MsgId: `"Hello"`})
+	path := filepath.Join(dir, "default.pot")
+	if err := d.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// Up to date files aren't written again
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || !info.ModTime().Equal(old) {
+		t.Errorf("Expected the file to be left untouched but got '%v'", err)
+	}
+}
+
+func TestDomainBanner(t *testing.T) {
+	dir := "/tmp/gotext_parser_banner"
+	os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := &DomainMap{Banner: []string{"Shop translations.", "Copyright (C) 2024 ACME", ""}}
+	data.AddTranslation("", &Translation{MsgId: `"Hello"`})
+	if err := data.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// The banner is written before the header, flagged fuzzy, and the file is still read as a template
+	path := filepath.Join(dir, "default.pot")
+	content, _ := ioutil.ReadFile(path)
+	prologue := "# Shop translations.\n# Copyright (C) 2024 ACME\n#\n#, fuzzy\nmsgid \"\"\nmsgstr \"\"\n\"Plural-Forms: "
+	if !strings.HasPrefix(string(content), prologue) {
+		t.Errorf("Expected the file to start with '%s' but got '%s'", prologue, content)
+	}
+	d, err := LoadDomain(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := d.Translations[`"Hello"`]; !ok || len(d.Translations) != 1 {
+		t.Errorf("Expected the strings of the file but got %v", d.Translations)
+	}
+
+	// The banner of existing files is replaced, and none is written by default
+	data.Banner = []string{"Shop translations, revised."}
+	if err := data.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := ioutil.ReadFile(path); !strings.HasPrefix(string(content), "# Shop translations, revised.\n#, fuzzy\nmsgid \"\"\n") {
+		t.Errorf("Expected the new banner but got '%s'", content)
+	}
+	data.Banner = nil
+	if err := data.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := ioutil.ReadFile(path); !strings.HasPrefix(string(content), "msgid \"\"\n") {
+		t.Errorf("Expected no banner but got '%s'", content)
+	}
+}
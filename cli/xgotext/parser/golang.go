@@ -3,12 +3,14 @@ package parser
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"go/types"
 	"log"
 	"path/filepath"
 	"strconv"
 
+	"github.com/leonelquinteros/gotext"
 	"golang.org/x/tools/go/packages"
 )
 
@@ -18,6 +20,8 @@ type GetterDef struct {
 	Plural  int
 	Context int
 	Domain  int
+	// Default is the default text of semantic keys, written as their translation in the template
+	Default int
 }
 
 // maxArgIndex returns the largest argument index
@@ -32,19 +36,88 @@ func (d *GetterDef) maxArgIndex() int {
 	if d.Domain > m {
 		m = d.Domain
 	}
+	if d.Default > m {
+		m = d.Default
+	}
 	return m
 }
 
 // list of supported getter
 var gotextGetter = map[string]GetterDef{
-	"Get":    {0, -1, -1, -1},
-	"GetN":   {0, 1, -1, -1},
-	"GetD":   {1, -1, -1, 0},
-	"GetND":  {1, 2, -1, 0},
-	"GetC":   {0, -1, 1, -1},
-	"GetNC":  {0, 1, 3, -1},
-	"GetDC":  {1, -1, 2, 0},
-	"GetNDC": {1, 2, 4, 0},
+	"Get":    {0, -1, -1, -1, -1},
+	"GetN":   {0, 1, -1, -1, -1},
+	"GetD":   {1, -1, -1, 0, -1},
+	"GetND":  {1, 2, -1, 0, -1},
+	"GetC":   {0, -1, 1, -1, -1},
+	"GetNC":  {0, 1, 3, -1, -1},
+	"GetDC":  {1, -1, 2, 0, -1},
+	"GetNDC": {1, 2, 4, 0, -1},
+	"TKey":   {0, -1, -1, -1, 1},
+	"TKeyD":  {1, -1, -1, 0, 2},
+	"Lazy":   {0, -1, -1, -1, -1},
+	"LazyD":  {1, -1, -1, 0, -1},
+	"LazyN":  {0, 1, -1, -1, -1},
+	"LazyC":  {0, -1, 1, -1, -1},
+
+	"NewError":  {0, -1, -1, -1, -1},
+	"NewErrorD": {1, -1, -1, 0, -1},
+	"WrapError": {1, -1, -1, -1, -1},
+
+	"GetHTML":   {0, -1, -1, -1, -1},
+	"GetNHTML":  {0, 1, -1, -1, -1},
+	"GetCHTML":  {0, -1, 1, -1, -1},
+	"GetNCHTML": {0, 1, 3, -1, -1},
+}
+
+// markdown getters, whose document is extracted paragraph by paragraph as gotext translates it
+var markdownGetter = map[string]GetterDef{
+	"GetMarkdown":  {0, -1, -1, -1, -1},
+	"GetDMarkdown": {1, -1, -1, 0, -1},
+}
+
+// gotextPackage is the import path of the gotext package
+const gotextPackage = "github.com/leonelquinteros/gotext"
+
+// CallPattern describes the calls of a function or method of another package than gotext whose string arguments are
+// extracted, i.e. of a framework built on top of gotext
+type CallPattern struct {
+	// Package is the import path of the function, or of the type of the method
+	Package string
+	// Name is the function name, or "Type.Method" for methods
+	Name string
+	GetterDef
+}
+
+// call patterns by package path and name
+var callPatterns map[string]map[string]GetterDef
+
+// SetCallPatterns replaces the call patterns extracted in addition to the gotext getters
+func SetCallPatterns(patterns []CallPattern) {
+	callPatterns = make(map[string]map[string]GetterDef)
+	for _, p := range patterns {
+		if callPatterns[p.Package] == nil {
+			callPatterns[p.Package] = make(map[string]GetterDef)
+		}
+		callPatterns[p.Package][p.Name] = p.GetterDef
+	}
+}
+
+// lookupGetter returns the getter called, by the package path and type name of the callee, empty for functions
+func lookupGetter(pkgPath, typeName, name string) (GetterDef, bool) {
+	key := name
+	if typeName != "" {
+		key = typeName + "." + name
+	}
+	if def, ok := callPatterns[pkgPath][key]; ok {
+		return def, true
+	}
+
+	// all gotext types share the getter names
+	if pkgPath == gotextPackage {
+		def, ok := gotextGetter[name]
+		return def, ok
+	}
+	return GetterDef{}, false
 }
 
 // register go parser
@@ -77,9 +150,10 @@ func goParser(dirPath, basePath string, data *DomainMap) error {
 		return nil
 	}
 
-	// handle each file
-	for _, node := range pkgs[0].Syntax {
-		file := GoFile{
+	files := make([]*GoFile, len(pkgs[0].Syntax))
+	namespaces := make(map[types.Object]string)
+	for i, node := range pkgs[0].Syntax {
+		files[i] = &GoFile{
 			pkgConf:  &conf,
 			filePath: fileSet.Position(node.Package).Filename,
 			basePath: basePath,
@@ -89,10 +163,27 @@ func goParser(dirPath, basePath string, data *DomainMap) error {
 			importedPackages: map[string]*packages.Package{
 				pkgs[0].Name: pkgs[0],
 			},
+			namespaces: namespaces,
 		}
+	}
 
-		ast.Inspect(node, file.inspectFile)
+	// package level namespaces first, as they can be used by any file
+	for i, node := range pkgs[0].Syntax {
+		for _, decl := range node.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.VAR {
+				for _, spec := range gen.Specs {
+					files[i].assignValueSpec(spec.(*ast.ValueSpec))
+				}
+			}
+		}
 	}
+
+	// handle each file
+	for i, node := range pkgs[0].Syntax {
+		ast.Inspect(node, files[i].inspectFile)
+	}
+
+	parseEmbeddedTemplates(pkgs[0].Syntax, dirPath, basePath, data)
 	return nil
 }
 
@@ -106,6 +197,9 @@ type GoFile struct {
 	pkgConf *packages.Config
 
 	importedPackages map[string]*packages.Package
+
+	// prefixes of the gotext namespaces assigned to the variables of the package
+	namespaces map[types.Object]string
 }
 
 // getPackage loads module by name
@@ -130,6 +224,87 @@ func (g *GoFile) getType(ident *ast.Ident) types.Object {
 	return nil
 }
 
+// getObject returns the object defined or used by the ident
+func (g *GoFile) getObject(ident *ast.Ident) types.Object {
+	for _, pkg := range g.importedPackages {
+		if obj, ok := pkg.TypesInfo.Defs[ident]; ok && obj != nil {
+			return obj
+		}
+	}
+	return g.getType(ident)
+}
+
+// namespacePrefix returns the prefix of the namespace created by the expression, a call of the Namespace method of a
+// Locale or namespace with a string literal
+func (g *GoFile) namespacePrefix(expr ast.Expr) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return "", false
+	}
+	fun, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || fun.Sel.Name != "Namespace" {
+		return "", false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	prefix, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+
+	var recv types.Object
+	switch x := fun.X.(type) {
+	case *ast.Ident:
+		recv = g.getType(x)
+	case *ast.SelectorExpr:
+		recv = g.getType(x.Sel)
+	}
+	if recv == nil {
+		return "", false
+	}
+	switch pkgPath, typeName := namedType(recv.Type()); {
+	case pkgPath != gotextPackage:
+		return "", false
+	case typeName == "Namespace":
+		parent, ok := g.namespaces[recv]
+		return parent + prefix, ok
+	case typeName == "Locale":
+		return prefix, true
+	}
+	return "", false
+}
+
+// assignNamespaces records the prefixes of the namespaces assigned to the identifiers
+func (g *GoFile) assignNamespaces(idents []ast.Expr, values []ast.Expr) {
+	if len(idents) != len(values) {
+		return
+	}
+	for i, value := range values {
+		ident, ok := idents[i].(*ast.Ident)
+		if !ok {
+			continue
+		}
+		prefix, ok := g.namespacePrefix(value)
+		if !ok {
+			continue
+		}
+		if obj := g.getObject(ident); obj != nil && g.namespaces != nil {
+			g.namespaces[obj] = prefix
+		}
+	}
+}
+
+// assignValueSpec records the prefixes of the namespaces of the variable declaration
+func (g *GoFile) assignValueSpec(spec *ast.ValueSpec) {
+	idents := make([]ast.Expr, len(spec.Names))
+	for i, name := range spec.Names {
+		idents[i] = name
+	}
+	g.assignNamespaces(idents, spec.Values)
+}
+
 func (g *GoFile) inspectFile(n ast.Node) bool {
 	switch x := n.(type) {
 	// get names of imported packages
@@ -147,6 +322,13 @@ func (g *GoFile) inspectFile(n ast.Node) bool {
 			}
 		}
 
+	// remember the prefixes of namespaces
+	case *ast.AssignStmt:
+		g.assignNamespaces(x.Lhs, x.Rhs)
+
+	case *ast.ValueSpec:
+		g.assignValueSpec(x)
+
 	// check each function call
 	case *ast.CallExpr:
 		g.inspectCallExpr(x)
@@ -158,20 +340,19 @@ func (g *GoFile) inspectFile(n ast.Node) bool {
 	return true
 }
 
-// checkType for gotext object
-func (g *GoFile) checkType(rawType types.Type) bool {
+// namedType returns the package path and name of the named type, or of the one pointed to
+func namedType(rawType types.Type) (string, string) {
 	switch t := rawType.(type) {
 	case *types.Pointer:
-		return g.checkType(t.Elem())
+		return namedType(t.Elem())
 
 	case *types.Named:
-		if t.Obj().Pkg() == nil || t.Obj().Pkg().Path() != "github.com/leonelquinteros/gotext" {
-			return false
+		if t.Obj().Pkg() == nil {
+			return "", ""
 		}
-	default:
-		return false
+		return t.Obj().Pkg().Path(), t.Obj().Name()
 	}
-	return true
+	return "", ""
 }
 
 func (g *GoFile) inspectCallExpr(n *ast.CallExpr) {
@@ -181,62 +362,142 @@ func (g *GoFile) inspectCallExpr(n *ast.CallExpr) {
 		return
 	}
 
+	var pkgPath, typeName string
+	var recv types.Object
 	switch e := expr.X.(type) {
 	// direct call
 	case *ast.Ident:
 		// object is a package if the Obj is not set
 		if e.Obj == nil {
 			pkg, ok := g.importedPackages[e.Name]
-			if !ok || pkg.PkgPath != "github.com/leonelquinteros/gotext" {
+			if !ok {
 				return
 			}
+			pkgPath = pkg.PkgPath
 
 		} else {
-			// validate type of object
-			t := g.getType(e)
-			if t == nil || !g.checkType(t.Type()) {
+			// get type of object
+			recv = g.getType(e)
+			if recv == nil {
 				return
 			}
+			pkgPath, typeName = namedType(recv.Type())
 		}
 
 	// call to attribute
 	case *ast.SelectorExpr:
-		// validate type of object
-		t := g.getType(e.Sel)
-		if t == nil || !g.checkType(t.Type()) {
+		// get type of object
+		recv = g.getType(e.Sel)
+		if recv == nil {
 			return
 		}
+		pkgPath, typeName = namedType(recv.Type())
 
 	default:
 		return
 	}
 
+	// handle getters
+	def, ok := lookupGetter(pkgPath, typeName, expr.Sel.String())
+	markdown := false
+	if !ok && pkgPath == gotextPackage {
+		def, markdown = markdownGetter[expr.Sel.String()]
+		ok = markdown
+	}
+	if !ok {
+		return
+	}
+
 	// convert args
 	args := make([]*ast.BasicLit, len(n.Args))
 	for idx, arg := range n.Args {
-		args[idx], _ = arg.(*ast.BasicLit)
+		args[idx] = g.literal(arg)
 	}
 
-	// get position
+	// get position, with slashes so references are the same on every OS
 	path, _ := filepath.Rel(g.basePath, g.filePath)
-	position := fmt.Sprintf("%s:%d", path, g.fileSet.Position(n.Lparen).Line)
+	position := fmt.Sprintf("%s:%d", filepath.ToSlash(path), g.fileSet.Position(n.Lparen).Line)
 
-	// handle getters
-	if def, ok := gotextGetter[expr.Sel.String()]; ok {
-		g.parseGetter(def, args, position)
+	if markdown {
+		g.parseMarkdown(def, args, position)
 		return
 	}
+
+	// namespaces prefix the keys and contexts
+	var prefix string
+	if pkgPath == gotextPackage && typeName == "Namespace" {
+		if prefix, ok = g.namespaces[recv]; !ok {
+			log.Printf("ERR: Unsupported call at %s (Namespace prefix unknown)", position)
+			return
+		}
+	}
+
+	g.parseGetter(def, args, position, prefix)
 }
 
-func (g *GoFile) parseGetter(def GetterDef, args []*ast.BasicLit, pos string) {
+// parseMarkdown adds the paragraphs of the Markdown document of the getter call, each one being looked up as a msgid
+func (g *GoFile) parseMarkdown(def GetterDef, args []*ast.BasicLit, pos string) {
+	if len(args) <= def.maxArgIndex() {
+		return
+	}
+
+	var domain string
+	if def.Domain != -1 && args[def.Domain] != nil {
+		domain, _ = strconv.Unquote(args[def.Domain].Value)
+	}
+
+	if args[def.Id] == nil || args[def.Id].Kind != token.STRING {
+		log.Printf("ERR: Unsupported call at %s (Markdown not a string)", pos)
+		return
+	}
+	src, err := strconv.Unquote(args[def.Id].Value)
+	if err != nil {
+		log.Printf("ERR: Unsupported call at %s (%s)", pos, err)
+		return
+	}
+
+	for _, msgid := range gotext.MarkdownParagraphs(src) {
+		g.data.AddTranslation(domain, &Translation{
+			MsgId:           poLiteral(msgid),
+			SourceLocations: []string{pos},
+		})
+	}
+}
+
+// literal returns the argument as a literal: the string constants, i.e. named or concatenated, as string literals of
+// their value, nil for other expressions than literals
+func (g *GoFile) literal(arg ast.Expr) *ast.BasicLit {
+	if lit, ok := arg.(*ast.BasicLit); ok {
+		return lit
+	}
+	for _, pkg := range g.importedPackages {
+		if tv, ok := pkg.TypesInfo.Types[arg]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			return &ast.BasicLit{ValuePos: arg.Pos(), Kind: token.STRING, Value: strconv.Quote(constant.StringVal(tv.Value))}
+		}
+	}
+	return nil
+}
+
+// prefixedLiteral returns the Go string literal lit prefixed, as a PO string
+func prefixedLiteral(prefix, lit string) string {
+	s, err := strconv.Unquote(lit)
+	if err != nil {
+		return lit
+	}
+	return poLiteral(prefix + s)
+}
+
+// parseGetter adds the translation of the getter call, the ID of semantic keys or the context of others prefixed with
+// the namespace prefix, if any
+func (g *GoFile) parseGetter(def GetterDef, args []*ast.BasicLit, pos, prefix string) {
 	// check if enough arguments are given
-	if len(args) < def.maxArgIndex() {
+	if len(args) <= def.maxArgIndex() {
 		return
 	}
 
 	// get domain
 	var domain string
-	if def.Domain != -1 {
+	if def.Domain != -1 && args[def.Domain] != nil {
 		domain, _ = strconv.Unquote(args[def.Domain].Value)
 	}
 
@@ -247,24 +508,37 @@ func (g *GoFile) parseGetter(def GetterDef, args []*ast.BasicLit, pos string) {
 	}
 
 	trans := Translation{
-		MsgId:           args[def.Id].Value,
+		MsgId:           normalizeLiteral(args[def.Id].Value),
 		SourceLocations: []string{pos},
 	}
-	if def.Plural > 0 {
+	if prefix != "" && def.Default != -1 {
+		trans.MsgId = prefixedLiteral(prefix, args[def.Id].Value)
+	} else if prefix != "" && def.Context == -1 {
+		trans.Context = poLiteral(prefix)
+	}
+	if def.Plural != -1 {
 		// plural ID must be a string
 		if args[def.Plural] == nil || args[def.Plural].Kind != token.STRING {
 			log.Printf("ERR: Unsupported call at %s (Plural not a string)", pos)
 			return
 		}
-		trans.MsgIdPlural = args[def.Plural].Value
+		trans.MsgIdPlural = normalizeLiteral(args[def.Plural].Value)
 	}
-	if def.Context > 0 {
+	if def.Context != -1 {
 		// Context must be a string
 		if args[def.Context] == nil || args[def.Context].Kind != token.STRING {
 			log.Printf("ERR: Unsupported call at %s (Context not a string)", pos)
 			return
 		}
-		trans.Context = args[def.Context].Value
+		trans.Context = prefixedLiteral(prefix, args[def.Context].Value)
+	}
+	if def.Default != -1 {
+		// Default must be a string
+		if args[def.Default] == nil || args[def.Default].Kind != token.STRING {
+			log.Printf("ERR: Unsupported call at %s (Default not a string)", pos)
+			return
+		}
+		trans.Default = normalizeLiteral(args[def.Default].Value)
 	}
 
 	g.data.AddTranslation(domain, &trans)
@@ -0,0 +1,294 @@
+package parser
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLookupGetter(t *testing.T) {
+	SetCallPatterns([]CallPattern{
+		{Package: "example.com/i18n", Name: "T", GetterDef: GetterDef{0, -1, -1, -1, 1}},
+		{Package: "example.com/i18n", Name: "Translator.N", GetterDef: GetterDef{1, 2, -1, 0, -1}},
+		// patterns take precedence over the gotext getters
+		{Package: gotextPackage, Name: "Locale.Get", GetterDef: GetterDef{0, -1, -1, -1, -1}},
+	})
+	defer SetCallPatterns(nil)
+
+	for _, test := range []struct {
+		pkgPath, typeName, name string
+		expected                GetterDef
+		ok                      bool
+	}{
+		{"example.com/i18n", "", "T", GetterDef{0, -1, -1, -1, 1}, true},
+		{"example.com/i18n", "Translator", "N", GetterDef{1, 2, -1, 0, -1}, true},
+		{"example.com/i18n", "Translator", "T", GetterDef{}, false},
+		{"example.com/i18n", "", "N", GetterDef{}, false},
+		{gotextPackage, "", "GetD", gotextGetter["GetD"], true},
+		{gotextPackage, "Po", "GetN", gotextGetter["GetN"], true},
+		{gotextPackage, "Locale", "Get", GetterDef{0, -1, -1, -1, -1}, true},
+		{"example.com/other", "", "Get", GetterDef{}, false},
+	} {
+		def, ok := lookupGetter(test.pkgPath, test.typeName, test.name)
+		if ok != test.ok || def != test.expected {
+			t.Errorf("Expected %v, %v for %s %s.%s but got %v, %v", test.expected, test.ok, test.pkgPath, test.typeName, test.name, def, ok)
+		}
+	}
+}
+
+func TestCallPatterns(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_parser_calls"
+	writeFixture(t, dir, map[string]string{
+		"go.mod": goMod("example.com/app"),
+		"i18n/i18n.go": `package i18n
+
+type Translator struct{}
+
+func T(key, def string) string { return def }
+
+func (t *Translator) N(domain, one, other string, n int) string { return one }
+`,
+		"main.go": `package main
+
+import "example.com/app/i18n"
+
+func main() {
+	i18n.T("home.title", "Welcome")
+	tr := &i18n.Translator{}
+	tr.N("admin", "One user", "%d users", 2)
+	tr.N("admin", "One user", "%d users", 3)
+}
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	SetCallPatterns([]CallPattern{
+		{Package: "example.com/app/i18n", Name: "T", GetterDef: GetterDef{0, -1, -1, -1, 1}},
+		{Package: "example.com/app/i18n", Name: "Translator.N", GetterDef: GetterDef{1, 2, -1, 0, -1}},
+	})
+	defer SetCallPatterns(nil)
+
+	data := &DomainMap{Default: "default"}
+	if err := ParseDirRec(dir, nil, data, false); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out")
+	if err := data.Save(out); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(out, "default.pot"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the default text of keys is written as their translation
+	if !strings.Contains(string(content), "#: main.go:6\nmsgid \"home.title\"\nmsgstr \"Welcome\"") {
+		t.Errorf("Expected the key with its default text but got '%s'", content)
+	}
+
+	content, err = ioutil.ReadFile(filepath.Join(out, "admin.pot"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "#: main.go:8\n#: main.go:9\nmsgid \"One user\"\nmsgid_plural \"%d users\"\n") {
+		t.Errorf("Expected the plural string of the method calls but got '%s'", content)
+	}
+}
+
+// extractGetters extracts the strings of a fixture module made of main.go, returning the output directory of the templates.
+func extractGetters(t *testing.T, dir, main string) string {
+	writeFixture(t, dir, map[string]string{
+		"go.mod":  goMod("example.com/app"),
+		"main.go": main,
+	})
+
+	data := &DomainMap{Default: "default"}
+	if err := ParseDirRec(dir, nil, data, false); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out")
+	if err := data.Save(out); err != nil {
+		t.Fatal(err)
+	}
+
+	return out
+}
+
+// checkExtracted checks the references of the strings of the templates of out, by file and msgid.
+func checkExtracted(t *testing.T, out string, expected map[string]map[string]string) {
+	for file, ids := range expected {
+		refs := readReferences(t, filepath.Join(out, file))
+		for id, ref := range ids {
+			if refs[id] != ref {
+				t.Errorf("Expected '%s' for '%s' in %s but got '%s'", ref, id, file, refs[id])
+			}
+		}
+		if len(refs) != len(ids) {
+			t.Errorf("Expected %d strings in %s but got %v", len(ids), file, refs)
+		}
+	}
+}
+
+func TestLazyGetters(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_parser_lazy"
+	defer os.RemoveAll(dir)
+	out := extractGetters(t, dir, `package main
+
+import (
+	"fmt"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+var (
+	title  = gotext.Lazy("Welcome")
+	denied = gotext.LazyD("errors", "Access denied")
+	files  = gotext.LazyN("One file", "%d files", 2, 2)
+	open   = gotext.LazyC("Open", "menu")
+)
+
+func main() {
+	fmt.Println(title, denied, files, open)
+}
+`)
+
+	// strings declared lazily are extracted as the ones of the getters
+	checkExtracted(t, out, map[string]map[string]string{
+		"default.pot": {"Welcome": "main.go:10", "One file": "main.go:12", "menu|Open": "main.go:13"},
+		"errors.pot":  {"Access denied": "main.go:11"},
+	})
+	content, err := ioutil.ReadFile(filepath.Join(out, "default.pot"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "msgid \"One file\"\nmsgid_plural \"%d files\"\n") {
+		t.Errorf("Expected the plural of LazyN but got '%s'", content)
+	}
+}
+
+func TestErrorGetters(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_parser_errors"
+	defer os.RemoveAll(dir)
+	out := extractGetters(t, dir, `package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+func main() {
+	fmt.Println(gotext.NewError("File %s not found", "a.txt"))
+	fmt.Println(gotext.NewErrorD("errors", "Access denied"))
+	fmt.Println(gotext.WrapError(os.ErrClosed, "Can't write %s", "a.txt"))
+}
+`)
+
+	// the msgid of WrapError follows the wrapped error
+	checkExtracted(t, out, map[string]map[string]string{
+		"default.pot": {"File %s not found": "main.go:11", "Can't write %s": "main.go:13"},
+		"errors.pot":  {"Access denied": "main.go:12"},
+	})
+}
+
+func TestHTMLGetters(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_parser_html"
+	defer os.RemoveAll(dir)
+	out := extractGetters(t, dir, `package main
+
+import (
+	"fmt"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+func main() {
+	l := gotext.NewLocale("locales", "fr")
+	fmt.Println(gotext.GetHTML("Hello <b>%s</b>", "you"))
+	fmt.Println(gotext.GetNHTML("<i>One</i> file", "<i>%d</i> files", 2, 2))
+	fmt.Println(l.GetCHTML("<b>Open</b>", "menu"))
+	fmt.Println(l.GetNCHTML("One <b>tab</b>", "%d <b>tabs</b>", 2, "menu", 2))
+}
+`)
+
+	checkExtracted(t, out, map[string]map[string]string{
+		"default.pot": {
+			"Hello <b>%s</b>":     "main.go:11",
+			"<i>One</i> file":     "main.go:12",
+			"menu|<b>Open</b>":    "main.go:13",
+			"menu|One <b>tab</b>": "main.go:14",
+		},
+	})
+}
+
+func TestMarkdownGetters(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_parser_markdown"
+	defer os.RemoveAll(dir)
+	out := extractGetters(t, dir, `package main
+
+import (
+	"fmt"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+func main() {
+	l := gotext.NewLocale("locales", "fr")
+	fmt.Println(gotext.GetMarkdown("# Welcome\n\nRead the **guide**.\n\n    go get ./...\n"))
+	fmt.Println(l.GetDMarkdown("help", "Ask for help."))
+}
+`)
+
+	// each paragraph is a msgid, code blocks aren't translated
+	checkExtracted(t, out, map[string]map[string]string{
+		"default.pot": {"# Welcome": "main.go:11", "Read the **guide**.": "main.go:11"},
+		"help.pot":    {"Ask for help.": "main.go:12"},
+	})
+}
+
+func TestConstantArguments(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_parser_constants"
+	defer os.RemoveAll(dir)
+	out := extractGetters(t, dir, `package main
+
+import (
+	"fmt"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+const (
+	greeting = "Hello"
+	errors   = "errors"
+)
+
+func main() {
+	name := "you"
+	fmt.Println(gotext.Get(greeting))
+	fmt.Println(gotext.Get("Hello " + "world"))
+	fmt.Println(gotext.GetD(errors, greeting+" error"))
+	fmt.Println(gotext.Get(name))
+}
+`)
+
+	// constants are extracted as literals, variables are left out as gotextvet reports them
+	checkExtracted(t, out, map[string]map[string]string{
+		"default.pot": {"Hello": "main.go:16", "Hello world": "main.go:17"},
+		"errors.pot":  {"Hello error": "main.go:18"},
+	})
+}
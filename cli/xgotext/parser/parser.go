@@ -47,10 +47,8 @@ func ParseDirRec(dirPath string, exclude []string, data *DomainMap, verbose bool
 		if info.IsDir() {
 			// skip directory if in exclude list
 			subDir, _ := filepath.Rel(dirPath, path)
-			for _, d := range exclude {
-				if strings.HasPrefix(subDir, d) {
-					return nil
-				}
+			if IsExcluded(subDir, exclude) {
+				return nil
 			}
 			if verbose {
 				log.Print(path)
@@ -65,3 +63,94 @@ func ParseDirRec(dirPath string, exclude []string, data *DomainMap, verbose bool
 	})
 	return err
 }
+
+// IsExcluded reports if the directory, relative to the parsed one, is excluded by ParseDirRec.
+func IsExcluded(dir string, exclude []string) bool {
+	for _, d := range exclude {
+		if d != "" && strings.HasPrefix(dir, d) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseDirs parses the directories, relative to basePath, updating the translations of the domain map.
+// Directories that don't exist anymore are skipped, their translations being removed with RemoveLocations.
+func ParseDirs(dirs []string, basePath string, data *DomainMap, verbose bool) error {
+	for _, dir := range dirs {
+		path := filepath.Join(basePath, dir)
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			continue
+		}
+		if verbose {
+			log.Print(path)
+		}
+		if err := ParseDir(path, basePath, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindModules returns the directories holding a go.mod file under dirPath, itself included, in walk order.
+// Hidden, vendor, testdata and excluded directories aren't walked.
+func FindModules(dirPath string, exclude []string) ([]string, error) {
+	var modules []string
+	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+
+		subDir, _ := filepath.Rel(dirPath, path)
+		if path != dirPath && (skippedDir(info.Name()) || IsExcluded(subDir, exclude)) {
+			return filepath.SkipDir
+		}
+		if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+			modules = append(modules, path)
+		}
+		return nil
+	})
+
+	return modules, err
+}
+
+// skippedDir reports if the directory is skipped by the go tool when matching packages.
+func skippedDir(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "vendor" || name == "testdata"
+}
+
+// ParseModule calls all known parser for each directory of the module in dirPath, without walking nested modules.
+// Source locations are relative to basePath.
+func ParseModule(dirPath, basePath string, exclude []string, data *DomainMap, verbose bool) error {
+	dirPath, _ = filepath.Abs(dirPath)
+	basePath, _ = filepath.Abs(basePath)
+
+	return filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+
+		if path != dirPath {
+			subDir, _ := filepath.Rel(dirPath, path)
+			if skippedDir(info.Name()) || IsExcluded(subDir, exclude) {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+		if verbose {
+			log.Print(path)
+		}
+
+		return ParseDir(path, basePath, data)
+	})
+}
@@ -0,0 +1,403 @@
+package parser
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// loaderProbe is the environment variable running the test binary as a probe of the package loader, see requireLoader.
+const loaderProbe = "GOTEXT_LOADER_PROBE"
+
+func TestMain(m *testing.M) {
+	if dir := os.Getenv(loaderProbe); dir != "" {
+		data := new(DomainMap)
+		if err := ParseDir(dir, dir, data); err != nil || len(data.Domains) == 0 {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+var (
+	loaderOnce sync.Once
+	loaderErr  error
+)
+
+// requireLoader skips the test when go/packages can't load the packages with the Go toolchain installed, as it exits the
+// process on the internal errors of toolchains newer than it supports. The test binary is run as a probe to find out.
+// On CI, with the CI environment variable set, the test fails instead, as the toolchain pinned by the workflow has to
+// load them.
+func requireLoader(t *testing.T) {
+	loaderOnce.Do(func() {
+		dir := "/tmp/gotext_parser_probe"
+		writeFixture(t, dir, map[string]string{
+			"go.mod":  goMod("example.com/probe"),
+			"main.go": "package main\n\nimport \"github.com/leonelquinteros/gotext\"\n\nfunc main() {\n\tgotext.Get(\"Probe\")\n}\n",
+		})
+
+		cmd := exec.Command(os.Args[0], "-test.run=^$")
+		cmd.Env = append(os.Environ(), loaderProbe+"="+dir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			loaderErr = fmt.Errorf("%v: %s", err, out)
+		}
+	})
+	if loaderErr != nil {
+		if os.Getenv("CI") != "" {
+			t.Fatalf("go/packages can't load packages with this Go toolchain: %v", loaderErr)
+		}
+		t.Skipf("go/packages can't load packages with this Go toolchain: %v", loaderErr)
+	}
+}
+
+// repoRoot is the root of the gotext module, the tests being run in the package directory.
+const repoRoot = "../../.."
+
+// goMod returns the go.mod file of a fixture module requiring gotext, replaced by the repository. Go 1.16 is the
+// first version embedding files.
+func goMod(module string) string {
+	root, _ := filepath.Abs(repoRoot)
+	return "module " + module + "\n\ngo 1.16\n\nrequire github.com/leonelquinteros/gotext v0.0.0\n\n" +
+		"replace github.com/leonelquinteros/gotext => " + filepath.ToSlash(root) + "\n"
+}
+
+// writeFixture writes the files of a fixture to dir, by path relative to it, replacing its previous content.
+// Modules get the go.sum of the repository, so their requirements are verified.
+func writeFixture(t *testing.T, dir string, files map[string]string) {
+	os.RemoveAll(dir)
+	sum, err := ioutil.ReadFile(filepath.Join(repoRoot, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Base(name) == "go.mod" {
+			if err := ioutil.WriteFile(filepath.Join(filepath.Dir(path), "go.sum"), sum, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+// readReferences returns the source references of the entries of the domain file, by msgid, prefixed with their context
+// and "|" when they have one.
+func readReferences(t *testing.T, path string) map[string]string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := gotext.ParsePoFile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs := make(map[string]string)
+	for _, e := range f.Entries {
+		if e.IsHeader() {
+			continue
+		}
+		key := e.ID
+		if e.HasContext {
+			key = e.Context + "|" + key
+		}
+		refs[key] = strings.Join(e.References, " ")
+	}
+	return refs
+}
+
+// appFixture is a module calling the gotext getters from two packages.
+var appFixture = map[string]string{
+	"go.mod": goMod("example.com/app"),
+	"main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/app/pkg"
+	"github.com/leonelquinteros/gotext"
+)
+
+func main() {
+	fmt.Println(gotext.Get("Hello"))
+	fmt.Println(gotext.GetN("One file", "%d files", 2))
+	fmt.Println(gotext.GetC("Open", "menu"))
+	fmt.Println(gotext.GetD("admin", "Dashboard"))
+	fmt.Println(pkg.Greet())
+}
+`,
+	"pkg/pkg.go": `package pkg
+
+import "github.com/leonelquinteros/gotext"
+
+func Greet() string {
+	return gotext.Get("Hello") + gotext.Get("Bye")
+}
+`,
+}
+
+func TestParseDirRec(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_parser_app"
+	writeFixture(t, dir, appFixture)
+	defer os.RemoveAll(dir)
+
+	data := &DomainMap{Default: "default"}
+	if err := ParseDirRec(dir, []string{".git"}, data, false); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "i18n")
+	if err := data.Save(out); err != nil {
+		t.Fatal(err)
+	}
+
+	refs := readReferences(t, filepath.Join(out, "default.pot"))
+	expected := map[string]string{
+		"Hello":     "main.go:11 pkg/pkg.go:6",
+		"One file":  "main.go:12",
+		"menu|Open": "main.go:13",
+		"Bye":       "pkg/pkg.go:6",
+	}
+	for id, ref := range expected {
+		if refs[id] != ref {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", ref, id, refs[id])
+		}
+	}
+	if len(refs) != len(expected) {
+		t.Errorf("Expected %d entries but got %v", len(expected), refs)
+	}
+
+	if refs := readReferences(t, filepath.Join(out, "admin.pot")); refs["Dashboard"] != "main.go:14" {
+		t.Errorf("Expected 'main.go:14' for 'Dashboard' but got %v", refs)
+	}
+}
+
+// TestParseDirs checks that updating the domain files with the packages changed gives the same files as a full
+// extraction.
+func TestParseDirs(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_parser_update"
+	writeFixture(t, dir, appFixture)
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "i18n")
+
+	data := &DomainMap{Default: "default"}
+	if err := ParseDirRec(dir, []string{".git", "i18n"}, data, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.Save(out); err != nil {
+		t.Fatal(err)
+	}
+
+	// The package strings change, and a new package is added
+	changes := map[string]string{
+		"pkg/pkg.go": "package pkg\n\nimport \"github.com/leonelquinteros/gotext\"\n\n" +
+			"func Greet() string {\n\treturn gotext.Get(\"Welcome\") + gotext.Get(\"Hello\")\n}\n",
+		"pkg/sub/sub.go": "package sub\n\nimport \"github.com/leonelquinteros/gotext\"\n\n" +
+			"var Title = gotext.Get(\"Hello\")\n",
+	}
+	for name, content := range changes {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), os.ModePerm)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	update := &DomainMap{Default: "default"}
+	if err := update.Load(out); err != nil {
+		t.Fatal(err)
+	}
+	changed := map[string]bool{"pkg": true, "pkg/sub": true}
+	update.RemoveLocations(func(file string) bool {
+		return changed[filepath.ToSlash(filepath.Dir(file))]
+	})
+	if err := ParseDirs([]string{"pkg", "pkg/sub", "removed"}, dir, update, false); err != nil {
+		t.Fatal(err)
+	}
+	update.SortLocations()
+	updated := filepath.Join(dir, "updated")
+	if err := update.Save(updated); err != nil {
+		t.Fatal(err)
+	}
+
+	refs := readReferences(t, filepath.Join(updated, "default.pot"))
+	if refs["Hello"] != "main.go:11 pkg/pkg.go:6 pkg/sub/sub.go:5" {
+		t.Errorf("Expected the references in walk order but got '%s'", refs["Hello"])
+	}
+	if _, ok := refs["Bye"]; ok {
+		t.Error("Expected the removed string to be removed")
+	}
+
+	full := &DomainMap{Default: "default"}
+	if err := ParseDirRec(dir, []string{".git", "i18n", "updated"}, full, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := full.Save(out); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"default.pot", "admin.pot"} {
+		expected, _ := ioutil.ReadFile(filepath.Join(out, name))
+		got, _ := ioutil.ReadFile(filepath.Join(updated, name))
+		if string(got) != string(expected) {
+			t.Errorf("Expected '%s' but got '%s'", expected, got)
+		}
+	}
+}
+
+func TestSortLocations(t *testing.T) {
+	data := &DomainMap{}
+	data.AddTranslation("", &Translation{
+		MsgId:           `"Hello"`,
+		SourceLocations: []string{"pkg/sub/a.go:1", "pkg/b.go:3", "main.go:10", "pkg/b.go:2", "main.go:9", "api/a.go:1"},
+	})
+	data.SortLocations()
+
+	expected := "main.go:9 main.go:10 api/a.go:1 pkg/b.go:2 pkg/b.go:3 pkg/sub/a.go:1"
+	if got := strings.Join(data.Domains["default"].Translations[`"Hello"`].SourceLocations, " "); got != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, got)
+	}
+}
+
+func TestRemoveLocations(t *testing.T) {
+	data := &DomainMap{}
+	data.AddTranslation("", &Translation{MsgId: `"Hello"`, SourceLocations: []string{"main.go:1", "pkg/pkg.go:2"}})
+	data.AddTranslation("", &Translation{MsgId: `"Bye"`, SourceLocations: []string{"pkg/pkg.go:3"}})
+	data.AddTranslation("", &Translation{MsgId: `"Open"`, Context: `"menu"`, SourceLocations: []string{"pkg/pkg.go:4"}})
+	data.RemoveLocations(func(file string) bool {
+		return strings.HasPrefix(file, "pkg/")
+	})
+
+	d := data.Domains["default"]
+	if got := strings.Join(d.Translations[`"Hello"`].SourceLocations, " "); got != "main.go:1" {
+		t.Errorf("Expected 'main.go:1' but got '%s'", got)
+	}
+	if _, ok := d.Translations[`"Bye"`]; ok {
+		t.Error("Expected the string without locations left to be removed")
+	}
+	if _, ok := d.ContextTranslations[`"menu"`][`"Open"`]; ok {
+		t.Error("Expected the context string without locations left to be removed")
+	}
+}
+
+func TestFindModules(t *testing.T) {
+	dir := "/tmp/gotext_parser_modules"
+	files := make(map[string]string)
+	for _, module := range []string{"", "a", "a/b", "vendor/v", "testdata/t", ".hidden", "_tmp", "excluded"} {
+		files[filepath.Join(module, "go.mod")] = "module example.com/" + module + "\n"
+	}
+	files["c/c.go"] = "package c\n"
+	writeFixture(t, dir, files)
+	defer os.RemoveAll(dir)
+
+	modules, err := FindModules(dir, []string{"excluded"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{dir, filepath.Join(dir, "a"), filepath.Join(dir, "a", "b")}
+	if strings.Join(modules, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %v but got %v", expected, modules)
+	}
+}
+
+// monorepoFixture is a module holding a nested one, both calling the gotext getters.
+var monorepoFixture = map[string]string{
+	"go.mod": goMod("example.com/mono"),
+	"main.go": `package main
+
+import "github.com/leonelquinteros/gotext"
+
+func main() {
+	gotext.Get("Hello")
+}
+`,
+	"services/api/go.mod": goMod("example.com/api"),
+	"services/api/main.go": `package main
+
+import "github.com/leonelquinteros/gotext"
+
+func main() {
+	gotext.Get("Hello")
+	gotext.Get("Not found")
+}
+`,
+}
+
+func TestParseModule(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_parser_monorepo"
+	writeFixture(t, dir, monorepoFixture)
+	defer os.RemoveAll(dir)
+
+	// The nested module isn't parsed with the one holding it
+	data := &DomainMap{Default: "default"}
+	if err := ParseModule(dir, dir, nil, data, false); err != nil {
+		t.Fatal(err)
+	}
+	translations := data.Domains["default"].Translations
+	if len(translations) != 1 || strings.Join(translations[`"Hello"`].SourceLocations, " ") != "main.go:6" {
+		t.Errorf("Expected the strings of the root module only but got %v", translations)
+	}
+
+	// Locations are relative to the base path
+	if err := ParseModule(filepath.Join(dir, "services", "api"), dir, nil, data, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(translations[`"Hello"`].SourceLocations, " "); got != "main.go:6 services/api/main.go:6" {
+		t.Errorf("Expected 'main.go:6 services/api/main.go:6' but got '%s'", got)
+	}
+	if got := strings.Join(translations[`"Not found"`].SourceLocations, " "); got != "services/api/main.go:7" {
+		t.Errorf("Expected 'services/api/main.go:7' but got '%s'", got)
+	}
+}
+
+func TestMapLocations(t *testing.T) {
+	data := &DomainMap{}
+	data.AddTranslation("", &Translation{MsgId: `"Hello"`, SourceLocations: []string{"main.go:1", "pkg/pkg.go:2"}})
+	data.AddTranslation("admin", &Translation{MsgId: `"Open"`, Context: `"menu"`, SourceLocations: []string{"admin.go:3"}})
+	data.MapLocations(func(location string) string {
+		return "backend/" + location
+	})
+
+	if got := strings.Join(data.Domains["default"].Translations[`"Hello"`].SourceLocations, " "); got != "backend/main.go:1 backend/pkg/pkg.go:2" {
+		t.Errorf("Expected 'backend/main.go:1 backend/pkg/pkg.go:2' but got '%s'", got)
+	}
+	if got := strings.Join(data.Domains["admin"].ContextTranslations[`"menu"`][`"Open"`].SourceLocations, " "); got != "backend/admin.go:3" {
+		t.Errorf("Expected 'backend/admin.go:3' but got '%s'", got)
+	}
+}
+
+func TestSplitLocation(t *testing.T) {
+	for _, test := range []struct {
+		location string
+		file     string
+		line     int
+	}{
+		{"main.go:12", "main.go", 12},
+		{"C:/src/main.go:3", "C:/src/main.go", 3},
+		{"main.go", "main.go", 0},
+		{"main.go:x", "main.go:x", 0},
+	} {
+		if file, line := SplitLocation(test.location); file != test.file || line != test.line {
+			t.Errorf("Expected '%s' and %d for '%s' but got '%s' and %d", test.file, test.line, test.location, file, line)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// TemplateExtensions are the extensions of the embedded files parsed as templates
+var TemplateExtensions = []string{".tmpl", ".tpl", ".gotmpl", ".gohtml", ".html"}
+
+// IsTemplate reports if the file is parsed as a template when embedded
+func IsTemplate(file string) bool {
+	ext := filepath.Ext(file)
+	for _, e := range TemplateExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// EmbeddingDir returns the directory of the package embedding the files of the directory, the nearest one holding
+// Go files, as go:embed only embeds files of the package directory and its sub-directories
+func EmbeddingDir(basePath, dir string) string {
+	for d := dir; ; d = filepath.Dir(d) {
+		if files, _ := filepath.Glob(filepath.Join(basePath, d, "*.go")); len(files) > 0 || d == "." || d == filepath.Dir(d) {
+			return d
+		}
+	}
+}
+
+// embedPatterns returns the patterns of the go:embed directives of the files
+func embedPatterns(files []*ast.File) []string {
+	var patterns []string
+	for _, file := range files {
+		for _, group := range file.Comments {
+			for _, c := range group.List {
+				if !strings.HasPrefix(c.Text, "//go:embed ") {
+					continue
+				}
+				patterns = append(patterns, splitEmbedPatterns(strings.TrimPrefix(c.Text, "//go:embed "))...)
+			}
+		}
+	}
+	return patterns
+}
+
+// splitEmbedPatterns splits the space separated patterns of a go:embed directive, which may be quoted
+func splitEmbedPatterns(s string) []string {
+	var patterns []string
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		if s[0] == '"' || s[0] == '`' {
+			end := 1
+			for end < len(s) && s[end] != s[0] {
+				if s[0] == '"' && s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return patterns
+			}
+			p, _ := strconv.Unquote(s[:end+1])
+			patterns = append(patterns, p)
+			s = s[end+1:]
+			continue
+		}
+
+		i := strings.IndexAny(s, " \t")
+		if i < 0 {
+			i = len(s)
+		}
+		patterns = append(patterns, s[:i])
+		s = s[i:]
+	}
+	return patterns
+}
+
+// embeddedFiles returns the files of the package directory matched by the go:embed patterns, in lexical order.
+// The files of matched directories are embedded recursively, except hidden ones unless the pattern starts with "all:"
+func embeddedFiles(dirPath string, patterns []string) []string {
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		all := strings.HasPrefix(pattern, "all:")
+		pattern = strings.TrimPrefix(pattern, "all:")
+
+		matches, _ := filepath.Glob(filepath.Join(dirPath, filepath.FromSlash(pattern)))
+		for _, match := range matches {
+			_ = filepath.Walk(match, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return nil
+				}
+				if path != match && !all && (strings.HasPrefix(info.Name(), ".") || strings.HasPrefix(info.Name(), "_")) {
+					if info.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				if !info.IsDir() {
+					seen[path] = true
+				}
+				return nil
+			})
+		}
+	}
+
+	files := make([]string, 0, len(seen))
+	for file := range seen {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// parseEmbeddedTemplates parses the templates embedded by the go:embed directives of the package files
+func parseEmbeddedTemplates(files []*ast.File, dirPath, basePath string, data *DomainMap) {
+	for _, file := range embeddedFiles(dirPath, embedPatterns(files)) {
+		if !IsTemplate(file) {
+			continue
+		}
+		if err := parseTemplateFile(file, basePath, data); err != nil {
+			log.Printf("ERR: Failed to parse template %s: %s", file, err)
+		}
+	}
+}
+
+var undefinedFunction = regexp.MustCompile(`function "([^"]+)" not defined`)
+
+// parseTemplate parses the template text. Its functions are unknown, so they're defined as they're found.
+func parseTemplate(name, text string) (*template.Template, error) {
+	funcs := make(template.FuncMap)
+	for {
+		t, err := template.New(name).Funcs(funcs).Parse(text)
+		if err == nil {
+			return t, nil
+		}
+
+		m := undefinedFunction.FindStringSubmatch(err.Error())
+		if m == nil || funcs[m[1]] != nil {
+			return nil, err
+		}
+		funcs[m[1]] = func(...interface{}) string { return "" }
+	}
+}
+
+// parseTemplateFile adds the strings of the gotext getters called by the template file, i.e. {{ .Loc.Get "text" }}
+func parseTemplateFile(path, basePath string, data *DomainMap) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	text := string(content)
+
+	t, err := parseTemplate(filepath.Base(path), text)
+	if err != nil {
+		return err
+	}
+
+	rel, _ := filepath.Rel(basePath, path)
+	tf := &templateFile{
+		GoFile: GoFile{filePath: path, basePath: basePath, data: data},
+		path:   filepath.ToSlash(rel),
+		text:   text,
+	}
+	// the templates of the file are inspected in the order they start in it, so references are the same on every run
+	var roots []*parse.ListNode
+	for _, tmpl := range t.Templates() {
+		if tmpl.Tree != nil && tmpl.Tree.Root != nil {
+			roots = append(roots, tmpl.Tree.Root)
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		return roots[i].Position() < roots[j].Position()
+	})
+	for _, root := range roots {
+		tf.inspect(root)
+	}
+	return nil
+}
+
+// templateFile handles the parsing of one template file
+type templateFile struct {
+	GoFile
+	path string
+	text string
+}
+
+// inspect the template node and its children
+func (t *templateFile) inspect(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			t.inspect(c)
+		}
+	case *parse.ActionNode:
+		t.inspect(n.Pipe)
+	case *parse.IfNode:
+		t.inspectBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		t.inspectBranch(&n.BranchNode)
+	case *parse.WithNode:
+		t.inspectBranch(&n.BranchNode)
+	case *parse.TemplateNode:
+		t.inspect(n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			t.inspectCommand(cmd)
+		}
+	}
+}
+
+func (t *templateFile) inspectBranch(n *parse.BranchNode) {
+	t.inspect(n.Pipe)
+	t.inspect(n.List)
+	t.inspect(n.ElseList)
+}
+
+// inspectCommand handles the getter calls, the commands whose first word is a field, chain or variable ending with the
+// name of a getter
+func (t *templateFile) inspectCommand(cmd *parse.CommandNode) {
+	if len(cmd.Args) == 0 {
+		return
+	}
+	for _, arg := range cmd.Args {
+		t.inspect(arg)
+	}
+
+	var idents []string
+	switch n := cmd.Args[0].(type) {
+	case *parse.FieldNode:
+		idents = n.Ident
+	case *parse.ChainNode:
+		idents = n.Field
+	case *parse.VariableNode:
+		idents = n.Ident[1:]
+	}
+	if len(idents) == 0 {
+		return
+	}
+	def, ok := lookupGetter(gotextPackage, "", idents[len(idents)-1])
+	if !ok {
+		return
+	}
+
+	args := make([]*ast.BasicLit, len(cmd.Args)-1)
+	for idx, arg := range cmd.Args[1:] {
+		if s, ok := arg.(*parse.StringNode); ok {
+			args[idx] = &ast.BasicLit{Kind: token.STRING, Value: s.Quoted}
+		}
+	}
+
+	line := 1 + strings.Count(t.text[:cmd.Position()], "\n")
+	t.parseGetter(def, args, t.path+":"+strconv.Itoa(line), "")
+}
@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitEmbedPatterns(t *testing.T) {
+	patterns := splitEmbedPatterns(" views/*.tmpl  \"with space.html\"\t`raw.tpl` all:static \"unterminated")
+	expected := []string{"views/*.tmpl", "with space.html", "raw.tpl", "all:static"}
+	if strings.Join(patterns, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q but got %q", expected, patterns)
+	}
+}
+
+func TestIsTemplate(t *testing.T) {
+	for file, expected := range map[string]bool{
+		"views/index.tmpl":   true,
+		"page.gohtml":        true,
+		"static/index.html":  true,
+		"main.go":            false,
+		"static/style.css":   false,
+		"views/tmpl/data.js": false,
+	} {
+		if IsTemplate(file) != expected {
+			t.Errorf("Expected %v for '%s'", expected, file)
+		}
+	}
+}
+
+func TestEmbeddedFiles(t *testing.T) {
+	dir := "/tmp/gotext_parser_embedded"
+	writeFixture(t, dir, map[string]string{
+		"web.go":                "package web\n",
+		"views/index.tmpl":      "index",
+		"views/.hidden.tmpl":    "hidden",
+		"views/_draft.tmpl":     "draft",
+		"views/admin/list.tmpl": "list",
+		"static/.well.html":     "well",
+		"static/page.html":      "page",
+		"other/other.tmpl":      "other",
+	})
+	defer os.RemoveAll(dir)
+
+	files := embeddedFiles(dir, []string{"views", "all:static", "missing/*.tmpl"})
+	var rel []string
+	for _, file := range files {
+		r, _ := filepath.Rel(dir, file)
+		rel = append(rel, filepath.ToSlash(r))
+	}
+
+	// hidden files of embedded directories are only embedded with "all:"
+	expected := []string{"static/.well.html", "static/page.html", "views/admin/list.tmpl", "views/index.tmpl"}
+	if strings.Join(rel, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %v but got %v", expected, rel)
+	}
+}
+
+func TestEmbeddingDir(t *testing.T) {
+	dir := "/tmp/gotext_parser_embedding"
+	writeFixture(t, dir, map[string]string{
+		"main.go":                   "package main\n",
+		"web/web.go":                "package web\n",
+		"web/views/admin/list.tmpl": "list",
+		"assets/index.html":         "index",
+	})
+	defer os.RemoveAll(dir)
+
+	for sub, expected := range map[string]string{
+		"web/views/admin": "web",
+		"web":             "web",
+		"assets":          ".",
+	} {
+		if got := EmbeddingDir(dir, filepath.FromSlash(sub)); got != filepath.FromSlash(expected) {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", expected, sub, got)
+		}
+	}
+}
+
+func TestParseTemplateFile(t *testing.T) {
+	dir := "/tmp/gotext_parser_template"
+	writeFixture(t, dir, map[string]string{
+		"views/index.tmpl": `{{/* the getters of the Locale given as .Loc */}}
+<h1>{{ .Loc.Get "Title" }}</h1>
+{{ if .User }}{{ .Loc.GetN "One message" "%d messages" .Count }}{{ else }}{{ .Loc.Get "Sign in" }}{{ end }}
+{{ range .Items }}{{ $.Loc.GetC "Open" "menu" }}{{ end }}
+{{ with .Loc }}{{ .GetD "admin" "Dashboard" }}{{ end }}
+{{ define "footer" }}{{ upper (.Loc.Get "Title") }}{{ end }}
+{{ .Loc.Get .Dynamic }}{{ .Other.Format "Not a getter" }}
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	data := &DomainMap{Default: "default"}
+	if err := parseTemplateFile(filepath.Join(dir, "views", "index.tmpl"), dir, data); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out")
+	if err := data.Save(out); err != nil {
+		t.Fatal(err)
+	}
+
+	refs := readReferences(t, filepath.Join(out, "default.pot"))
+	expected := map[string]string{
+		"Title":       "views/index.tmpl:2 views/index.tmpl:6",
+		"One message": "views/index.tmpl:3",
+		"Sign in":     "views/index.tmpl:3",
+		"menu|Open":   "views/index.tmpl:4",
+	}
+	for id, ref := range expected {
+		if refs[id] != ref {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", ref, id, refs[id])
+		}
+	}
+	if len(refs) != len(expected) {
+		t.Errorf("Expected %d entries but got %v", len(expected), refs)
+	}
+	if refs := readReferences(t, filepath.Join(out, "admin.pot")); refs["Dashboard"] != "views/index.tmpl:5" {
+		t.Errorf("Expected 'views/index.tmpl:5' for 'Dashboard' but got %v", refs)
+	}
+
+	if err := parseTemplateFile(filepath.Join(dir, "views", "missing.tmpl"), dir, data); err == nil {
+		t.Error("Expected an error for a missing template")
+	}
+}
+
+func TestEmbeddedTemplates(t *testing.T) {
+	requireLoader(t)
+
+	dir := "/tmp/gotext_parser_embed"
+	writeFixture(t, dir, map[string]string{
+		"go.mod": goMod("example.com/web"),
+		"web.go": `package web
+
+import (
+	"embed"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+//go:embed views/*.tmpl
+var views embed.FS
+
+var Title = gotext.Get("Title")
+`,
+		"views/index.tmpl":  `<h1>{{ .Loc.Get "Title" }}</h1>`,
+		"views/layout.html": `{{ .Loc.Get "Not embedded" }}`,
+	})
+	defer os.RemoveAll(dir)
+
+	data := &DomainMap{Default: "default"}
+	if err := ParseDirRec(dir, nil, data, false); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out")
+	if err := data.Save(out); err != nil {
+		t.Fatal(err)
+	}
+
+	refs := readReferences(t, filepath.Join(out, "default.pot"))
+	if refs["Title"] != "web.go:12 views/index.tmpl:1" {
+		t.Errorf("Expected 'web.go:12 views/index.tmpl:1' but got '%s'", refs["Title"])
+	}
+	if _, ok := refs["Not embedded"]; ok {
+		t.Error("Expected the templates not embedded to be skipped")
+	}
+}
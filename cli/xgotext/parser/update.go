@@ -0,0 +1,210 @@
+package parser
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// poLiteral returns s quoted as a PO string, the form of the strings of translations.
+func poLiteral(s string) string {
+	return `"` + gotext.EscapePoString(s) + `"`
+}
+
+// normalizeLiteral returns the Go string literal lit as a PO string, so strings are the same however they're written in
+// the sources, i.e. as raw or interpreted literals. Literals that can't be unquoted are returned unchanged.
+func normalizeLiteral(lit string) string {
+	s, err := strconv.Unquote(lit)
+	if err != nil {
+		return lit
+	}
+
+	return poLiteral(s)
+}
+
+// LoadDomain reads a domain file written by Save, so it can be updated without parsing all of the sources again.
+func LoadDomain(path string) (*Domain, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := gotext.ParsePoFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	d := new(Domain)
+	for _, e := range f.Entries {
+		if e.IsHeader() || e.Obsolete {
+			continue
+		}
+
+		t := &Translation{MsgId: poLiteral(e.ID)}
+		if e.PluralID != "" {
+			t.MsgIdPlural = poLiteral(e.PluralID)
+		}
+		if e.HasContext {
+			t.Context = poLiteral(e.Context)
+		}
+		if e.PluralID == "" && len(e.Str) > 0 && e.Str[0] != "" {
+			t.Default = poLiteral(e.Str[0])
+		}
+		for _, line := range e.References {
+			t.AddLocations(strings.Fields(line))
+		}
+		d.AddTranslation(t)
+	}
+
+	return d, nil
+}
+
+// Load reads the domain files saved in the directory, and the ones of the domains mapped to other output directories,
+// so they can be updated with the strings of the sources parsed again.
+func (m *DomainMap) Load(directory string) error {
+	files, err := filepath.Glob(filepath.Join(directory, "*.pot"))
+	if err != nil {
+		return err
+	}
+	for name, dir := range m.OutputDirs {
+		files = append(files, filepath.Join(dir, name+".pot"))
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".pot")
+		if m.DomainPath(directory, name) != file {
+			// saved elsewhere
+			continue
+		}
+
+		d, err := LoadDomain(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if m.Domains == nil {
+			m.Domains = make(map[string]*Domain, len(files))
+		}
+		m.Domains[name] = d
+	}
+
+	return nil
+}
+
+// SplitLocation returns the file and line of a "file:line" source location.
+func SplitLocation(location string) (string, int) {
+	if i := strings.LastIndex(location, ":"); i > 0 {
+		if line, err := strconv.Atoi(location[i+1:]); err == nil {
+			return location[:i], line
+		}
+	}
+
+	return location, 0
+}
+
+// RemoveLocations removes the source locations whose file is selected by remove, i.e. the ones of sources about to be
+// parsed again. Translations left without locations are removed.
+func (m *DomainMap) RemoveLocations(remove func(file string) bool) {
+	filter := func(tm TranslationMap) {
+		for id, t := range tm {
+			if len(t.SourceLocations) == 0 {
+				continue
+			}
+
+			var kept []string
+			for _, location := range t.SourceLocations {
+				if file, _ := SplitLocation(location); !remove(file) {
+					kept = append(kept, location)
+				}
+			}
+			if len(kept) == 0 {
+				delete(tm, id)
+				continue
+			}
+			t.SourceLocations = kept
+		}
+	}
+
+	for _, d := range m.Domains {
+		filter(d.Translations)
+		for _, tm := range d.ContextTranslations {
+			filter(tm)
+		}
+	}
+}
+
+// SortLocations sorts the source locations of the translations in the order ParseDirRec finds them: the files of a
+// directory before the ones of its sub-directories, each in lexical order, then by line.
+func (m *DomainMap) SortLocations() {
+	sortMap := func(tm TranslationMap) {
+		for _, t := range tm {
+			sort.SliceStable(t.SourceLocations, func(i, j int) bool {
+				return locationLess(t.SourceLocations[i], t.SourceLocations[j])
+			})
+		}
+	}
+
+	for _, d := range m.Domains {
+		sortMap(d.Translations)
+		for _, tm := range d.ContextTranslations {
+			sortMap(tm)
+		}
+	}
+}
+
+// locationLess reports if the source location a is found before b by ParseDirRec.
+func locationLess(a, b string) bool {
+	fa, la := SplitLocation(a)
+	fb, lb := SplitLocation(b)
+	if fa == fb {
+		return la < lb
+	}
+
+	da, db := pathElements(filepath.Dir(fa)), pathElements(filepath.Dir(fb))
+	for i := 0; i < len(da) && i < len(db); i++ {
+		if da[i] != db[i] {
+			return da[i] < db[i]
+		}
+	}
+	if len(da) != len(db) {
+		return len(da) < len(db)
+	}
+
+	return fa < fb
+}
+
+// pathElements returns the elements of a relative directory path, none for the current directory.
+func pathElements(dir string) []string {
+	dir = filepath.ToSlash(dir)
+	if dir == "." || dir == "" {
+		return nil
+	}
+
+	return strings.Split(dir, "/")
+}
+
+// MapLocations replaces the source locations of the translations with the ones returned by f, i.e. to prefix them with
+// the path of the parsed directory in the repository.
+func (m *DomainMap) MapLocations(f func(location string) string) {
+	mapLocations := func(tm TranslationMap) {
+		for _, t := range tm {
+			for i, location := range t.SourceLocations {
+				t.SourceLocations[i] = f(location)
+			}
+		}
+	}
+
+	for _, d := range m.Domains {
+		mapLocations(d.Translations)
+		for _, tm := range d.ContextTranslations {
+			mapLocations(tm)
+		}
+	}
+}
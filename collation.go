@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// collators holds a pool of collators by language tag, as building them is expensive
+// and they can't be shared between goroutines.
+var collators sync.Map
+
+// collatorPool returns the pool of collators of the language of the Locale.
+func (l *Locale) collatorPool() *sync.Pool {
+	tag := l.GetLanguageTag()
+	if pool, ok := collators.Load(tag); ok {
+		return pool.(*sync.Pool)
+	}
+
+	pool, _ := collators.LoadOrStore(tag, &sync.Pool{
+		New: func() interface{} {
+			return collate.New(language.Make(tag))
+		},
+	})
+
+	return pool.(*sync.Pool)
+}
+
+// Compare compares the strings with the collation rules of the language of the Locale, returning -1, 0 or 1
+// when a sorts before, as or after b, so "ä" sorts along "a" in German and after "z" in Swedish.
+// Collators are pooled by language, so Compare is cheap enough for sort loops, although LessFunc saves the
+// pool accesses.
+func (l *Locale) Compare(a, b string) int {
+	pool := l.collatorPool()
+	c := pool.Get().(*collate.Collator)
+	defer pool.Put(c)
+
+	return c.CompareString(a, b)
+}
+
+/*
+LessFunc returns the less function of sort.Slice sorting the items with the collation rules of the language of
+the Locale, as Compare, i.e. to sort translated labels the way users of the language expect.
+It isn't safe for concurrent use.
+
+Example:
+
+	labels := []string{l.Get("Oranges"), l.Get("Apples"), l.Get("Äpfel")}
+	sort.Slice(labels, l.LessFunc(labels))
+*/
+func (l *Locale) LessFunc(items []string) func(i, j int) bool {
+	c := collate.New(language.Make(l.GetLanguageTag()))
+
+	return func(i, j int) bool {
+		return c.CompareString(items[i], items[j]) < 0
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestLocaleCollation(t *testing.T) {
+	de := NewLocale("fixtures/", "de_DE")
+	sv := NewLocale("fixtures/", "sv")
+
+	if c := de.Compare("äpfel", "birnen"); c != -1 {
+		t.Errorf("Expected %d but got %d", -1, c)
+	}
+	if c := sv.Compare("äpple", "zebra"); c != 1 {
+		t.Errorf("Expected %d but got %d", 1, c)
+	}
+	if c := de.Compare("Straße", "Straße"); c != 0 {
+		t.Errorf("Expected %d but got %d", 0, c)
+	}
+
+	labels := []string{"Zucker", "Äpfel", "apfel", "Birnen"}
+	sort.Slice(labels, de.LessFunc(labels))
+	expected := []string{"apfel", "Äpfel", "Birnen", "Zucker"}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Errorf("Expected %v but got %v", expected, labels)
+	}
+
+	labels = []string{"ör", "zon", "ost"}
+	sort.Slice(labels, sv.LessFunc(labels))
+	expected = []string{"ost", "zon", "ör"}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Errorf("Expected %v but got %v", expected, labels)
+	}
+}
+
+func TestLocaleCompareConcurrent(t *testing.T) {
+	de := NewLocale("fixtures/", "de")
+
+	done := make(chan bool)
+	for i := 0; i < 8; i++ {
+		go func() {
+			for j := 0; j < 100; j++ {
+				if c := de.Compare("Äpfel", "Birnen"); c != -1 {
+					t.Errorf("Expected %d but got %d", -1, c)
+				}
+			}
+			done <- true
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		<-done
+	}
+}
+
+func BenchmarkLocaleCompare(b *testing.B) {
+	de := NewLocale("fixtures/", "de")
+	for i := 0; i < b.N; i++ {
+		de.Compare("Äpfel", "Birnen")
+	}
+}
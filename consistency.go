@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "fmt"
+
+const (
+	// IssueMissing reports a msgid of the template missing from a translated catalog.
+	IssueMissing IssueKind = "missing"
+
+	// IssueExtra reports a msgid of a translated catalog missing from the template.
+	IssueExtra IssueKind = "extra"
+
+	// IssueContextMismatch reports a msgid of the template found in a translated catalog with another context.
+	IssueContextMismatch IssueKind = "context-mismatch"
+)
+
+// CheckConsistency reports the drift between a template and one of its translated catalogs: msgids missing from either,
+// msgids translated with another context than the template one, and plural entries whose number of translations differs
+// from the nplurals of the catalog header.
+// Obsolete entries are ignored. The lines of the issues are unknown, so 0.
+func CheckConsistency(pot, po *PoFile) []Issue {
+	var issues []Issue
+	issue := func(kind IssueKind, e *PoEntry, format string, args ...interface{}) {
+		issues = append(issues, Issue{Kind: kind, Context: e.Context, Msgid: e.ID, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Entries of the catalog by msgid, to find the ones with another context.
+	byID := make(map[string][]*PoEntry)
+	for _, e := range po.Entries {
+		if !e.IsHeader() && !e.Obsolete {
+			byID[e.ID] = append(byID[e.ID], e)
+		}
+	}
+
+	matched := make(map[*PoEntry]bool)
+	for _, t := range pot.Entries {
+		if t.IsHeader() || t.Obsolete {
+			continue
+		}
+		if e := po.Find(t.Context, t.ID); e != nil && !e.Obsolete {
+			matched[e] = true
+			continue
+		}
+
+		var other *PoEntry
+		for _, e := range byID[t.ID] {
+			if !matched[e] && pot.Find(e.Context, e.ID) == nil {
+				other = e
+				break
+			}
+		}
+		if other == nil {
+			issue(IssueMissing, t, "msgid of the template missing from the catalog")
+			continue
+		}
+		matched[other] = true
+		issue(IssueContextMismatch, t, "msgid found with msgctxt %q in the catalog", other.Context)
+	}
+
+	n := po.nplurals()
+	for _, e := range po.Entries {
+		if e.IsHeader() || e.Obsolete {
+			continue
+		}
+		if !matched[e] {
+			issue(IssueExtra, e, "msgid of the catalog missing from the template")
+		}
+		if e.PluralID != "" && len(e.Str) != n {
+			issue(IssuePluralCount, e, "%d plural translations but the header declares nplurals=%d", len(e.Str), n)
+		}
+	}
+
+	return issues
+}
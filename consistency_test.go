@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"testing"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	pot := mustParsePoFile(t, `msgid ""
+msgstr ""
+
+msgid "Hello"
+msgstr ""
+
+msgctxt "menu"
+msgid "Open"
+msgstr ""
+
+msgid "Missing"
+msgstr ""
+
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+`)
+	po := mustParsePoFile(t, `msgid ""
+msgstr ""
+"Plural-Forms: nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "Hello"
+msgstr "Cześć"
+
+msgctxt "toolbar"
+msgid "Open"
+msgstr "Otwórz"
+
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "Jeden plik"
+msgstr[1] "%d pliki"
+
+msgid "Removed"
+msgstr "Usunięty"
+
+#~ msgid "Old"
+#~ msgstr "Stary"
+`)
+
+	expected := []Issue{
+		{Kind: IssueContextMismatch, Context: "menu", Msgid: "Open", Message: `msgid found with msgctxt "toolbar" in the catalog`},
+		{Kind: IssueMissing, Msgid: "Missing", Message: "msgid of the template missing from the catalog"},
+		{Kind: IssuePluralCount, Msgid: "One file", Message: "2 plural translations but the header declares nplurals=3"},
+		{Kind: IssueExtra, Msgid: "Removed", Message: "msgid of the catalog missing from the template"},
+	}
+
+	issues := CheckConsistency(pot, po)
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d issues but got %d: %v", len(expected), len(issues), issues)
+	}
+	for i, issue := range issues {
+		if issue != expected[i] {
+			t.Errorf("Expected '%s' but got '%s'", expected[i], issue)
+		}
+	}
+
+	if issues = CheckConsistency(pot, pot); len(issues) != 0 {
+		t.Errorf("Expected no issues checking the template against itself but got %v", issues)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"context"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// contextKey is the type of the key storing the Locale in a context.Context.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the given Locale, to be retrieved with FromContext.
+func NewContext(ctx context.Context, l *Locale) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the Locale carried by ctx, or nil if there's none.
+func FromContext(ctx context.Context) *Locale {
+	l, _ := ctx.Value(contextKey{}).(*Locale)
+	return l
+}
+
+// ParseAcceptLanguage returns the languages listed in an Accept-Language header value (i.e. "fr-CH, fr;q=0.9, en;q=0.8"),
+// ordered by preference and simplified (see SimplifiedLocale). Wildcards and languages with a zero quality are skipped.
+func ParseAcceptLanguage(header string) []string {
+	type pref struct {
+		lang string
+		q    float64
+	}
+
+	var prefs []pref
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		lang := strings.TrimSpace(fields[0])
+		if lang == "" || lang == "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+
+		prefs = append(prefs, pref{lang: SimplifiedLocale(lang), q: q})
+	}
+
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].q > prefs[j].q
+	})
+
+	langs := make([]string, 0, len(prefs))
+	for _, p := range prefs {
+		langs = append(langs, p.lang)
+	}
+
+	return langs
+}
+
+// maxMatchCandidates caps the languages tried by Match, fallbacks included, so long headers take a bounded time.
+const maxMatchCandidates = 32
+
+// Match returns the Locale for the first of the given languages (i.e. from ParseAcceptLanguage) having catalogs
+// for the domains loaded on l, falling back from regional variants to their base language ("pt_BR" to "pt").
+// The language of l always matches, whatever its domains were added with. Locales for other languages are created
+// with WithLanguage, which loads their catalog files. It returns l itself when no language matches.
+func (l *Locale) Match(langs ...string) *Locale {
+	available := l.availableLanguages()
+
+	candidates := 0
+	for _, lang := range langs {
+		lang = SimplifiedLocale(lang)
+		if !isLanguageCode(lang) {
+			continue
+		}
+
+		// Try from the most specific to the most general form
+		for candidate := lang; candidate != ""; {
+			if candidates++; candidates > maxMatchCandidates {
+				return l
+			}
+			if candidate == l.lang {
+				return l
+			}
+			if available[candidate] {
+				return l.WithLanguage(candidate)
+			}
+
+			idx := strings.LastIndex(candidate, "_")
+			if idx == -1 {
+				break
+			}
+			candidate = candidate[:idx]
+		}
+	}
+
+	return l
+}
+
+// availableLanguages returns the languages of the directories of the path of l having catalogs for its domains.
+// They're listed once for each snapshot, so again after domains are loaded or swapped.
+func (l *Locale) availableLanguages() map[string]bool {
+	snap := l.load()
+
+	l.matchMutex.Lock()
+	defer l.matchMutex.Unlock()
+
+	if l.matchLanguages != nil && l.matchSnapshot == snap {
+		return l.matchLanguages
+	}
+
+	langs := make(map[string]bool)
+	entries, _ := ioutil.ReadDir(l.path)
+	for _, entry := range entries {
+		lang := entry.Name()
+		if !entry.IsDir() || !isLanguageCode(lang) {
+			continue
+		}
+		for dom := range snap.domains {
+			if l.findExt(lang, dom, "po", nil) != "" || l.findExt(lang, dom, "mo", nil) != "" {
+				langs[lang] = true
+				break
+			}
+		}
+	}
+	l.matchSnapshot, l.matchLanguages = snap, langs
+
+	return langs
+}
+
+// isLanguageCode reports if a simplified language code only holds letters and digits subtags,
+// so it's safe to use as a path component.
+func isLanguageCode(lang string) bool {
+	if lang == "" {
+		return false
+	}
+	for _, st := range strings.Split(lang, "_") {
+		if st == "" || !isAlpha(st) && !isDigit(st) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	if FromContext(ctx) != nil {
+		t.Error("Expected no Locale in empty context")
+	}
+
+	l := NewLocale("fixtures/", "en_US")
+	if FromContext(NewContext(ctx, l)) != l {
+		t.Error("Expected Locale from context")
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	for _, test := range []struct {
+		header   string
+		expected []string
+	}{
+		{"fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5", []string{"fr_CH", "fr", "en", "de"}},
+		{"en;q=0.5, pt-br", []string{"pt_BR", "en"}},
+		{"es;q=0, it", []string{"it"}},
+		{"", []string{}},
+	} {
+		if got := ParseAcceptLanguage(test.header); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Expected %v for '%s' but got %v", test.expected, test.header, got)
+		}
+	}
+}
+
+func TestLocaleMatch(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	l.AddDomain("default")
+
+	for _, test := range []struct {
+		langs    []string
+		expected string
+	}{
+		{[]string{"fr_CA", "en"}, "fr"},
+		{[]string{"xx", "de-DE"}, "de_DE"},
+		{[]string{"../fr", "xx"}, "en_US"},
+		{[]string{"en-US"}, "en_US"},
+		{nil, "en_US"},
+	} {
+		if got := l.Match(test.langs...).GetLanguage(); got != test.expected {
+			t.Errorf("Expected '%s' for %v but got '%s'", test.expected, test.langs, got)
+		}
+	}
+
+	if tr := l.Match("fr").Get("language"); tr != "fr" {
+		t.Errorf("Expected 'fr' but got '%s'", tr)
+	}
+}
+
+func TestLocaleMatchReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gotext_match")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(lang string) {
+		if err := os.MkdirAll(filepath.Join(dir, lang), 0755); err != nil {
+			t.Fatal(err)
+		}
+		po := "msgid \"language\"\nmsgstr \"" + lang + "\"\n"
+		if err := ioutil.WriteFile(filepath.Join(dir, lang, "default.po"), []byte(po), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("en")
+
+	l := NewLocale(dir, "en")
+	l.AddDomain("default")
+	if got := l.Match("de").GetLanguage(); got != "en" {
+		t.Errorf("Expected 'en' but got '%s'", got)
+	}
+
+	// the available languages are listed again once the domains are reloaded
+	write("de")
+	if got := l.Match("de").GetLanguage(); got != "en" {
+		t.Errorf("Expected 'en' before reloading but got '%s'", got)
+	}
+	l.AddDomain("default")
+	if got := l.Match("de").GetLanguage(); got != "de" {
+		t.Errorf("Expected 'de' but got '%s'", got)
+	}
+	if tr := l.Match("de_AT").Get("language"); tr != "de" {
+		t.Errorf("Expected 'de' but got '%s'", tr)
+	}
+}
+
+func TestLocaleMatchCandidates(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	l.AddDomain("default")
+
+	langs := make([]string, 0, maxMatchCandidates+1)
+	for i := 0; i < maxMatchCandidates; i++ {
+		langs = append(langs, strings.Repeat("x", i+1))
+	}
+	if got := l.Match(append(langs[1:], "fr")...).GetLanguage(); got != "fr" {
+		t.Errorf("Expected 'fr' but got '%s'", got)
+	}
+
+	// languages past the cap aren't tried
+	if got := l.Match(append(langs, "fr")...).GetLanguage(); got != "en_US" {
+		t.Errorf("Expected 'en_US' but got '%s'", got)
+	}
+}
@@ -0,0 +1,239 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"net/textproto"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// VolatileHeaders are the headers changing on every generation of a catalog, to be ignored when diffing catalogs.
+var VolatileHeaders = []string{"POT-Creation-Date", "PO-Revision-Date", "Last-Translator", "X-Generator"}
+
+// DiffOptions configures DiffCatalogs.
+type DiffOptions struct {
+	// IgnoreHeaders are the headers not compared, i.e. VolatileHeaders.
+	IgnoreHeaders []string
+}
+
+// EntryChange is an entry changed between two catalogs.
+type EntryChange struct {
+	Context string
+	Msgid   string
+
+	// Old and New are the entries in the compared catalogs, nil when the entry was added or removed.
+	Old *Translation
+	New *Translation
+}
+
+// HeaderChange is a header changed between two catalogs, with empty values for missing headers.
+type HeaderChange struct {
+	Name     string
+	Old, New string
+}
+
+// CatalogDiff is the structural difference between two catalogs.
+type CatalogDiff struct {
+	Headers []HeaderChange
+	Added   []EntryChange
+	Removed []EntryChange
+	Changed []EntryChange
+}
+
+// Empty reports if the catalogs are equivalent.
+func (d CatalogDiff) Empty() bool {
+	return len(d.Headers) == 0 && len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// describeEntry returns the msgid of an entry with its context.
+func describeEntry(ctx, id string) string {
+	if ctx == "" {
+		return fmt.Sprintf("%q", id)
+	}
+
+	return fmt.Sprintf("%q (context %q)", id, ctx)
+}
+
+// describeTranslation returns the plural id and translations of an entry.
+func describeTranslation(tr *Translation) string {
+	idx := make([]int, 0, len(tr.Trs))
+	for i := range tr.Trs {
+		idx = append(idx, i)
+	}
+	sort.Ints(idx)
+
+	var parts []string
+	if tr.PluralID != "" {
+		parts = append(parts, fmt.Sprintf("msgid_plural %q", tr.PluralID))
+	}
+	for _, i := range idx {
+		parts = append(parts, fmt.Sprintf("msgstr[%d] %q", i, tr.Trs[i]))
+	}
+	if tr.Fuzzy {
+		parts = append(parts, "fuzzy")
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// String pretty-prints the difference, one change per line.
+func (d CatalogDiff) String() string {
+	var b strings.Builder
+	for _, h := range d.Headers {
+		fmt.Fprintf(&b, "~ header %s: %q => %q\n", h.Name, h.Old, h.New)
+	}
+	for _, e := range d.Removed {
+		fmt.Fprintf(&b, "- %s\n", describeEntry(e.Context, e.Msgid))
+	}
+	for _, e := range d.Added {
+		fmt.Fprintf(&b, "+ %s\n", describeEntry(e.Context, e.Msgid))
+	}
+	for _, e := range d.Changed {
+		fmt.Fprintf(&b, "~ %s: %s => %s\n", describeEntry(e.Context, e.Msgid), describeTranslation(e.Old), describeTranslation(e.New))
+	}
+
+	return b.String()
+}
+
+// translatorEncoding returns the encoding of the Translator, with the entries of chains flattened.
+func translatorEncoding(tr Translator) (*TranslatorEncoding, error) {
+	data, err := tr.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	enc := new(TranslatorEncoding)
+	if err = gob.NewDecoder(bytes.NewReader(data)).Decode(enc); err != nil {
+		return nil, err
+	}
+
+	return enc, nil
+}
+
+// flatEntries returns the entries of a catalog indexed by context and msgid, without the header entry.
+func flatEntries(enc *TranslatorEncoding) map[entryKey]*Translation {
+	entries := make(map[entryKey]*Translation)
+	for id, tr := range enc.Translations {
+		if id != "" {
+			entries[entryKey{id: id}] = tr
+		}
+	}
+	for ctx, trs := range enc.Contexts {
+		for id, tr := range trs {
+			entries[entryKey{ctx: ctx, id: id}] = tr
+		}
+	}
+
+	return entries
+}
+
+// diffHeaders returns the headers changed between two catalogs, except the ignored ones.
+func diffHeaders(oldHeaders, newHeaders textproto.MIMEHeader, ignore []string) []HeaderChange {
+	ignored := make(map[string]bool)
+	for _, h := range ignore {
+		ignored[textproto.CanonicalMIMEHeaderKey(h)] = true
+	}
+
+	names := make(map[string]bool)
+	for k := range oldHeaders {
+		names[k] = true
+	}
+	for k := range newHeaders {
+		names[k] = true
+	}
+
+	var changes []HeaderChange
+	for name := range names {
+		if ignored[name] {
+			continue
+		}
+		if o, n := oldHeaders.Get(name), newHeaders.Get(name); o != n {
+			changes = append(changes, HeaderChange{Name: name, Old: o, New: n})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+
+	return changes
+}
+
+/*
+DiffCatalogs returns the structural difference between the old and new catalogs: the entries added, removed and
+changed, and the headers changed, ignoring the order of entries and the headers of the options. Entries are identified
+by context and msgid, and changed when their plural id, translations or fuzzy state differ, empty translations being
+equivalent to missing ones.
+
+Example:
+
+	oldPo, newPo := gotext.NewPo(), gotext.NewPo()
+	oldPo.ParseFile("old/de.po")
+	newPo.ParseFile("new/de.po")
+
+	diff, err := gotext.DiffCatalogs(oldPo, newPo, gotext.DiffOptions{IgnoreHeaders: gotext.VolatileHeaders})
+	if err == nil && !diff.Empty() {
+		fmt.Print(diff)
+	}
+*/
+func DiffCatalogs(oldTr, newTr Translator, opts DiffOptions) (CatalogDiff, error) {
+	oldEnc, err := translatorEncoding(oldTr)
+	if err != nil {
+		return CatalogDiff{}, err
+	}
+	newEnc, err := translatorEncoding(newTr)
+	if err != nil {
+		return CatalogDiff{}, err
+	}
+
+	diff := CatalogDiff{Headers: diffHeaders(oldEnc.Headers, newEnc.Headers, opts.IgnoreHeaders)}
+
+	oldEntries, newEntries := flatEntries(oldEnc), flatEntries(newEnc)
+	for k, o := range oldEntries {
+		n, ok := newEntries[k]
+		switch {
+		case !ok:
+			diff.Removed = append(diff.Removed, EntryChange{Context: k.ctx, Msgid: k.id, Old: o})
+		case o.PluralID != n.PluralID || o.Fuzzy != n.Fuzzy || !sameForms(o.Trs, n.Trs):
+			diff.Changed = append(diff.Changed, EntryChange{Context: k.ctx, Msgid: k.id, Old: o, New: n})
+		}
+	}
+	for k, n := range newEntries {
+		if _, ok := oldEntries[k]; !ok {
+			diff.Added = append(diff.Added, EntryChange{Context: k.ctx, Msgid: k.id, New: n})
+		}
+	}
+
+	for _, changes := range [][]EntryChange{diff.Added, diff.Removed, diff.Changed} {
+		sort.Slice(changes, func(i, j int) bool {
+			if changes[i].Context != changes[j].Context {
+				return changes[i].Context < changes[j].Context
+			}
+			return changes[i].Msgid < changes[j].Msgid
+		})
+	}
+
+	return diff, nil
+}
+
+// sameForms compares translation forms, empty forms being equivalent to missing ones.
+func sameForms(a, b map[int]string) bool {
+	clean := func(trs map[int]string) map[int]string {
+		m := make(map[int]string)
+		for i, s := range trs {
+			if s != "" {
+				m[i] = s
+			}
+		}
+		return m
+	}
+
+	return reflect.DeepEqual(clean(a), clean(b))
+}
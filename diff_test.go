@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "testing"
+
+func TestDiffCatalogs(t *testing.T) {
+	oldPo, newPo := NewPo(), NewPo()
+	oldPo.Parse([]byte(`msgid ""
+msgstr ""
+"Project-Id-Version: app\n"
+"PO-Revision-Date: 2020-01-01 10:00+0000\n"
+
+msgid "Hello"
+msgstr "Hallo"
+
+msgid "Bye"
+msgstr "Tschüss"
+
+msgctxt "menu"
+msgid "File"
+msgstr "Datei"
+`))
+	newPo.Parse([]byte(`msgid ""
+msgstr ""
+"Project-Id-Version: app 2\n"
+"PO-Revision-Date: 2021-06-01 12:00+0000\n"
+
+msgid "Hello"
+msgstr "Hallo"
+
+msgctxt "menu"
+msgid "File"
+msgstr "Ablage"
+
+#, fuzzy
+msgid "Welcome"
+msgstr "Willkommen"
+`))
+
+	diff, err := DiffCatalogs(oldPo, newPo, DiffOptions{IgnoreHeaders: VolatileHeaders})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diff.Headers) != 1 || diff.Headers[0] != (HeaderChange{Name: "Project-Id-Version", Old: "app", New: "app 2"}) {
+		t.Errorf("Unexpected header changes %+v", diff.Headers)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Msgid != "Bye" || diff.Removed[0].New != nil {
+		t.Errorf("Unexpected removed entries %+v", diff.Removed)
+	}
+	if len(diff.Added) != 1 || diff.Added[0].Msgid != "Welcome" || !diff.Added[0].New.Fuzzy {
+		t.Errorf("Unexpected added entries %+v", diff.Added)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Context != "menu" || diff.Changed[0].Old.Get() != "Datei" || diff.Changed[0].New.Get() != "Ablage" {
+		t.Errorf("Unexpected changed entries %+v", diff.Changed)
+	}
+
+	expected := `~ header Project-Id-Version: "app" => "app 2"
+- "Bye"
++ "Welcome"
+~ "File" (context "menu"): msgstr[0] "Datei" => msgstr[0] "Ablage"
+`
+	if diff.String() != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, diff)
+	}
+
+	// Headers aren't ignored unless asked to
+	diff, err = DiffCatalogs(oldPo, newPo, DiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Headers) != 2 {
+		t.Errorf("Expected 2 header changes but got %d", len(diff.Headers))
+	}
+
+	diff, err = DiffCatalogs(newPo, newPo, DiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Empty() {
+		t.Errorf("Expected an empty diff but got '%s'", diff)
+	}
+}
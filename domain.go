@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"golang.org/x/text/language"
 
@@ -31,52 +32,196 @@ type Domain struct {
 	plural      string
 	pluralforms plurals.Expression
 
-	// Storage
-	translations       map[string]*Translation
-	contexts           map[string]map[string]*Translation
+	// Storage, only modified by parsers while holding trMutex.
+	// Entries are indexed by context and msgid together, so any lookup is a single map access.
+	entries            map[entryKey]*Translation
 	pluralTranslations map[string]*Translation
 
-	// Sync Mutex
-	trMutex     sync.RWMutex
-	pluralMutex sync.RWMutex
+	// Selects the entries kept when parsing, nil keeps all of them.
+	filter EntryFilter
+
+	// Lookups fall back to the entries matching regardless of case and surrounding whitespace.
+	looseLookup bool
+
+	// Lookups skip the fuzzy translations, which need review.
+	skipFuzzy bool
+
+	// Returns the strings of entries with an empty translation, nil for their msgid.
+	empty EmptyFunc
+
+	// Called with the strings formatted with arguments not matching their verbs, nil when not checked.
+	formatCheck FormatCheckFunc
+
+	// Immutable snapshot of the storage used by lookups.
+	// It's swapped atomically once parsing is done, so reads never block.
+	snapshot atomic.Value
+
+	// Sync Mutex, serializes parsers.
+	trMutex sync.Mutex
 
 	// Parsing buffers
 	trBuffer  *Translation
 	ctxBuffer string
 }
 
+// EntryFilter reports if the entry with the given context (empty for none) and msgid has to be kept when parsing a catalog.
+// Filters allow loading only a subset of a big catalog. See Domain.SetFilter.
+type EntryFilter func(ctx, id string) bool
+
+// FilterContexts returns an EntryFilter keeping only the entries in any of the given contexts.
+func FilterContexts(ctxs ...string) EntryFilter {
+	set := make(map[string]bool, len(ctxs))
+	for _, ctx := range ctxs {
+		set[ctx] = true
+	}
+
+	return func(ctx, id string) bool {
+		return set[ctx]
+	}
+}
+
+// FilterPrefix returns an EntryFilter keeping only the entries with a msgid starting with any of the given prefixes.
+func FilterPrefix(prefixes ...string) EntryFilter {
+	return func(ctx, id string) bool {
+		for _, p := range prefixes {
+			if strings.HasPrefix(id, p) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// entryKey identifies a Translation by its context (empty for none) and msgid.
+type entryKey struct {
+	ctx string
+	id  string
+}
+
+// catalog is an immutable snapshot of the translations stored on a Domain.
+// It must never be modified after being published.
+type catalog struct {
+	entries     map[entryKey]*Translation
+	pluralforms plurals.Expression
+
+	// Entries whose key isn't in Unicode normalization form C by normalized key, nil if there is none.
+	nfc map[entryKey]*Translation
+
+	// Entries by loose key, nil unless loose lookups are enabled.
+	loose map[entryKey]*Translation
+
+	// Fuzzy translations are missing to lookups.
+	skipFuzzy bool
+
+	// Returns the strings of entries with an empty translation, nil for their msgid.
+	empty EmptyFunc
+
+	// Called with the strings formatted with arguments not matching their verbs, nil when not checked.
+	formatCheck FormatCheckFunc
+}
+
+var emptyCatalog = new(catalog)
+
 func NewDomain() *Domain {
 	domain := new(Domain)
 
-	domain.translations = make(map[string]*Translation)
-	domain.contexts = make(map[string]map[string]*Translation)
+	domain.entries = make(map[entryKey]*Translation)
 	domain.pluralTranslations = make(map[string]*Translation)
 
 	return domain
 }
 
+// Lookup returns the Translation stored for the given context (empty for none) and msgid. It implements the Backend interface.
+func (do *Domain) Lookup(ctx, id string) (*Translation, bool) {
+	return do.load().lookup(entryKey{ctx: ctx, id: id})
+}
+
+// PluralForm returns the index of the plural form to use for n, according to the Plural-Forms header.
+// It implements the Backend interface.
+func (do *Domain) PluralForm(n int) int {
+	return do.pluralForm(n)
+}
+
+// SetFilter sets the filter selecting the entries kept by subsequent parsing. Headers are always kept.
+// Use nil to keep every entry.
+func (do *Domain) SetFilter(filter EntryFilter) {
+	do.trMutex.Lock()
+	do.filter = filter
+	do.trMutex.Unlock()
+}
+
+// keep reports if an entry has to be stored, it must be called while holding trMutex.
+func (do *Domain) keep(ctx, id string) bool {
+	if do.filter == nil || (ctx == "" && id == "") {
+		return true
+	}
+	return do.filter(ctx, id)
+}
+
+// load returns the current catalog snapshot without locking.
+func (do *Domain) load() *catalog {
+	if c, ok := do.snapshot.Load().(*catalog); ok {
+		return c
+	}
+	return emptyCatalog
+}
+
+// beginUpdate copies the storage maps, so parsers can modify them while lookups keep reading the published snapshot.
+// It must be called while holding trMutex.
+func (do *Domain) beginUpdate() {
+	entries := make(map[entryKey]*Translation, len(do.entries))
+	for k, v := range do.entries {
+		entries[k] = v
+	}
+
+	pluralTranslations := make(map[string]*Translation, len(do.pluralTranslations))
+	for k, v := range do.pluralTranslations {
+		pluralTranslations[k] = v
+	}
+
+	do.entries = entries
+	do.pluralTranslations = pluralTranslations
+}
+
+// publish atomically replaces the snapshot used by lookups with the current storage.
+// The storage maps must not be modified afterwards, use beginUpdate before parsing again.
+func (do *Domain) publish() {
+	c := &catalog{
+		entries:     do.entries,
+		pluralforms: do.pluralforms,
+		nfc:         nfcIndex(do.entries),
+		skipFuzzy:   do.skipFuzzy,
+		empty:       do.empty,
+		formatCheck: do.formatCheck,
+	}
+	if do.looseLookup {
+		c.loose = looseIndex(do.entries)
+	}
+	do.snapshot.Store(c)
+}
+
 func (do *Domain) pluralForm(n int) int {
-	// do we really need locking here? not sure how this plurals.Expression works, so sticking with it for now
-	do.pluralMutex.RLock()
-	defer do.pluralMutex.RUnlock()
+	return do.load().pluralForm(n)
+}
 
+func (c *catalog) pluralForm(n int) int {
 	// Failure fallback
-	if do.pluralforms == nil {
+	if c.pluralforms == nil {
 		/* Use the Germanic plural rule.  */
 		if n == 1 {
 			return 0
 		}
 		return 1
 	}
-	return do.pluralforms.Eval(uint32(n))
+	return c.pluralforms.Eval(uint32(n))
 }
 
 // parseHeaders retrieves data from previously parsed headers. it's called by both Mo and Po when parsing
 func (do *Domain) parseHeaders() {
 	// Make sure we end with 2 carriage returns.
 	empty := ""
-	if _, ok := do.translations[empty]; ok {
-		empty = do.translations[empty].Get()
+	if tr, ok := do.entries[entryKey{}]; ok {
+		empty = tr.Get()
 	}
 	raw := empty + "\n\n"
 
@@ -101,8 +246,28 @@ func (do *Domain) parseHeaders() {
 		return
 	}
 
+	do.nplurals, do.plural = parsePluralForms(do.PluralForms)
+	do.pluralforms = compilePlural(do.plural)
+}
+
+// compilePlural compiles a plural formula, returning nil when it's empty or invalid.
+// Catalogs without Plural-Forms header have an empty formula, which the plurals package can't compile.
+func compilePlural(plural string) plurals.Expression {
+	if strings.TrimSpace(plural) == "" {
+		return nil
+	}
+	if expr, err := plurals.CompileCached(plural); err == nil {
+		return expr
+	}
+
+	return nil
+}
+
+// parsePluralForms splits a Plural-Forms header value (i.e. "nplurals=2; plural=(n != 1);")
+// into the amount of plural forms and the plural formula.
+func parsePluralForms(header string) (nplurals int, plural string) {
 	// Split plural form header value
-	pfs := strings.Split(do.PluralForms, ";")
+	pfs := strings.Split(header, ";")
 
 	// Parse values
 	for _, i := range pfs {
@@ -113,94 +278,105 @@ func (do *Domain) parseHeaders() {
 
 		switch strings.TrimSpace(vs[0]) {
 		case "nplurals":
-			do.nplurals, _ = strconv.Atoi(vs[1])
+			nplurals, _ = strconv.Atoi(strings.TrimSpace(vs[1]))
 
 		case "plural":
-			do.plural = vs[1]
-
-			if expr, err := plurals.Compile(do.plural); err == nil {
-				do.pluralforms = expr
-			}
+			plural = vs[1]
 
 		}
 	}
+
+	return nplurals, plural
 }
 
+// Get retrieves the corresponding Translation for the given string.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func (do *Domain) Get(str string, vars ...interface{}) string {
-	// Sync read
-	do.trMutex.RLock()
-	defer do.trMutex.RUnlock()
+	c := do.load()
 
-	if do.translations != nil {
-		if _, ok := do.translations[str]; ok {
-			return Printf(do.translations[str].Get(), vars...)
-		}
+	if tr, ok := c.lookup(entryKey{id: str}); ok {
+		return c.translate(tr, "", 0, 1, vars)
 	}
 
 	// Return the same we received by default
-	return Printf(str, vars...)
+	return c.printf("", str, str, vars)
 }
 
 // GetN retrieves the (N)th plural form of Translation for the given string.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func (do *Domain) GetN(str, plural string, n int, vars ...interface{}) string {
-	// Sync read
-	do.trMutex.RLock()
-	defer do.trMutex.RUnlock()
+	c := do.load()
 
-	if do.translations != nil {
-		if _, ok := do.translations[str]; ok {
-			return Printf(do.translations[str].GetN(do.pluralForm(n)), vars...)
-		}
+	if tr, ok := c.lookup(entryKey{id: str}); ok {
+		return c.translate(tr, "", c.pluralForm(n), n, vars)
 	}
 
 	// Parse plural forms to distinguish between plural and singular
-	if do.pluralForm(n) == 0 {
-		return Printf(str, vars...)
+	if c.pluralForm(n) == 0 {
+		return c.printf("", str, str, vars)
 	}
-	return Printf(plural, vars...)
+	return c.printf("", str, plural, vars)
 }
 
 // GetC retrieves the corresponding Translation for a given string in the given context.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func (do *Domain) GetC(str, ctx string, vars ...interface{}) string {
-	do.trMutex.RLock()
-	defer do.trMutex.RUnlock()
-
-	if do.contexts != nil {
-		if _, ok := do.contexts[ctx]; ok {
-			if do.contexts[ctx] != nil {
-				if _, ok := do.contexts[ctx][str]; ok {
-					return Printf(do.contexts[ctx][str].Get(), vars...)
-				}
-			}
-		}
+	c := do.load()
+
+	if tr, ok := c.lookup(entryKey{ctx: ctx, id: str}); ok {
+		return c.translate(tr, ctx, 0, 1, vars)
 	}
 
 	// Return the string we received by default
-	return Printf(str, vars...)
+	return c.printf(ctx, str, str, vars)
 }
 
 // GetNC retrieves the (N)th plural form of Translation for the given string in the given context.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func (do *Domain) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
-	do.trMutex.RLock()
-	defer do.trMutex.RUnlock()
-
-	if do.contexts != nil {
-		if _, ok := do.contexts[ctx]; ok {
-			if do.contexts[ctx] != nil {
-				if _, ok := do.contexts[ctx][str]; ok {
-					return Printf(do.contexts[ctx][str].GetN(do.pluralForm(n)), vars...)
-				}
-			}
-		}
+	c := do.load()
+
+	if tr, ok := c.lookup(entryKey{ctx: ctx, id: str}); ok {
+		return c.translate(tr, ctx, c.pluralForm(n), n, vars)
 	}
 
 	if n == 1 {
-		return Printf(str, vars...)
+		return c.printf(ctx, str, str, vars)
+	}
+	return c.printf(ctx, str, plural, vars)
+}
+
+// encodeEntries returns the catalog entries split by context, as stored by TranslatorEncoding.
+func (c *catalog) encodeEntries() (map[string]*Translation, map[string]map[string]*Translation) {
+	translations := make(map[string]*Translation)
+	contexts := make(map[string]map[string]*Translation)
+
+	for k, tr := range c.entries {
+		if k.ctx == "" {
+			translations[k.id] = tr
+			continue
+		}
+		if _, ok := contexts[k.ctx]; !ok {
+			contexts[k.ctx] = make(map[string]*Translation)
+		}
+		contexts[k.ctx][k.id] = tr
+	}
+
+	return translations, contexts
+}
+
+// setEncodedEntries replaces the storage with entries split by context, as stored by TranslatorEncoding.
+// It must be called while holding trMutex.
+func (do *Domain) setEncodedEntries(translations map[string]*Translation, contexts map[string]map[string]*Translation) {
+	do.entries = make(map[entryKey]*Translation, len(translations))
+	for id, tr := range translations {
+		do.entries[entryKey{id: id}] = tr
+	}
+	for ctx, trs := range contexts {
+		for id, tr := range trs {
+			do.entries[entryKey{ctx: ctx, id: id}] = tr
+		}
 	}
-	return Printf(plural, vars...)
 }
 
 // MarshalBinary implements encoding.BinaryMarshaler interface
@@ -211,8 +387,7 @@ func (do *Domain) MarshalBinary() ([]byte, error) {
 	obj.PluralForms = do.PluralForms
 	obj.Nplurals = do.nplurals
 	obj.Plural = do.plural
-	obj.Translations = do.translations
-	obj.Contexts = do.contexts
+	obj.Translations, obj.Contexts = do.load().encodeEntries()
 
 	var buff bytes.Buffer
 	encoder := gob.NewEncoder(&buff)
@@ -232,17 +407,18 @@ func (do *Domain) UnmarshalBinary(data []byte) error {
 		return err
 	}
 
+	do.trMutex.Lock()
+	defer do.trMutex.Unlock()
+
 	do.Headers = obj.Headers
 	do.Language = obj.Language
 	do.PluralForms = obj.PluralForms
 	do.nplurals = obj.Nplurals
 	do.plural = obj.Plural
-	do.translations = obj.Translations
-	do.contexts = obj.Contexts
+	do.setEncodedEntries(obj.Translations, obj.Contexts)
+	do.pluralforms = compilePlural(do.plural)
 
-	if expr, err := plurals.Compile(do.plural); err == nil {
-		do.pluralforms = expr
-	}
+	do.publish()
 
 	return nil
 }
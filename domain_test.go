@@ -32,3 +32,77 @@ func TestBinaryEncoding(t *testing.T) {
 		t.Errorf("Expected 'en_US' but got '%s'", tr)
 	}
 }
+
+func TestDomainSnapshotReload(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`msgid "My text"
+msgstr "Translated text"`))
+
+	done := make(chan bool)
+
+	// Reload concurrently while reading
+	go func() {
+		for i := 0; i < 100; i++ {
+			po.Parse([]byte(`msgid "Another string"
+msgstr "Another translation"`))
+		}
+		done <- true
+	}()
+
+	for i := 0; i < 100; i++ {
+		if tr := po.Get("My text"); tr != translatedText {
+			t.Fatalf("Expected '%s' but got '%s'", translatedText, tr)
+		}
+	}
+	<-done
+
+	// Both parsed buffers are available
+	if tr := po.Get("Another string"); tr != "Another translation" {
+		t.Errorf("Expected 'Another translation' but got '%s'", tr)
+	}
+	if tr := po.Get("My text"); tr != translatedText {
+		t.Errorf("Expected '%s' but got '%s'", translatedText, tr)
+	}
+}
+
+func TestDomainContextIndex(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`msgid "Open"
+msgstr "Abrir"
+
+msgctxt "Menu"
+msgid "Open"
+msgstr "Abrir menú"
+
+msgctxt "Door"
+msgid "Open"
+msgstr "Abierta"`))
+
+	for _, test := range []struct{ ctx, expected string }{
+		{"", "Abrir"},
+		{"Menu", "Abrir menú"},
+		{"Door", "Abierta"},
+		{"Window", "Open"},
+	} {
+		tr := po.GetC("Open", test.ctx)
+		if test.ctx == "" {
+			tr = po.Get("Open")
+		}
+		if tr != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, tr)
+		}
+	}
+
+	// Context entries survive binary encoding
+	buff, err := po.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	po2 := NewPo()
+	if err := po2.UnmarshalBinary(buff); err != nil {
+		t.Fatal(err)
+	}
+	if tr := po2.GetC("Open", "Door"); tr != "Abierta" {
+		t.Errorf("Expected 'Abierta' but got '%s'", tr)
+	}
+}
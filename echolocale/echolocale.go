@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+/*
+Package echolocale provides an Echo middleware resolving the gotext Locale of each request.
+
+It's a separate module, so the gotext package doesn't depend on Echo.
+
+Example:
+
+	l := gotext.NewLocale("/path/to/i18n/dir", "en_US")
+	l.AddDomain("default")
+
+	e := echo.New()
+	e.Use(echolocale.Middleware(l))
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, echolocale.Locale(c).Get("Hello"))
+	})
+*/
+package echolocale
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/leonelquinteros/gotext"
+)
+
+// Key is the key storing the Locale in the Echo context, so it's available as c.Get("locale").
+const Key = "locale"
+
+// Middleware returns an Echo middleware storing in the context the Locale returned by gotext.RequestLocale,
+// resolved with the given Resolvers (the Accept-Language header by default).
+// It's also injected in the request context, to be retrieved with gotext.FromContext.
+func Middleware(l *gotext.Locale, resolvers ...gotext.Resolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			r := c.Request()
+			loc := gotext.RequestLocale(l, r, resolvers...)
+			c.Set(Key, loc)
+			c.SetRequest(r.WithContext(gotext.NewContext(r.Context(), loc)))
+
+			return next(c)
+		}
+	}
+}
+
+// Locale returns the Locale stored in the Echo context by Middleware, or nil if there's none.
+func Locale(c echo.Context) *gotext.Locale {
+	l, _ := c.Get(Key).(*gotext.Locale)
+	return l
+}
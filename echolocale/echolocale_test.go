@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package echolocale
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/leonelquinteros/gotext"
+)
+
+func TestMiddleware(t *testing.T) {
+	l := gotext.NewLocale("../fixtures/", "en_US")
+	l.AddDomain("default")
+
+	e := echo.New()
+	e.Use(Middleware(l))
+	e.GET("/", func(c echo.Context) error {
+		if c.Get("locale") != Locale(c) || gotext.FromContext(c.Request().Context()) != Locale(c) {
+			t.Error("Expected the same Locale in every context")
+		}
+		return c.String(http.StatusOK, Locale(c).Get("language"))
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "de-DE")
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+
+	if got := w.Body.String(); got != "de_DE" {
+		t.Errorf("Expected 'de_DE' but got '%s'", got)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+// EmptyFunc returns the string looked up for an entry whose translation is empty, from the context (empty for none),
+// msgid and plural msgid of the entry, and n, 1 for singular lookups. A non-empty string is formatted with the
+// parameters of the lookup.
+type EmptyFunc func(ctx, id, plural string, n int) string
+
+// EmptyString is an EmptyFunc returning an empty string, i.e. for products rather showing nothing than source strings.
+func EmptyString(ctx, id, plural string, n int) string {
+	return ""
+}
+
+// translate returns the plural form of the translation formatted with vars, or the string returned by the EmptyFunc
+// when the form is empty.
+func (c *catalog) translate(tr *Translation, ctx string, form, n int, vars []interface{}) string {
+	if c.empty != nil && tr.Trs[form] == "" {
+		if s := c.empty(ctx, tr.ID, tr.PluralID, n); s != "" {
+			return c.printf(ctx, tr.ID, s, vars)
+		}
+		return ""
+	}
+
+	return c.printf(ctx, tr.ID, tr.GetN(form), vars)
+}
+
+/*
+SetEmptyFunc sets what lookups return for the entries existing with an empty translation: by default, or with a nil
+EmptyFunc, the msgid or plural msgid, EmptyString for an empty string, or the string returned by any other EmptyFunc.
+Strings without entry still return their msgid.
+
+Example:
+
+	po.GetDomain().SetEmptyFunc(func(ctx, id, plural string, n int) string {
+		log.Printf("untranslated: %q", id)
+		return english.GetNC(id, plural, n, ctx)
+	})
+*/
+func (do *Domain) SetEmptyFunc(f EmptyFunc) {
+	do.trMutex.Lock()
+	do.empty = f
+	do.publish()
+	do.trMutex.Unlock()
+}
+
+// SetEmptyFunc sets what lookups return for entries with an empty translation in the domains loaded, and the ones
+// loaded afterwards, including the ones added with AddTranslator, AddDomainURL or an Updater, as Domain.SetEmptyFunc.
+// Entries empty in every layer stacked on a domain, i.e. with SetOverride, return their msgid.
+func (l *Locale) SetEmptyFunc(f EmptyFunc) {
+	l.Lock()
+	l.empty = f
+	for _, tr := range l.Domains {
+		if do := tr.GetDomain(); do != nil {
+			do.SetEmptyFunc(f)
+		}
+	}
+	l.Unlock()
+}
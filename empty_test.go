@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+)
+
+const emptyPo = `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Save"
+msgstr "Speichern"
+
+msgid "Delete %s"
+msgstr ""
+
+msgctxt "menu"
+msgid "Open"
+msgstr ""
+
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "%d Datei"
+msgstr[1] ""
+`
+
+func TestEmptyFunc(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(emptyPo))
+
+	// Default to the msgid
+	if tr := po.Get("Delete %s", "x"); tr != "Delete x" {
+		t.Errorf("Expected '%s' but got '%s'", "Delete x", tr)
+	}
+
+	po.GetDomain().SetEmptyFunc(EmptyString)
+	tests := []struct {
+		tr, expected string
+	}{
+		{po.Get("Save"), "Speichern"},
+		{po.Get("Delete %s", "x"), ""},
+		{po.GetC("Open", "menu"), ""},
+		{po.GetN("One file", "%d files", 1, 1), "1 Datei"},
+		{po.GetN("One file", "%d files", 2, 2), ""},
+		{po.Get("Missing"), "Missing"},
+	}
+	for _, test := range tests {
+		if test.tr != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.tr)
+		}
+	}
+
+	var got []string
+	po.GetDomain().SetEmptyFunc(func(ctx, id, plural string, n int) string {
+		got = append(got, ctx+"|"+id+"|"+plural)
+		if n != 1 {
+			return "[" + plural + "]"
+		}
+		return "[" + id + "]"
+	})
+	tests = []struct {
+		tr, expected string
+	}{
+		{po.Get("Delete %s", "x"), "[Delete x]"},
+		{po.GetC("Open", "menu"), "[Open]"},
+		{po.GetN("One file", "%d files", 2, 2), "[2 files]"},
+	}
+	for _, test := range tests {
+		if test.tr != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.tr)
+		}
+	}
+	if len(got) != 3 || got[1] != "menu|Open|" || got[2] != "|One file|%d files" {
+		t.Errorf("Unexpected calls %q", got)
+	}
+
+	po.GetDomain().SetEmptyFunc(nil)
+	if tr := po.GetC("Open", "menu"); tr != "Open" {
+		t.Errorf("Expected '%s' but got '%s'", "Open", tr)
+	}
+}
+
+func TestLocaleEmptyFunc(t *testing.T) {
+	dirname := path.Join("/tmp", "emptyfunc")
+	if err := os.MkdirAll(path.Join(dirname, "de", "LC_MESSAGES"), os.ModePerm); err != nil {
+		t.Fatalf("Can't create test directory: %s", err.Error())
+	}
+	defer os.RemoveAll(dirname)
+	for _, dom := range []string{"default", "extra"} {
+		err := ioutil.WriteFile(path.Join(dirname, "de", "LC_MESSAGES", dom+".po"), []byte(emptyPo), os.ModePerm)
+		if err != nil {
+			t.Fatalf("Can't write test file: %s", err.Error())
+		}
+	}
+
+	l := NewLocale(dirname, "de")
+	l.AddDomain("default")
+	l.SetEmptyFunc(EmptyString)
+	l.AddDomain("extra")
+
+	if tr := l.Get("Delete %s", "x"); tr != "" {
+		t.Errorf("Expected '%s' but got '%s'", "", tr)
+	}
+	if tr := l.GetD("extra", "Delete %s", "x"); tr != "" {
+		t.Errorf("Expected '%s' but got '%s'", "", tr)
+	}
+}
+
+func TestLocaleEmptyFuncAdded(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(emptyPo))
+	}))
+	defer srv.Close()
+
+	l := NewLocale("", "de")
+	l.SetEmptyFunc(EmptyString)
+
+	// Remote domains, and translators added afterwards, use the EmptyFunc too
+	if err := l.AddDomainURL(srv.URL + "/de/remote.po"); err != nil {
+		t.Fatal(err)
+	}
+	po := NewPo()
+	po.Parse([]byte(emptyPo))
+	l.AddTranslator("added", po)
+
+	for _, dom := range []string{"remote", "added"} {
+		if tr := l.GetDC(dom, "Open", "menu"); tr != "" {
+			t.Errorf("Expected '%s' in %s but got '%s'", "", dom, tr)
+		}
+		if tr := l.GetD(dom, "Save"); tr != "Speichern" {
+			t.Errorf("Expected '%s' in %s but got '%s'", "Speichern", dom, tr)
+		}
+	}
+}
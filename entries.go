@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "sort"
+
+// Entry is an entry of a catalog with its translations, as listed by Domain.Entries and Locale.GetAll.
+type Entry struct {
+	// Context of the entry, empty for none.
+	Context string
+
+	ID       string
+	PluralID string
+
+	// Translations holds the translation of each plural form by index, empty strings for the untranslated ones.
+	Translations []string
+
+	// Fuzzy marks draft translations needing review.
+	Fuzzy bool
+}
+
+// Get returns the translation of the entry, or its msgid when untranslated, as Translation.Get does.
+func (e Entry) Get() string {
+	if e.hasTranslation() {
+		return e.Translations[0]
+	}
+	return e.ID
+}
+
+// IsTranslated reports if all of the translations of the entry are set.
+func (e Entry) IsTranslated() bool {
+	for _, tr := range e.Translations {
+		if tr == "" {
+			return false
+		}
+	}
+	return len(e.Translations) > 0
+}
+
+// EntryState is a translation state selected by an EntryQuery.
+type EntryState int
+
+const (
+	// StateAny selects all of the entries.
+	StateAny EntryState = iota
+
+	// StateTranslated selects the entries whose translations are all set.
+	StateTranslated
+
+	// StateUntranslated selects the entries missing translations.
+	StateUntranslated
+
+	// StateFuzzy selects the entries flagged fuzzy.
+	StateFuzzy
+)
+
+// EntryQuery selects the entries iterated by Domain.All and Locale.All.
+type EntryQuery struct {
+	// Domains selects the domains iterated by Locale.All, all of them when empty.
+	Domains []string
+
+	// Filter selects the entries by context and msgid, i.e. FilterContexts("menu"), nil for all of them.
+	Filter EntryFilter
+
+	// State selects the entries by translation state.
+	State EntryState
+}
+
+// matches reports if the query selects the translation stored with the key. The header entry is never selected.
+func (q EntryQuery) matches(k entryKey, t *Translation) bool {
+	if k.id == "" || (q.Filter != nil && !q.Filter(k.ctx, k.id)) {
+		return false
+	}
+
+	switch q.State {
+	case StateTranslated, StateUntranslated:
+		translated := len(t.Trs) > 0
+		for _, tr := range t.Trs {
+			if tr == "" {
+				translated = false
+			}
+		}
+		return translated == (q.State == StateTranslated)
+	case StateFuzzy:
+		return t.Fuzzy
+	}
+	return true
+}
+
+// matchesEntry reports if the query selects the entry.
+func (q EntryQuery) matchesEntry(e Entry) bool {
+	t := &Translation{ID: e.ID, PluralID: e.PluralID, Trs: make(map[int]string, len(e.Translations)), Fuzzy: e.Fuzzy}
+	for i, tr := range e.Translations {
+		t.Trs[i] = tr
+	}
+	return q.matches(entryKey{ctx: e.Context, id: e.ID}, t)
+}
+
+// hasTranslation reports if the singular translation of the entry is set.
+func (e Entry) hasTranslation() bool {
+	return len(e.Translations) > 0 && e.Translations[0] != ""
+}
+
+// newEntry returns the entry of the translation stored with the key.
+func newEntry(k entryKey, t *Translation) Entry {
+	n := 0
+	for i := range t.Trs {
+		if i+1 > n {
+			n = i + 1
+		}
+	}
+	trs := make([]string, n)
+	for i, tr := range t.Trs {
+		if i >= 0 {
+			trs[i] = tr
+		}
+	}
+
+	return Entry{Context: k.ctx, ID: t.ID, PluralID: t.PluralID, Translations: trs, Fuzzy: t.Fuzzy}
+}
+
+// sortEntries sorts entries by context, then msgid.
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Context != entries[j].Context {
+			return entries[i].Context < entries[j].Context
+		}
+		return entries[i].ID < entries[j].ID
+	})
+}
+
+// Entries returns all of the entries of the domain, translated or not, sorted by context and msgid.
+// The header entry isn't listed. The entries are copies, modifying them doesn't change the domain.
+func (do *Domain) Entries() []Entry {
+	c := do.load()
+	entries := make([]Entry, 0, len(c.entries))
+	for k, t := range c.entries {
+		if k.id == "" {
+			continue
+		}
+		entries = append(entries, newEntry(k, t))
+	}
+	sortEntries(entries)
+
+	return entries
+}
+
+// Entries returns all of the entries of the catalog, as Domain.Entries.
+func (po *Po) Entries() []Entry {
+	return po.domain.Entries()
+}
+
+// Entries returns all of the entries of the catalog, as Domain.Entries.
+func (mo *Mo) Entries() []Entry {
+	return mo.domain.Entries()
+}
+
+// translatorEntries returns the entries of the Translator. The entries of chained Translators are resolved as lookups
+// are: each one is taken from the first Translator translating it, or holding it when none does.
+func translatorEntries(tr Translator) []Entry {
+	c, ok := tr.(*ChainTranslator)
+	if !ok {
+		if do := tr.GetDomain(); do != nil {
+			return do.Entries()
+		}
+		return nil
+	}
+
+	byKey := make(map[entryKey]int)
+	var entries []Entry
+	for _, link := range c.links {
+		for _, e := range translatorEntries(link) {
+			k := entryKey{ctx: e.Context, id: e.ID}
+			i, ok := byKey[k]
+			if !ok {
+				byKey[k] = len(entries)
+				entries = append(entries, e)
+				continue
+			}
+			if !entries[i].hasTranslation() && e.hasTranslation() {
+				entries[i] = e
+			}
+		}
+	}
+	sortEntries(entries)
+
+	return entries
+}
+
+// GetAll returns all of the entries of the domain, translated or not, sorted by context and msgid, so exporters, admin
+// interfaces and caches can list a catalog without parsing its files again. With overrides, the entries are resolved
+// as lookups are. It returns nil for domains not loaded.
+func (l *Locale) GetAll(dom string) []Entry {
+	tr := l.load().domains[dom]
+	if tr == nil {
+		return nil
+	}
+
+	return translatorEntries(tr)
+}
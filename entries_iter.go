@@ -0,0 +1,77 @@
+//go:build go1.23
+// +build go1.23
+
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"iter"
+	"sort"
+)
+
+// All returns an iterator over the entries of the domain selected by the query, in no particular order, so large
+// catalogs can be processed without copying all of their entries first. Domains of the query are ignored.
+// It iterates over the entries loaded when it's called, even if the domain is parsed again meanwhile.
+func (do *Domain) All(q EntryQuery) iter.Seq[Entry] {
+	c := do.load()
+	return func(yield func(Entry) bool) {
+		for k, t := range c.entries {
+			if q.matches(k, t) && !yield(newEntry(k, t)) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the entries of the catalog selected by the query, as Domain.All.
+func (po *Po) All(q EntryQuery) iter.Seq[Entry] {
+	return po.domain.All(q)
+}
+
+// All returns an iterator over the entries of the catalog selected by the query, as Domain.All.
+func (mo *Mo) All(q EntryQuery) iter.Seq[Entry] {
+	return mo.domain.All(q)
+}
+
+// All returns an iterator over the entries of the domains selected by the query, with the name of their domain.
+// Domains are iterated in lexical order, their entries in no particular order. Domains with overrides are resolved
+// as lookups are, which requires listing their entries first.
+func (l *Locale) All(q EntryQuery) iter.Seq2[string, Entry] {
+	snap := l.load()
+	doms := q.Domains
+	if len(doms) == 0 {
+		for dom := range snap.domains {
+			doms = append(doms, dom)
+		}
+		sort.Strings(doms)
+	}
+
+	return func(yield func(string, Entry) bool) {
+		for _, dom := range doms {
+			tr := snap.domains[dom]
+			if tr == nil {
+				continue
+			}
+
+			if _, ok := tr.(*ChainTranslator); ok {
+				for _, e := range translatorEntries(tr) {
+					if q.matchesEntry(e) && !yield(dom, e) {
+						return
+					}
+				}
+				continue
+			}
+			if do := tr.GetDomain(); do != nil {
+				for e := range do.All(q) {
+					if !yield(dom, e) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
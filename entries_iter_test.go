@@ -0,0 +1,92 @@
+//go:build go1.23
+// +build go1.23
+
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestPoAll(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(entriesPo))
+
+	ids := func(q EntryQuery) []string {
+		var ids []string
+		for e := range po.All(q) {
+			ids = append(ids, e.ID)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	tests := []struct {
+		query    EntryQuery
+		expected []string
+	}{
+		{EntryQuery{}, []string{"Hello", "One file", "Open", "Untranslated"}},
+		{EntryQuery{State: StateTranslated}, []string{"Hello", "One file", "Open"}},
+		{EntryQuery{State: StateUntranslated}, []string{"Untranslated"}},
+		{EntryQuery{State: StateFuzzy}, []string{"Open"}},
+		{EntryQuery{Filter: FilterContexts("menu")}, []string{"Open"}},
+		{EntryQuery{Filter: FilterPrefix("O"), State: StateTranslated}, []string{"One file", "Open"}},
+	}
+	for _, test := range tests {
+		if got := ids(test.query); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Expected %v but got %v for %+v", test.expected, got, test.query)
+		}
+	}
+
+	// Breaking stops the iteration
+	n := 0
+	for range po.All(EntryQuery{}) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("Expected 1 entry iterated but got %d", n)
+	}
+}
+
+func TestLocaleAll(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(entriesPo))
+	other := NewPo()
+	other.Parse([]byte(`msgid "Bye"
+msgstr "Tschüss"
+`))
+	override := NewPo()
+	override.Parse([]byte(`msgid "Untranslated"
+msgstr "Übersetzt"
+`))
+
+	l := NewLocale("", "de")
+	l.AddTranslator("default", po)
+	l.AddTranslator("other", other)
+	l.SetOverride("default", override)
+
+	var got []string
+	for dom, e := range l.All(EntryQuery{State: StateTranslated}) {
+		got = append(got, dom+":"+e.Get())
+	}
+	sort.Strings(got)
+	expected := []string{"default:Eine Datei", "default:Hallo", "default:Öffnen", "default:Übersetzt", "other:Tschüss"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v but got %v", expected, got)
+	}
+
+	got = nil
+	for dom, e := range l.All(EntryQuery{Domains: []string{"other", "missing"}}) {
+		got = append(got, dom+":"+e.ID)
+	}
+	if !reflect.DeepEqual(got, []string{"other:Bye"}) {
+		t.Errorf("Expected only the entries of the selected domain but got %v", got)
+	}
+}
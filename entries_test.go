@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"reflect"
+	"testing"
+)
+
+const entriesPo = `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Hello"
+msgstr "Hallo"
+
+#, fuzzy
+msgctxt "menu"
+msgid "Open"
+msgstr "Öffnen"
+
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "Eine Datei"
+msgstr[1] "%d Dateien"
+
+msgid "Untranslated"
+msgstr ""
+`
+
+func TestPoEntries(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(entriesPo))
+
+	expected := []Entry{
+		{ID: "Hello", Translations: []string{"Hallo"}},
+		{ID: "One file", PluralID: "%d files", Translations: []string{"Eine Datei", "%d Dateien"}},
+		{ID: "Untranslated", Translations: []string{""}},
+		{Context: "menu", ID: "Open", Translations: []string{"Öffnen"}, Fuzzy: true},
+	}
+	entries := po.Entries()
+	if !reflect.DeepEqual(entries, expected) {
+		t.Fatalf("Expected %v but got %v", expected, entries)
+	}
+
+	if entries[2].IsTranslated() || !entries[1].IsTranslated() {
+		t.Errorf("Expected only the translated entries to be reported translated")
+	}
+	if entries[2].Get() != "Untranslated" {
+		t.Errorf("Expected '%s' but got '%s'", "Untranslated", entries[2].Get())
+	}
+
+	// Entries are copies
+	entries[0].Translations[0] = "Changed"
+	if tr := po.Get("Hello"); tr != "Hallo" {
+		t.Errorf("Expected '%s' but got '%s'", "Hallo", tr)
+	}
+}
+
+func TestLocaleGetAll(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(entriesPo))
+	override := NewPo()
+	override.Parse([]byte(`msgid "Hello"
+msgstr "Servus"
+
+msgid "Untranslated"
+msgstr "Übersetzt"
+
+msgid "Extra"
+msgstr "Zusätzlich"
+`))
+
+	l := NewLocale("", "de")
+	l.AddTranslator("default", po)
+	if entries := l.GetAll("missing"); entries != nil {
+		t.Errorf("Expected no entries for a missing domain but got %v", entries)
+	}
+	if entries := l.GetAll("default"); len(entries) != 4 || entries[0].Get() != "Hallo" {
+		t.Errorf("Expected the 4 entries of the catalog but got %v", entries)
+	}
+
+	l.SetOverride("default", override)
+	var got []string
+	for _, e := range l.GetAll("default") {
+		got = append(got, e.ID+"="+e.Get())
+	}
+	expected := []string{"Extra=Zusätzlich", "Hello=Servus", "One file=Eine Datei", "Untranslated=Übersetzt", "Open=Öffnen"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v but got %v", expected, got)
+	}
+}
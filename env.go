@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"os"
+	"strings"
+)
+
+// LanguagesFromEnv returns the list of languages configured in the environment, in priority order.
+// It follows the GNU gettext precedence rules: the locale is taken from LC_ALL, LC_MESSAGES or LANG (first non-empty wins),
+// and when it isn't the "C" or "POSIX" locale, the colon-separated LANGUAGE variable (i.e. "fr_CH:fr:en") takes precedence over it.
+// Returned language codes are simplified (see SimplifiedLocale). It returns nil when no usable language is configured.
+func LanguagesFromEnv() []string {
+	locale := ""
+	for _, v := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale = os.Getenv(v); locale != "" {
+			break
+		}
+	}
+
+	// LANGUAGE is ignored for the "C" locale, as GNU gettext does.
+	if locale == "" || isCLocale(locale) {
+		return nil
+	}
+
+	var langs []string
+	for _, lang := range strings.Split(os.Getenv("LANGUAGE"), ":") {
+		if lang = SimplifiedLocale(lang); lang != "" && !isCLocale(lang) {
+			langs = append(langs, lang)
+		}
+	}
+	if len(langs) > 0 {
+		return langs
+	}
+
+	return []string{SimplifiedLocale(locale)}
+}
+
+// isCLocale reports if the locale name refers to the default "C" (or "POSIX") locale.
+func isCLocale(locale string) bool {
+	locale = SimplifiedLocale(locale)
+	return locale == "C" || locale == "POSIX"
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+// setEnv sets the given environment variables and returns a function restoring previous values.
+func setEnv(vars map[string]string) func() {
+	old := make(map[string]*string)
+	for k, v := range vars {
+		if prev, ok := os.LookupEnv(k); ok {
+			old[k] = &prev
+		} else {
+			old[k] = nil
+		}
+		os.Setenv(k, v)
+	}
+
+	return func() {
+		for k, v := range old {
+			if v == nil {
+				os.Unsetenv(k)
+			} else {
+				os.Setenv(k, *v)
+			}
+		}
+	}
+}
+
+func TestLanguagesFromEnv(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want []string
+	}{
+		{
+			env:  map[string]string{"LANGUAGE": "fr_CH:fr:en", "LC_ALL": "", "LC_MESSAGES": "", "LANG": "de_DE.UTF-8"},
+			want: []string{"fr_CH", "fr", "en"},
+		},
+		{
+			env:  map[string]string{"LANGUAGE": "", "LC_ALL": "", "LC_MESSAGES": "es_AR.UTF-8", "LANG": "de_DE.UTF-8"},
+			want: []string{"es_AR"},
+		},
+		{
+			env:  map[string]string{"LANGUAGE": "", "LC_ALL": "pt_BR@latin", "LC_MESSAGES": "es_AR", "LANG": "de_DE"},
+			want: []string{"pt_BR"},
+		},
+		{
+			env:  map[string]string{"LANGUAGE": "fr:en", "LC_ALL": "C", "LC_MESSAGES": "", "LANG": "de_DE"},
+			want: nil,
+		},
+		{
+			env:  map[string]string{"LANGUAGE": "fr", "LC_ALL": "", "LC_MESSAGES": "", "LANG": ""},
+			want: nil,
+		},
+		{
+			env:  map[string]string{"LANGUAGE": "::", "LC_ALL": "", "LC_MESSAGES": "", "LANG": "de_DE"},
+			want: []string{"de_DE"},
+		},
+	}
+
+	for _, test := range tests {
+		restore := setEnv(test.env)
+		got := LanguagesFromEnv()
+		restore()
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Expected %v for %v but got %v", test.want, test.env, got)
+		}
+	}
+}
+
+func TestNewLocaleFromEnv(t *testing.T) {
+	restore := setEnv(map[string]string{"LANGUAGE": "xx_YY:fr:en_US", "LC_ALL": "", "LC_MESSAGES": "", "LANG": "en_US.UTF-8"})
+	defer restore()
+
+	// Create Locale from environment: there are no xx_YY translations, so fr is used.
+	l := NewLocaleFromEnv("fixtures/")
+	l.AddDomain("default")
+
+	tr := l.Get("My text")
+	if tr != "Translated text" {
+		t.Errorf("Expected 'Translated text' but got '%s'", tr)
+	}
+
+	tr = l.Get("language")
+	if tr != "fr" {
+		t.Errorf("Expected 'fr' but got '%s'", tr)
+	}
+}
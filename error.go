@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"context"
+	"errors"
+)
+
+// localizable is implemented by values translated when they're rendered for a Locale.
+type localizable interface {
+	Localize(l *Locale) string
+}
+
+// localizeArgs renders the localizable formatting arguments for the given Locale.
+func localizeArgs(l *Locale, args []interface{}) []interface{} {
+	if len(args) == 0 {
+		return args
+	}
+
+	out := make([]interface{}, len(args))
+	for i, a := range args {
+		if v, ok := a.(localizable); ok {
+			out[i] = v.Localize(l)
+		} else {
+			out[i] = a
+		}
+	}
+
+	return out
+}
+
+/*
+LocalizedError is an error whose message is translated when it's rendered for a Locale instead of when it's created.
+It captures the msgid, formatting arguments and domain, so the same error can be returned to users speaking different languages.
+Error returns the untranslated message, which is what gets logged.
+
+Example:
+
+	func handler(w http.ResponseWriter, r *http.Request) {
+		err := gotext.NewError("You've used %d of %d requests", used, limit)
+		http.Error(w, gotext.LocalizeError(r.Context(), err), http.StatusTooManyRequests)
+	}
+*/
+type LocalizedError struct {
+	// Domain is the domain the message is translated from. An empty Domain stands for the Locale default domain.
+	Domain string
+
+	// Context is the optional msgctxt of the message.
+	Context string
+
+	// Msgid and Plural are the source message and its optional plural form, selected with N.
+	Msgid  string
+	Plural string
+	N      int
+
+	// Args are the formatting arguments of the message. Localizable arguments, like other LocalizedError or Lazy
+	// values, are rendered in the same Locale.
+	Args []interface{}
+
+	// Err is the optional underlying error, returned by Unwrap.
+	Err error
+}
+
+// NewError returns a LocalizedError translated from the default domain.
+func NewError(msgid string, args ...interface{}) *LocalizedError {
+	return &LocalizedError{Msgid: msgid, Args: args}
+}
+
+// NewErrorD returns a LocalizedError translated from the given domain.
+func NewErrorD(dom, msgid string, args ...interface{}) *LocalizedError {
+	return &LocalizedError{Domain: dom, Msgid: msgid, Args: args}
+}
+
+// WrapError returns a LocalizedError translated from the default domain wrapping err.
+func WrapError(err error, msgid string, args ...interface{}) *LocalizedError {
+	return &LocalizedError{Msgid: msgid, Args: args, Err: err}
+}
+
+// Error returns the message formatted in the source language.
+func (e *LocalizedError) Error() string {
+	return e.Localize(nil)
+}
+
+// Unwrap returns the underlying error.
+func (e *LocalizedError) Unwrap() error {
+	return e.Err
+}
+
+// Localize returns the message translated for the given Locale. A nil Locale renders the source message.
+func (e *LocalizedError) Localize(l *Locale) string {
+	msg := LazyString{Domain: e.Domain, Context: e.Context, Msgid: e.Msgid, Plural: e.Plural, N: e.N, Args: e.Args}
+	if l == nil {
+		return msg.source()
+	}
+
+	return msg.Localize(l)
+}
+
+// LocalizeContext returns the message translated for the Locale carried by ctx (see NewContext),
+// or the source message if there's none.
+func (e *LocalizedError) LocalizeContext(ctx context.Context) string {
+	return e.Localize(FromContext(ctx))
+}
+
+// LocalizeError returns the message of err translated for the Locale carried by ctx.
+// The first LocalizedError found in the err chain is localized, other errors are returned as they are.
+func LocalizeError(ctx context.Context, err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var le *LocalizedError
+	if errors.As(err, &le) {
+		return le.LocalizeContext(ctx)
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func newErrorTestLocale(lang, prefix string) *Locale {
+	po := NewPo()
+	po.Parse([]byte(fmt.Sprintf(`
+msgid ""
+msgstr "Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "User %%s not found"
+msgstr "%s %%s"
+
+msgid "%%d file is too big"
+msgid_plural "%%d files are too big"
+msgstr[0] "%s one %%d"
+msgstr[1] "%s many %%d"
+
+msgid "Invalid value"
+msgstr "%s value"
+`, prefix, prefix, prefix, prefix)))
+
+	l := NewLocale("", lang)
+	l.AddTranslator("errors", po)
+	return l
+}
+
+func TestLocalizedError(t *testing.T) {
+	fr := newErrorTestLocale("fr", "fr")
+	de := newErrorTestLocale("de", "de")
+
+	err := NewError("User %s not found", "bob")
+	if err.Error() != "User bob not found" {
+		t.Errorf("Expected 'User bob not found' but got '%s'", err.Error())
+	}
+	if got := err.Localize(fr); got != "fr bob" {
+		t.Errorf("Expected 'fr bob' but got '%s'", got)
+	}
+	if got := err.Localize(de); got != "de bob" {
+		t.Errorf("Expected 'de bob' but got '%s'", got)
+	}
+
+	plural := &LocalizedError{Domain: "errors", Msgid: "%d file is too big", Plural: "%d files are too big", N: 3, Args: []interface{}{3}}
+	if plural.Error() != "3 files are too big" {
+		t.Errorf("Expected '3 files are too big' but got '%s'", plural.Error())
+	}
+	if got := plural.Localize(de); got != "de many 3" {
+		t.Errorf("Expected 'de many 3' but got '%s'", got)
+	}
+
+	// Localizable arguments are rendered in the same Locale.
+	nested := NewError("User %s not found", NewError("Invalid value"))
+	if got := nested.Localize(fr); got != "fr fr value" {
+		t.Errorf("Expected 'fr fr value' but got '%s'", got)
+	}
+}
+
+func TestLocalizeError(t *testing.T) {
+	cause := errors.New("sql: no rows")
+	err := fmt.Errorf("lookup: %w", WrapError(cause, "User %s not found", "bob"))
+
+	if !errors.Is(err, cause) {
+		t.Error("Expected the cause to be unwrapped")
+	}
+
+	ctx := NewContext(context.Background(), newErrorTestLocale("fr", "fr"))
+	if got := LocalizeError(ctx, err); got != "fr bob" {
+		t.Errorf("Expected 'fr bob' but got '%s'", got)
+	}
+	if got := LocalizeError(context.Background(), err); got != "User bob not found" {
+		t.Errorf("Expected 'User bob not found' but got '%s'", got)
+	}
+	if got := LocalizeError(ctx, cause); got != "sql: no rows" {
+		t.Errorf("Expected 'sql: no rows' but got '%s'", got)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// ExportI18next returns the translations of a Translator in the i18next JSON format (v3 compatibility),
+// so frontends using i18next share the backend catalogs. Contexts are appended to keys as "key_context",
+// and plurals use "key"/"key_plural" for languages with 2 forms or "key_0", "key_1"... otherwise.
+// i18next has to be configured with keySeparator and nsSeparator set to false, as msgids are used as keys.
+func ExportI18next(tr Translator) ([]byte, error) {
+	dj := domainJSON(tr)
+	nplurals := tr.GetDomain().nplurals
+	if nplurals == 0 {
+		nplurals = 2
+	}
+
+	out := make(map[string]string)
+	for ctx, ids := range dj.Translations {
+		for id, forms := range ids {
+			key := id
+			if ctx != "" {
+				key += "_" + ctx
+			}
+
+			if len(forms) == 1 {
+				out[key] = forms[0]
+				continue
+			}
+
+			for i, form := range forms {
+				switch {
+				case nplurals == 2 && i == 0:
+					out[key] = form
+				case nplurals == 2 && i == 1:
+					out[key+"_plural"] = form
+				default:
+					out[key+"_"+strconv.Itoa(i)] = form
+				}
+			}
+		}
+	}
+
+	return marshalExport(out)
+}
+
+// ExportGettextJS returns the translations of a Translator in the JSON format loaded by gettext.js,
+// with the language and Plural-Forms headers under the empty key, plural forms as arrays,
+// and contexts prepended to msgids with the "\u0004" separator.
+func ExportGettextJS(tr Translator) ([]byte, error) {
+	dj := domainJSON(tr)
+	do := tr.GetDomain()
+
+	out := map[string]interface{}{
+		"": map[string]string{
+			"language":     do.Language,
+			"plural-forms": do.PluralForms,
+		},
+	}
+	for ctx, ids := range dj.Translations {
+		for id, forms := range ids {
+			key := id
+			if ctx != "" {
+				key = ctx + EotSeparator + id
+			}
+
+			if len(forms) == 1 {
+				out[key] = forms[0]
+			} else {
+				out[key] = forms
+			}
+		}
+	}
+
+	return marshalExport(out)
+}
+
+// marshalExport encodes exported translations as indented JSON, without escaping HTML characters.
+func marshalExport(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const exportPo = `
+msgid ""
+msgstr ""
+"Language: pl\n"
+"Plural-Forms: nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "Open"
+msgstr "Otwórz"
+
+msgctxt "Menu"
+msgid "Open"
+msgstr "Otwórz menu"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d plik"
+msgstr[1] "%d pliki"
+msgstr[2] "%d plików"
+
+msgid "Untranslated"
+msgstr ""
+`
+
+func TestExportI18next(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(exportPo))
+
+	data, err := ExportI18next(po)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]string
+	if err = json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"Open":      "Otwórz",
+		"Open_Menu": "Otwórz menu",
+		"%d file_0": "%d plik",
+		"%d file_1": "%d pliki",
+		"%d file_2": "%d plików",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v but got %v", expected, got)
+	}
+
+	// Languages with 2 plural forms
+	po = NewPo()
+	po.ParseFile("fixtures/en_US/default.po")
+	if data, err = ExportI18next(po); err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	json.Unmarshal(data, &got)
+	if got["One with var: %s"] != "This one is the singular: %s" || got["One with var: %s_plural"] != "This one is the plural: %s" {
+		t.Errorf("Unexpected plurals in %v", got)
+	}
+}
+
+func TestExportGettextJS(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(exportPo))
+
+	data, err := ExportGettextJS(po)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]interface{}
+	if err = json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"": map[string]interface{}{
+			"language":     "pl",
+			"plural-forms": "nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+		},
+		"Open":         "Otwórz",
+		"Menu\x04Open": "Otwórz menu",
+		"%d file":      []interface{}{"%d plik", "%d pliki", "%d plików"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v but got %v", expected, got)
+	}
+}
@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+/*
+SetFallbackDomains sets the loaded domains consulted, in the given order, when a string is missing from the catalog of
+the requested domain, easing gradual migrations where strings move between domains. The catalogs of the fallback
+domains are consulted after the override and the catalog of the requested domain, and before the MissingTranslator.
+Calling it without domains removes the fallbacks. They aren't serialized along with the Locale, see MarshalBinary.
+
+Example:
+
+	l.AddDomain("billing")
+	l.AddDomain("default")
+	l.AddDomain("legacy")
+	l.SetFallbackDomains("default", "legacy")
+
+	fmt.Println(l.GetD("billing", "Invoice")) // from "default" or "legacy" until "billing" translates it
+*/
+func (l *Locale) SetFallbackDomains(doms ...string) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.fallbackDomains = append([]string(nil), doms...)
+	l.publish()
+}
+
+// GetFallbackDomains returns the domains consulted for the strings missing in the requested domain, in order.
+func (l *Locale) GetFallbackDomains() []string {
+	l.RLock()
+	defer l.RUnlock()
+
+	return append([]string(nil), l.fallbackDomains...)
+}
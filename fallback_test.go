@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "testing"
+
+func TestFallbackDomains(t *testing.T) {
+	parsePo := func(str string) *Po {
+		po := NewPo()
+		po.Parse([]byte(str))
+		return po
+	}
+
+	l := NewLocale("/tmp", "de")
+	l.AddTranslator("billing", parsePo("msgid \"Invoice\"\nmsgstr \"Rechnung\"\n"))
+	l.AddTranslator("default", parsePo("msgid \"Invoice\"\nmsgstr \"Faktura\"\n\nmsgid \"Total\"\nmsgstr \"Summe\"\n\nmsgid \"Tax\"\nmsgstr \"\"\n"))
+	l.AddTranslator("legacy", parsePo("msgid \"Total\"\nmsgstr \"Gesamt\"\n\nmsgid \"Tax\"\nmsgstr \"Steuer\"\n\nmsgid \"One item\"\nmsgid_plural \"%d items\"\nmsgstr[0] \"Ein Artikel\"\nmsgstr[1] \"%d Artikel\"\n"))
+
+	if tr := l.GetD("billing", "Total"); tr != "Total" {
+		t.Errorf("Expected '%s' but got '%s'", "Total", tr)
+	}
+
+	l.SetFallbackDomains("default", "legacy")
+	if doms := l.GetFallbackDomains(); len(doms) != 2 || doms[0] != "default" || doms[1] != "legacy" {
+		t.Errorf("Expected [default legacy] but got %v", doms)
+	}
+
+	tests := []struct {
+		dom, str, expected string
+		layer              Layer
+	}{
+		{"billing", "Invoice", "Rechnung", LayerBase},
+		{"billing", "Total", "Summe", LayerFallback},
+		{"billing", "Tax", "Steuer", LayerFallback},
+		{"billing", "Discount", "Discount", LayerNone},
+		{"legacy", "Invoice", "Faktura", LayerFallback},
+		{"default", "Tax", "Steuer", LayerFallback},
+	}
+	for _, test := range tests {
+		if tr := l.GetD(test.dom, test.str); tr != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, tr)
+		}
+		if ly := l.ResolveLayer(test.dom, test.str, ""); ly != test.layer {
+			t.Errorf("Expected the %s layer for '%s' but got %s", test.layer, test.str, ly)
+		}
+	}
+	if tr := l.GetND("billing", "One item", "%d items", 2, 2); tr != "2 Artikel" {
+		t.Errorf("Expected '%s' but got '%s'", "2 Artikel", tr)
+	}
+
+	l.SetFallbackDomains()
+	if tr := l.GetD("billing", "Total"); tr != "Total" {
+		t.Errorf("Expected '%s' but got '%s'", "Total", tr)
+	}
+}
+
+func TestFallbackDomainsMarshal(t *testing.T) {
+	billing := NewPo()
+	billing.Parse([]byte("msgid \"Invoice\"\nmsgstr \"Rechnung\"\n"))
+	legacy := NewPo()
+	legacy.Parse([]byte("msgid \"Total\"\nmsgstr \"Gesamt\"\n"))
+
+	l := NewLocale("/tmp", "de")
+	l.AddTranslator("billing", billing)
+	l.AddTranslator("legacy", legacy)
+	l.SetFallbackDomains("legacy")
+
+	// Each domain is serialized with its own catalog only
+	data, err := l.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := new(Locale)
+	if err = restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct{ expected, got string }{
+		{"Rechnung", restored.GetD("billing", "Invoice")},
+		{"Total", restored.GetD("billing", "Total")},
+		{"Invoice", restored.GetD("legacy", "Invoice")},
+		{"Gesamt", restored.GetD("legacy", "Total")},
+	} {
+		if test.expected != test.got {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.got)
+		}
+	}
+
+	restored.SetFallbackDomains("legacy")
+	if tr := restored.GetD("billing", "Total"); tr != "Gesamt" {
+		t.Errorf("Expected '%s' but got '%s'", "Gesamt", tr)
+	}
+}
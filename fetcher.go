@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// Fetcher downloads catalog files from a storage location, such as "s3://bucket/de/default.po".
+// It decouples the package from storage SDKs: implementations wrap the client of choice (AWS SDK, Google Cloud Storage...).
+type Fetcher interface {
+	Fetch(ctx context.Context, location *url.URL) ([]byte, error)
+}
+
+// FetcherFunc adapts a function to the Fetcher interface.
+type FetcherFunc func(ctx context.Context, location *url.URL) ([]byte, error)
+
+// Fetch calls f(ctx, location).
+func (f FetcherFunc) Fetch(ctx context.Context, location *url.URL) ([]byte, error) {
+	return f(ctx, location)
+}
+
+var (
+	fetchers = map[string]Fetcher{
+		"http":  FetcherFunc(fetchHTTP),
+		"https": FetcherFunc(fetchHTTP),
+	}
+	fetchersMutex sync.RWMutex
+)
+
+// fetchHTTP downloads location with HTTPClient. It's the default Fetcher for "http" and "https" locations.
+func fetchHTTP(ctx context.Context, location *url.URL) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, location.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := HTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", location, resp.Status)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// fetch downloads location with the Fetcher registered for its scheme.
+func fetch(ctx context.Context, location *url.URL) ([]byte, error) {
+	fetchersMutex.RLock()
+	f := fetchers[location.Scheme]
+	fetchersMutex.RUnlock()
+	if f == nil {
+		return nil, fmt.Errorf("no fetcher registered for scheme %q", location.Scheme)
+	}
+
+	return f.Fetch(ctx, location)
+}
+
+/*
+RegisterFetcher makes a Fetcher available for locations with the given URL scheme (i.e. "s3" or "gs") in Locale.AddDomainFrom.
+"http" and "https" locations are fetched with HTTPClient by default. Registering a nil Fetcher removes it.
+
+Example using the AWS SDK:
+
+	gotext.RegisterFetcher("s3", gotext.FetcherFunc(func(ctx context.Context, u *url.URL) ([]byte, error) {
+		out, err := client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(u.Host),
+			Key:    aws.String(strings.TrimPrefix(u.Path, "/")),
+		})
+		if err != nil {
+			return nil, err
+		}
+		defer out.Body.Close()
+		return io.ReadAll(out.Body)
+	}))
+*/
+func RegisterFetcher(scheme string, f Fetcher) {
+	fetchersMutex.Lock()
+	defer fetchersMutex.Unlock()
+
+	if f == nil {
+		delete(fetchers, scheme)
+		return
+	}
+	fetchers[scheme] = f
+}
+
+// AddDomainFrom downloads a .po or .mo catalog with the Fetcher registered for the location scheme,
+// and adds it as a domain named after the file, so "s3://bucket/tenants/acme/de/default.po" is added as the "default" domain.
+// It allows serverless deployments to pull per-tenant catalogs from a bucket at cold start.
+func (l *Locale) AddDomainFrom(ctx context.Context, location string) error {
+	u, err := url.Parse(location)
+	if err != nil {
+		return err
+	}
+
+	dom, file := domainFromPath(u.Path)
+	if dom == "" {
+		return fmt.Errorf("no catalog file in location %s", location)
+	}
+
+	data, err := fetch(ctx, u)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return errors.New("empty catalog fetched from " + location)
+	}
+
+	l.addCatalogData(dom, file, data)
+
+	return nil
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"testing"
+)
+
+func TestLocaleAddDomainFrom(t *testing.T) {
+	bucket := map[string]string{
+		"tenants/acme/en_US/default.po": "fixtures/en_US/default.po",
+		"tenants/acme/en_US/default.mo": "fixtures/en_US/default.mo",
+	}
+
+	RegisterFetcher("mem", FetcherFunc(func(ctx context.Context, u *url.URL) ([]byte, error) {
+		if u.Host != "bucket" {
+			return nil, errors.New("no such bucket")
+		}
+		file, ok := bucket[u.Path[1:]]
+		if !ok {
+			return nil, errors.New("no such key")
+		}
+		return ioutil.ReadFile(file)
+	}))
+	defer RegisterFetcher("mem", nil)
+
+	l := NewLocale("", "en_US")
+	if err := l.AddDomainFrom(context.Background(), "mem://bucket/tenants/acme/en_US/default.po"); err != nil {
+		t.Fatal(err)
+	}
+	if tr := l.Get("My text"); tr != translatedText {
+		t.Errorf("Expected '%s' but got '%s'", translatedText, tr)
+	}
+
+	m := NewLocale("", "en_US")
+	if err := m.AddDomainFrom(context.Background(), "mem://bucket/tenants/acme/en_US/default.mo"); err != nil {
+		t.Fatal(err)
+	}
+	if tr := m.Get("My text"); tr != translatedText {
+		t.Errorf("Expected '%s' but got '%s'", translatedText, tr)
+	}
+
+	for _, location := range []string{
+		"mem://bucket/tenants/other/en_US/default.po",
+		"mem://other/tenants/acme/en_US/default.po",
+		"unknown://bucket/tenants/acme/en_US/default.po",
+		"mem://bucket/",
+	} {
+		if err := l.AddDomainFrom(context.Background(), location); err == nil {
+			t.Errorf("Expected error loading %s", location)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+/*
+Package fiberlocale provides a Fiber middleware resolving the gotext Locale of each request.
+
+It's a separate module, so the gotext package doesn't depend on Fiber.
+
+Example:
+
+	l := gotext.NewLocale("/path/to/i18n/dir", "en_US")
+	l.AddDomain("default")
+
+	app := fiber.New()
+	app.Use(fiberlocale.Middleware(l))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(fiberlocale.Locale(c).Get("Hello"))
+	})
+*/
+package fiberlocale
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/leonelquinteros/gotext"
+)
+
+// Key is the key storing the Locale in the Fiber context, so it's available as c.Locals("locale").
+const Key = "locale"
+
+// Middleware returns a Fiber middleware storing in the context locals the Locale matching the Accept-Language header.
+// Locales for other languages than l are created with gotext.Locale.Match.
+// It's also injected in the user context, to be retrieved with gotext.FromContext.
+func Middleware(l *gotext.Locale) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		loc := l.Match(gotext.ParseAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))...)
+		c.Locals(Key, loc)
+		c.SetUserContext(gotext.NewContext(c.UserContext(), loc))
+
+		return c.Next()
+	}
+}
+
+// Locale returns the Locale stored in the Fiber context by Middleware, or nil if there's none.
+func Locale(c *fiber.Ctx) *gotext.Locale {
+	l, _ := c.Locals(Key).(*gotext.Locale)
+	return l
+}
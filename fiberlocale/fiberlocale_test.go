@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package fiberlocale
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/leonelquinteros/gotext"
+)
+
+func TestMiddleware(t *testing.T) {
+	l := gotext.NewLocale("../fixtures/", "en_US")
+	l.AddDomain("default")
+
+	app := fiber.New()
+	app.Use(Middleware(l))
+	app.Get("/", func(c *fiber.Ctx) error {
+		if c.Locals("locale") != Locale(c) || gotext.FromContext(c.UserContext()) != Locale(c) {
+			t.Error("Expected the same Locale in every context")
+		}
+		return c.SendString(Locale(c).Get("language"))
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "en-AU, en;q=0.9")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	if got := string(body); got != "en_AU" {
+		t.Errorf("Expected 'en_AU' but got '%s'", got)
+	}
+}
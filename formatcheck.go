@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+// FormatMismatch is a string formatted with arguments not matching its verbs, which fmt would print with
+// %!s(MISSING) or %!(EXTRA ...) markers.
+type FormatMismatch struct {
+	// Language and domain of the string, set for the checks of a Locale.
+	Lang, Domain string
+
+	// Context (empty for none) and msgid of the lookup.
+	Context, ID string
+
+	// Format is the selected string, the translation or the msgid when untranslated.
+	Format string
+
+	// Amount of arguments expected by the verbs of Format, and the amount passed.
+	Expected, Args int
+}
+
+// FormatCheckFunc is called with the lookups formatting strings with mismatching arguments.
+type FormatCheckFunc func(m FormatMismatch)
+
+// expectedArgs returns the amount of arguments used by the verbs of a format, reporting if any has an explicit index.
+func expectedArgs(format string) (n int, indexed bool) {
+	for _, a := range formatArgs(format) {
+		if a.arg > n {
+			n = a.arg
+		}
+		indexed = indexed || a.indexed
+	}
+
+	return n, indexed
+}
+
+// printf formats the string selected for a lookup, checking its arguments first when enabled.
+func (c *catalog) printf(ctx, id, format string, vars []interface{}) string {
+	if c.formatCheck != nil && len(vars) > 0 {
+		// fmt doesn't report extra arguments of formats with explicit indexes
+		n, indexed := expectedArgs(format)
+		if n > len(vars) || n < len(vars) && !indexed {
+			c.formatCheck(FormatMismatch{Context: ctx, ID: id, Format: format, Expected: n, Args: len(vars)})
+		}
+	}
+
+	return Printf(format, vars...)
+}
+
+/*
+SetFormatCheck enables a debug mode calling f when a lookup formats its translation, or msgid when untranslated,
+with arguments not matching its verbs, instead of silently printing %!s(MISSING) markers. Lookups without
+arguments aren't checked, as their strings aren't formatted. A nil FormatCheckFunc disables the checks.
+
+Example:
+
+	po.GetDomain().SetFormatCheck(func(m gotext.FormatMismatch) {
+		log.Printf("%q expects %d arguments, got %d", m.Format, m.Expected, m.Args)
+	})
+*/
+func (do *Domain) SetFormatCheck(f FormatCheckFunc) {
+	do.trMutex.Lock()
+	do.formatCheck = f
+	do.publish()
+	do.trMutex.Unlock()
+}
+
+// SetFormatCheck enables the format checks of the domains loaded, and the ones loaded afterwards, including the ones
+// added with AddTranslator, AddDomainURL or an Updater, as Domain.SetFormatCheck, with the language and domain set in
+// the FormatMismatch. Strings resolved through
+// layers stacked on a domain, i.e. with SetOverride, aren't checked.
+func (l *Locale) SetFormatCheck(f FormatCheckFunc) {
+	l.Lock()
+	l.formatCheck = f
+	for dom, tr := range l.Domains {
+		if do := tr.GetDomain(); do != nil {
+			do.SetFormatCheck(l.domainFormatCheck(dom))
+		}
+	}
+	l.Unlock()
+}
+
+// domainFormatCheck returns the FormatCheckFunc of a domain, reporting its language and name.
+// It must be called holding the lock.
+func (l *Locale) domainFormatCheck(dom string) FormatCheckFunc {
+	f, lang := l.formatCheck, l.lang
+	if f == nil {
+		return nil
+	}
+
+	return func(m FormatMismatch) {
+		m.Lang, m.Domain = lang, dom
+		f(m)
+	}
+}
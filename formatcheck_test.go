@@ -0,0 +1,144 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+const formatCheckPo = `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Hello %s"
+msgstr "Hallo %s, willkommen %s"
+
+msgid "%s of %s"
+msgstr "%[2]s"
+
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "Eine Datei"
+msgstr[1] "%d Dateien"
+`
+
+func TestFormatCheck(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(formatCheckPo))
+
+	var got []FormatMismatch
+	po.GetDomain().SetFormatCheck(func(m FormatMismatch) {
+		got = append(got, m)
+	})
+
+	po.Get("Hello %s", "Ana")
+	po.Get("%s of %s", "a", "b")
+	po.GetN("One file", "%d files", 2, 2)
+	po.GetN("One file", "%d files", 1, 1)
+	po.GetC("Missing %s %d", "ctx", "x")
+	po.Get("No arguments %s")
+
+	expected := []FormatMismatch{
+		{ID: "Hello %s", Format: "Hallo %s, willkommen %s", Expected: 2, Args: 1},
+		{ID: "One file", Format: "Eine Datei", Expected: 0, Args: 1},
+		{Context: "ctx", ID: "Missing %s %d", Format: "Missing %s %d", Expected: 2, Args: 1},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v but got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Expected %v but got %v", expected[i], got[i])
+		}
+	}
+
+	got = nil
+	po.GetDomain().SetFormatCheck(nil)
+	po.Get("Hello %s", "Ana")
+	if len(got) != 0 {
+		t.Errorf("Unexpected mismatches %v", got)
+	}
+}
+
+func TestLocaleFormatCheck(t *testing.T) {
+	dirname := path.Join("/tmp", "formatcheck")
+	if err := os.MkdirAll(path.Join(dirname, "de", "LC_MESSAGES"), os.ModePerm); err != nil {
+		t.Fatalf("Can't create test directory: %s", err.Error())
+	}
+	defer os.RemoveAll(dirname)
+	err := ioutil.WriteFile(path.Join(dirname, "de", "LC_MESSAGES", "default.po"), []byte(formatCheckPo), os.ModePerm)
+	if err != nil {
+		t.Fatalf("Can't write test file: %s", err.Error())
+	}
+
+	var got []FormatMismatch
+	l := NewLocale(dirname, "de")
+	l.SetFormatCheck(func(m FormatMismatch) {
+		got = append(got, m)
+	})
+	l.AddDomain("default")
+
+	l.Get("Hello %s", "Ana")
+	if len(got) != 1 || got[0].Lang != "de" || got[0].Domain != "default" || got[0].Expected != 2 {
+		t.Errorf("Unexpected mismatches %v", got)
+	}
+}
+
+func TestLocaleFormatCheckAdded(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(formatCheckPo))
+	}))
+	defer srv.Close()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle := makeBundle(t, map[string]string{"de/updated.po": formatCheckPo})
+	RegisterFetcher("ota", FetcherFunc(func(ctx context.Context, u *url.URL) ([]byte, error) {
+		if u.Path == "/de.tar.gz.sig" {
+			return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, bundle))), nil
+		}
+		return bundle, nil
+	}))
+	defer RegisterFetcher("ota", nil)
+
+	var got []string
+	l := NewLocale("", "de")
+	l.SetFormatCheck(func(m FormatMismatch) {
+		got = append(got, m.Domain)
+	})
+
+	// Remote domains, translators added afterwards and updates are checked too
+	if err := l.AddDomainURL(srv.URL + "/de/remote.po"); err != nil {
+		t.Fatal(err)
+	}
+	po := NewPo()
+	po.Parse([]byte(formatCheckPo))
+	l.AddTranslator("added", po)
+	if _, err := NewUpdater(l, "ota://cdn/de.tar.gz", pub).Update(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dom := range []string{"remote", "added", "updated"} {
+		l.GetD(dom, "Hello %s", "Ana")
+	}
+	if !reflect.DeepEqual(got, []string{"remote", "added", "updated"}) {
+		t.Errorf("Expected mismatches of every domain but got %v", got)
+	}
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+/*
+Package ginlocale provides a Gin middleware resolving the gotext Locale of each request.
+
+It's a separate module, so the gotext package doesn't depend on Gin.
+
+Example:
+
+	l := gotext.NewLocale("/path/to/i18n/dir", "en_US")
+	l.AddDomain("default")
+
+	r := gin.New()
+	r.Use(ginlocale.Middleware(l))
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, ginlocale.Locale(c).Get("Hello"))
+	})
+*/
+package ginlocale
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/leonelquinteros/gotext"
+)
+
+// Key is the key storing the Locale in the Gin context, so it's available as c.Get("locale").
+const Key = "locale"
+
+// Middleware returns a Gin middleware storing in the context the Locale returned by gotext.RequestLocale,
+// resolved with the given Resolvers (the Accept-Language header by default).
+// It's also injected in the request context, to be retrieved with gotext.FromContext.
+func Middleware(l *gotext.Locale, resolvers ...gotext.Resolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		loc := gotext.RequestLocale(l, c.Request, resolvers...)
+		c.Set(Key, loc)
+		c.Request = c.Request.WithContext(gotext.NewContext(c.Request.Context(), loc))
+		c.Next()
+	}
+}
+
+// Locale returns the Locale stored in the Gin context by Middleware, or nil if there's none.
+func Locale(c *gin.Context) *gotext.Locale {
+	v, _ := c.Get(Key)
+	l, _ := v.(*gotext.Locale)
+	return l
+}
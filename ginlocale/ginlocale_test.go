@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package ginlocale
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leonelquinteros/gotext"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	l := gotext.NewLocale("../fixtures/", "en_US")
+	l.AddDomain("default")
+
+	r := gin.New()
+	r.Use(Middleware(l))
+	r.GET("/", func(c *gin.Context) {
+		v, _ := c.Get("locale")
+		if v != Locale(c) || gotext.FromContext(c.Request.Context()) != Locale(c) {
+			t.Error("Expected the same Locale in every context")
+		}
+		c.String(http.StatusOK, Locale(c).Get("language"))
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "fr-CH, en;q=0.8")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if got := w.Body.String(); got != "fr" {
+		t.Errorf("Expected 'fr' but got '%s'", got)
+	}
+}
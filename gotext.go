@@ -42,6 +42,9 @@ type config struct {
 
 	// Storage for package level methods
 	storage *Locale
+
+	// Incremented when the storage is reloaded, so pending background language switches don't replace newer storage.
+	generation uint64
 }
 
 var globalConfig *config
@@ -66,6 +69,7 @@ func loadStorage(force bool) {
 
 	if globalConfig.storage == nil || force {
 		globalConfig.storage = NewLocale(globalConfig.library, globalConfig.language)
+		globalConfig.generation++
 	}
 
 	if _, ok := globalConfig.storage.Domains[globalConfig.domain]; !ok || force {
@@ -104,6 +108,17 @@ func SetDomain(dom string) {
 	loadStorage(true)
 }
 
+// GetDomains returns the sorted list of names of the domains loaded at package level.
+func GetDomains() []string {
+	loadStorage(false)
+
+	globalConfig.RLock()
+	doms := globalConfig.storage.GetDomains()
+	globalConfig.RUnlock()
+
+	return doms
+}
+
 // GetLanguage is the language getter for the package configuration
 func GetLanguage() string {
 	globalConfig.RLock()
@@ -123,6 +138,62 @@ func SetLanguage(lang string) {
 	loadStorage(true)
 }
 
+/*
+SetLanguageAtomic sets the language code to be used at package level like SetLanguage, but loads the catalogs of the
+domains loaded at package level in the background, and swaps them all at once when they're ready: lookups keep using
+the previous language until then, so users switching languages don't see a mix of old and new strings or a pause.
+The returned channel receives the loading error, listing the domains without catalog in the new language, once the
+switch is done. A switch superseded by a later one or by another change of the package configuration is dropped.
+
+Example:
+
+	func onLanguageSelected(lang string) {
+		go func() {
+			if err := <-gotext.SetLanguageAtomic(lang); err != nil {
+				log.Print(err)
+			}
+			redraw()
+		}()
+	}
+*/
+func SetLanguageAtomic(lang string) <-chan error {
+	lang = SimplifiedLocale(lang)
+	done := make(chan error, 1)
+
+	globalConfig.Lock()
+	globalConfig.generation++
+	generation := globalConfig.generation
+	lib, dom := globalConfig.library, globalConfig.domain
+	doms := []string{dom}
+	if globalConfig.storage != nil {
+		for _, d := range globalConfig.storage.GetDomains() {
+			if d != dom {
+				doms = append(doms, d)
+			}
+		}
+	}
+	globalConfig.Unlock()
+
+	go func() {
+		defer close(done)
+
+		l := NewLocale(lib, lang)
+		err := l.AddDomains(0, doms...)
+		l.SetDomain(dom)
+
+		globalConfig.Lock()
+		defer globalConfig.Unlock()
+		if globalConfig.generation != generation {
+			return
+		}
+		globalConfig.language = lang
+		globalConfig.storage = l
+		done <- err
+	}()
+
+	return done
+}
+
 // GetLibrary is the library getter for the package configuration
 func GetLibrary() string {
 	globalConfig.RLock()
@@ -508,3 +508,45 @@ func TestPackageArabicMissingPluralForm(t *testing.T) {
 		t.Errorf("Expected to get 'الكحول والتبغ', but got '%s'", tr)
 	}
 }
+
+func TestPackageGetDomains(t *testing.T) {
+	Configure("fixtures/", "ar", "categories")
+	GetD("no_plural_header", "Alcohol & Tobacco")
+
+	doms := GetDomains()
+	if len(doms) != 2 || doms[0] != "categories" || doms[1] != "no_plural_header" {
+		t.Errorf("Expected [categories no_plural_header] but got %v", doms)
+	}
+}
+
+func TestSetLanguageAtomic(t *testing.T) {
+	Configure("fixtures/", "ar", "categories")
+	GetD("no_plural_header", "Alcohol & Tobacco")
+
+	err := <-SetLanguageAtomic("de")
+	if err == nil || err.Error() != "no catalog found for domains: categories, no_plural_header" {
+		t.Errorf("Expected the missing domains error but got %v", err)
+	}
+	if lang := GetLanguage(); lang != "de" {
+		t.Errorf("Expected '%s' but got '%s'", "de", lang)
+	}
+	if tr := GetD("no_plural_header", "Alcohol & Tobacco"); tr != "Alcohol & Tobacco" {
+		t.Errorf("Expected '%s' but got '%s'", "Alcohol & Tobacco", tr)
+	}
+
+	// The last switch wins
+	first := SetLanguageAtomic("de")
+	if err = <-SetLanguageAtomic("ar"); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	<-first
+	if lang := GetLanguage(); lang != "ar" {
+		t.Errorf("Expected '%s' but got '%s'", "ar", lang)
+	}
+	if tr := GetD("no_plural_header", "Alcohol & Tobacco"); tr != "الكحول والتبغ" {
+		t.Errorf("Expected 'الكحول والتبغ' but got '%s'", tr)
+	}
+	if dom := GetDomain(); dom != "categories" {
+		t.Errorf("Expected '%s' but got '%s'", "categories", dom)
+	}
+}
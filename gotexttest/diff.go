@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotexttest
+
+import (
+	"path/filepath"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// VolatileHeaders are the headers ignored when diffing catalogs, as they change on every generation.
+var VolatileHeaders = append([]string(nil), gotext.VolatileHeaders...)
+
+// EntryChange is an entry changed between two catalogs, see gotext.EntryChange.
+type EntryChange = gotext.EntryChange
+
+// HeaderChange is a header changed between two catalogs, see gotext.HeaderChange.
+type HeaderChange = gotext.HeaderChange
+
+// CatalogDiff is the structural difference between two catalogs, see gotext.CatalogDiff.
+type CatalogDiff = gotext.CatalogDiff
+
+// Diff returns the structural difference between the old and new catalogs,
+// ignoring the order of entries and the VolatileHeaders.
+func Diff(oldTr, newTr gotext.Translator) (CatalogDiff, error) {
+	return gotext.DiffCatalogs(oldTr, newTr, gotext.DiffOptions{IgnoreHeaders: VolatileHeaders})
+}
+
+// loadCatalog parses a .po or .mo file.
+func loadCatalog(file string) gotext.Translator {
+	var tr gotext.Translator = gotext.NewPo()
+	if filepath.Ext(file) == ".mo" {
+		tr = gotext.NewMo()
+	}
+	tr.ParseFile(file)
+
+	return tr
+}
+
+// AssertCatalogEqual fails the test printing the difference when the catalogs aren't equivalent.
+// It reports if they are.
+func AssertCatalogEqual(t TestingT, want, got gotext.Translator) bool {
+	t.Helper()
+
+	diff, err := Diff(want, got)
+	if err != nil {
+		t.Errorf("%v", err)
+		return false
+	}
+	if !diff.Empty() {
+		t.Errorf("Catalogs differ:\n%s", diff)
+		return false
+	}
+
+	return true
+}
+
+// AssertGolden fails the test when the catalog file got isn't equivalent to the golden one,
+// i.e. to guard a generated .pot file against accidental edits of source strings.
+//
+//	func TestTemplate(t *testing.T) {
+//		// Generate locales/default.pot
+//		gotexttest.AssertGolden(t, "testdata/default.pot", "locales/default.pot")
+//	}
+func AssertGolden(t TestingT, golden, got string) bool {
+	t.Helper()
+
+	return AssertCatalogEqual(t, loadCatalog(golden), loadCatalog(got))
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotexttest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+const goldenPot = `msgid ""
+msgstr ""
+"Project-Id-Version: app\n"
+"POT-Creation-Date: 2020-01-01 10:00+0000\n"
+"X-Generator: xgotext\n"
+
+msgid "Hello"
+msgstr ""
+
+msgid "Bye"
+msgstr ""
+
+msgctxt "menu"
+msgid "File"
+msgid_plural "Files"
+msgstr[0] ""
+msgstr[1] ""
+`
+
+func parsePo(s string) gotext.Translator {
+	po := gotext.NewPo()
+	po.Parse([]byte(s))
+	return po
+}
+
+func TestDiff(t *testing.T) {
+	got := parsePo(`msgid ""
+msgstr ""
+"Project-Id-Version: app 2\n"
+"POT-Creation-Date: 2021-06-01 12:00+0000\n"
+
+msgctxt "menu"
+msgid "File"
+msgid_plural "All files"
+msgstr[0] ""
+msgstr[1] ""
+
+msgid "Hello"
+msgstr ""
+
+msgid "Welcome"
+msgstr ""
+`)
+
+	diff, err := Diff(parsePo(goldenPot), got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `~ header Project-Id-Version: "app" => "app 2"
+- "Bye"
++ "Welcome"
+~ "File" (context "menu"): msgid_plural "Files", msgstr[0] "", msgstr[1] "" => msgid_plural "All files", msgstr[0] "", msgstr[1] ""
+`
+	if diff.String() != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, diff)
+	}
+	if diff.Empty() {
+		t.Error("Expected a non empty diff")
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gotexttest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	golden := filepath.Join(dir, "golden.pot")
+	regenerated := filepath.Join(dir, "default.pot")
+	ioutil.WriteFile(golden, []byte(goldenPot), 0644)
+
+	// Only volatile headers and the entries order changed.
+	ioutil.WriteFile(regenerated, []byte(`msgid ""
+msgstr ""
+"Project-Id-Version: app\n"
+"POT-Creation-Date: 2022-02-02 22:00+0000\n"
+
+msgctxt "menu"
+msgid "File"
+msgid_plural "Files"
+msgstr[0] ""
+msgstr[1] ""
+
+msgid "Bye"
+msgstr ""
+
+msgid "Hello"
+msgstr ""
+`), 0644)
+	AssertGolden(t, golden, regenerated)
+
+	r := new(recorder)
+	if AssertCatalogEqual(r, parsePo(goldenPot), parsePo(`msgid "Hello"
+msgstr ""
+`)) {
+		t.Error("Expected the catalogs to differ")
+	}
+	if len(r.errors) != 1 {
+		t.Errorf("Expected 1 error but got %q", r.errors)
+	}
+}
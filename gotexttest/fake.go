@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotexttest
+
+import (
+	"sync"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// FakeFormat is the default format of the strings returned by a Fake, the msgid replacing the %s verb.
+const FakeFormat = "[[%s]]"
+
+// Call is a lookup recorded by a Fake.
+type Call struct {
+	// Method is the name of the called Translator method: Get, GetN, GetC or GetNC.
+	Method  string
+	Msgid   string
+	Plural  string
+	N       int
+	Context string
+	Vars    []interface{}
+}
+
+/*
+Fake is an in-memory gotext.Translator for unit tests, so applications don't need catalog files on disk.
+By default it translates every string to its msgid wrapped as "[[msgid]]", making untranslated strings stand out
+in assertions. Explicit translations can be set, misses simulated, and the lookups made are recorded.
+
+Example:
+
+	fake := gotexttest.NewFake().Set("Hello %s", "Bonjour %s").SetMissing("Bye")
+
+	l := gotext.NewLocale("", "fr")
+	l.AddTranslator("default", fake)
+
+	l.Get("Hello %s", "Eve") // "Bonjour Eve"
+	l.Get("Bye")             // "Bye"
+	l.Get("Welcome")         // "[[Welcome]]"
+*/
+type Fake struct {
+	// Format of the strings returned for msgids without explicit translation, see FakeFormat.
+	Format string
+
+	// MissAll simulates misses for all strings without explicit translation.
+	MissAll bool
+
+	mu           sync.Mutex
+	translations map[string]string
+	missing      map[string]bool
+	calls        []Call
+}
+
+// NewFake returns a Fake using FakeFormat.
+func NewFake() *Fake {
+	return &Fake{
+		Format:       FakeFormat,
+		translations: make(map[string]string),
+		missing:      make(map[string]bool),
+	}
+}
+
+// fakeKey returns the key of a msgid in the given context.
+func fakeKey(ctx, id string) string {
+	if ctx == "" {
+		return id
+	}
+
+	return ctx + "\x04" + id
+}
+
+// Set sets the translation of msgid. Plural msgids are translated with their own entries.
+func (f *Fake) Set(msgid, translation string) *Fake {
+	return f.SetC(msgid, "", translation)
+}
+
+// SetC sets the translation of msgid in the given context.
+func (f *Fake) SetC(msgid, ctx, translation string) *Fake {
+	f.mu.Lock()
+	f.translations[fakeKey(ctx, msgid)] = translation
+	f.mu.Unlock()
+
+	return f
+}
+
+// SetMissing simulates misses for the given msgids, returned untranslated.
+func (f *Fake) SetMissing(msgids ...string) *Fake {
+	f.mu.Lock()
+	for _, id := range msgids {
+		f.missing[id] = true
+	}
+	f.mu.Unlock()
+
+	return f
+}
+
+// Calls returns the lookups made so far, in order.
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Call(nil), f.calls...)
+}
+
+// Called reports if msgid has been looked up.
+func (f *Fake) Called(msgid string) bool {
+	for _, c := range f.Calls() {
+		if c.Msgid == msgid {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Reset forgets the recorded lookups.
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	f.calls = nil
+	f.mu.Unlock()
+}
+
+// translate records the call and returns its translation, not formatted yet.
+func (f *Fake) translate(c Call) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, c)
+
+	str := c.Msgid
+	if c.Plural != "" && c.N != 1 {
+		str = c.Plural
+	}
+
+	if tr, ok := f.translations[fakeKey(c.Context, str)]; ok {
+		return tr
+	}
+	if f.MissAll || f.missing[c.Msgid] {
+		return str
+	}
+
+	return gotext.Printf(f.Format, str)
+}
+
+// Get implements the gotext.Translator interface.
+func (f *Fake) Get(str string, vars ...interface{}) string {
+	return gotext.Printf(f.translate(Call{Method: "Get", Msgid: str, Vars: vars}), vars...)
+}
+
+// GetN implements the gotext.Translator interface.
+func (f *Fake) GetN(str, plural string, n int, vars ...interface{}) string {
+	return gotext.Printf(f.translate(Call{Method: "GetN", Msgid: str, Plural: plural, N: n, Vars: vars}), vars...)
+}
+
+// GetC implements the gotext.Translator interface.
+func (f *Fake) GetC(str, ctx string, vars ...interface{}) string {
+	return gotext.Printf(f.translate(Call{Method: "GetC", Msgid: str, Context: ctx, Vars: vars}), vars...)
+}
+
+// GetNC implements the gotext.Translator interface.
+func (f *Fake) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	return gotext.Printf(f.translate(Call{Method: "GetNC", Msgid: str, Plural: plural, N: n, Context: ctx, Vars: vars}), vars...)
+}
+
+// ParseFile implements the gotext.Translator interface, fakes don't load catalogs.
+func (f *Fake) ParseFile(file string) {}
+
+// Parse implements the gotext.Translator interface, fakes don't load catalogs.
+func (f *Fake) Parse(buf []byte) {}
+
+// GetDomain implements the gotext.Translator interface, returning an empty Domain.
+func (f *Fake) GetDomain() *gotext.Domain {
+	return gotext.NewDomain()
+}
+
+// MarshalBinary implements the gotext.Translator interface, encoding an empty catalog.
+func (f *Fake) MarshalBinary() ([]byte, error) {
+	return gotext.NewDomain().MarshalBinary()
+}
+
+// UnmarshalBinary implements the gotext.Translator interface, fakes can't be restored.
+func (f *Fake) UnmarshalBinary(data []byte) error {
+	return nil
+}
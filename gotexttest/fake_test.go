@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotexttest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+func TestFake(t *testing.T) {
+	fake := NewFake().Set("Hello %s", "Bonjour %s").SetC("Open", "menu", "Ouvrir").SetMissing("Bye")
+
+	var _ gotext.Translator = fake
+
+	l := gotext.NewLocale("", "fr")
+	l.AddTranslator("default", fake)
+
+	for _, test := range []struct {
+		got, expected string
+	}{
+		{l.Get("Hello %s", "Eve"), "Bonjour Eve"},
+		{l.Get("Bye"), "Bye"},
+		{l.Get("Welcome %s", "Eve"), "[[Welcome Eve]]"},
+		{l.GetC("Open", "menu"), "Ouvrir"},
+		{l.GetN("%d file", "%d files", 3, 3), "[[3 files]]"},
+		{l.GetNC("%d file", "%d files", 1, "disk", 1), "[[1 file]]"},
+	} {
+		if test.got != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.got)
+		}
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 6 {
+		t.Fatalf("Expected 6 calls but got %d", len(calls))
+	}
+	expected := Call{Method: "GetNC", Msgid: "%d file", Plural: "%d files", N: 1, Context: "disk", Vars: []interface{}{1}}
+	if !reflect.DeepEqual(calls[5], expected) {
+		t.Errorf("Expected %v but got %v", expected, calls[5])
+	}
+	if !fake.Called("Bye") || fake.Called("Unknown") {
+		t.Error("Unexpected Called results")
+	}
+
+	fake.Reset()
+	fake.MissAll = true
+	if got := l.Get("Welcome"); got != "Welcome" {
+		t.Errorf("Expected 'Welcome' but got '%s'", got)
+	}
+	if len(fake.Calls()) != 1 {
+		t.Errorf("Expected 1 call but got %d", len(fake.Calls()))
+	}
+
+	if _, err := l.MarshalBinary(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
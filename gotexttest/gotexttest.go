@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+// Package gotexttest provides helpers to test applications using gotext,
+// so missing translations break tests instead of reaching production.
+package gotexttest
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// TestingT is the subset of testing.TB used by the helpers.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Entry is a catalog entry reported by the helpers.
+type Entry struct {
+	Language string
+	Domain   string
+	Context  string
+	Msgid    string
+
+	// Fuzzy is set for entries translated but flagged as fuzzy, unset for untranslated ones.
+	Fuzzy bool
+}
+
+// String returns a description of the entry.
+func (e Entry) String() string {
+	s := fmt.Sprintf("%q", e.Msgid)
+	if e.Context != "" {
+		s += fmt.Sprintf(" (context %q)", e.Context)
+	}
+	if e.Fuzzy {
+		return "fuzzy: " + s
+	}
+
+	return "untranslated: " + s
+}
+
+// encoding returns the catalog of a Translator with all of its entries.
+func encoding(tr gotext.Translator) (*gotext.TranslatorEncoding, error) {
+	data, err := tr.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	enc := new(gotext.TranslatorEncoding)
+	if err = gob.NewDecoder(bytes.NewReader(data)).Decode(enc); err != nil {
+		return nil, err
+	}
+
+	return enc, nil
+}
+
+// incomplete reports if a translation misses any of its nplurals forms.
+func incomplete(tr *gotext.Translation, nplurals int) bool {
+	if tr.PluralID == "" {
+		return tr.Trs[0] == ""
+	}
+	for i := 0; i < nplurals; i++ {
+		if tr.Trs[i] == "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Incomplete returns the untranslated and fuzzy entries of the given domains of a Locale, all of them if none is given.
+// Entries are sorted by domain, context and msgid.
+func Incomplete(l *gotext.Locale, domains ...string) ([]Entry, error) {
+	if len(domains) == 0 {
+		domains = l.GetDomains()
+	}
+
+	var entries []Entry
+	for _, dom := range domains {
+		tr, ok := l.Domains[dom]
+		if !ok {
+			return nil, fmt.Errorf("domain %q isn't loaded for language %q", dom, l.GetLanguage())
+		}
+		enc, err := encoding(tr)
+		if err != nil {
+			return nil, fmt.Errorf("reading domain %q for language %q: %v", dom, l.GetLanguage(), err)
+		}
+
+		nplurals := enc.Nplurals
+		if nplurals == 0 {
+			nplurals = 2
+		}
+
+		check := func(ctx string, trs map[string]*gotext.Translation) {
+			for id, tr := range trs {
+				// Skip the header entry
+				if id == "" && ctx == "" {
+					continue
+				}
+				missing := incomplete(tr, nplurals)
+				if missing || tr.Fuzzy {
+					entries = append(entries, Entry{Language: l.GetLanguage(), Domain: dom, Context: ctx, Msgid: id, Fuzzy: !missing})
+				}
+			}
+		}
+		check("", enc.Translations)
+		for ctx, trs := range enc.Contexts {
+			check(ctx, trs)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Domain != b.Domain {
+			return a.Domain < b.Domain
+		}
+		if a.Context != b.Context {
+			return a.Context < b.Context
+		}
+		return a.Msgid < b.Msgid
+	})
+
+	return entries, nil
+}
+
+// AssertComplete fails the test listing every untranslated or fuzzy entry of the given domains of a Locale,
+// all of them if none is given. It reports if the catalogs are complete.
+//
+//	func TestTranslations(t *testing.T) {
+//		for _, lang := range []string{"de", "fr"} {
+//			l := gotext.NewLocale("locales", lang)
+//			l.AddDomain("default")
+//			gotexttest.AssertComplete(t, l)
+//		}
+//	}
+func AssertComplete(t TestingT, l *gotext.Locale, domains ...string) bool {
+	t.Helper()
+
+	entries, err := Incomplete(l, domains...)
+	if err != nil {
+		t.Errorf("%v", err)
+		return false
+	}
+	if len(entries) == 0 {
+		return true
+	}
+
+	byDomain := make(map[string][]string)
+	var doms []string
+	for _, e := range entries {
+		if _, ok := byDomain[e.Domain]; !ok {
+			doms = append(doms, e.Domain)
+		}
+		byDomain[e.Domain] = append(byDomain[e.Domain], e.String())
+	}
+	for _, dom := range doms {
+		t.Errorf("Language %q, domain %q has %d incomplete entries:\n\t%s",
+			l.GetLanguage(), dom, len(byDomain[dom]), strings.Join(byDomain[dom], "\n\t"))
+	}
+
+	return false
+}
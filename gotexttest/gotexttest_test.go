@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotexttest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// recorder is a TestingT recording the reported errors.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func newLocale(catalog string) *gotext.Locale {
+	po := gotext.NewPo()
+	po.Parse([]byte(catalog))
+
+	l := gotext.NewLocale("", "fr")
+	l.AddTranslator("default", po)
+	return l
+}
+
+func TestAssertComplete(t *testing.T) {
+	l := newLocale(`
+#, fuzzy
+msgid ""
+msgstr ""
+"Language: fr\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+msgid "Translated"
+msgstr "Traduit"
+
+msgid "Missing"
+msgstr ""
+
+#, fuzzy
+msgctxt "menu"
+msgid "Open"
+msgstr "Ouvrir"
+
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "Un fichier"
+msgstr[1] ""
+`)
+
+	expected := []Entry{
+		{Language: "fr", Domain: "default", Msgid: "Missing"},
+		{Language: "fr", Domain: "default", Msgid: "One file"},
+		{Language: "fr", Domain: "default", Context: "menu", Msgid: "Open", Fuzzy: true},
+	}
+	entries, err := Incomplete(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected %v but got %v", expected, entries)
+	}
+
+	r := new(recorder)
+	if AssertComplete(r, l, "default") {
+		t.Error("Expected the catalog to be incomplete")
+	}
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], `fuzzy: "Open" (context "menu")`) {
+		t.Errorf("Unexpected errors: %q", r.errors)
+	}
+
+	r = new(recorder)
+	AssertComplete(r, l, "unknown")
+	if len(r.errors) != 1 {
+		t.Errorf("Expected an error for an unknown domain, got %q", r.errors)
+	}
+}
+
+func TestAssertCompleteTranslated(t *testing.T) {
+	l := newLocale(`
+msgid "Translated"
+msgstr "Traduit"
+`)
+
+	AssertComplete(t, l)
+}
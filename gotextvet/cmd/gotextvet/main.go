@@ -0,0 +1,22 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+// Command gotextvet runs the gotext analyzers, to be used with go vet:
+//
+//	go vet -vettool=$(which gotextvet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"github.com/leonelquinteros/gotext/gotextvet"
+)
+
+func main() {
+	unitchecker.Main(
+		gotextvet.LiteralMsgid,
+		gotextvet.Untranslated,
+	)
+}
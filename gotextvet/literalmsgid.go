@@ -0,0 +1,213 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+// Package gotextvet provides go/analysis analyzers checking the use of gotext in Go packages.
+// The analyzers can be run with go vet through the gotextvet command:
+//
+//	go install github.com/leonelquinteros/gotext/gotextvet/cmd/gotextvet
+//	go vet -vettool=$(which gotextvet) ./...
+package gotextvet
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// gotextPath is the import path of the gotext package.
+const gotextPath = "github.com/leonelquinteros/gotext"
+
+// getter describes the argument positions of a Get-family function, -1 when the argument isn't taken.
+type getter struct {
+	id, plural int
+}
+
+// getters are the Get-family functions and methods of the gotext package, by name.
+var getters = map[string]getter{
+	"Get":       {0, -1},
+	"GetN":      {0, 1},
+	"GetD":      {1, -1},
+	"GetND":     {1, 2},
+	"GetC":      {0, -1},
+	"GetNC":     {0, 1},
+	"GetDC":     {1, -1},
+	"GetNDC":    {1, 2},
+	"GetHTML":   {0, -1},
+	"GetNHTML":  {0, 1},
+	"GetCHTML":  {0, -1},
+	"GetNCHTML": {0, 1},
+
+	"GetMarkdown":  {0, -1},
+	"GetDMarkdown": {1, -1},
+}
+
+// LiteralMsgid reports Get-family calls whose msgid isn't a constant string.
+// Such strings can't be extracted by xgotext, so they never reach translators.
+// When the msgid is a variable only ever assigned a string literal, a fix inlining the literal is suggested.
+var LiteralMsgid = &analysis.Analyzer{
+	Name:     "literalmsgid",
+	Doc:      "report gotext Get-family calls whose msgid is not a constant string",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runLiteralMsgid,
+}
+
+func runLiteralMsgid(pass *analysis.Pass) (interface{}, error) {
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	ins.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		name, g, ok := gotextGetter(pass.TypesInfo, call)
+		if !ok {
+			return
+		}
+
+		for _, i := range []int{g.id, g.plural} {
+			if i < 0 || i >= len(call.Args) {
+				continue
+			}
+			arg := call.Args[i]
+			if tv, ok := pass.TypesInfo.Types[arg]; ok && tv.Value != nil {
+				continue
+			}
+
+			diag := analysis.Diagnostic{
+				Pos:     arg.Pos(),
+				End:     arg.End(),
+				Message: "msgid of gotext " + name + " is not a constant string, it can't be extracted for translation",
+			}
+			if edits := inlineFix(pass, arg); edits != nil {
+				diag.SuggestedFixes = []analysis.SuggestedFix{{
+					Message:   "Inline the string literal",
+					TextEdits: edits,
+				}}
+			}
+			pass.Report(diag)
+		}
+	})
+
+	return nil, nil
+}
+
+// gotextGetter returns the name and arguments of the gotext Get-family function or method called by call.
+func gotextGetter(info *types.Info, call *ast.CallExpr) (string, getter, bool) {
+	var ident *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return "", getter{}, false
+	}
+
+	fn, ok := info.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != gotextPath {
+		return "", getter{}, false
+	}
+	g, ok := getters[fn.Name()]
+
+	return fn.Name(), g, ok
+}
+
+// inlineFix returns the edits replacing the variable used as arg by the string literal it holds,
+// when it's only ever assigned at its declaration with that literal.
+// Local variables used only there are removed, so the fixed code still compiles.
+func inlineFix(pass *analysis.Pass, arg ast.Expr) []analysis.TextEdit {
+	ident, ok := ast.Unparen(arg).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	obj, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+	if !ok || obj.Pkg() != pass.Pkg {
+		return nil
+	}
+
+	var (
+		lit     *ast.BasicLit
+		decl    ast.Stmt
+		assigns int
+		uses    int
+	)
+	// assign records the assignments to obj, stmt being the statement declaring or assigning them.
+	assign := func(stmt ast.Stmt, lhs []ast.Expr, rhs []ast.Expr) {
+		for i, l := range lhs {
+			id, ok := ast.Unparen(l).(*ast.Ident)
+			if !ok || (pass.TypesInfo.Defs[id] != obj && pass.TypesInfo.Uses[id] != obj) {
+				continue
+			}
+			assigns++
+			if len(lhs) == len(rhs) {
+				if bl, ok := rhs[i].(*ast.BasicLit); ok && bl.Kind == token.STRING {
+					lit = bl
+				}
+			}
+			if len(lhs) == 1 {
+				decl = stmt
+			}
+		}
+	}
+
+	for _, f := range pass.Files {
+		var stmt ast.Stmt
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch x := n.(type) {
+			case *ast.DeclStmt:
+				stmt = x
+			case *ast.AssignStmt:
+				assign(x, x.Lhs, x.Rhs)
+			case *ast.GenDecl:
+				if len(x.Specs) != 1 {
+					stmt = nil
+				}
+			case *ast.ValueSpec:
+				lhs := make([]ast.Expr, len(x.Names))
+				for i, name := range x.Names {
+					lhs[i] = name
+				}
+				assign(stmt, lhs, x.Values)
+				stmt = nil
+			case *ast.IncDecStmt:
+				assign(x, []ast.Expr{x.X}, nil)
+			case *ast.UnaryExpr:
+				// Variables whose address is taken may be modified anywhere.
+				if x.Op == token.AND {
+					assign(nil, []ast.Expr{x.X}, nil)
+					assigns++
+				}
+			case *ast.RangeStmt:
+				var lhs []ast.Expr
+				for _, e := range []ast.Expr{x.Key, x.Value} {
+					if e != nil {
+						lhs = append(lhs, e)
+					}
+				}
+				assign(x, lhs, nil)
+			case *ast.Ident:
+				if pass.TypesInfo.Uses[x] == obj {
+					uses++
+				}
+			}
+			return true
+		})
+	}
+
+	if assigns != 1 || lit == nil {
+		return nil
+	}
+
+	edits := []analysis.TextEdit{{Pos: arg.Pos(), End: arg.End(), NewText: []byte(lit.Value)}}
+	if obj.Parent() != pass.Pkg.Scope() && uses == 1 {
+		if decl == nil {
+			return nil
+		}
+		edits = append(edits, analysis.TextEdit{Pos: decl.Pos(), End: decl.End()})
+	}
+
+	return edits
+}
@@ -0,0 +1,16 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotextvet
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestLiteralMsgid(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), LiteralMsgid, "literalmsgid")
+}
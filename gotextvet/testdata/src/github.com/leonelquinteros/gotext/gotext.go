@@ -0,0 +1,16 @@
+// Package gotext is a stub of the gotext API used by the analyzer tests.
+package gotext
+
+type Locale struct{}
+
+func (l *Locale) Get(str string, vars ...interface{}) string                 { return str }
+func (l *Locale) GetN(str, plural string, n int, vars ...interface{}) string { return str }
+func (l *Locale) GetD(dom, str string, vars ...interface{}) string           { return str }
+func (l *Locale) GetC(str, ctx string, vars ...interface{}) string           { return str }
+func (l *Locale) GetDMarkdown(dom, src string) string                        { return src }
+
+func Get(str string, vars ...interface{}) string                 { return str }
+func GetN(str, plural string, n int, vars ...interface{}) string { return str }
+func GetD(dom, str string, vars ...interface{}) string           { return str }
+func GetMarkdown(src string) string                              { return src }
+func SetDomain(dom string)                                       {}
@@ -0,0 +1,36 @@
+package literalmsgid
+
+import "github.com/leonelquinteros/gotext"
+
+const greeting = "Hello"
+
+var title = "Title"
+
+func f(l *gotext.Locale, name string, n int) {
+	gotext.Get("Literal")
+	gotext.Get(greeting)
+	gotext.Get("Hello " + "world")
+	gotext.Get(name) // want `msgid of gotext Get is not a constant string`
+	gotext.GetD(name, "Domains don't need to be constant")
+	gotext.GetN("One", name, n) // want `msgid of gotext GetN is not a constant string`
+
+	msg := "Inline me"
+	l.Get(msg) // want `msgid of gotext Get is not a constant string`
+
+	l.GetC(title, "ctx") // want `msgid of gotext GetC is not a constant string`
+
+	changed := "First"
+	changed = "Second"
+	l.Get(changed) // want `msgid of gotext Get is not a constant string`
+
+	reused := "Reused"
+	l.Get(reused)       // want `msgid of gotext Get is not a constant string`
+	l.Get(reused + "!") // want `msgid of gotext Get is not a constant string`
+
+	gotext.GetMarkdown("# Literal")
+	gotext.GetMarkdown(name) // want `msgid of gotext GetMarkdown is not a constant string`
+	l.GetDMarkdown(name, "# Domains")
+	l.GetDMarkdown("help", name+"\n") // want `msgid of gotext GetDMarkdown is not a constant string`
+
+	gotext.SetDomain(name)
+}
@@ -0,0 +1,30 @@
+package untranslated
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+const notice = "Service unavailable"
+
+func handler(w http.ResponseWriter, r *http.Request, tmpl *template.Template, name string) {
+	fmt.Println("Hello world") // want `untranslated string passed to fmt.Println`
+	fmt.Println(gotext.Get("Hello world"))
+	fmt.Printf("%s: %d\n", name, 3)
+	fmt.Printf("Welcome %s\n", name) // want `untranslated string passed to fmt.Printf`
+	fmt.Fprintln(os.Stderr, "E")
+	fmt.Fprintf(w, gotext.Get("Welcome %s"), name)
+
+	http.Error(w, notice, http.StatusServiceUnavailable) // want `untranslated string passed to net/http.Error`
+	http.Error(w, gotext.Get(notice), http.StatusServiceUnavailable)
+
+	tmpl.ExecuteTemplate(w, "page.html", "Page title") // want `untranslated string passed to \(\*html/template.Template\).ExecuteTemplate`
+	tmpl.Execute(w, name)
+
+	log.Println("Internal messages aren't checked")
+}
@@ -0,0 +1,14 @@
+package untranslatedflags
+
+import (
+	"fmt"
+	"log"
+)
+
+func show(msg string) {}
+
+func f() {
+	fmt.Println("Allowed")
+	log.Println("Checked") // want `untranslated string passed to log.Println`
+	show("Shown to users") // want `untranslated string passed to untranslatedflags.show`
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotextvet
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// DefaultUserFacingFuncs are the functions whose string arguments are shown to users, checked by Untranslated.
+// Functions are named as by types.Func.FullName, i.e. "fmt.Println" or "(*html/template.Template).Execute".
+var DefaultUserFacingFuncs = []string{
+	"fmt.Print",
+	"fmt.Println",
+	"fmt.Printf",
+	"fmt.Fprint",
+	"fmt.Fprintln",
+	"fmt.Fprintf",
+	"net/http.Error",
+	"(*html/template.Template).Execute",
+	"(*html/template.Template).ExecuteTemplate",
+	"(*text/template.Template).Execute",
+	"(*text/template.Template).ExecuteTemplate",
+}
+
+// dataOnlyFuncs are user facing functions where only the last argument is shown to users.
+// The name of the executed template isn't.
+var dataOnlyFuncs = map[string]bool{
+	"(*html/template.Template).ExecuteTemplate": true,
+	"(*text/template.Template).ExecuteTemplate": true,
+}
+
+var (
+	untranslatedFuncs string
+	untranslatedAllow string
+)
+
+// Untranslated reports constant strings passed to user facing functions (see DefaultUserFacingFuncs)
+// without going through gotext, to find hardcoded texts before release.
+// Strings without words, like "%s: %d\n", aren't reported.
+//
+// The checked functions can be extended with the -funcs flag and excluded with the -allow flag,
+// both taking comma separated lists of function names.
+var Untranslated = &analysis.Analyzer{
+	Name:     "untranslated",
+	Doc:      "report hardcoded strings passed to user facing functions without being translated by gotext",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runUntranslated,
+}
+
+func init() {
+	Untranslated.Flags.StringVar(&untranslatedFuncs, "funcs", "", "comma separated list of additional user facing functions")
+	Untranslated.Flags.StringVar(&untranslatedAllow, "allow", "", "comma separated list of functions allowed to receive untranslated strings")
+}
+
+// verbRe matches fmt verbs, with their flags, width and precision.
+var verbRe = regexp.MustCompile(`%(?:\[\d+\])?[-+# 0]*(?:\d+|\*)?(?:\.(?:\d+|\*))?[a-zA-Z%]`)
+
+// hasWords reports if s holds text to be translated: a word of at least two letters once fmt verbs are removed.
+func hasWords(s string) bool {
+	letters := 0
+	for _, r := range verbRe.ReplaceAllString(s, " ") {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', r > 0x7f:
+			if letters++; letters >= 2 {
+				return true
+			}
+		default:
+			letters = 0
+		}
+	}
+
+	return false
+}
+
+// splitList returns the non-empty items of a comma separated list.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func runUntranslated(pass *analysis.Pass) (interface{}, error) {
+	funcs := make(map[string]bool)
+	for _, f := range DefaultUserFacingFuncs {
+		funcs[f] = true
+	}
+	for _, f := range splitList(untranslatedFuncs) {
+		funcs[f] = true
+	}
+	for _, f := range splitList(untranslatedAllow) {
+		delete(funcs, f)
+	}
+
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+
+		var ident *ast.Ident
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			ident = fun
+		case *ast.SelectorExpr:
+			ident = fun.Sel
+		default:
+			return
+		}
+		fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+		if !ok || !funcs[fn.FullName()] {
+			return
+		}
+
+		args := call.Args
+		if dataOnlyFuncs[fn.FullName()] && len(args) > 0 {
+			args = args[len(args)-1:]
+		}
+		for _, arg := range args {
+			tv, ok := pass.TypesInfo.Types[arg]
+			if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+				continue
+			}
+			if hasWords(constant.StringVal(tv.Value)) {
+				pass.Reportf(arg.Pos(), "untranslated string passed to %s, use gotext to translate it", fn.FullName())
+			}
+		}
+	})
+
+	return nil, nil
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotextvet
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestUntranslated(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Untranslated, "untranslated")
+}
+
+func TestUntranslatedFlags(t *testing.T) {
+	defer Untranslated.Flags.Set("funcs", "")
+	defer Untranslated.Flags.Set("allow", "")
+
+	Untranslated.Flags.Set("funcs", "log.Println,untranslatedflags.show")
+	Untranslated.Flags.Set("allow", "fmt.Println")
+	analysistest.Run(t, analysistest.TestData(), Untranslated, "untranslatedflags")
+}
+
+func TestHasWords(t *testing.T) {
+	for s, expected := range map[string]bool{
+		"Hello":         true,
+		"%s: %d\n":      false,
+		"%-10s|%5.2f":   false,
+		"OK":            true,
+		"A":             false,
+		"Größe: %d":     true,
+		"%[1]s (%[2]v)": false,
+	} {
+		if got := hasWords(s); got != expected {
+			t.Errorf("Expected %v for '%s' but got %v", expected, s, got)
+		}
+	}
+}
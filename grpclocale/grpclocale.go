@@ -0,0 +1,144 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+/*
+Package grpclocale provides gRPC interceptors resolving the gotext Locale of each call from its metadata.
+
+It's a separate module, so the gotext package doesn't depend on gRPC.
+
+Server side, the Locale is matched from the languages found in the "accept-language" metadata (or a custom key),
+and injected in the call context:
+
+	l := gotext.NewLocale("/path/to/i18n/dir", "en_US")
+	l.AddDomain("default")
+
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(grpclocale.UnaryServerInterceptor(l)),
+		grpc.StreamInterceptor(grpclocale.StreamServerInterceptor(l)),
+	)
+
+	func (s *server) SayHello(ctx context.Context, req *pb.HelloRequest) (*pb.HelloReply, error) {
+		return &pb.HelloReply{Message: gotext.FromContext(ctx).Get("Hello %s", req.Name)}, nil
+	}
+
+Client side, the language of the Locale in the call context is forwarded downstream:
+
+	conn, err := grpc.NewClient(target,
+		grpc.WithUnaryInterceptor(grpclocale.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(grpclocale.StreamClientInterceptor()),
+	)
+*/
+package grpclocale
+
+import (
+	"context"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// DefaultKey is the metadata key holding the languages of a call, in Accept-Language format.
+const DefaultKey = "accept-language"
+
+// Option configures the interceptors.
+type Option func(*options)
+
+type options struct {
+	key string
+}
+
+// WithMetadataKey sets a custom metadata key holding the languages of a call.
+// Server interceptors still fall back to DefaultKey when it's missing.
+func WithMetadataKey(key string) Option {
+	return func(o *options) {
+		o.key = strings.ToLower(key)
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{key: DefaultKey}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// resolve returns a copy of ctx carrying the Locale matching the languages in the incoming metadata.
+func (o *options) resolve(ctx context.Context, l *gotext.Locale) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var langs []string
+	for _, key := range []string{o.key, DefaultKey} {
+		for _, v := range md.Get(key) {
+			langs = append(langs, gotext.ParseAcceptLanguage(v)...)
+		}
+		if len(langs) > 0 {
+			break
+		}
+	}
+
+	return gotext.NewContext(ctx, l.Match(langs...))
+}
+
+// UnaryServerInterceptor returns an interceptor injecting in the context of unary calls the Locale matching their metadata.
+// Locales for other languages than l are created with Locale.Match.
+func UnaryServerInterceptor(l *gotext.Locale, opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(o.resolve(ctx, l), req)
+	}
+}
+
+// StreamServerInterceptor returns an interceptor injecting in the context of streams the Locale matching their metadata.
+// Locales for other languages than l are created with Locale.Match.
+func StreamServerInterceptor(l *gotext.Locale, opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: o.resolve(ss.Context(), l)})
+	}
+}
+
+// serverStream overrides the context of a grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// forward returns a copy of ctx with outgoing metadata holding the language of the Locale it carries, if any.
+func (o *options) forward(ctx context.Context) context.Context {
+	l := gotext.FromContext(ctx)
+	if l == nil || l.GetLanguage() == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, o.key, l.GetLanguageTag())
+}
+
+// UnaryClientInterceptor returns an interceptor forwarding the language of the Locale in the context of unary calls.
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts)
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		return invoker(o.forward(ctx), method, req, reply, cc, callOpts...)
+	}
+}
+
+// StreamClientInterceptor returns an interceptor forwarding the language of the Locale in the context of streams.
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	o := newOptions(opts)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(o.forward(ctx), desc, cc, method, callOpts...)
+	}
+}
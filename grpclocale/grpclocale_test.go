@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package grpclocale
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func newLocale() *gotext.Locale {
+	l := gotext.NewLocale("../fixtures/", "en_US")
+	l.AddDomain("default")
+
+	return l
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	l := newLocale()
+
+	for _, test := range []struct {
+		md       metadata.MD
+		opts     []Option
+		expected string
+	}{
+		{metadata.Pairs("accept-language", "fr-CA, de;q=0.5"), nil, "fr"},
+		{metadata.Pairs("x-lang", "de-DE", "accept-language", "fr"), []Option{WithMetadataKey("X-Lang")}, "de_DE"},
+		{metadata.Pairs("accept-language", "fr"), []Option{WithMetadataKey("x-lang")}, "fr"},
+		{metadata.Pairs("accept-language", "xx"), nil, "en_US"},
+		{nil, nil, "en_US"},
+	} {
+		ctx := context.Background()
+		if test.md != nil {
+			ctx = metadata.NewIncomingContext(ctx, test.md)
+		}
+
+		var got string
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			got = gotext.FromContext(ctx).GetLanguage()
+			return nil, nil
+		}
+		UnaryServerInterceptor(l, test.opts...)(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+
+		if got != test.expected {
+			t.Errorf("Expected '%s' for %v but got '%s'", test.expected, test.md, got)
+		}
+	}
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "fr"))
+
+	var got string
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		got = gotext.FromContext(ss.Context()).Get("language")
+		return nil
+	}
+	StreamServerInterceptor(newLocale())(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, handler)
+
+	if got != "fr" {
+		t.Errorf("Expected 'fr' but got '%s'", got)
+	}
+}
+
+func TestClientInterceptors(t *testing.T) {
+	ctx := gotext.NewContext(context.Background(), gotext.NewLocale("", "pt_BR"))
+
+	var got []string
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		got = md.Get("accept-language")
+		return nil
+	}
+	UnaryClientInterceptor()(ctx, "/test", nil, nil, nil, invoker)
+	if len(got) != 1 || got[0] != "pt-BR" {
+		t.Errorf("Expected [pt-BR] but got %v", got)
+	}
+
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		got = md.Get("x-lang")
+		return nil, nil
+	}
+	StreamClientInterceptor(WithMetadataKey("x-lang"))(ctx, &grpc.StreamDesc{}, nil, "/test", streamer)
+	if len(got) != 1 || got[0] != "pt-BR" {
+		t.Errorf("Expected [pt-BR] but got %v", got)
+	}
+
+	// Contexts without Locale are left untouched
+	UnaryClientInterceptor()(context.Background(), "/test", nil, nil, nil, invoker)
+	if len(got) != 0 {
+		t.Errorf("Expected no metadata but got %v", got)
+	}
+}
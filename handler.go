@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+// CatalogJSON is the JSON document served by CatalogHandler.
+type CatalogJSON struct {
+	Language string                `json:"language"`
+	Domains  map[string]DomainJSON `json:"domains"`
+}
+
+// DomainJSON holds the translations of a domain in CatalogJSON.
+type DomainJSON struct {
+	// Plural-Forms header of the catalog
+	PluralForms string `json:"pluralForms,omitempty"`
+
+	// Translations by context (empty for none) and msgid, with one element per plural form
+	Translations map[string]map[string][]string `json:"translations"`
+}
+
+// domainJSON returns the translations of a Translator, skipping the header and untranslated entries.
+// Chained Translators are merged by precedence.
+func domainJSON(tr Translator) DomainJSON {
+	if c, ok := tr.(*ChainTranslator); ok {
+		dj := DomainJSON{Translations: make(map[string]map[string][]string)}
+		for i := len(c.links) - 1; i >= 0; i-- {
+			link := domainJSON(c.links[i])
+			for ctx, ids := range link.Translations {
+				if dj.Translations[ctx] == nil {
+					dj.Translations[ctx] = make(map[string][]string)
+				}
+				for id, forms := range ids {
+					dj.Translations[ctx][id] = forms
+				}
+			}
+			if i == 0 {
+				dj.PluralForms = link.PluralForms
+			}
+		}
+
+		return dj
+	}
+
+	do := tr.GetDomain()
+	dj := DomainJSON{
+		PluralForms:  do.PluralForms,
+		Translations: make(map[string]map[string][]string),
+	}
+
+	for k, t := range do.load().entries {
+		if k.id == "" {
+			continue
+		}
+
+		forms := make([]string, 0, len(t.Trs))
+		for i := 0; i < len(t.Trs); i++ {
+			forms = append(forms, t.Trs[i])
+		}
+		if len(forms) == 0 || forms[0] == "" {
+			continue
+		}
+
+		if dj.Translations[k.ctx] == nil {
+			dj.Translations[k.ctx] = make(map[string][]string)
+		}
+		dj.Translations[k.ctx][k.id] = forms
+	}
+
+	return dj
+}
+
+// CatalogJSON returns the translations of the given domains (all of them if none is given) for JSON serialization.
+// Only the domains holding their entries in a Domain, like Po and Mo ones, are exported.
+func (l *Locale) CatalogJSON(doms ...string) CatalogJSON {
+	snap := l.load()
+	if len(doms) == 0 {
+		for dom := range snap.domains {
+			doms = append(doms, dom)
+		}
+		sort.Strings(doms)
+	}
+
+	c := CatalogJSON{
+		Language: l.GetLanguage(),
+		Domains:  make(map[string]DomainJSON, len(doms)),
+	}
+	for _, dom := range doms {
+		if tr, ok := snap.domains[dom]; ok {
+			c.Domains[dom] = domainJSON(tr)
+		}
+	}
+
+	return c
+}
+
+/*
+CatalogHandler returns an http.Handler serving the catalogs of a Locale as JSON (see CatalogJSON),
+so single-page apps use the exact same translations as the backend.
+
+The language is taken from the "lang" query parameter, falling back to the given Resolvers (Accept-Language by default)
+and resolved with Locale.Match. Domains are filtered with "domain" query parameters, all of them are served by default.
+Responses carry an ETag, so clients revalidate cheaply with If-None-Match.
+
+Example:
+
+	http.Handle("/i18n", gotext.CatalogHandler(l))
+
+	// GET /i18n?lang=fr&domain=default
+*/
+func CatalogHandler(l *Locale, resolvers ...Resolver) http.Handler {
+	if len(resolvers) == 0 {
+		resolvers = []Resolver{AcceptLanguageResolver()}
+	}
+	resolvers = append([]Resolver{QueryResolver("lang")}, resolvers...)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		loc := RequestLocale(l, r, resolvers...)
+		doms := r.URL.Query()["domain"]
+		c := loc.CatalogJSON(doms...)
+		if len(c.Domains) < len(doms) {
+			http.NotFound(w, r)
+			return
+		}
+
+		body, err := json.Marshal(c)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		sum := sha256.Sum256(body)
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+		h := w.Header()
+		h.Set("ETag", etag)
+		h.Set("Cache-Control", "no-cache")
+		h.Set("Vary", "Accept-Language")
+		h.Set("Content-Language", loc.GetLanguageTag())
+
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		h.Set("Content-Type", "application/json; charset=utf-8")
+		if r.Method == http.MethodGet {
+			w.Write(body)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCatalogHandler(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	l.AddDomain("default")
+	h := CatalogHandler(l)
+
+	r := httptest.NewRequest("GET", "/?lang=fr&domain=default", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 but got %d", w.Code)
+	}
+
+	var c CatalogJSON
+	if err := json.Unmarshal(w.Body.Bytes(), &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Language != "fr" {
+		t.Errorf("Expected 'fr' but got '%s'", c.Language)
+	}
+	if got := c.Domains["default"].Translations[""]["language"]; len(got) != 1 || got[0] != "fr" {
+		t.Errorf("Expected [fr] but got %v", got)
+	}
+
+	// Revalidation
+	etag := w.Header().Get("ETag")
+	r = httptest.NewRequest("GET", "/?lang=fr&domain=default", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected status 304 but got %d", w.Code)
+	}
+
+	// Language from Accept-Language, plurals and contexts
+	r = httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "en-US")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	c = CatalogJSON{}
+	if err := json.Unmarshal(w.Body.Bytes(), &c); err != nil {
+		t.Fatal(err)
+	}
+	d := c.Domains["default"]
+	if got := d.Translations["Ctx"]["One with var: %s"]; len(got) != 2 || got[1] != "This one is the plural in a Ctx context: %s" {
+		t.Errorf("Unexpected plural translations %v", got)
+	}
+	if _, ok := d.Translations[""]["Empty translation"]; ok {
+		t.Error("Expected untranslated entries to be skipped")
+	}
+	if w.Header().Get("ETag") == etag {
+		t.Error("Expected different ETag for another language")
+	}
+
+	// Overrides are merged
+	overrides := NewPo()
+	overrides.Parse([]byte("msgid \"My text\"\nmsgstr \"Overridden text\"\n"))
+	l.SetOverride("default", overrides)
+	c = l.CatalogJSON("default")
+	if got := c.Domains["default"].Translations[""]["My text"]; len(got) != 1 || got[0] != "Overridden text" {
+		t.Errorf("Expected [Overridden text] but got %v", got)
+	}
+	if got := c.Domains["default"].Translations[""]["language"]; len(got) != 1 || got[0] != "en_US" {
+		t.Errorf("Expected [en_US] but got %v", got)
+	}
+
+	// Unknown domains
+	r = httptest.NewRequest("GET", "/?domain=missing", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 but got %d", w.Code)
+	}
+}
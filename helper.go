@@ -14,6 +14,7 @@ import (
 var re = regexp.MustCompile(`%\(([a-zA-Z0-9_]+)\)[.0-9]*[svTtbcdoqXxUeEfFgGp]`)
 
 // SimplifiedLocale simplified locale like " en_US"/"de_DE "/en_US.UTF-8/zh_CN/zh_TW/el_GR@euro/... to en_US, de_DE, zh_CN, el_GR...
+// BCP 47 tags are accepted as well and normalized to the same POSIX form, so "en-us" becomes en_US and "zh-hant-tw" becomes zh_Hant_TW.
 func SimplifiedLocale(lang string) string {
 	// en_US/en_US.UTF-8/zh_CN/zh_TW/el_GR@euro/...
 	if idx := strings.Index(lang, ":"); idx != -1 {
@@ -25,7 +26,57 @@ func SimplifiedLocale(lang string) string {
 	if idx := strings.Index(lang, "."); idx != -1 {
 		lang = lang[:idx]
 	}
-	return strings.TrimSpace(lang)
+	return normalizeSubtags(strings.TrimSpace(lang))
+}
+
+// LanguageTag returns the canonical BCP 47 tag (i.e. "en-US", "zh-Hant-TW") for a language code given in either
+// POSIX or BCP 47 form, ready to be used in HTTP headers like Content-Language.
+func LanguageTag(lang string) string {
+	return strings.Replace(SimplifiedLocale(lang), "_", "-", -1)
+}
+
+// normalizeSubtags joins the language subtags with underscores and applies the conventional case to each of them:
+// lower case language, title case script and upper case region.
+func normalizeSubtags(lang string) string {
+	if lang == "C" || lang == "POSIX" {
+		return lang
+	}
+
+	subtags := strings.FieldsFunc(lang, func(r rune) bool {
+		return r == '_' || r == '-'
+	})
+	for i, st := range subtags {
+		switch {
+		case i == 0:
+			subtags[i] = strings.ToLower(st)
+		case len(st) == 4 && isAlpha(st):
+			subtags[i] = strings.ToUpper(st[:1]) + strings.ToLower(st[1:])
+		case len(st) == 2 && isAlpha(st), len(st) == 3 && isDigit(st):
+			subtags[i] = strings.ToUpper(st)
+		default:
+			subtags[i] = strings.ToLower(st)
+		}
+	}
+
+	return strings.Join(subtags, "_")
+}
+
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
 }
 
 // Printf applies text formatting only when needed to parse variables.
@@ -48,39 +99,37 @@ func NPrintf(format string, params map[string]interface{}) {
 //      Sprintf("%(name)s is Type %(type)s", map[string]interface{}{"name": "Gotext", "type": "struct"})
 func Sprintf(format string, params map[string]interface{}) string {
 	f, p := parseSprintf(format, params)
-	return fmt.Sprintf(f, p...)
+	return Printf(f, p...)
 }
 
 func parseSprintf(format string, params map[string]interface{}) (string, []interface{}) {
 	f, n := reformatSprintf(format)
-	var p []interface{}
-	for _, v := range n {
-		p = append(p, params[v])
+	p := make([]interface{}, len(n))
+	for i, v := range n {
+		p[i] = params[v]
 	}
 	return f, p
 }
 
+// reformatSprintf removes the names from the named verbs in f, returning the resulting format and the names in order.
 func reformatSprintf(f string) (string, []string) {
-	m := re.FindAllStringSubmatch(f, -1)
-	i := re.FindAllStringSubmatchIndex(f, -1)
-
-	ord := []string{}
-	for _, v := range m {
-		ord = append(ord, v[1])
+	idx := re.FindAllStringSubmatchIndex(f, -1)
+	if len(idx) == 0 {
+		return f, []string{}
 	}
 
-	pair := []int{0}
-	for _, v := range i {
-		pair = append(pair, v[2]-1)
-		pair = append(pair, v[3]+1)
-	}
-	pair = append(pair, len(f))
-	plen := len(pair)
+	var buf strings.Builder
+	buf.Grow(len(f))
 
-	out := ""
-	for n := 0; n < plen; n += 2 {
-		out += f[pair[n]:pair[n+1]]
+	ord := make([]string, len(idx))
+	last := 0
+	for i, v := range idx {
+		// Skip the "(name)" part between the % sign and the verb
+		buf.WriteString(f[last : v[2]-1])
+		last = v[3] + 1
+		ord[i] = f[v[2]:v[3]]
 	}
+	buf.WriteString(f[last:])
 
-	return out, ord
+	return buf.String(), ord
 }
@@ -25,6 +25,38 @@ func TestSimplifiedLocale(t *testing.T) {
 	if tr != "de_DE" {
 		t.Errorf("Expected 'de_DE' but got '%s'", tr)
 	}
+
+	tr = SimplifiedLocale("EN-us")
+	if tr != "en_US" {
+		t.Errorf("Expected 'en_US' but got '%s'", tr)
+	}
+
+	tr = SimplifiedLocale("zh-hant-tw")
+	if tr != "zh_Hant_TW" {
+		t.Errorf("Expected 'zh_Hant_TW' but got '%s'", tr)
+	}
+
+	tr = SimplifiedLocale("es-419")
+	if tr != "es_419" {
+		t.Errorf("Expected 'es_419' but got '%s'", tr)
+	}
+
+	tr = SimplifiedLocale("C")
+	if tr != "C" {
+		t.Errorf("Expected 'C' but got '%s'", tr)
+	}
+}
+
+func TestLanguageTag(t *testing.T) {
+	tr := LanguageTag("sr_latn_rs.UTF-8")
+	if tr != "sr-Latn-RS" {
+		t.Errorf("Expected 'sr-Latn-RS' but got '%s'", tr)
+	}
+
+	tr = LanguageTag("pt-BR")
+	if tr != "pt-BR" {
+		t.Errorf("Expected 'pt-BR' but got '%s'", tr)
+	}
 }
 
 func TestReformattingSingleNamedPattern(t *testing.T) {
@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "sync"
+
+/*
+LocaleHolder carries the current Locale through code that doesn't take a context.Context, such as template helper
+functions or legacy call chains, without goroutine local storage: the holder is shared explicitly between the code
+setting the Locale, once per request, and the code translating. A holder isn't bound to a goroutine, so concurrent
+requests need holders of their own, i.e. one per worker. Without a Locale, strings are returned untranslated.
+
+Example:
+
+	type worker struct {
+		locale *gotext.LocaleHolder
+	}
+
+	func (w *worker) handle(r *http.Request) {
+		defer w.locale.Set(gotext.FromContext(r.Context()))()
+
+		w.render() // calls w.locale.Get("Welcome") deep down
+	}
+
+	// Template helpers translate with the Locale of the request being rendered
+	funcs := template.FuncMap{"T": w.locale.Get}
+*/
+type LocaleHolder struct {
+	locale *Locale
+	mutex  sync.RWMutex
+}
+
+// NewLocaleHolder returns a holder of the given Locale, which can be nil.
+func NewLocaleHolder(l *Locale) *LocaleHolder {
+	return &LocaleHolder{locale: l}
+}
+
+// Locale returns the current Locale, nil if none is set.
+func (h *LocaleHolder) Locale() *Locale {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.locale
+}
+
+// Set makes l the current Locale, and returns the function restoring the previous one, to be deferred:
+//
+//	defer holder.Set(l)()
+func (h *LocaleHolder) Set(l *Locale) (restore func()) {
+	h.mutex.Lock()
+	prev := h.locale
+	h.locale = l
+	h.mutex.Unlock()
+
+	return func() {
+		h.mutex.Lock()
+		h.locale = prev
+		h.mutex.Unlock()
+	}
+}
+
+// Get returns the Translation of the given string with the current Locale, as Locale.Get.
+func (h *LocaleHolder) Get(str string, vars ...interface{}) string {
+	if l := h.Locale(); l != nil {
+		return l.Get(str, vars...)
+	}
+	return Printf(str, vars...)
+}
+
+// GetN retrieves the (N)th plural form of Translation for the given string with the current Locale, as Locale.GetN.
+func (h *LocaleHolder) GetN(str, plural string, n int, vars ...interface{}) string {
+	if l := h.Locale(); l != nil {
+		return l.GetN(str, plural, n, vars...)
+	}
+	return untranslatedN(str, plural, n, vars...)
+}
+
+// GetD returns the Translation of the given string in the given domain with the current Locale, as Locale.GetD.
+func (h *LocaleHolder) GetD(dom, str string, vars ...interface{}) string {
+	if l := h.Locale(); l != nil {
+		return l.GetD(dom, str, vars...)
+	}
+	return Printf(str, vars...)
+}
+
+// GetND retrieves the (N)th plural form of Translation in the given domain with the current Locale, as Locale.GetND.
+func (h *LocaleHolder) GetND(dom, str, plural string, n int, vars ...interface{}) string {
+	if l := h.Locale(); l != nil {
+		return l.GetND(dom, str, plural, n, vars...)
+	}
+	return untranslatedN(str, plural, n, vars...)
+}
+
+// GetC returns the Translation of the given string in the given context with the current Locale, as Locale.GetC.
+func (h *LocaleHolder) GetC(str, ctx string, vars ...interface{}) string {
+	if l := h.Locale(); l != nil {
+		return l.GetC(str, ctx, vars...)
+	}
+	return Printf(str, vars...)
+}
+
+// GetNC retrieves the (N)th plural form of Translation in the given context with the current Locale, as Locale.GetNC.
+func (h *LocaleHolder) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	if l := h.Locale(); l != nil {
+		return l.GetNC(str, plural, n, ctx, vars...)
+	}
+	return untranslatedN(str, plural, n, vars...)
+}
+
+// GetDC returns the Translation of the given string in the given domain and context with the current Locale, as
+// Locale.GetDC.
+func (h *LocaleHolder) GetDC(dom, str, ctx string, vars ...interface{}) string {
+	if l := h.Locale(); l != nil {
+		return l.GetDC(dom, str, ctx, vars...)
+	}
+	return Printf(str, vars...)
+}
+
+// GetNDC retrieves the (N)th plural form of Translation in the given domain and context with the current Locale, as
+// Locale.GetNDC.
+func (h *LocaleHolder) GetNDC(dom, str, plural string, n int, ctx string, vars ...interface{}) string {
+	if l := h.Locale(); l != nil {
+		return l.GetNDC(dom, str, plural, n, ctx, vars...)
+	}
+	return untranslatedN(str, plural, n, vars...)
+}
+
+// untranslatedN returns the untranslated plural form for n, using the western default rule (plural > 1).
+func untranslatedN(str, plural string, n int, vars ...interface{}) string {
+	if n == 1 {
+		return Printf(str, vars...)
+	}
+	return Printf(plural, vars...)
+}
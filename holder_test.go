@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "testing"
+
+func TestLocaleHolder(t *testing.T) {
+	de := NewLocale("/tmp", "de")
+	po := NewPo()
+	po.Parse([]byte("msgid \"Hello\"\nmsgstr \"Hallo\"\n\nmsgid \"One file\"\nmsgid_plural \"%d files\"\nmsgstr[0] \"Eine Datei\"\nmsgstr[1] \"%d Dateien\"\n"))
+	de.AddTranslator("default", po)
+
+	fr := NewLocale("/tmp", "fr")
+	po = NewPo()
+	po.Parse([]byte("msgid \"Hello\"\nmsgstr \"Bonjour\"\n"))
+	fr.AddTranslator("default", po)
+
+	h := NewLocaleHolder(nil)
+	if tr := h.Get("Hello"); tr != "Hello" {
+		t.Errorf("Expected '%s' but got '%s'", "Hello", tr)
+	}
+	if tr := h.GetN("One file", "%d files", 2, 2); tr != "2 files" {
+		t.Errorf("Expected '%s' but got '%s'", "2 files", tr)
+	}
+
+	restoreDe := h.Set(de)
+	if tr := h.Get("Hello"); tr != "Hallo" {
+		t.Errorf("Expected '%s' but got '%s'", "Hallo", tr)
+	}
+	if tr := h.GetND("default", "One file", "%d files", 3, 3); tr != "3 Dateien" {
+		t.Errorf("Expected '%s' but got '%s'", "3 Dateien", tr)
+	}
+
+	func() {
+		defer h.Set(fr)()
+		if tr := h.GetD("default", "Hello"); tr != "Bonjour" {
+			t.Errorf("Expected '%s' but got '%s'", "Bonjour", tr)
+		}
+	}()
+
+	if h.Locale() != de {
+		t.Errorf("Expected the %s Locale but got %v", "de", h.Locale())
+	}
+	restoreDe()
+	if h.Locale() != nil {
+		t.Errorf("Expected no Locale but got %v", h.Locale())
+	}
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"fmt"
+	"html/template"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// HTMLAllowedTags is the default allowlist of tags translators may use in translations rendered as HTML.
+// Attributes are always removed from allowed tags, other tags are escaped.
+var HTMLAllowedTags = []string{"b", "strong", "i", "em", "u", "s", "br", "code", "small", "sub", "sup", "mark", "span"}
+
+var (
+	htmlTagRe    = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9]*)(?:\s[^<>]*)?(/?)>`)
+	htmlEntityRe = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+)
+
+// SanitizeHTML escapes the HTML in s except the allowed tags (HTMLAllowedTags if none is given),
+// which are kept without attributes. Character entities are kept as well.
+func SanitizeHTML(s string, tags ...string) template.HTML {
+	if len(tags) == 0 {
+		tags = HTMLAllowedTags
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '<':
+			if m := htmlTagRe.FindStringSubmatch(s[i:]); m != nil && isAllowedTag(m[2], tags) {
+				b.WriteString("<" + m[1] + strings.ToLower(m[2]) + m[3] + ">")
+				i += len(m[0]) - 1
+				continue
+			}
+			b.WriteString("&lt;")
+
+		case '&':
+			if m := htmlEntityRe.FindString(s[i:]); m != "" {
+				b.WriteString(m)
+				i += len(m) - 1
+				continue
+			}
+			b.WriteString("&amp;")
+
+		case '>':
+			b.WriteString("&gt;")
+		case '"':
+			b.WriteString("&#34;")
+		case '\'':
+			b.WriteString("&#39;")
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return template.HTML(b.String())
+}
+
+func isAllowedTag(name string, tags []string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(name, t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// escapeHTMLArg returns the HTML escaped form of a formatting argument.
+// template.HTML values are trusted as they are, and numbers and booleans are kept so numeric verbs keep working.
+// Any other value, i.e. strings, errors, Stringers, slices, maps or structs, is formatted with fmt.Sprint and escaped,
+// byte slices being escaped as strings.
+func escapeHTMLArg(v interface{}) interface{} {
+	switch t := v.(type) {
+	case template.HTML:
+		return string(t)
+	case []byte:
+		return template.HTMLEscapeString(string(t))
+	case error, fmt.Stringer:
+		return template.HTMLEscapeString(fmt.Sprint(t))
+	}
+
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return v
+	}
+
+	return template.HTMLEscapeString(fmt.Sprint(v))
+}
+
+// PrintfHTML formats a translated string as HTML: the markup of the format is sanitized with SanitizeHTML,
+// and the arguments are escaped, so neither translators nor user input can inject scripts.
+// Arguments holding trusted markup have to be passed as template.HTML.
+func PrintfHTML(format string, vars ...interface{}) template.HTML {
+	format = string(SanitizeHTML(format))
+	if len(vars) == 0 {
+		return template.HTML(format)
+	}
+
+	escaped := make([]interface{}, len(vars))
+	for i, v := range vars {
+		escaped[i] = escapeHTMLArg(v)
+	}
+
+	return template.HTML(Printf(format, escaped...))
+}
+
+// SprintfHTML is the named format version of PrintfHTML.
+//
+//	SprintfHTML("<strong>%(name)s</strong> joined", map[string]interface{}{"name": userName})
+func SprintfHTML(format string, params map[string]interface{}) template.HTML {
+	escaped := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		escaped[k] = escapeHTMLArg(v)
+	}
+	return template.HTML(Sprintf(string(SanitizeHTML(format)), escaped))
+}
+
+// GetHTML returns the corresponding Translation of a given string as HTML, formatted with PrintfHTML.
+func (l *Locale) GetHTML(str string, vars ...interface{}) template.HTML {
+	return PrintfHTML(l.Get(str), vars...)
+}
+
+// GetNHTML retrieves the (N)th plural form of Translation for the given string as HTML, formatted with PrintfHTML.
+func (l *Locale) GetNHTML(str, plural string, n int, vars ...interface{}) template.HTML {
+	return PrintfHTML(l.GetN(str, plural, n), vars...)
+}
+
+// GetCHTML returns the corresponding Translation of the given string in the given context as HTML, formatted with PrintfHTML.
+func (l *Locale) GetCHTML(str, ctx string, vars ...interface{}) template.HTML {
+	return PrintfHTML(l.GetC(str, ctx), vars...)
+}
+
+// GetNCHTML retrieves the (N)th plural form of Translation for the given string in the given context as HTML,
+// formatted with PrintfHTML.
+func (l *Locale) GetNCHTML(str, plural string, n int, ctx string, vars ...interface{}) template.HTML {
+	return PrintfHTML(l.GetNC(str, plural, n, ctx), vars...)
+}
+
+// GetHTML uses the default domain globally set to return the corresponding Translation of a given string as HTML,
+// formatted with PrintfHTML.
+func GetHTML(str string, vars ...interface{}) template.HTML {
+	return PrintfHTML(Get(str), vars...)
+}
+
+// GetNHTML retrieves the (N)th plural form of Translation for the given string in the default domain as HTML,
+// formatted with PrintfHTML.
+func GetNHTML(str, plural string, n int, vars ...interface{}) template.HTML {
+	return PrintfHTML(GetN(str, plural, n), vars...)
+}
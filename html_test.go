@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"testing"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	for _, test := range []struct {
+		in       string
+		tags     []string
+		expected template.HTML
+	}{
+		{"Hello <strong>world</strong>", nil, "Hello <strong>world</strong>"},
+		{`<b onclick="alert(1)">Hi</b><br/>`, nil, "<b>Hi</b><br/>"},
+		{`<script>alert("x")</script>`, nil, "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;"},
+		{`<a href="javascript:alert(1)">link</a>`, nil, "&lt;a href=&#34;javascript:alert(1)&#34;&gt;link&lt;/a&gt;"},
+		{"Tom &amp; Jerry & co &#8212; 3 < 4", nil, "Tom &amp; Jerry &amp; co &#8212; 3 &lt; 4"},
+		{"<EM>x</EM> <strong>y</strong>", []string{"em"}, "<em>x</em> &lt;strong&gt;y&lt;/strong&gt;"},
+	} {
+		if got := SanitizeHTML(test.in, test.tags...); got != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, got)
+		}
+	}
+}
+
+func TestPrintfHTML(t *testing.T) {
+	for _, test := range []struct {
+		got, expected template.HTML
+	}{
+		{PrintfHTML("Hello <strong>%s</strong>", "<img src=x onerror=alert(1)>"), "Hello <strong>&lt;img src=x onerror=alert(1)&gt;</strong>"},
+		{PrintfHTML("%d <em>items</em>", 3), "3 <em>items</em>"},
+		{PrintfHTML("Error: %v", errors.New("<bad>")), "Error: &lt;bad&gt;"},
+		{PrintfHTML("Go to %s", template.HTML(`<a href="/home">home</a>`)), `Go to <a href="/home">home</a>`},
+		{SprintfHTML("<b>%(name)s</b> joined", map[string]interface{}{"name": "<i>Eve</i>"}), "<b>&lt;i&gt;Eve&lt;/i&gt;</b> joined"},
+		{PrintfHTML("%t, %.1f and %x", true, 1.5, 255), "true, 1.5 and ff"},
+	} {
+		if test.got != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.got)
+		}
+	}
+}
+
+func TestPrintfHTMLComposite(t *testing.T) {
+	type profile struct {
+		Name string
+		Bio  *string
+	}
+	bio := "<img src=x onerror=alert(1)>"
+
+	// Slices, maps, structs and pointers are formatted as with %v, then escaped
+	for _, test := range []struct {
+		got, expected template.HTML
+	}{
+		{PrintfHTML("Tags: %v", []string{"<script>x</script>", "go"}), "Tags: [&lt;script&gt;x&lt;/script&gt; go]"},
+		{PrintfHTML("User: %v", profile{Name: "<img src=x onerror=alert(1)>"}), "User: {&lt;img src=x onerror=alert(1)&gt; &lt;nil&gt;}"},
+		{PrintfHTML("User: %v", &profile{Name: "Eve", Bio: &bio}), template.HTML("User: &amp;{Eve " + fmt.Sprint(&bio) + "}")},
+		{PrintfHTML("Meta: %v", map[string]string{"<k>": "<v>"}), "Meta: map[&lt;k&gt;:&lt;v&gt;]"},
+		{SprintfHTML("Tags: %(tags)v", map[string]interface{}{"tags": []string{"<b>"}}), "Tags: [&lt;b&gt;]"},
+	} {
+		if test.got != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.got)
+		}
+	}
+}
+
+func TestLocaleGetHTML(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`
+msgid "Welcome <b>%s</b>"
+msgstr "Bienvenue <b>%s</b><script>steal()</script>"
+`))
+
+	l := NewLocale("", "fr")
+	l.AddTranslator("default", po)
+
+	expected := template.HTML("Bienvenue <b>&lt;Mallory&gt;</b>&lt;script&gt;steal()&lt;/script&gt;")
+	if got := l.GetHTML("Welcome <b>%s</b>", "<Mallory>"); got != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, got)
+	}
+	if got := l.GetNHTML("<i>%d</i> file", "<i>%d</i> files", 2, 2); got != "<i>2</i> files" {
+		t.Errorf("Expected '<i>2</i> files' but got '%s'", got)
+	}
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "net/http"
+
+// RequestLocale returns the Locale matching the languages of an HTTP request found by the given Resolvers, by priority.
+// The Accept-Language header is used when no Resolver is given.
+// Locales for other languages than l are created with Locale.Match, l itself is returned when none matches.
+func RequestLocale(l *Locale, r *http.Request, resolvers ...Resolver) *Locale {
+	if len(resolvers) == 0 {
+		return l.Match(AcceptLanguageResolver().Resolve(r)...)
+	}
+
+	return l.Match(ResolverChain(resolvers).Resolve(r)...)
+}
+
+/*
+Middleware returns an HTTP middleware injecting in the context of each request the Locale returned by RequestLocale,
+resolved with the given Resolvers. Handlers retrieve it with FromContext.
+
+Example:
+
+	l := gotext.NewLocale("/path/to/i18n/dir", "en_US")
+	l.AddDomain("default")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, gotext.FromContext(r.Context()).Get("Hello"))
+	})
+
+	http.ListenAndServe(":8080", gotext.Middleware(l)(mux))
+*/
+func Middleware(l *Locale, resolvers ...Resolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), RequestLocale(l, r, resolvers...))))
+		})
+	}
+}
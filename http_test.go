@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	l.AddDomain("default")
+
+	h := Middleware(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(FromContext(r.Context()).Get("language")))
+	}))
+
+	for _, test := range []struct {
+		header, expected string
+	}{
+		{"fr-FR, en;q=0.5", "fr"},
+		{"xx, de-DE;q=0.9", "de_DE"},
+		{"", "en_US"},
+	} {
+		r := httptest.NewRequest("GET", "/", nil)
+		if test.header != "" {
+			r.Header.Set("Accept-Language", test.header)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if got := w.Body.String(); got != test.expected {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", test.expected, test.header, got)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// interning is set to 1 when parsers should intern strings. See SetInterning.
+var interning int32
+
+// internTable holds the canonical copy of every interned string.
+var internTable = struct {
+	sync.Mutex
+	strings map[string]string
+}{
+	strings: make(map[string]string),
+}
+
+// SetInterning enables or disables string interning for catalogs parsed afterwards.
+// When enabled, identical msgids, plural ids, contexts and translations are stored only once in memory and shared
+// by every catalog, which greatly reduces heap usage for very large catalogs and for the same domain loaded in many languages.
+// Interned strings are kept for the lifetime of the process, so it's meant for catalogs loaded at startup rather than
+// for catalogs frequently reloaded with changing contents.
+func SetInterning(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&interning, 1)
+	} else {
+		atomic.StoreInt32(&interning, 0)
+	}
+}
+
+// intern returns the canonical copy of s when interning is enabled, or s itself otherwise.
+func intern(s string) string {
+	if s == "" || atomic.LoadInt32(&interning) == 0 {
+		return s
+	}
+
+	internTable.Lock()
+	defer internTable.Unlock()
+
+	if c, ok := internTable.strings[s]; ok {
+		return c
+	}
+	internTable.strings[s] = s
+
+	return s
+}
+
+// internTranslation replaces the strings of a Translation being built with their canonical copies.
+// It must not be used on Translations already published to lookups.
+func internTranslation(tr *Translation) {
+	if atomic.LoadInt32(&interning) == 0 {
+		return
+	}
+
+	tr.ID = intern(tr.ID)
+	tr.PluralID = intern(tr.PluralID)
+	for i, s := range tr.Trs {
+		tr.Trs[i] = intern(s)
+	}
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+// stringData returns the address of the bytes backing s.
+func stringData(s string) uintptr {
+	return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+}
+
+func TestInterning(t *testing.T) {
+	SetInterning(true)
+	defer SetInterning(false)
+
+	po := NewPo()
+	po.ParseFile("fixtures/en_US/default.po")
+	mo := NewMo()
+	mo.ParseFile("fixtures/en_US/default.mo")
+
+	// Same msgid and translation parsed from different catalogs share memory
+	poTr := po.GetDomain().load().entries[entryKey{id: "My text"}]
+	moTr := mo.GetDomain().load().entries[entryKey{id: "My text"}]
+	if poTr == nil || moTr == nil {
+		t.Fatal("Expected 'My text' to be translated in both catalogs")
+	}
+	if stringData(poTr.ID) != stringData(moTr.ID) {
+		t.Error("Expected msgids to be interned")
+	}
+	if stringData(poTr.Trs[0]) != stringData(moTr.Trs[0]) {
+		t.Error("Expected translations to be interned")
+	}
+
+	// Lookups aren't affected
+	tr := mo.Get("My text")
+	if tr != translatedText {
+		t.Errorf("Expected '%s' but got '%s'", translatedText, tr)
+	}
+}
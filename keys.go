@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+// keyTranslation returns the translation of the key in the Backend, else def.
+func keyTranslation(b Backend, key, def string) string {
+	if t, ok := b.Lookup("", key); ok && t.Trs[0] != "" {
+		return t.Trs[0]
+	}
+	return def
+}
+
+// TKey returns the translation of the semantic key, i.e. "checkout.button.confirm", or def when it isn't translated.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (do *Domain) TKey(key, def string, vars ...interface{}) string {
+	return Printf(keyTranslation(do, key, def), vars...)
+}
+
+// TKey returns the translation of the semantic key, or def when it isn't translated, as Domain.TKey.
+func (po *Po) TKey(key, def string, vars ...interface{}) string {
+	return po.domain.TKey(key, def, vars...)
+}
+
+// TKey returns the translation of the semantic key, or def when it isn't translated, as Domain.TKey.
+func (mo *Mo) TKey(key, def string, vars ...interface{}) string {
+	return mo.domain.TKey(key, def, vars...)
+}
+
+/*
+TKey returns the translation of the semantic key, i.e. "checkout.button.confirm", in the default domain, or def when it
+isn't translated. It's meant for catalogs keyed by IDs instead of source strings, as with i18next: the source text is
+the translation of the keys in the catalog of the source language, and the default of the call sites until then.
+xgotext extracts the keys of TKey calls with their default text.
+Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+
+Example:
+
+	l := gotext.NewLocale("/path/to/i18n/dir", "de")
+	l.AddDomain("default")
+
+	fmt.Println(l.TKey("checkout.button.confirm", "Confirm"))
+	fmt.Println(l.TKey("cart.items", "%d items in your cart", 3))
+*/
+func (l *Locale) TKey(key, def string, vars ...interface{}) string {
+	return l.TKeyD(l.GetDomain(), key, def, vars...)
+}
+
+// TKeyD returns the translation of the semantic key in the given domain, or def when it isn't translated.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (l *Locale) TKeyD(dom, key, def string, vars ...interface{}) string {
+	snap := l.load()
+	tr := snap.domains[dom]
+	if snap.observer != nil {
+		l.observeLookup(snap.observer, dom, "", key, tr)
+	}
+	if tr != nil {
+		return Printf(keyTranslation(backendOf(tr), key, def), vars...)
+	}
+
+	return Printf(def, vars...)
+}
+
+// TKey returns the translation of the semantic key in the default domain globally set, or def when it isn't translated.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func TKey(key, def string, vars ...interface{}) string {
+	return TKeyD(GetDomain(), key, def, vars...)
+}
+
+// TKeyD returns the translation of the semantic key in the given domain, or def when it isn't translated.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func TKeyD(dom, key, def string, vars ...interface{}) string {
+	// Try to load default package Locale storage
+	loadStorage(false)
+
+	// Return Translation
+	globalConfig.RLock()
+
+	if _, ok := globalConfig.storage.Domains[dom]; !ok {
+		globalConfig.storage.AddDomain(dom)
+	}
+
+	tr := globalConfig.storage.TKeyD(dom, key, def, vars...)
+	globalConfig.RUnlock()
+
+	return tr
+}
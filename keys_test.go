@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+const keysPo = `msgid ""
+msgstr ""
+"Language: de\n"
+
+msgid "checkout.button.confirm"
+msgstr "Bestätigen"
+
+msgid "cart.items"
+msgstr "%d Artikel im Warenkorb"
+
+msgid "cart.empty"
+msgstr ""
+`
+
+func TestTKey(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(keysPo))
+
+	tests := []struct {
+		tr, expected string
+	}{
+		{po.TKey("checkout.button.confirm", "Confirm"), "Bestätigen"},
+		{po.TKey("cart.items", "%d items in your cart", 3), "3 Artikel im Warenkorb"},
+		{po.TKey("cart.empty", "Your cart is empty"), "Your cart is empty"},
+		{po.TKey("cart.total", "Total: %s", "9 €"), "Total: 9 €"},
+	}
+	for _, test := range tests {
+		if test.tr != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.tr)
+		}
+	}
+}
+
+func TestLocaleTKey(t *testing.T) {
+	// Create Locales directory
+	dirname := path.Join("/tmp", "gotextkeys", "de", "LC_MESSAGES")
+	err := os.MkdirAll(dirname, os.ModePerm)
+	if err != nil {
+		t.Fatalf("Can't create test directory: %s", err.Error())
+	}
+
+	// Write PO content to file
+	err = ioutil.WriteFile(path.Join(dirname, "keys.po"), []byte(keysPo), 0644)
+	if err != nil {
+		t.Fatalf("Can't write to test file: %s", err.Error())
+	}
+
+	l := NewLocale("/tmp/gotextkeys", "de")
+	l.AddDomain("keys")
+
+	if tr := l.TKeyD("keys", "checkout.button.confirm", "Confirm"); tr != "Bestätigen" {
+		t.Errorf("Expected '%s' but got '%s'", "Bestätigen", tr)
+	}
+	if tr := l.TKeyD("keys", "cart.empty", "Your cart is empty"); tr != "Your cart is empty" {
+		t.Errorf("Expected '%s' but got '%s'", "Your cart is empty", tr)
+	}
+	if tr := l.TKeyD("missing", "cart.items", "%d items in your cart", 2); tr != "2 items in your cart" {
+		t.Errorf("Expected '%s' but got '%s'", "2 items in your cart", tr)
+	}
+
+	l.SetDomain("keys")
+	if tr := l.TKey("cart.items", "%d items in your cart", 2); tr != "2 Artikel im Warenkorb" {
+		t.Errorf("Expected '%s' but got '%s'", "2 Artikel im Warenkorb", tr)
+	}
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leonelquinteros/gotext/plurals"
+)
+
+// KVStore is the minimal interface of a remote key-value store (Redis, Memcached, etcd...) holding translations.
+// Get returns found == false for missing keys, and an error only when the store can't be reached.
+type KVStore interface {
+	Get(key string) (value string, found bool, err error)
+}
+
+// KVStoreFunc adapts a function to the KVStore interface.
+type KVStoreFunc func(key string) (string, bool, error)
+
+// Get calls f(key).
+func (f KVStoreFunc) Get(key string) (string, bool, error) {
+	return f(key)
+}
+
+// DefaultKVMaxEntries is the default amount of entries a KVBackend caches locally.
+const DefaultKVMaxEntries = 10000
+
+// kvEntry is a cached KVBackend lookup result. A nil Translation caches a missing key.
+type kvEntry struct {
+	tr          *Translation
+	pluralforms plurals.Expression
+	expires     time.Time
+}
+
+/*
+KVBackend is a Backend reading translations from a remote key-value store, so a fleet of services can share
+centrally updated translations without redeploys. Lookups are cached locally for a TTL, and missing keys are cached
+as well for a (usually shorter) negative TTL. If the store can't be reached, expired entries keep being served,
+and the failed lookups are cached for the negative TTL too, so the store isn't queried on every lookup during outages.
+The local cache holds up to DefaultKVMaxEntries entries, see SetMaxEntries.
+
+Keys are formed with the MO file conventions: the prefix followed by the msgid, with the context and a "\x04"
+separator before it when there's one. Values hold the translation, and plural forms separated by "\x00".
+The catalog header (i.e. "Plural-Forms: nplurals=2; plural=(n != 1);\n") is stored at the prefix key itself.
+
+Example using github.com/redis/go-redis:
+
+	store := gotext.KVStoreFunc(func(key string) (string, bool, error) {
+		v, err := rdb.Get(ctx, key).Result()
+		if err == redis.Nil {
+			return "", false, nil
+		}
+		return v, err == nil, err
+	})
+
+	l := gotext.NewLocale("", "de")
+	l.AddTranslator("default", gotext.NewBackendTranslator(gotext.NewKVBackend(store, "i18n:default:de:", time.Minute, 10*time.Second)))
+*/
+type KVBackend struct {
+	store       KVStore
+	prefix      string
+	ttl         time.Duration
+	negativeTTL time.Duration
+
+	cache      map[entryKey]*kvEntry
+	maxEntries int
+	mutex      sync.RWMutex
+
+	// now returns the current time, replaced by tests.
+	now func() time.Time
+}
+
+// NewKVBackend returns a Backend reading keys starting with prefix from store.
+// Found entries are cached for ttl, and missing ones for negativeTTL.
+func NewKVBackend(store KVStore, prefix string, ttl, negativeTTL time.Duration) *KVBackend {
+	return &KVBackend{
+		store:       store,
+		prefix:      prefix,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		cache:       make(map[entryKey]*kvEntry),
+		maxEntries:  DefaultKVMaxEntries,
+		now:         time.Now,
+	}
+}
+
+// SetMaxEntries sets the amount of entries cached locally, DefaultKVMaxEntries by default.
+// Once full, expired entries are dropped first, then arbitrary ones down to 90% of n. Zero or less doesn't bound the cache.
+func (b *KVBackend) SetMaxEntries(n int) {
+	b.mutex.Lock()
+	b.maxEntries = n
+	b.mutex.Unlock()
+}
+
+// Lookup returns the Translation stored for the given context (empty for none) and msgid.
+func (b *KVBackend) Lookup(ctx, id string) (*Translation, bool) {
+	e := b.entry(entryKey{ctx: ctx, id: id})
+	return e.tr, e.tr != nil
+}
+
+// PluralForm returns the index of the plural form to use for n, according to the Plural-Forms of the stored header.
+func (b *KVBackend) PluralForm(n int) int {
+	c := catalog{pluralforms: b.entry(entryKey{}).pluralforms}
+	return c.pluralForm(n)
+}
+
+// Purge drops all locally cached entries.
+func (b *KVBackend) Purge() {
+	b.mutex.Lock()
+	b.cache = make(map[entryKey]*kvEntry)
+	b.mutex.Unlock()
+}
+
+// entry returns the cached entry for k, fetching it from the store when missing or expired.
+func (b *KVBackend) entry(k entryKey) *kvEntry {
+	now := b.now()
+
+	b.mutex.RLock()
+	e, ok := b.cache[k]
+	b.mutex.RUnlock()
+	if ok && now.Before(e.expires) {
+		return e
+	}
+
+	key := b.prefix + k.id
+	if k.ctx != "" {
+		key = b.prefix + k.ctx + "\x04" + k.id
+	}
+
+	v, found, err := b.store.Get(key)
+	if err != nil {
+		// Serve the expired entry, if any, until the store is back, querying it again after the negative TTL.
+		failed := &kvEntry{expires: now.Add(b.negativeTTL)}
+		if ok {
+			failed.tr, failed.pluralforms = e.tr, e.pluralforms
+		}
+		b.cacheEntry(k, failed)
+		return failed
+	}
+
+	e = &kvEntry{expires: now.Add(b.negativeTTL)}
+	if found {
+		e.expires = now.Add(b.ttl)
+		e.tr = NewTranslation()
+		e.tr.ID = k.id
+		for i, str := range strings.Split(v, "\x00") {
+			e.tr.Trs[i] = str
+		}
+		if k == (entryKey{}) {
+			e.pluralforms = headerPluralForms(v)
+		}
+	}
+
+	b.cacheEntry(k, e)
+
+	return e
+}
+
+// cacheEntry caches the entry for k, making room for it when the cache is full. Expired entries are dropped first,
+// then arbitrary ones, down to 90% of the maximum so the cache isn't scanned again on the next inserts.
+func (b *KVBackend) cacheEntry(k entryKey, e *kvEntry) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if _, ok := b.cache[k]; !ok && b.maxEntries > 0 && len(b.cache) >= b.maxEntries {
+		low := b.maxEntries - b.maxEntries/10 - 1
+		now := b.now()
+		for key, old := range b.cache {
+			if !now.Before(old.expires) {
+				delete(b.cache, key)
+			}
+		}
+		for key := range b.cache {
+			if len(b.cache) <= low {
+				break
+			}
+			delete(b.cache, key)
+		}
+	}
+	b.cache[k] = e
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestKVBackend(t *testing.T) {
+	data := map[string]string{
+		"de:":             "Plural-Forms: nplurals=2; plural=(n != 1);\n",
+		"de:Hello":        "Hallo",
+		"de:Menu\x04Open": "Öffnen",
+		"de:%d file":      "%d Datei\x00%d Dateien",
+		"de:%d folder":    "%d Ordner",
+	}
+	gets := 0
+	var storeErr error
+	store := KVStoreFunc(func(key string) (string, bool, error) {
+		gets++
+		if storeErr != nil {
+			return "", false, storeErr
+		}
+		v, ok := data[key]
+		return v, ok, nil
+	})
+
+	now := time.Now()
+	b := NewKVBackend(store, "de:", time.Minute, time.Second)
+	b.now = func() time.Time { return now }
+
+	l := NewLocale("", "de")
+	l.AddTranslator("default", NewBackendTranslator(b))
+
+	for _, test := range []struct{ expected, got string }{
+		{"Hallo", l.Get("Hello")},
+		{"Öffnen", l.GetC("Open", "Menu")},
+		{"1 Datei", l.GetN("%d file", "%d files", 1, 1)},
+		{"3 Dateien", l.GetN("%d file", "%d files", 3, 3)},
+		{"Missing", l.Get("Missing")},
+		// Values hold no plural msgid, the one of the lookup is returned for missing forms
+		{"1 Ordner", l.GetN("%d folder", "%d folders", 1, 1)},
+		{"3 folders", l.GetN("%d folder", "%d folders", 3, 3)},
+	} {
+		if test.expected != test.got {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.got)
+		}
+	}
+
+	// Positive and negative results are cached
+	gets = 0
+	data["de:Hello"] = "Servus"
+	l.Get("Hello")
+	l.Get("Missing")
+	if gets != 0 {
+		t.Errorf("Expected cached lookups but got %d store reads", gets)
+	}
+
+	// Negative results expire first
+	now = now.Add(2 * time.Second)
+	data["de:Missing"] = "Fehlt"
+	if tr := l.Get("Missing"); tr != "Fehlt" {
+		t.Errorf("Expected 'Fehlt' but got '%s'", tr)
+	}
+	if tr := l.Get("Hello"); tr != "Hallo" {
+		t.Errorf("Expected cached 'Hallo' but got '%s'", tr)
+	}
+
+	// Expired entries are served while the store is down
+	now = now.Add(time.Hour)
+	storeErr = errors.New("connection refused")
+	if tr := l.Get("Hello"); tr != "Hallo" {
+		t.Errorf("Expected stale 'Hallo' but got '%s'", tr)
+	}
+
+	// Failed lookups are cached for the negative TTL, with or without a stale entry
+	gets = 0
+	for i := 0; i < 3; i++ {
+		l.Get("Hello")
+		l.Get("Unknown")
+	}
+	if gets != 1 {
+		t.Errorf("Expected 1 store read during the outage but got %d", gets)
+	}
+
+	storeErr = nil
+	if tr := l.Get("Hello"); tr != "Hallo" {
+		t.Errorf("Expected stale 'Hallo' but got '%s'", tr)
+	}
+	now = now.Add(2 * time.Second)
+	if tr := l.Get("Hello"); tr != "Servus" {
+		t.Errorf("Expected 'Servus' but got '%s'", tr)
+	}
+}
+
+func TestKVBackendMaxEntries(t *testing.T) {
+	store := KVStoreFunc(func(key string) (string, bool, error) {
+		return "", false, nil
+	})
+
+	now := time.Now()
+	b := NewKVBackend(store, "de:", time.Minute, time.Second)
+	b.now = func() time.Time { return now }
+	b.SetMaxEntries(100)
+
+	for i := 0; i < 100; i++ {
+		b.Lookup("", fmt.Sprintf("msgid %d", i))
+	}
+	if n := len(b.cache); n != 100 {
+		t.Errorf("Expected %d cached entries but got %d", 100, n)
+	}
+
+	// Entries are evicted in bulk, down to 90%
+	b.Lookup("", "msgid 100")
+	if n := len(b.cache); n != 90 {
+		t.Errorf("Expected %d cached entries but got %d", 90, n)
+	}
+	for i := 101; i < 250; i++ {
+		b.Lookup("", fmt.Sprintf("msgid %d", i))
+		if n := len(b.cache); n > 100 {
+			t.Fatalf("Expected at most %d cached entries but got %d", 100, n)
+		}
+	}
+
+	// Expired entries are dropped first
+	for i := 250; len(b.cache) < 100; i++ {
+		b.Lookup("", fmt.Sprintf("msgid %d", i))
+	}
+	now = now.Add(time.Hour)
+	b.Lookup("", "fresh")
+	if n := len(b.cache); n != 1 {
+		t.Errorf("Expected %d cached entries but got %d", 1, n)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"context"
+)
+
+/*
+LazyString is a translatable string whose lookup is deferred until it's rendered,
+so menus, enum labels and validation messages can be declared before the user language is known.
+It implements fmt.Stringer, rendering with the package level configuration, and Localize renders it for a given Locale.
+
+Example:
+
+	var statusLabels = map[Status]gotext.LazyString{
+		Active:   gotext.Lazy("Active"),
+		Disabled: gotext.LazyC("Disabled", "account status"),
+	}
+
+	func label(l *gotext.Locale, s Status) string {
+		return statusLabels[s].Localize(l)
+	}
+*/
+type LazyString struct {
+	// Domain is the domain the string is translated from. An empty Domain stands for the default domain.
+	Domain string
+
+	// Context is the optional msgctxt of the string.
+	Context string
+
+	// Msgid and Plural are the source string and its optional plural form, selected with N.
+	Msgid  string
+	Plural string
+	N      int
+
+	// Args are the formatting arguments of the string. Localizable arguments, like other LazyString
+	// or LocalizedError values, are rendered in the same Locale.
+	Args []interface{}
+}
+
+// Lazy returns a LazyString translated from the default domain.
+func Lazy(msgid string, args ...interface{}) LazyString {
+	return LazyString{Msgid: msgid, Args: args}
+}
+
+// LazyD returns a LazyString translated from the given domain.
+func LazyD(dom, msgid string, args ...interface{}) LazyString {
+	return LazyString{Domain: dom, Msgid: msgid, Args: args}
+}
+
+// LazyN returns a LazyString for the (N)th plural form of msgid, translated from the default domain.
+func LazyN(msgid, plural string, n int, args ...interface{}) LazyString {
+	return LazyString{Msgid: msgid, Plural: plural, N: n, Args: args}
+}
+
+// LazyC returns a LazyString for msgid in the given context, translated from the default domain.
+func LazyC(msgid, ctx string, args ...interface{}) LazyString {
+	return LazyString{Context: ctx, Msgid: msgid, Args: args}
+}
+
+// String renders the string with the package level configuration (see Configure).
+func (s LazyString) String() string {
+	dom := s.Domain
+	if dom == "" {
+		dom = GetDomain()
+	}
+
+	switch {
+	case s.Plural != "" && s.Context != "":
+		return GetNDC(dom, s.Msgid, s.Plural, s.N, s.Context, s.Args...)
+	case s.Plural != "":
+		return GetND(dom, s.Msgid, s.Plural, s.N, s.Args...)
+	case s.Context != "":
+		return GetDC(dom, s.Msgid, s.Context, s.Args...)
+	}
+
+	return GetD(dom, s.Msgid, s.Args...)
+}
+
+// Localize renders the string for the given Locale. A nil Locale renders it as String does.
+func (s LazyString) Localize(l *Locale) string {
+	if l == nil {
+		return s.String()
+	}
+
+	dom := s.Domain
+	if dom == "" {
+		dom = l.GetDomain()
+	}
+	args := localizeArgs(l, s.Args)
+
+	switch {
+	case s.Plural != "" && s.Context != "":
+		return l.GetNDC(dom, s.Msgid, s.Plural, s.N, s.Context, args...)
+	case s.Plural != "":
+		return l.GetND(dom, s.Msgid, s.Plural, s.N, args...)
+	case s.Context != "":
+		return l.GetDC(dom, s.Msgid, s.Context, args...)
+	}
+
+	return l.GetD(dom, s.Msgid, args...)
+}
+
+// LocalizeContext renders the string for the Locale carried by ctx (see NewContext),
+// or with the package level configuration if there's none.
+func (s LazyString) LocalizeContext(ctx context.Context) string {
+	return s.Localize(FromContext(ctx))
+}
+
+// source renders the string in the source language.
+func (s LazyString) source() string {
+	str := s.Msgid
+	if s.Plural != "" && s.N != 1 {
+		str = s.Plural
+	}
+
+	return Printf(str, localizeArgs(nil, s.Args)...)
+}
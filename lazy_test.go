@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	labels := []LazyString{
+		Lazy("My text"),
+		Lazy("language"),
+		LazyN("One with var: %s", "Several with vars: %s", 2, "lazy"),
+		LazyC("Some random in a context", "Ctx"),
+	}
+
+	l := NewLocale("fixtures/", "fr")
+	l.AddDomain("default")
+
+	expected := []string{translatedText, "fr", "This one is the plural: lazy", "Some random translation in a context"}
+	for i, label := range labels {
+		if got := label.Localize(l); got != expected[i] {
+			t.Errorf("Expected '%s' but got '%s'", expected[i], got)
+		}
+	}
+
+	de := NewLocale("fixtures/", "de")
+	de.AddDomain("default")
+	if got := labels[1].LocalizeContext(NewContext(context.Background(), de)); got != "de" {
+		t.Errorf("Expected 'de' but got '%s'", got)
+	}
+
+	// Localizable arguments are rendered in the same Locale.
+	nested := Lazy("One with var: %s", Lazy("language"))
+	if got := nested.Localize(de); got != "This one is the singular: de" {
+		t.Errorf("Expected 'This one is the singular: de' but got '%s'", got)
+	}
+}
+
+func TestLazyString(t *testing.T) {
+	fixPath, _ := filepath.Abs("./fixtures/")
+	Configure(fixPath, "en_GB", "default")
+
+	label := Lazy("language")
+	if got := fmt.Sprint(label); got != "en_GB" {
+		t.Errorf("Expected 'en_GB' but got '%s'", got)
+	}
+
+	// The lookup happens when the string is rendered.
+	SetLanguage("en_AU")
+	if got := label.String(); got != "en_AU" {
+		t.Errorf("Expected 'en_AU' but got '%s'", got)
+	}
+}
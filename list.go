@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "strings"
+
+// ListContext is the msgctxt of the patterns looked up by Locale.FormatList and Locale.FormatListOr.
+const ListContext = "list"
+
+// listPatterns are the patterns joining the items of a list: pairs of items, the items before the last two,
+// and the last two.
+type listPatterns struct {
+	pair, middle, end string
+}
+
+var (
+	englishAnd = listPatterns{"%s and %s", "%s, %s", "%s, and %s"}
+	englishOr  = listPatterns{"%s or %s", "%s, %s", "%s, or %s"}
+)
+
+// commaList returns the patterns of languages joining the items with commas and a word before the last one.
+func commaList(word string) listPatterns {
+	return listPatterns{"%s " + word + " %s", "%s, %s", "%s " + word + " %s"}
+}
+
+// listData holds the conjunction and disjunction patterns of languages, from the CLDR list patterns.
+var listData = map[string][2]listPatterns{
+	"cs": {commaList("a"), commaList("nebo")},
+	"da": {commaList("og"), commaList("eller")},
+	"de": {commaList("und"), commaList("oder")},
+	"es": {commaList("y"), commaList("o")},
+	"fi": {commaList("ja"), commaList("tai")},
+	"fr": {commaList("et"), commaList("ou")},
+	"it": {commaList("e"), commaList("o")},
+	"ja": {{"%s、%s", "%s、%s", "%s、%s"}, {"%sまたは%s", "%s、%s", "%s、または%s"}},
+	"ko": {commaList("및"), commaList("또는")},
+	"nb": {commaList("og"), commaList("eller")},
+	"nl": {commaList("en"), commaList("of")},
+	"pl": {commaList("i"), commaList("lub")},
+	"pt": {commaList("e"), commaList("ou")},
+	"ru": {commaList("и"), commaList("или")},
+	"sv": {commaList("och"), commaList("eller")},
+	"tr": {commaList("ve"), commaList("veya")},
+	"uk": {commaList("і"), commaList("або")},
+	"zh": {{"%s和%s", "%s、%s", "%s和%s"}, {"%s或%s", "%s、%s", "%s或%s"}},
+}
+
+// listPattern returns the translation of an English pattern in the default domain with the ListContext,
+// or else the pattern of the language of the Locale.
+func (l *Locale) listPattern(english, builtin string) string {
+	if tr := l.GetC(english, ListContext); tr != english {
+		return tr
+	}
+
+	return builtin
+}
+
+// formatList joins the items with the English patterns, translated by the catalogs or the bundled patterns.
+func (l *Locale) formatList(items []string, english listPatterns, or bool) string {
+	builtin := english
+	lang := strings.SplitN(l.GetLanguage(), "_", 2)[0]
+	if data, ok := listData[lang]; ok {
+		builtin = data[0]
+		if or {
+			builtin = data[1]
+		}
+	}
+
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	case 2:
+		return Printf(l.listPattern(english.pair, builtin.pair), items[0], items[1])
+	}
+
+	s := Printf(l.listPattern(english.end, builtin.end), items[len(items)-2], items[len(items)-1])
+	middle := l.listPattern(english.middle, builtin.middle)
+	for i := len(items) - 3; i >= 0; i-- {
+		s = Printf(middle, items[i], s)
+	}
+
+	return s
+}
+
+/*
+FormatList joins the items as a conjunction, "a, b, and c" in English or "a, b et c" in French, following the
+CLDR list patterns bundled for Czech, Danish, Dutch, English, Finnish, French, German, Italian, Japanese, Korean,
+Norwegian Bokmål, Polish, Portuguese, Russian, Spanish, Swedish, Turkish, Ukrainian and Chinese.
+Other languages get the English patterns unless their catalogs translate them.
+
+The patterns of the bundled languages can be replaced, and the ones of other languages provided, by the default
+domain of the Locale with the ListContext: msgid "%s and %s" joins two items, "%s, %s" all of them but the last
+two, and "%s, and %s" the last two.
+
+Example:
+
+	l.FormatList([]string{"Anna", "Bea", "Chris"}) // "Anna, Bea, and Chris"
+*/
+func (l *Locale) FormatList(items []string) string {
+	return l.formatList(items, englishAnd, false)
+}
+
+// FormatListOr joins the items as a disjunction, "a, b, or c", as FormatList with the patterns "%s or %s",
+// "%s, %s" and "%s, or %s".
+func (l *Locale) FormatListOr(items []string) string {
+	return l.formatList(items, englishOr, true)
+}
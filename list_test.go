@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestFormatList(t *testing.T) {
+	dirname := path.Join("/tmp", "formatlist")
+	if err := os.MkdirAll(path.Join(dirname, "fr", "LC_MESSAGES"), os.ModePerm); err != nil {
+		t.Fatalf("Can't create test directory: %s", err.Error())
+	}
+	defer os.RemoveAll(dirname)
+
+	str := `msgid ""
+msgstr ""
+"Language: fr\n"
+
+msgctxt "list"
+msgid "%s and %s"
+msgstr "%s et %s"
+
+msgctxt "list"
+msgid "%s, and %s"
+msgstr "%s et %s"
+
+msgctxt "list"
+msgid "%s or %s"
+msgstr "%s ou bien %s"
+
+msgctxt "list"
+msgid "%s, or %s"
+msgstr "%s ou %s"
+`
+	err := ioutil.WriteFile(path.Join(dirname, "fr", "LC_MESSAGES", "default.po"), []byte(str), os.ModePerm)
+	if err != nil {
+		t.Fatalf("Can't write test file: %s", err.Error())
+	}
+
+	fr := NewLocale(dirname, "fr")
+	fr.AddDomain("default")
+	en := NewLocale(dirname, "en")
+	de := NewLocale(dirname, "de_AT")
+	ja := NewLocale(dirname, "ja")
+	zh := NewLocale(dirname, "zh_Hant_TW")
+	xx := NewLocale(dirname, "xx")
+
+	tests := []struct {
+		tr, expected string
+	}{
+		{en.FormatList(nil), ""},
+		{en.FormatList([]string{"a"}), "a"},
+		{en.FormatList([]string{"a", "b"}), "a and b"},
+		{en.FormatList([]string{"a", "b", "c"}), "a, b, and c"},
+		{en.FormatList([]string{"a", "b", "c", "d"}), "a, b, c, and d"},
+		{en.FormatListOr([]string{"a", "b", "c"}), "a, b, or c"},
+		{en.FormatList([]string{"100%", "b"}), "100% and b"},
+		{fr.FormatList([]string{"a", "b"}), "a et b"},
+		{fr.FormatList([]string{"a", "b", "c"}), "a, b et c"},
+		{fr.FormatListOr([]string{"a", "b", "c", "d"}), "a, b, c ou d"},
+		{fr.FormatListOr([]string{"a", "b"}), "a ou bien b"},
+
+		// Bundled patterns
+		{de.FormatList([]string{"a", "b", "c"}), "a, b und c"},
+		{de.FormatListOr([]string{"a", "b"}), "a oder b"},
+		{ja.FormatList([]string{"a", "b", "c"}), "a、b、c"},
+		{zh.FormatList([]string{"a", "b", "c"}), "a、b和c"},
+		{xx.FormatList([]string{"a", "b", "c"}), "a, b, and c"},
+	}
+	for _, test := range tests {
+		if test.tr != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.tr)
+		}
+	}
+}
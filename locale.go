@@ -8,9 +8,14 @@ package gotext
 import (
 	"bytes"
 	"encoding/gob"
+	"fmt"
 	"os"
 	"path"
+	"runtime"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 /*
@@ -52,16 +57,89 @@ type Locale struct {
 	// Language for this Locale
 	lang string
 
+	// Ordered list of languages to look catalogs up for, starting with lang.
+	languages []string
+
 	// List of available Domains for this locale.
+	//
+	// Deprecated: lookups read an immutable snapshot of this map, so direct changes have no effect
+	// until Refresh is called, and they race with other changes. Use AddDomain and AddTranslator
+	// to modify it, and GetDomains and GetTranslator to read it.
 	Domains map[string]Translator
 
 	// First AddDomain is default Domain
 	defaultDomain string
 
+	// Override layers stacked on top of domains
+	overrides map[string]Translator
+
+	// Domains consulted, in order, for the strings missing in the requested one
+	fallbackDomains []string
+
+	// Provider of draft translations for missing ones, with the Translators caching them by domain
+	missing            MissingTranslator
+	missingTranslators map[string]Translator
+
+	// Receiver of translation events, if any
+	observer Observer
+
+	// Loose lookups of the domains loaded, see Domain.SetLooseLookup
+	looseLookup bool
+
+	// Fuzzy translations skipped by the domains loaded, see Domain.SetSkipFuzzy
+	skipFuzzy bool
+
+	// Strings of the empty translations of the domains loaded, see Domain.SetEmptyFunc
+	empty EmptyFunc
+
+	// Called with the strings formatted with mismatching arguments, see Locale.SetFormatCheck
+	formatCheck FormatCheckFunc
+
+	// Catalog files each domain was loaded from, and the candidate files that were missing
+	sources map[string][]string
+	absent  map[string][]string
+
+	// Immutable snapshot of Domains and defaultDomain used by lookups.
+	// It's swapped atomically on changes, so reads never block.
+	snapshot atomic.Value
+
+	// Catalogs loaded with AddDomainURL, by domain
+	remotes      map[string]*remoteCatalog
+	remotesMutex sync.Mutex
+
+	// Locales for other languages created by WithLanguage
+	views      map[string]*languageView
+	viewsMutex sync.Mutex
+
+	// Languages having catalogs for the domains of a snapshot, matched by Match
+	matchSnapshot  *localeSnapshot
+	matchLanguages map[string]bool
+	matchMutex     sync.Mutex
+
 	// Sync Mutex
 	sync.RWMutex
 }
 
+// localeSnapshot is an immutable copy of the Locale domains. It must never be modified after being published.
+type localeSnapshot struct {
+	domains       map[string]Translator
+	defaultDomain string
+
+	// Receiver of lookup events, if any
+	observer Observer
+
+	// Layers of the domains resolved with a chain of overrides, base catalog, fallback domains and missing translations.
+	layers map[string][]Layer
+}
+
+// languageView is a Locale for another language created by WithLanguage,
+// along with the Translators of this Locale its domains were loaded for.
+type languageView struct {
+	locale   *Locale
+	loaded   map[string]Translator
+	snapshot *localeSnapshot
+}
+
 // NewLocale creates and initializes a new Locale object for a given language.
 // It receives a path for the i18n .po/.mo files directory (p) and a language code to use (l).
 func NewLocale(p, l string) *Locale {
@@ -72,59 +150,227 @@ func NewLocale(p, l string) *Locale {
 	}
 }
 
-func (l *Locale) findExt(dom, ext string) string {
-	filename := path.Join(l.path, l.lang, "LC_MESSAGES", dom+"."+ext)
-	if _, err := os.Stat(filename); err == nil {
-		return filename
+// NewLocaleFromEnv creates and initializes a new Locale object using the languages configured in the environment.
+// Catalogs are looked up following the priority list returned by LanguagesFromEnv,
+// so LANGUAGE=fr_CH:fr:en loads the first of fr_CH, fr or en providing each domain.
+// It receives a path for the i18n .po/.mo files directory (p).
+func NewLocaleFromEnv(p string) *Locale {
+	langs := LanguagesFromEnv()
+	if len(langs) == 0 {
+		return NewLocale(p, "")
 	}
 
-	if len(l.lang) > 2 {
-		filename = path.Join(l.path, l.lang[:2], "LC_MESSAGES", dom+"."+ext)
-		if _, err := os.Stat(filename); err == nil {
-			return filename
+	l := NewLocale(p, langs[0])
+	l.languages = langs
+
+	return l
+}
+
+var emptyLocaleSnapshot = new(localeSnapshot)
+
+// load returns the current domains snapshot without locking.
+func (l *Locale) load() *localeSnapshot {
+	if snap, ok := l.snapshot.Load().(*localeSnapshot); ok {
+		return snap
+	}
+	return emptyLocaleSnapshot
+}
+
+// publish atomically replaces the snapshot used by lookups with a copy of the current domains.
+// It must be called while holding the write lock.
+func (l *Locale) publish() {
+	domains := make(map[string]Translator, len(l.Domains))
+	for k, v := range l.Domains {
+		domains[k] = v
+	}
+
+	var layers map[string][]Layer
+	if len(l.overrides) > 0 || l.missing != nil || len(l.fallbackDomains) > 0 {
+		layers = make(map[string][]Layer)
+		for k := range l.overrides {
+			if _, ok := domains[k]; !ok {
+				domains[k] = nil
+			}
+		}
+
+		for k, base := range domains {
+			var links []Translator
+			var ly []Layer
+			if tr, ok := l.overrides[k]; ok {
+				links = append(links, tr)
+				ly = append(ly, LayerOverride)
+			}
+			if base != nil {
+				links = append(links, base)
+				ly = append(ly, LayerBase)
+			}
+			for _, fd := range l.fallbackDomains {
+				if tr := l.Domains[fd]; tr != nil && fd != k {
+					links = append(links, tr)
+					ly = append(ly, LayerFallback)
+				}
+			}
+			if l.missing != nil {
+				links = append(links, l.missingTranslator(k))
+				ly = append(ly, LayerMissing)
+			}
+
+			domains[k] = NewChainTranslator(links...)
+			layers[k] = ly
 		}
 	}
 
-	filename = path.Join(l.path, l.lang, dom+"."+ext)
-	if _, err := os.Stat(filename); err == nil {
-		return filename
+	l.snapshot.Store(&localeSnapshot{
+		domains:       domains,
+		defaultDomain: l.defaultDomain,
+		layers:        layers,
+		observer:      l.observer,
+	})
+}
+
+// getLanguages returns the ordered list of languages to look catalogs up for.
+func (l *Locale) getLanguages() []string {
+	if len(l.languages) > 0 {
+		return l.languages
 	}
+	return []string{l.lang}
+}
 
-	if len(l.lang) > 2 {
-		filename = path.Join(l.path, l.lang[:2], dom+"."+ext)
+// findExt returns the catalog file with the given extension for a domain in exactly the given language.
+// The candidate files that don't exist are appended to absent, unless it's nil.
+func (l *Locale) findExt(lang, dom, ext string, absent *[]string) string {
+	for _, filename := range []string{
+		path.Join(l.path, lang, "LC_MESSAGES", dom+"."+ext),
+		path.Join(l.path, lang, dom+"."+ext),
+	} {
 		if _, err := os.Stat(filename); err == nil {
 			return filename
 		}
+		if absent != nil {
+			*absent = append(*absent, filename)
+		}
 	}
 
 	return ""
 }
 
+// findFiles returns the catalog files for a domain in the given language, from the most general to the most specific one.
+// i.e. for "pt_BR" it returns the "pt" catalog followed by the "pt_BR" one, when they exist.
+// PO files are preferred over MO files found at the same level.
+// The candidate files that don't exist are appended to absent, unless it's nil.
+func (l *Locale) findFiles(lang, dom string, absent *[]string) []string {
+	var files []string
+
+	subtags := strings.Split(lang, "_")
+	for i := range subtags {
+		tag := strings.Join(subtags[:i+1], "_")
+		if file := l.findExt(tag, dom, "po", absent); file != "" {
+			files = append(files, file)
+		} else if file := l.findExt(tag, dom, "mo", absent); file != "" {
+			files = append(files, file)
+		}
+	}
+
+	return files
+}
+
 // AddDomain creates a new domain for a given locale object and initializes the Po object.
+// Regional catalogs (i.e. pt_BR) are overlaid on top of their base language ones (i.e. pt),
+// so they only need to contain the translations that actually differ.
 // If the domain exists, it gets reloaded.
 func (l *Locale) AddDomain(dom string) {
+	l.AddDomainFiltered(dom, nil)
+}
+
+// AddDomainFiltered works like AddDomain, but only loads the entries selected by the given filter,
+// i.e. FilterContexts("billing") or FilterPrefix("billing."), so a service owning one feature area
+// doesn't pay the memory of a whole company-wide catalog.
+func (l *Locale) AddDomainFiltered(dom string, filter EntryFilter) {
+	l.addDomain(dom, filter)
+}
+
+// AddDomains loads the given domains in parallel, using at most concurrency goroutines (GOMAXPROCS if it's lower than 1).
+// The first given domain found becomes the default one if none was set before.
+// It returns an error listing the domains for which no catalog file was found, after loading all the others.
+func (l *Locale) AddDomains(concurrency int, doms ...string) error {
+	if concurrency < 1 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	setDefault := l.GetDomain() == ""
+
+	found := make([]bool, len(doms))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, dom := range doms {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, dom string) {
+			defer wg.Done()
+			found[i] = l.addDomain(dom, nil)
+			<-sem
+		}(i, dom)
+	}
+	wg.Wait()
+
+	var missing []string
+	for i, dom := range doms {
+		if !found[i] {
+			missing = append(missing, dom)
+			continue
+		}
+		if setDefault {
+			l.SetDomain(dom)
+			setDefault = false
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("no catalog found for domains: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// addDomain loads the catalog files for a domain, reporting if any was found.
+func (l *Locale) addDomain(dom string, filter EntryFilter) bool {
 	var poObj Translator
+	var files, absent []string
+
+	for _, lang := range l.getLanguages() {
+		files = l.findFiles(lang, dom, &absent)
+		if len(files) == 0 {
+			continue
+		}
 
-	file := l.findExt(dom, "po")
-	if file != "" {
-		poObj = NewPo()
-		// Parse file.
-		poObj.ParseFile(file)
-	} else {
-		file = l.findExt(dom, "mo")
-		if file != "" {
-			poObj = NewMo()
-			// Parse file.
+		// Parse every file into the same storage, most specific last.
+		domain := NewDomain()
+		domain.filter = filter
+		l.RLock()
+		l.configureDomain(dom, domain)
+		l.RUnlock()
+		for _, file := range files {
+			poObj = newTranslator(file, domain)
 			poObj.ParseFile(file)
-		} else {
-			// fallback return if no file found with
-			return
 		}
+		break
 	}
 
 	// Save new domain
 	l.Lock()
 
+	if l.absent == nil {
+		l.absent = make(map[string][]string)
+	}
+	l.absent[dom] = absent
+
+	// fallback return if no file found with
+	if poObj == nil {
+		l.Unlock()
+		return false
+	}
+
 	if l.Domains == nil {
 		l.Domains = make(map[string]Translator)
 	}
@@ -132,9 +378,17 @@ func (l *Locale) AddDomain(dom string) {
 		l.defaultDomain = dom
 	}
 	l.Domains[dom] = poObj
+	if l.sources == nil {
+		l.sources = make(map[string][]string)
+	}
+	l.sources[dom] = files
+	l.publish()
+	l.observeLoad(dom, poObj)
 
 	// Unlock "Save new domain"
 	l.Unlock()
+
+	return true
 }
 
 // AddTranslator takes a domain name and a Translator object to make it available in the Locale object.
@@ -148,25 +402,207 @@ func (l *Locale) AddTranslator(dom string, tr Translator) {
 		l.defaultDomain = dom
 	}
 	l.Domains[dom] = tr
+	delete(l.sources, dom)
+	delete(l.absent, dom)
+	if do := tr.GetDomain(); do != nil {
+		l.configureDomain(dom, do)
+	}
+	l.publish()
+	l.observeLoad(dom, tr)
 
 	l.Unlock()
 }
 
+// configureDomain applies the lookup settings of the Locale to the Domain of a domain being added, whether loaded from
+// files, added with AddTranslator, fetched or swapped by an Updater. Settings left to their defaults keep the ones of
+// the Domain. It must be called holding the lock.
+func (l *Locale) configureDomain(dom string, do *Domain) {
+	if !l.looseLookup && !l.skipFuzzy && l.empty == nil && l.formatCheck == nil {
+		return
+	}
+
+	do.trMutex.Lock()
+	if l.looseLookup {
+		do.looseLookup = true
+	}
+	if l.skipFuzzy {
+		do.skipFuzzy = true
+	}
+	if l.empty != nil {
+		do.empty = l.empty
+	}
+	if l.formatCheck != nil {
+		do.formatCheck = l.domainFormatCheck(dom)
+	}
+	do.publish()
+	do.trMutex.Unlock()
+}
+
+// swapTranslators atomically replaces several domains, so lookups see either all or none of the changes.
+// A nil Translator removes the domain. It returns the replaced Translators, to be restored by another call.
+func (l *Locale) swapTranslators(trs map[string]Translator) map[string]Translator {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.Domains == nil {
+		l.Domains = make(map[string]Translator)
+	}
+
+	previous := make(map[string]Translator, len(trs))
+	for dom, tr := range trs {
+		previous[dom] = l.Domains[dom]
+		if tr == nil {
+			delete(l.Domains, dom)
+			continue
+		}
+		if l.defaultDomain == "" {
+			l.defaultDomain = dom
+		}
+		l.Domains[dom] = tr
+		delete(l.sources, dom)
+		delete(l.absent, dom)
+		if do := tr.GetDomain(); do != nil {
+			l.configureDomain(dom, do)
+		}
+	}
+	l.publish()
+	for dom, tr := range trs {
+		l.observeLoad(dom, tr)
+	}
+
+	return previous
+}
+
+// GetLanguage is the language getter for Locale configuration.
+// The language code is returned in its normalized POSIX form (i.e. "en_US").
+func (l *Locale) GetLanguage() string {
+	return l.lang
+}
+
+// GetLanguageTag returns the Locale language as a canonical BCP 47 tag (i.e. "en-US").
+func (l *Locale) GetLanguageTag() string {
+	return LanguageTag(l.lang)
+}
+
 // GetDomain is the domain getter for Locale configuration
 func (l *Locale) GetDomain() string {
-	l.RLock()
-	dom := l.defaultDomain
-	l.RUnlock()
-	return dom
+	return l.load().defaultDomain
 }
 
 // SetDomain sets the name for the domain to be used.
+// Get, GetN, GetC and GetNC will lookup translations on this domain, which should be loaded using AddDomain or AddTranslator.
 func (l *Locale) SetDomain(dom string) {
 	l.Lock()
 	l.defaultDomain = dom
+	l.publish()
 	l.Unlock()
 }
 
+// GetTranslator returns the Translator loaded for a domain, or nil if there's none.
+func (l *Locale) GetTranslator(dom string) Translator {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.Domains[dom]
+}
+
+// Refresh makes lookups see the changes made directly to the Domains map.
+// It's meant for code that still writes Domains, which must not run concurrently with lookups or other changes.
+func (l *Locale) Refresh() {
+	l.Lock()
+	l.publish()
+	l.Unlock()
+}
+
+// GetDomains returns the sorted list of names of the domains loaded on this Locale.
+func (l *Locale) GetDomains() []string {
+	domains := l.load().domains
+	doms := make([]string, 0, len(domains))
+	for dom := range domains {
+		doms = append(doms, dom)
+	}
+
+	sort.Strings(doms)
+
+	return doms
+}
+
+// WithLanguage returns a Locale for the given language with the same path, domains and default domain as this one.
+// Catalogs are looked up for the given language first, then for the fallback languages of this Locale (see NewLocaleFromEnv).
+// Locales are created once per language and cached, so it's cheap to use it on every call,
+// i.e. to render an email in the recipient's language: l.WithLanguage(lang).Get("Welcome").
+// Domains added, reloaded or removed on this Locale afterwards are applied on the next call.
+func (l *Locale) WithLanguage(lang string) *Locale {
+	lang = SimplifiedLocale(lang)
+	if lang == l.lang {
+		return l
+	}
+
+	l.viewsMutex.Lock()
+	defer l.viewsMutex.Unlock()
+
+	if l.views == nil {
+		l.views = make(map[string]*languageView)
+	}
+
+	view, ok := l.views[lang]
+	if !ok {
+		view = &languageView{
+			locale: NewLocale(l.path, lang),
+			loaded: make(map[string]Translator),
+		}
+		if len(l.languages) > 1 {
+			view.locale.languages = []string{lang}
+			for _, fallback := range l.languages[1:] {
+				if fallback != lang {
+					view.locale.languages = append(view.locale.languages, fallback)
+				}
+			}
+		}
+		l.views[lang] = view
+	}
+
+	// Every change to this Locale publishes a new snapshot
+	snap := l.load()
+	if snap == view.snapshot {
+		return view.locale
+	}
+
+	l.RLock()
+	domains := make(map[string]Translator, len(l.Domains))
+	for dom, tr := range l.Domains {
+		domains[dom] = tr
+	}
+	l.RUnlock()
+
+	for dom, tr := range domains {
+		if loaded, ok := view.loaded[dom]; !ok || loaded != tr {
+			view.locale.AddDomain(dom)
+			view.loaded[dom] = tr
+		}
+	}
+	removed := make(map[string]Translator)
+	for dom := range view.loaded {
+		if _, ok := domains[dom]; !ok {
+			removed[dom] = nil
+			delete(view.loaded, dom)
+		}
+	}
+	if len(removed) > 0 {
+		view.locale.swapTranslators(removed)
+	}
+	view.locale.SetDomain(snap.defaultDomain)
+	view.snapshot = snap
+
+	return view.locale
+}
+
+// GetInLanguage returns the corresponding Translation of a given string in the given language, using the default domain.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (l *Locale) GetInLanguage(lang, str string, vars ...interface{}) string {
+	return l.WithLanguage(lang).Get(str, vars...)
+}
+
 // Get uses a domain "default" to return the corresponding Translation of a given string.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func (l *Locale) Get(str string, vars ...interface{}) string {
@@ -182,16 +618,13 @@ func (l *Locale) GetN(str, plural string, n int, vars ...interface{}) string {
 // GetD returns the corresponding Translation in the given domain for the given string.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func (l *Locale) GetD(dom, str string, vars ...interface{}) string {
-	// Sync read
-	l.RLock()
-	defer l.RUnlock()
-
-	if l.Domains != nil {
-		if _, ok := l.Domains[dom]; ok {
-			if l.Domains[dom] != nil {
-				return l.Domains[dom].Get(str, vars...)
-			}
-		}
+	snap := l.load()
+	tr := snap.domains[dom]
+	if snap.observer != nil {
+		l.observeLookup(snap.observer, dom, "", str, tr)
+	}
+	if tr != nil {
+		return tr.Get(str, vars...)
 	}
 
 	return Printf(str, vars...)
@@ -200,16 +633,13 @@ func (l *Locale) GetD(dom, str string, vars ...interface{}) string {
 // GetND retrieves the (N)th plural form of Translation in the given domain for the given string.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func (l *Locale) GetND(dom, str, plural string, n int, vars ...interface{}) string {
-	// Sync read
-	l.RLock()
-	defer l.RUnlock()
-
-	if l.Domains != nil {
-		if _, ok := l.Domains[dom]; ok {
-			if l.Domains[dom] != nil {
-				return l.Domains[dom].GetN(str, plural, n, vars...)
-			}
-		}
+	snap := l.load()
+	tr := snap.domains[dom]
+	if snap.observer != nil {
+		l.observeLookup(snap.observer, dom, "", str, tr)
+	}
+	if tr != nil {
+		return tr.GetN(str, plural, n, vars...)
 	}
 
 	// Use western default rule (plural > 1) to handle missing domain default result.
@@ -234,16 +664,13 @@ func (l *Locale) GetNC(str, plural string, n int, ctx string, vars ...interface{
 // GetDC returns the corresponding Translation in the given domain for the given string in the given context.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func (l *Locale) GetDC(dom, str, ctx string, vars ...interface{}) string {
-	// Sync read
-	l.RLock()
-	defer l.RUnlock()
-
-	if l.Domains != nil {
-		if _, ok := l.Domains[dom]; ok {
-			if l.Domains[dom] != nil {
-				return l.Domains[dom].GetC(str, ctx, vars...)
-			}
-		}
+	snap := l.load()
+	tr := snap.domains[dom]
+	if snap.observer != nil {
+		l.observeLookup(snap.observer, dom, ctx, str, tr)
+	}
+	if tr != nil {
+		return tr.GetC(str, ctx, vars...)
 	}
 
 	return Printf(str, vars...)
@@ -252,16 +679,13 @@ func (l *Locale) GetDC(dom, str, ctx string, vars ...interface{}) string {
 // GetNDC retrieves the (N)th plural form of Translation in the given domain for the given string in the given context.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func (l *Locale) GetNDC(dom, str, plural string, n int, ctx string, vars ...interface{}) string {
-	// Sync read
-	l.RLock()
-	defer l.RUnlock()
-
-	if l.Domains != nil {
-		if _, ok := l.Domains[dom]; ok {
-			if l.Domains[dom] != nil {
-				return l.Domains[dom].GetNC(str, plural, n, ctx, vars...)
-			}
-		}
+	snap := l.load()
+	tr := snap.domains[dom]
+	if snap.observer != nil {
+		l.observeLookup(snap.observer, dom, ctx, str, tr)
+	}
+	if tr != nil {
+		return tr.GetNC(str, plural, n, ctx, vars...)
 	}
 
 	// Use western default rule (plural > 1) to handle missing domain default result.
@@ -279,12 +703,22 @@ type LocaleEncoding struct {
 	DefaultDomain string
 }
 
-// MarshalBinary implements encoding BinaryMarshaler interface
+// MarshalBinary implements encoding BinaryMarshaler interface.
+// The base catalogs of the domains are serialized, not the layers stacked on them: overrides, fallback domains and
+// draft translations are left to be set again on the restored Locale.
 func (l *Locale) MarshalBinary() ([]byte, error) {
+	l.RLock()
+	domains := make(map[string]Translator, len(l.Domains))
+	for k, v := range l.Domains {
+		domains[k] = v
+	}
+	defaultDomain := l.defaultDomain
+	l.RUnlock()
+
 	obj := new(LocaleEncoding)
-	obj.DefaultDomain = l.defaultDomain
+	obj.DefaultDomain = defaultDomain
 	obj.Domains = make(map[string][]byte)
-	for k, v := range l.Domains {
+	for k, v := range domains {
 		var err error
 		obj.Domains[k], err = v.MarshalBinary()
 		if err != nil {
@@ -312,6 +746,9 @@ func (l *Locale) UnmarshalBinary(data []byte) error {
 		return err
 	}
 
+	l.Lock()
+	defer l.Unlock()
+
 	l.defaultDomain = obj.DefaultDomain
 	l.lang = obj.Lang
 	l.path = obj.Path
@@ -329,6 +766,10 @@ func (l *Locale) UnmarshalBinary(data []byte) error {
 
 		l.Domains[k] = tr.GetTranslator()
 	}
+	l.publish()
+	for dom, tr := range l.Domains {
+		l.observeLoad(dom, tr)
+	}
 
 	return nil
 }
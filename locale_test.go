@@ -6,8 +6,11 @@
 package gotext
 
 import (
+	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
+	"reflect"
 	"testing"
 )
 
@@ -406,6 +409,202 @@ msgstr "More Translation"
 	}
 }
 
+func TestLocaleLanguageTag(t *testing.T) {
+	// Create Locale with a BCP 47 language tag
+	l := NewLocale("fixtures/", "de-de")
+
+	if l.GetLanguage() != "de_DE" {
+		t.Errorf("Expected 'de_DE' but got '%s'", l.GetLanguage())
+	}
+	if l.GetLanguageTag() != "de-DE" {
+		t.Errorf("Expected 'de-DE' but got '%s'", l.GetLanguageTag())
+	}
+
+	// Catalogs are found in the POSIX named directory
+	l.AddDomain("default")
+
+	tr := l.Get("My text")
+	if tr != translatedText {
+		t.Errorf("Expected '%s' but got '%s'", translatedText, tr)
+	}
+}
+
+func TestLocaleRegionalOverlay(t *testing.T) {
+	files := map[string]string{
+		"pt": `msgid ""
+msgstr ""
+"Language: pt\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+msgid "Bus"
+msgstr "Autocarro"
+
+msgid "Train"
+msgstr "Comboio"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d ficheiro"
+msgstr[1] "%d ficheiros"
+`,
+		"pt_BR": `msgid "Bus"
+msgstr "Ônibus"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d arquivo"
+msgstr[1] "%d arquivos"
+`,
+	}
+
+	for lang, str := range files {
+		// Create Locales directory
+		dirname := path.Join("/tmp", lang, "LC_MESSAGES")
+		err := os.MkdirAll(dirname, os.ModePerm)
+		if err != nil {
+			t.Fatalf("Can't create test directory: %s", err.Error())
+		}
+
+		// Write PO content to file
+		err = ioutil.WriteFile(path.Join(dirname, "overlay.po"), []byte(str), 0644)
+		if err != nil {
+			t.Fatalf("Can't write to test file: %s", err.Error())
+		}
+	}
+
+	l := NewLocale("/tmp", "pt_BR")
+	l.AddDomain("overlay")
+
+	// Regional translation
+	tr := l.GetD("overlay", "Bus")
+	if tr != "Ônibus" {
+		t.Errorf("Expected 'Ônibus' but got '%s'", tr)
+	}
+
+	// Base language translation
+	tr = l.GetD("overlay", "Train")
+	if tr != "Comboio" {
+		t.Errorf("Expected 'Comboio' but got '%s'", tr)
+	}
+
+	// Plural-Forms header comes from the base catalog
+	tr = l.GetND("overlay", "%d file", "%d files", 0, 0)
+	if tr != "0 arquivo" {
+		t.Errorf("Expected '0 arquivo' but got '%s'", tr)
+	}
+}
+
+func TestLocaleDomains(t *testing.T) {
+	l := NewLocale("fixtures/", "ar")
+	l.AddDomain("no_plural_header")
+	l.AddDomain("categories")
+
+	doms := l.GetDomains()
+	if !reflect.DeepEqual(doms, []string{"categories", "no_plural_header"}) {
+		t.Errorf("Expected [categories no_plural_header] but got %v", doms)
+	}
+
+	// First added domain is the default one
+	tr := l.Get("Alcohol & Tobacco")
+	if tr != "الكحول والتبغ" {
+		t.Errorf("Expected to get 'الكحول والتبغ', but got '%s'", tr)
+	}
+
+	// Route plain Get calls to another domain
+	l.SetDomain("categories")
+	tr = l.GetN("Load %d more document", "Load %d more documents", 1)
+	if tr != "حمّل مستند واحد إضافي" {
+		t.Errorf("Expected to get 'حمّل مستند واحد إضافي', but got '%s'", tr)
+	}
+}
+
+func TestLocaleWithLanguage(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	l.AddDomain("default")
+
+	tr := l.GetInLanguage("de", "language")
+	if tr != "de" {
+		t.Errorf("Expected 'de' but got '%s'", tr)
+	}
+
+	tr = l.WithLanguage("fr-FR").GetN("One with var: %s", "Several with vars: %s", 1, "v")
+	if tr != "This one is the singular: v" {
+		t.Errorf("Expected 'This one is the singular: v' but got '%s'", tr)
+	}
+
+	// Views are cached
+	if l.WithLanguage("de") != l.WithLanguage("de") {
+		t.Error("Expected the same Locale for the same language")
+	}
+	if l.WithLanguage("en-US") != l {
+		t.Error("Expected the same Locale for its own language")
+	}
+
+	// Original Locale isn't affected
+	tr = l.Get("language")
+	if tr != "en_US" {
+		t.Errorf("Expected 'en_US' but got '%s'", tr)
+	}
+}
+
+func TestLocaleFiltered(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	l.AddDomainFiltered("default", FilterContexts("Ctx"))
+
+	// Entries in the context are loaded
+	tr := l.GetC("Some random in a context", "Ctx")
+	if tr != "Some random translation in a context" {
+		t.Errorf("Expected 'Some random translation in a context' but got '%s'", tr)
+	}
+
+	// Entries out of the context aren't
+	tr = l.Get("My text")
+	if tr != "My text" {
+		t.Errorf("Expected 'My text' but got '%s'", tr)
+	}
+
+	// Headers are always loaded
+	if l.Domains["default"].GetDomain().Language != "en_US" {
+		t.Errorf("Expected 'en_US' but got '%s'", l.Domains["default"].GetDomain().Language)
+	}
+
+	// Same from MO files and by prefix
+	mo := NewMo()
+	mo.GetDomain().SetFilter(FilterPrefix("My"))
+	mo.ParseFile("fixtures/en_US/default.mo")
+
+	if tr = mo.Get("My text"); tr != translatedText {
+		t.Errorf("Expected '%s' but got '%s'", translatedText, tr)
+	}
+	if tr = mo.Get("More"); tr != "More" {
+		t.Errorf("Expected 'More' but got '%s'", tr)
+	}
+}
+
+func TestLocaleAddDomains(t *testing.T) {
+	l := NewLocale("fixtures/", "ar")
+
+	err := l.AddDomains(2, "missing", "categories", "no_plural_header")
+	if err == nil || err.Error() != "no catalog found for domains: missing" {
+		t.Errorf("Expected error for missing domain but got '%v'", err)
+	}
+
+	doms := l.GetDomains()
+	if !reflect.DeepEqual(doms, []string{"categories", "no_plural_header"}) {
+		t.Errorf("Expected [categories no_plural_header] but got %v", doms)
+	}
+
+	// First found domain is the default one
+	if l.GetDomain() != "categories" {
+		t.Errorf("Expected 'categories' but got '%s'", l.GetDomain())
+	}
+
+	tr := l.GetD("no_plural_header", "Alcohol & Tobacco")
+	if tr != "الكحول والتبغ" {
+		t.Errorf("Expected to get 'الكحول والتبغ', but got '%s'", tr)
+	}
+}
+
 func TestLocaleRace(t *testing.T) {
 	// Set PO content
 	str := `# Some comment
@@ -601,3 +800,77 @@ func TestLocaleBinaryEncoding(t *testing.T) {
 		t.Errorf("'%s' is different from '%s", l.GetN("One with var: %s", "Several with vars: %s", 3, "VALUE"), l2.GetN("One with var: %s", "Several with vars: %s", 3, "VALUE"))
 	}
 }
+
+func TestLocaleRefresh(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`msgid "Hello"
+msgstr "Hallo"
+`))
+
+	l := NewLocale("", "de")
+	l.SetDomain("default")
+	l.Domains["default"] = po
+	if tr := l.Get("Hello"); tr != "Hello" {
+		t.Errorf("Expected '%s' but got '%s'", "Hello", tr)
+	}
+
+	l.Refresh()
+	if tr := l.Get("Hello"); tr != "Hallo" {
+		t.Errorf("Expected '%s' but got '%s'", "Hallo", tr)
+	}
+	if l.GetTranslator("default") != po {
+		t.Error("Expected the Translator of the default domain")
+	}
+	if l.GetTranslator("missing") != nil {
+		t.Error("Expected no Translator for a missing domain")
+	}
+}
+
+func TestLocaleWithLanguageReload(t *testing.T) {
+	dir := "/tmp/gotext_views"
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+
+	write := func(lang, dom, str string) {
+		if err := os.MkdirAll(path.Join(dir, lang), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		po := fmt.Sprintf("msgid \"Hello\"\nmsgstr \"%s\"\n", str)
+		if err := ioutil.WriteFile(path.Join(dir, lang, dom+".po"), []byte(po), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("fr", "app", "Bonjour")
+	write("de", "app", "Hallo")
+	write("en", "extra", "Hi")
+
+	l := NewLocale(dir, "fr")
+	l.languages = []string{"fr", "en"}
+	l.AddDomain("app")
+	l.AddDomain("extra")
+
+	// The fallback languages are shared
+	de := l.WithLanguage("de")
+	if tr := de.Get("Hello"); tr != "Hallo" {
+		t.Errorf("Expected '%s' but got '%s'", "Hallo", tr)
+	}
+	if tr := de.GetD("extra", "Hello"); tr != "Hi" {
+		t.Errorf("Expected '%s' but got '%s'", "Hi", tr)
+	}
+
+	// Reloads of this Locale reload the views
+	write("de", "app", "Servus")
+	if tr := l.WithLanguage("de").Get("Hello"); tr != "Hallo" {
+		t.Errorf("Expected '%s' but got '%s'", "Hallo", tr)
+	}
+	l.AddDomain("app")
+	if tr := l.GetInLanguage("de", "Hello"); tr != "Servus" {
+		t.Errorf("Expected '%s' but got '%s'", "Servus", tr)
+	}
+
+	// And so do removals
+	l.swapTranslators(map[string]Translator{"extra": nil})
+	if doms := l.WithLanguage("de").GetDomains(); len(doms) != 1 || doms[0] != "app" {
+		t.Errorf("Expected the 'app' domain but got %v", doms)
+	}
+}
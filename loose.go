@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "strings"
+
+// looseKey returns the key of the entries matched by loose lookups: normalized, without surrounding whitespace, in lower
+// case.
+func looseKey(k entryKey) entryKey {
+	k = nfcKey(k)
+	return entryKey{
+		ctx: strings.ToLower(strings.TrimSpace(k.ctx)),
+		id:  strings.ToLower(strings.TrimSpace(k.id)),
+	}
+}
+
+// looseIndex returns the entries by loose key. When several entries share a loose key, the one with the lowest exact
+// key is kept, so lookups don't depend on the order entries were parsed.
+func looseIndex(entries map[entryKey]*Translation) map[entryKey]*Translation {
+	loose := make(map[entryKey]*Translation, len(entries))
+	exact := make(map[entryKey]entryKey, len(entries))
+	for k, tr := range entries {
+		lk := looseKey(k)
+		if prev, ok := exact[lk]; ok && keyLess(prev, k) {
+			continue
+		}
+		loose[lk] = tr
+		exact[lk] = k
+	}
+
+	return loose
+}
+
+// find returns the translation stored with the key, else the one stored with the same key in Unicode normalization
+// form C or, when loose lookups are enabled, the one stored with the same loose key.
+func (c *catalog) find(k entryKey) (*Translation, bool) {
+	if tr, ok := c.entries[k]; ok {
+		return tr, true
+	}
+	nk := nfcKey(k)
+	if nk != k {
+		if tr, ok := c.entries[nk]; ok {
+			return tr, true
+		}
+	}
+	if tr, ok := c.nfc[nk]; ok {
+		return tr, true
+	}
+	if c.loose == nil {
+		return nil, false
+	}
+
+	tr, ok := c.loose[looseKey(k)]
+	return tr, ok
+}
+
+// SetLooseLookup enables lookups ignoring case and leading or trailing whitespace differences between the strings looked
+// up and the msgids and contexts of the catalog, as when msgids were edited by hand in a translation management
+// system. Exact matches always come first. It's disabled by default.
+func (do *Domain) SetLooseLookup(enabled bool) {
+	do.trMutex.Lock()
+	do.looseLookup = enabled
+	do.publish()
+	do.trMutex.Unlock()
+}
+
+// SetLooseLookup enables loose lookups, as Domain.SetLooseLookup, for the domains loaded and the ones loaded afterwards,
+// including the ones added with AddTranslator, AddDomainURL or an Updater.
+func (l *Locale) SetLooseLookup(enabled bool) {
+	l.Lock()
+	l.looseLookup = enabled
+	for _, tr := range l.Domains {
+		if do := tr.GetDomain(); do != nil {
+			do.SetLooseLookup(enabled)
+		}
+	}
+	l.Unlock()
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+)
+
+const loosePo = `msgid ""
+msgstr ""
+"Language: de\n"
+
+msgid "Save "
+msgstr "Speichern"
+
+msgid "save"
+msgstr "sichern"
+
+msgctxt "Menu"
+msgid "Open File"
+msgstr "Datei öffnen"
+
+msgid "One item"
+msgid_plural "%d items"
+msgstr[0] "Ein Eintrag"
+msgstr[1] "%d Einträge"
+`
+
+func TestLooseLookup(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(loosePo))
+
+	if tr := po.Get("SAVE"); tr != "SAVE" {
+		t.Errorf("Expected '%s' but got '%s'", "SAVE", tr)
+	}
+
+	po.GetDomain().SetLooseLookup(true)
+	tests := []struct {
+		tr, expected string
+	}{
+		{po.Get("save"), "sichern"},
+		{po.Get("Save "), "Speichern"},
+		{po.Get(" SAVE"), "Speichern"},
+		{po.GetC("open file", " menu"), "Datei öffnen"},
+		{po.GetN(" one item", "%d Items", 3, 3), "3 Einträge"},
+		{po.Get("Close"), "Close"},
+	}
+	for _, test := range tests {
+		if test.tr != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.tr)
+		}
+	}
+
+	// Parsing again keeps loose lookups
+	po.Parse([]byte("msgid \"Quit\"\nmsgstr \"Beenden\"\n"))
+	if tr := po.Get("quit"); tr != "Beenden" {
+		t.Errorf("Expected '%s' but got '%s'", "Beenden", tr)
+	}
+
+	po.GetDomain().SetLooseLookup(false)
+	if tr := po.Get("quit"); tr != "quit" {
+		t.Errorf("Expected '%s' but got '%s'", "quit", tr)
+	}
+}
+
+func TestLocaleLooseLookup(t *testing.T) {
+	// Create Locales directory
+	dirname := path.Join("/tmp", "gotextloose", "de", "LC_MESSAGES")
+	err := os.MkdirAll(dirname, os.ModePerm)
+	if err != nil {
+		t.Fatalf("Can't create test directory: %s", err.Error())
+	}
+
+	// Write PO content to file
+	err = ioutil.WriteFile(path.Join(dirname, "default.po"), []byte(loosePo), 0644)
+	if err != nil {
+		t.Fatalf("Can't write to test file: %s", err.Error())
+	}
+
+	l := NewLocale("/tmp/gotextloose", "de")
+	l.SetLooseLookup(true)
+	l.AddDomain("default")
+	if tr := l.Get("OPEN FILE"); tr != "OPEN FILE" {
+		t.Errorf("Expected '%s' but got '%s'", "OPEN FILE", tr)
+	}
+	if tr := l.GetC("OPEN FILE", "menu"); tr != "Datei öffnen" {
+		t.Errorf("Expected '%s' but got '%s'", "Datei öffnen", tr)
+	}
+}
+
+func TestLocaleLooseLookupAdded(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(loosePo))
+	}))
+	defer srv.Close()
+
+	l := NewLocale("", "de")
+	l.SetLooseLookup(true)
+
+	// Remote domains, and translators added afterwards, are looked up loosely too
+	if err := l.AddDomainURL(srv.URL + "/de/remote.po"); err != nil {
+		t.Fatal(err)
+	}
+	po := NewPo()
+	po.Parse([]byte(loosePo))
+	l.AddTranslator("added", po)
+
+	for _, dom := range []string{"remote", "added"} {
+		if tr := l.GetDC(dom, "OPEN FILE", "menu"); tr != "Datei öffnen" {
+			t.Errorf("Expected '%s' in %s but got '%s'", "Datei öffnen", dom, tr)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"strings"
+)
+
+// mdBlock is a piece of a Markdown document: either a translatable paragraph or text kept verbatim
+// (blank lines, code blocks, thematic breaks).
+type mdBlock struct {
+	text      string
+	translate bool
+}
+
+// splitMarkdown splits a Markdown document into blocks separated by blank lines.
+// Fenced and indented code blocks, thematic breaks and the separators between blocks aren't translatable.
+func splitMarkdown(src string) []mdBlock {
+	lines := strings.SplitAfter(strings.Replace(src, "\r\n", "\n", -1), "\n")
+
+	var (
+		blocks []mdBlock
+		cur    []string
+		fence  string
+	)
+	flush := func(translate bool) {
+		if len(cur) > 0 {
+			blocks = append(blocks, mdBlock{strings.Join(cur, ""), translate})
+			cur = nil
+		}
+	}
+
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case fence != "":
+			cur = append(cur, line)
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+				flush(false)
+			}
+
+		case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+			flush(true)
+			fence = trimmed[:3]
+			cur = append(cur, line)
+
+		case trimmed == "":
+			flush(true)
+			blocks = append(blocks, mdBlock{line, false})
+
+		case len(cur) == 0 && (strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")), isThematicBreak(trimmed):
+			flush(true)
+			blocks = append(blocks, mdBlock{line, false})
+
+		default:
+			cur = append(cur, line)
+		}
+	}
+	// An unterminated fence runs to the end of the document.
+	flush(fence == "")
+
+	return blocks
+}
+
+// isThematicBreak reports if the line is a Markdown horizontal rule (i.e. "---", "* * *").
+func isThematicBreak(line string) bool {
+	s := strings.Replace(line, " ", "", -1)
+	if len(s) < 3 {
+		return false
+	}
+	for _, c := range []string{"-", "*", "_"} {
+		if strings.Trim(s, c) == "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// markdownMsgid returns the msgid of a paragraph: its text without the trailing line break.
+func markdownMsgid(text string) (msgid, trailing string) {
+	msgid = strings.TrimRight(text, "\n")
+	return msgid, text[len(msgid):]
+}
+
+// MarkdownParagraphs returns the translatable paragraphs of a Markdown document, in order,
+// as they're looked up as msgids by TranslateMarkdown. Use it to extract the strings of Markdown sources into catalogs.
+func MarkdownParagraphs(src string) []string {
+	var msgids []string
+	for _, b := range splitMarkdown(src) {
+		if b.translate {
+			msgid, _ := markdownMsgid(b.text)
+			msgids = append(msgids, msgid)
+		}
+	}
+
+	return msgids
+}
+
+// TranslateMarkdown translates each paragraph of a Markdown document with the get function and reassembles it,
+// keeping code blocks and the document layout unchanged.
+func TranslateMarkdown(src string, get func(msgid string) string) string {
+	var b strings.Builder
+	for _, block := range splitMarkdown(src) {
+		if !block.translate {
+			b.WriteString(block.text)
+			continue
+		}
+		msgid, trailing := markdownMsgid(block.text)
+		b.WriteString(get(msgid))
+		b.WriteString(trailing)
+	}
+
+	return b.String()
+}
+
+// GetMarkdown translates a Markdown document paragraph by paragraph in the default domain of the Locale.
+func (l *Locale) GetMarkdown(src string) string {
+	return l.GetDMarkdown(l.GetDomain(), src)
+}
+
+// GetDMarkdown translates a Markdown document paragraph by paragraph in the given domain.
+func (l *Locale) GetDMarkdown(dom, src string) string {
+	return TranslateMarkdown(src, func(msgid string) string {
+		return l.GetD(dom, msgid)
+	})
+}
+
+// GetMarkdown translates a Markdown document paragraph by paragraph in the default domain globally set.
+func GetMarkdown(src string) string {
+	return TranslateMarkdown(src, func(msgid string) string {
+		return Get(msgid)
+	})
+}
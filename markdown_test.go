@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const markdownSrc = `# Usage
+
+Run the tool with a
+configuration file.
+
+` + "```sh\ntool -c config.yml\n\ntool --help\n```" + `
+
+---
+
+    indented code
+
+- First item
+- Second item
+`
+
+func TestMarkdownParagraphs(t *testing.T) {
+	expected := []string{
+		"# Usage",
+		"Run the tool with a\nconfiguration file.",
+		"- First item\n- Second item",
+	}
+	if got := MarkdownParagraphs(markdownSrc); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+}
+
+func TestTranslateMarkdown(t *testing.T) {
+	got := TranslateMarkdown(markdownSrc, strings.ToUpper)
+	expected := strings.Replace(markdownSrc, "# Usage", "# USAGE", 1)
+	expected = strings.Replace(expected, "Run the tool with a\nconfiguration file.", "RUN THE TOOL WITH A\nCONFIGURATION FILE.", 1)
+	expected = strings.Replace(expected, "- First item\n- Second item", "- FIRST ITEM\n- SECOND ITEM", 1)
+	if got != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, got)
+	}
+
+	// Untranslated documents are unchanged.
+	if got := TranslateMarkdown(markdownSrc, func(s string) string { return s }); got != markdownSrc {
+		t.Errorf("Expected '%s' but got '%s'", markdownSrc, got)
+	}
+}
+
+func TestLocaleGetMarkdown(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`
+msgid "# Usage"
+msgstr "# Utilisation"
+
+msgid "Use 100% of it."
+msgstr "Utilisez-en 100%."
+`))
+
+	l := NewLocale("", "fr")
+	l.AddTranslator("help", po)
+
+	expected := "# Utilisation\n\nUtilisez-en 100%.\n\nNot translated\n"
+	if got := l.GetMarkdown("# Usage\n\nUse 100% of it.\n\nNot translated\n"); got != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, got)
+	}
+}
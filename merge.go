@@ -0,0 +1,198 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MergeStrategy selects the translations kept by Merge when both catalogs translate an entry differently.
+type MergeStrategy int
+
+const (
+	// MergeKeepTheirs keeps the translations of the catalog merged in.
+	MergeKeepTheirs MergeStrategy = iota
+	// MergeKeepOurs keeps the translations of the catalog merged into.
+	MergeKeepOurs
+	// MergeErrorOnConflict fails with a *MergeConflictError, leaving the catalog unchanged.
+	MergeErrorOnConflict
+)
+
+// MergeConflictError is returned by Merge with MergeErrorOnConflict, listing the entries translated differently by
+// both catalogs, sorted by context and msgid.
+type MergeConflictError struct {
+	Conflicts []MergeConflict
+}
+
+// MergeConflict is an entry translated differently by both catalogs merged.
+type MergeConflict struct {
+	Context string
+	ID      string
+
+	// Ours and Theirs are the translations of the entry, one per plural form.
+	Ours   []string
+	Theirs []string
+}
+
+func (e *MergeConflictError) Error() string {
+	c := e.Conflicts[0]
+	msg := fmt.Sprintf("merge conflict on msgid %q", c.ID)
+	if c.Context != "" {
+		msg += fmt.Sprintf(" in context %q", c.Context)
+	}
+	if len(e.Conflicts) > 1 {
+		msg += fmt.Sprintf(" and %d more", len(e.Conflicts)-1)
+	}
+
+	return msg
+}
+
+// sameTranslations reports if both translations have the same translated forms.
+func sameTranslations(a, b *Translation) bool {
+	if len(a.Trs) != len(b.Trs) {
+		return false
+	}
+	for i, tr := range a.Trs {
+		if b.Trs[i] != tr {
+			return false
+		}
+	}
+
+	return true
+}
+
+// translated reports if the singular translation is set.
+func translated(t *Translation) bool {
+	return t.Trs[0] != ""
+}
+
+// copyTranslation returns a copy of the translation, so catalogs don't share their storage.
+func copyTranslation(t *Translation) *Translation {
+	c := NewTranslation()
+	c.ID = t.ID
+	c.PluralID = t.PluralID
+	c.Fuzzy = t.Fuzzy
+	for i, tr := range t.Trs {
+		c.Trs[i] = tr
+	}
+
+	return c
+}
+
+// merge adds the entries of the other domain to the storage, keeping the ones of the catalog or of the other as selected
+// by the strategy when both are translated differently, and returns the keys of the entries taken from the other. The
+// header is only taken when the domain has none. It must be called holding trMutex, after beginUpdate.
+func (do *Domain) merge(other *Domain, strategy MergeStrategy) ([]entryKey, error) {
+	theirs := other.load().entries
+
+	if strategy == MergeErrorOnConflict {
+		var conflicts []MergeConflict
+		for k, t := range theirs {
+			ours, ok := do.entries[k]
+			if !ok || k == (entryKey{}) || !translated(ours) || !translated(t) || sameTranslations(ours, t) {
+				continue
+			}
+			conflicts = append(conflicts, MergeConflict{
+				Context: k.ctx,
+				ID:      k.id,
+				Ours:    newEntry(k, ours).Translations,
+				Theirs:  newEntry(k, t).Translations,
+			})
+		}
+		if len(conflicts) > 0 {
+			sort.Slice(conflicts, func(i, j int) bool {
+				return keyLess(entryKey{ctx: conflicts[i].Context, id: conflicts[i].ID},
+					entryKey{ctx: conflicts[j].Context, id: conflicts[j].ID})
+			})
+			return nil, &MergeConflictError{Conflicts: conflicts}
+		}
+	}
+
+	var taken []entryKey
+	for k, t := range theirs {
+		if !do.keep(k.ctx, k.id) {
+			continue
+		}
+		if ours, ok := do.entries[k]; ok {
+			if k == (entryKey{}) || !translated(t) || sameTranslations(ours, t) {
+				continue
+			}
+			if translated(ours) && strategy == MergeKeepOurs {
+				continue
+			}
+		}
+		do.entries[k] = copyTranslation(t)
+		taken = append(taken, k)
+	}
+	if len(taken) > 0 {
+		do.parseHeaders()
+	}
+
+	return taken, nil
+}
+
+/*
+Merge adds the entries of the other domain, as services assembling catalogs from several sources do. The entries
+translated by one of the catalogs only get its translation, and the strategy selects the translations kept for the ones
+translated differently by both. The header of the domain is kept, the other's is only taken when it has none.
+
+Example:
+
+	po := gotext.NewPo()
+	po.ParseFile("base/de.po")
+
+	overrides := gotext.NewPo()
+	overrides.ParseFile("tenant/de.po")
+
+	if err := po.Merge(overrides, gotext.MergeErrorOnConflict); err != nil {
+		log.Fatal(err)
+	}
+*/
+func (do *Domain) Merge(other *Domain, strategy MergeStrategy) error {
+	do.trMutex.Lock()
+	defer do.trMutex.Unlock()
+
+	do.beginUpdate()
+	if _, err := do.merge(other, strategy); err != nil {
+		return err
+	}
+	do.publish()
+
+	return nil
+}
+
+// Merge adds the entries of the other catalog, as Domain.Merge. When the reverse index is enabled, the source
+// references of the entries taken from the other catalog are kept if it has its reverse index enabled too.
+func (po *Po) Merge(other *Po, strategy MergeStrategy) error {
+	po.domain.trMutex.Lock()
+	defer po.domain.trMutex.Unlock()
+
+	po.domain.beginUpdate()
+	taken, err := po.domain.merge(other.domain, strategy)
+	if err != nil {
+		return err
+	}
+
+	if po.reverseEnabled {
+		theirs, _ := other.reverse.Load().(*reverseIndex)
+		for _, k := range taken {
+			if theirs != nil && len(theirs.refs[k]) > 0 {
+				po.references[k] = theirs.refs[k]
+			} else {
+				delete(po.references, k)
+			}
+		}
+		po.buildReverseIndex()
+	}
+	po.domain.publish()
+
+	po.Language = po.domain.Language
+	po.PluralForms = po.domain.PluralForms
+	po.Headers = po.domain.Headers
+
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "testing"
+
+const (
+	mergeOurs = `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Save"
+msgstr "Speichern"
+
+msgid "Open"
+msgstr "Öffnen"
+
+msgid "Close"
+msgstr ""
+
+msgctxt "menu"
+msgid "Quit"
+msgstr "Beenden"
+`
+
+	mergeTheirs = `msgid ""
+msgstr ""
+"Language: fr\n"
+
+msgid "Save"
+msgstr "Sichern"
+
+msgid "Open"
+msgstr ""
+
+msgid "Close"
+msgstr "Schließen"
+
+#: main.go:12
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "Eine Datei"
+msgstr[1] "%d Dateien"
+
+msgctxt "menu"
+msgid "Quit"
+msgstr "Verlassen"
+`
+)
+
+func TestPoMerge(t *testing.T) {
+	tests := []struct {
+		strategy     MergeStrategy
+		save, quit   string
+		open, closed string
+	}{
+		{MergeKeepTheirs, "Sichern", "Verlassen", "Öffnen", "Schließen"},
+		{MergeKeepOurs, "Speichern", "Beenden", "Öffnen", "Schließen"},
+	}
+	for _, test := range tests {
+		ours, theirs := NewPo(), NewPo()
+		ours.Parse([]byte(mergeOurs))
+		theirs.Parse([]byte(mergeTheirs))
+
+		if err := ours.Merge(theirs, test.strategy); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		for _, c := range []struct{ tr, expected string }{
+			{ours.Get("Save"), test.save},
+			{ours.GetC("Quit", "menu"), test.quit},
+			{ours.Get("Open"), test.open},
+			{ours.Get("Close"), test.closed},
+			{ours.GetN("One file", "%d files", 2, 2), "2 Dateien"},
+			{ours.Language, "de"},
+		} {
+			if c.tr != c.expected {
+				t.Errorf("Expected '%s' but got '%s'", c.expected, c.tr)
+			}
+		}
+
+		// The catalog merged in is unchanged
+		if tr := theirs.Get("Open"); tr != "Open" {
+			t.Errorf("Expected '%s' but got '%s'", "Open", tr)
+		}
+	}
+}
+
+func TestPoMergeConflict(t *testing.T) {
+	ours, theirs := NewPo(), NewPo()
+	ours.Parse([]byte(mergeOurs))
+	theirs.Parse([]byte(mergeTheirs))
+
+	err := ours.Merge(theirs, MergeErrorOnConflict)
+	conflict, ok := err.(*MergeConflictError)
+	if !ok {
+		t.Fatalf("Expected a merge conflict error but got %v", err)
+	}
+	if len(conflict.Conflicts) != 2 {
+		t.Fatalf("Expected 2 conflicts but got %d", len(conflict.Conflicts))
+	}
+	first := conflict.Conflicts[0]
+	if first.ID != "Save" || first.Ours[0] != "Speichern" || first.Theirs[0] != "Sichern" {
+		t.Errorf("Unexpected conflict %+v", first)
+	}
+	if conflict.Conflicts[1].Context != "menu" {
+		t.Errorf("Expected '%s' but got '%s'", "menu", conflict.Conflicts[1].Context)
+	}
+	if msg := err.Error(); msg != `merge conflict on msgid "Save" and 1 more` {
+		t.Errorf("Expected '%s' but got '%s'", `merge conflict on msgid "Save" and 1 more`, msg)
+	}
+
+	// Nothing is merged
+	if tr := ours.Get("Close"); tr != "Close" {
+		t.Errorf("Expected '%s' but got '%s'", "Close", tr)
+	}
+}
+
+func TestPoMergeEmpty(t *testing.T) {
+	ours, theirs := NewPo(), NewPo()
+	ours.EnableReverseIndex()
+	theirs.EnableReverseIndex()
+	theirs.Parse([]byte(mergeTheirs))
+
+	if err := ours.Merge(theirs, MergeErrorOnConflict); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if ours.Language != "fr" {
+		t.Errorf("Expected '%s' but got '%s'", "fr", ours.Language)
+	}
+	matches := ours.Lookup("3 Dateien")
+	if len(matches) != 1 || len(matches[0].References) != 1 || matches[0].References[0] != "main.go:12" {
+		t.Errorf("Unexpected reverse lookup %+v", matches)
+	}
+}
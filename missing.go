@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "sync"
+
+// MissingTranslator provides draft translations for strings missing in the catalogs, i.e. calling a machine translation service.
+type MissingTranslator interface {
+	// TranslateMissing returns a translation into lang of str, from the given domain and context (empty for none).
+	TranslateMissing(lang, dom, ctx, str string) (string, error)
+}
+
+// MissingTranslatorFunc adapts a function to the MissingTranslator interface.
+type MissingTranslatorFunc func(lang, dom, ctx, str string) (string, error)
+
+// TranslateMissing calls f(lang, dom, ctx, str).
+func (f MissingTranslatorFunc) TranslateMissing(lang, dom, ctx, str string) (string, error) {
+	return f(lang, dom, ctx, str)
+}
+
+/*
+SetMissingTranslator sets a MissingTranslator called when a string has no translation in the Locale catalogs,
+so staging environments can show draft translations instead of the msgid. Results are cached, failures included,
+and returned as fuzzy Translations. Only singular forms are translated. A nil MissingTranslator removes it.
+
+Example:
+
+	l.SetMissingTranslator(gotext.MissingTranslatorFunc(func(lang, dom, ctx, str string) (string, error) {
+		return mtClient.Translate(str, "en", lang)
+	}))
+*/
+func (l *Locale) SetMissingTranslator(mt MissingTranslator) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.missing = mt
+	l.missingTranslators = nil
+	l.publish()
+}
+
+// missingTranslator returns the Translator caching missing translations for a domain.
+// It must be called while holding the write lock.
+func (l *Locale) missingTranslator(dom string) Translator {
+	if tr, ok := l.missingTranslators[dom]; ok {
+		return tr
+	}
+
+	tr := &missingTranslator{NewBackendTranslator(&missingBackend{
+		mt:    l.missing,
+		lang:  l.lang,
+		dom:   dom,
+		cache: make(map[entryKey]*Translation),
+	})}
+	if l.missingTranslators == nil {
+		l.missingTranslators = make(map[string]Translator)
+	}
+	l.missingTranslators[dom] = tr
+
+	return tr
+}
+
+// missingTranslator is the Translator resolving missing translations for a domain.
+type missingTranslator struct {
+	*BackendTranslator
+}
+
+// MarshalBinary encodes an empty catalog, as draft translations aren't persisted along with the Locale.
+func (mt *missingTranslator) MarshalBinary() ([]byte, error) {
+	return NewDomain().MarshalBinary()
+}
+
+// missingBackend is a Backend translating strings with a MissingTranslator, caching the results.
+type missingBackend struct {
+	mt        MissingTranslator
+	lang, dom string
+
+	// Translations by key, nil for failures
+	cache map[entryKey]*Translation
+	mutex sync.RWMutex
+}
+
+func (b *missingBackend) Lookup(ctx, id string) (*Translation, bool) {
+	k := entryKey{ctx: ctx, id: id}
+
+	b.mutex.RLock()
+	tr, ok := b.cache[k]
+	b.mutex.RUnlock()
+	if ok {
+		return tr, tr != nil
+	}
+
+	str, err := b.mt.TranslateMissing(b.lang, b.dom, ctx, id)
+	if err == nil && str != "" {
+		tr = NewTranslation()
+		tr.ID = id
+		tr.Trs[0] = str
+		tr.Fuzzy = true
+	}
+
+	b.mutex.Lock()
+	b.cache[k] = tr
+	b.mutex.Unlock()
+
+	return tr, tr != nil
+}
+
+func (b *missingBackend) PluralForm(n int) int {
+	return emptyCatalog.pluralForm(n)
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLocaleMissingTranslator(t *testing.T) {
+	calls := 0
+	mt := MissingTranslatorFunc(func(lang, dom, ctx, str string) (string, error) {
+		calls++
+		if str == "Fail" {
+			return "", errors.New("quota exceeded")
+		}
+		return "[" + lang + "] " + strings.ToUpper(str), nil
+	})
+
+	l := NewLocale("fixtures/", "en_US")
+	l.AddDomain("default")
+	l.SetMissingTranslator(mt)
+
+	for _, test := range []struct {
+		expected, got string
+	}{
+		{translatedText, l.Get("My text")},
+		{"[en_US] MISSING", l.Get("Missing")},
+		{"[en_US] MISSING", l.Get("Missing")},
+		{"Fail", l.Get("Fail")},
+		{"Fail", l.Get("Fail")},
+	} {
+		if test.expected != test.got {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.got)
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected 2 calls but got %d", calls)
+	}
+	if ly := l.ResolveLayer("default", "Missing", ""); ly != LayerMissing {
+		t.Errorf("Expected layer 'missing' but got '%s'", ly)
+	}
+
+	tr, ok := l.load().domains["default"].(Backend).Lookup("", "Missing")
+	if !ok || !tr.Fuzzy {
+		t.Error("Expected fuzzy missing translation")
+	}
+
+	// Draft translations aren't serialized
+	data, err := l.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := new(Locale)
+	if err = restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if tr := restored.Get("Missing"); tr != "Missing" {
+		t.Errorf("Expected 'Missing' but got '%s'", tr)
+	}
+	if tr := restored.Get("My text"); tr != translatedText {
+		t.Errorf("Expected '%s' but got '%s'", translatedText, tr)
+	}
+
+	l.SetMissingTranslator(nil)
+	if tr := l.Get("Missing"); tr != "Missing" {
+		t.Errorf("Expected 'Missing' but got '%s'", tr)
+	}
+}
@@ -105,9 +105,10 @@ func (mo *Mo) ParseFile(f string) {
 func (mo *Mo) Parse(buf []byte) {
 	// Lock while parsing
 	mo.domain.trMutex.Lock()
-	mo.domain.pluralMutex.Lock()
 	defer mo.domain.trMutex.Unlock()
-	defer mo.domain.pluralMutex.Unlock()
+
+	// Work on a copy of the storage, lookups keep using the published snapshot meanwhile
+	mo.domain.beginUpdate()
 
 	r := bytes.NewReader(buf)
 
@@ -214,6 +215,9 @@ func (mo *Mo) Parse(buf []byte) {
 	// Parse headers
 	mo.domain.parseHeaders()
 
+	// Make new translations available to lookups
+	mo.domain.publish()
+
 	// set values on this struct
 	// this is for backwards compatibility
 	mo.Language = mo.domain.Language
@@ -253,13 +257,13 @@ func (mo *Mo) addTranslation(msgid, msgstr []byte) {
 		}
 	}
 
-	if len(msgctxt) > 0 {
-		// With context...
-		if _, ok := mo.domain.contexts[string(msgctxt)]; !ok {
-			mo.domain.contexts[string(msgctxt)] = make(map[string]*Translation)
-		}
-		mo.domain.contexts[string(msgctxt)][translation.ID] = translation
-	} else {
-		mo.domain.translations[translation.ID] = translation
+	// Skip entries excluded by the domain filter
+	if !mo.domain.keep(string(msgctxt), translation.ID) {
+		return
 	}
+
+	// Share repeated strings when interning is enabled
+	internTranslation(translation)
+
+	mo.domain.entries[entryKey{ctx: intern(string(msgctxt)), id: translation.ID}] = translation
 }
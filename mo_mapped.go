@@ -0,0 +1,370 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"sync"
+)
+
+/*
+MappedMo provides read-only access to a MO file mapped into memory.
+Instead of loading every translation into the Go heap, it keeps the file mapped, indexes nothing but the
+headers and resolves strings lazily from the mapping on each lookup, using a binary search over the sorted
+msgid table of the file. It's meant for huge catalogs on memory-constrained environments.
+On platforms without mmap support the file is read into memory, but still never parsed into the heap.
+
+Returned translations are copied out of the mapping, so they remain valid after Close.
+MappedMo is safe for concurrent use by multiple goroutines.
+
+Example:
+
+	import (
+		"fmt"
+		"github.com/leonelquinteros/gotext"
+	)
+
+	func main() {
+		mo, err := gotext.OpenMappedMo("/path/to/mo/file/translations.mo")
+		if err != nil {
+			panic(err)
+		}
+		defer mo.Close()
+
+		l := gotext.NewLocale("", "de")
+		l.AddTranslator("translations", mo)
+
+		fmt.Println(l.Get("Translate this"))
+	}
+*/
+type MappedMo struct {
+	mutex sync.RWMutex
+
+	// File contents and the function releasing them
+	data  []byte
+	unmap func() error
+
+	// Tables layout
+	bo         binary.ByteOrder
+	count      uint32
+	origTable  uint32
+	transTable uint32
+
+	// Entries order, only set if the file msgid table isn't sorted
+	order []uint32
+
+	// Headers and plural forms
+	domain *Domain
+}
+
+// NewMappedMo should always be used to instantiate a new MappedMo object
+func NewMappedMo() *MappedMo {
+	return &MappedMo{domain: NewDomain()}
+}
+
+// OpenMappedMo maps the given MO file into memory and returns the MappedMo object to access it.
+func OpenMappedMo(f string) (*MappedMo, error) {
+	mo := NewMappedMo()
+	if err := mo.Open(f); err != nil {
+		return nil, err
+	}
+
+	return mo, nil
+}
+
+// Open maps the given MO file, releasing any previously mapped file.
+func (mo *MappedMo) Open(f string) error {
+	data, unmap, err := mmapFile(f)
+	if err != nil {
+		return err
+	}
+
+	if err := mo.load(data, unmap); err != nil {
+		unmap()
+		return err
+	}
+
+	return nil
+}
+
+// ParseFile maps the given MO file, ignoring errors to satisfy the Translator interface. Use Open to get them.
+func (mo *MappedMo) ParseFile(f string) {
+	mo.Open(f)
+}
+
+// Parse uses the provided byte slice, in the GNU gettext .mo format, as the mapped data.
+// The slice must not be modified afterwards.
+func (mo *MappedMo) Parse(buf []byte) {
+	mo.load(buf, nil)
+}
+
+// Close releases the mapped file. Lookups after Close return untranslated strings.
+func (mo *MappedMo) Close() error {
+	mo.mutex.Lock()
+	defer mo.mutex.Unlock()
+
+	return mo.release()
+}
+
+// release unmaps the current data, it must be called holding the write lock.
+func (mo *MappedMo) release() error {
+	var err error
+	if mo.unmap != nil {
+		err = mo.unmap()
+	}
+
+	mo.data = nil
+	mo.unmap = nil
+	mo.count = 0
+	mo.order = nil
+	mo.domain = NewDomain()
+
+	return err
+}
+
+// load validates the data layout and indexes the headers.
+func (mo *MappedMo) load(data []byte, unmap func() error) error {
+	if len(data) < 28 {
+		return errors.New("invalid mo file")
+	}
+
+	var bo binary.ByteOrder
+	switch binary.LittleEndian.Uint32(data) {
+	case MoMagicLittleEndian:
+		bo = binary.LittleEndian
+	case MoMagicBigEndian:
+		bo = binary.BigEndian
+	default:
+		return errors.New("invalid magic number")
+	}
+
+	if v := bo.Uint16(data[4:]); v != 0 && v != 1 {
+		return errors.New("invalid version number")
+	}
+	if v := bo.Uint16(data[6:]); v != 0 && v != 1 {
+		return errors.New("invalid version number")
+	}
+
+	count := bo.Uint32(data[8:])
+	origTable := bo.Uint32(data[12:])
+	transTable := bo.Uint32(data[16:])
+	if uint64(origTable)+uint64(count)*8 > uint64(len(data)) || uint64(transTable)+uint64(count)*8 > uint64(len(data)) {
+		return errors.New("invalid string tables")
+	}
+
+	mo.mutex.Lock()
+	defer mo.mutex.Unlock()
+
+	mo.release()
+	mo.data = data
+	mo.unmap = unmap
+	mo.bo = bo
+	mo.count = count
+	mo.origTable = origTable
+	mo.transTable = transTable
+
+	// msgfmt sorts the msgid table, build an index only for files that aren't sorted.
+	for i := uint32(1); i < count; i++ {
+		if bytes.Compare(mo.key(i-1), mo.key(i)) > 0 {
+			mo.order = make([]uint32, count)
+			for j := range mo.order {
+				mo.order[j] = uint32(j)
+			}
+			sort.Slice(mo.order, func(a, b int) bool {
+				return bytes.Compare(mo.key(mo.order[a]), mo.key(mo.order[b])) < 0
+			})
+			break
+		}
+	}
+
+	// Parse headers
+	if header, ok := mo.lookup(""); ok {
+		tr := NewTranslation()
+		tr.Trs[0] = string(header)
+		mo.domain.entries[entryKey{}] = tr
+		mo.domain.parseHeaders()
+		mo.domain.publish()
+	}
+
+	return nil
+}
+
+// entry returns the string at index i of the table starting at offset table, or nil if it's out of bounds.
+func (mo *MappedMo) entry(table, i uint32) []byte {
+	pos := uint64(table) + uint64(i)*8
+	length := uint64(mo.bo.Uint32(mo.data[pos:]))
+	offset := uint64(mo.bo.Uint32(mo.data[pos+4:]))
+	if offset+length > uint64(len(mo.data)) {
+		return nil
+	}
+
+	return mo.data[offset : offset+length]
+}
+
+// key returns the msgid at index i, with its context but without the plural msgid.
+func (mo *MappedMo) key(i uint32) []byte {
+	k := mo.entry(mo.origTable, i)
+	if idx := bytes.IndexByte(k, 0); idx != -1 {
+		k = k[:idx]
+	}
+
+	return k
+}
+
+// lookup returns the raw translation data for the given key, it must be called holding the read lock.
+func (mo *MappedMo) lookup(key string) ([]byte, bool) {
+	k := []byte(key)
+
+	i := sort.Search(int(mo.count), func(i int) bool {
+		return bytes.Compare(mo.key(mo.index(uint32(i))), k) >= 0
+	})
+	if i == int(mo.count) || !bytes.Equal(mo.key(mo.index(uint32(i))), k) {
+		return nil, false
+	}
+
+	return mo.entry(mo.transTable, mo.index(uint32(i))), true
+}
+
+// index maps a position in sorted order to an entry index.
+func (mo *MappedMo) index(i uint32) uint32 {
+	if mo.order != nil {
+		return mo.order[i]
+	}
+	return i
+}
+
+// get returns the nth plural form of the translation for the given key, copied out of the mapping.
+func (mo *MappedMo) get(key string, n int) (string, bool) {
+	data, ok := mo.lookup(key)
+	if !ok {
+		return "", false
+	}
+
+	forms := bytes.Split(data, []byte(NulSeparator))
+	if n >= len(forms) || len(forms[n]) == 0 {
+		return "", false
+	}
+
+	return string(forms[n]), true
+}
+
+// Lookup returns the Translation for the given context (empty for none) and msgid, copied out of the mapping.
+// It implements the Backend interface.
+func (mo *MappedMo) Lookup(ctx, id string) (*Translation, bool) {
+	mo.mutex.RLock()
+	defer mo.mutex.RUnlock()
+
+	key := id
+	if ctx != "" {
+		key = ctx + EotSeparator + id
+	}
+
+	data, ok := mo.lookup(key)
+	if !ok {
+		return nil, false
+	}
+
+	tr := NewTranslation()
+	tr.ID = id
+	for i, form := range bytes.Split(data, []byte(NulSeparator)) {
+		tr.Trs[i] = string(form)
+	}
+
+	return tr, true
+}
+
+// PluralForm returns the index of the plural form to use for n, according to the Plural-Forms header.
+// It implements the Backend interface.
+func (mo *MappedMo) PluralForm(n int) int {
+	return mo.GetDomain().pluralForm(n)
+}
+
+func (mo *MappedMo) GetDomain() *Domain {
+	mo.mutex.RLock()
+	defer mo.mutex.RUnlock()
+
+	return mo.domain
+}
+
+// Get retrieves the corresponding Translation for the given string.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (mo *MappedMo) Get(str string, vars ...interface{}) string {
+	mo.mutex.RLock()
+	defer mo.mutex.RUnlock()
+
+	if tr, ok := mo.get(str, 0); ok {
+		return Printf(tr, vars...)
+	}
+
+	return Printf(str, vars...)
+}
+
+// GetN retrieves the (N)th plural form of Translation for the given string.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (mo *MappedMo) GetN(str, plural string, n int, vars ...interface{}) string {
+	mo.mutex.RLock()
+	defer mo.mutex.RUnlock()
+
+	form := mo.domain.pluralForm(n)
+	if tr, ok := mo.get(str, form); ok {
+		return Printf(tr, vars...)
+	}
+
+	if form == 0 {
+		return Printf(str, vars...)
+	}
+	return Printf(plural, vars...)
+}
+
+// GetC retrieves the corresponding Translation for a given string in the given context.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (mo *MappedMo) GetC(str, ctx string, vars ...interface{}) string {
+	mo.mutex.RLock()
+	defer mo.mutex.RUnlock()
+
+	if tr, ok := mo.get(ctx+EotSeparator+str, 0); ok {
+		return Printf(tr, vars...)
+	}
+
+	return Printf(str, vars...)
+}
+
+// GetNC retrieves the (N)th plural form of Translation for the given string in the given context.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (mo *MappedMo) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	mo.mutex.RLock()
+	defer mo.mutex.RUnlock()
+
+	if tr, ok := mo.get(ctx+EotSeparator+str, mo.domain.pluralForm(n)); ok {
+		return Printf(tr, vars...)
+	}
+
+	if n == 1 {
+		return Printf(str, vars...)
+	}
+	return Printf(plural, vars...)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+// It loads the whole catalog into memory, as the encoding is shared with the other Translator implementations.
+func (mo *MappedMo) MarshalBinary() ([]byte, error) {
+	mo.mutex.RLock()
+	defer mo.mutex.RUnlock()
+
+	m := NewMo()
+	m.Parse(mo.data)
+
+	return m.MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+// Mapped catalogs can't be restored from their encoding, use TranslatorEncoding.GetTranslator instead.
+func (mo *MappedMo) UnmarshalBinary(data []byte) error {
+	return errors.New("MappedMo can't be unmarshalled")
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestMappedMo(t *testing.T) {
+	mo := NewMo()
+	mo.ParseFile("fixtures/en_US/default.mo")
+
+	mapped, err := OpenMappedMo("fixtures/en_US/default.mo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Compare with the regular Mo implementation
+	v := "Variable"
+	for _, test := range []struct{ expected, got string }{
+		{mo.Get("My text"), mapped.Get("My text")},
+		{mo.Get("One with var: %s", v), mapped.Get("One with var: %s", v)},
+		{mo.Get("multilineid"), mapped.Get("multilineid")},
+		{mo.Get("Multi-line"), mapped.Get("Multi-line")},
+		{mo.GetN("One with var: %s", "Several with vars: %s", 2, v), mapped.GetN("One with var: %s", "Several with vars: %s", 2, v)},
+		{mo.Get("This is a test"), mapped.Get("This is a test")},
+		{mo.GetN("This is a test", "This are tests", 100), mapped.GetN("This is a test", "This are tests", 100)},
+		{mo.GetC("One with var: %s", "Ctx", v), mapped.GetC("One with var: %s", "Ctx", v)},
+		{mo.GetNC("One with var: %s", "Several with vars: %s", 17, "Ctx", v), mapped.GetNC("One with var: %s", "Several with vars: %s", 17, "Ctx", v)},
+		{mo.Get("Empty Translation"), mapped.Get("Empty Translation")},
+		{mo.GetN("Empty plural form singular", "Empty plural form", 2), mapped.GetN("Empty plural form singular", "Empty plural form", 2)},
+		{mo.Get("More"), mapped.Get("More")},
+	} {
+		if test.expected != test.got {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.got)
+		}
+	}
+
+	if mapped.GetDomain().Language != "en_US" {
+		t.Errorf("Expected 'en_US' but got '%s'", mapped.GetDomain().Language)
+	}
+
+	// Use it from a Locale
+	l := NewLocale("", "en_US")
+	l.AddTranslator("default", mapped)
+	if tr := l.Get("My text"); tr != translatedText {
+		t.Errorf("Expected '%s' but got '%s'", translatedText, tr)
+	}
+
+	// Strings are still available after closing, but lookups aren't
+	tr := mapped.Get("My text")
+	if err := mapped.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if tr != translatedText {
+		t.Errorf("Expected '%s' but got '%s'", translatedText, tr)
+	}
+	if tr = mapped.Get("My text"); tr != "My text" {
+		t.Errorf("Expected 'My text' but got '%s'", tr)
+	}
+}
+
+func TestMappedMoInvalid(t *testing.T) {
+	if _, err := OpenMappedMo("fixtures/en_US/default.po"); err == nil {
+		t.Error("Expected error opening a PO file")
+	}
+
+	data, err := ioutil.ReadFile("fixtures/en_US/default.mo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Truncated tables
+	mapped := NewMappedMo()
+	mapped.Parse(data[:40])
+	if tr := mapped.Get("My text"); tr != "My text" {
+		t.Errorf("Expected 'My text' but got '%s'", tr)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// moKey returns the key of the entry in a MO file: the msgid, joined with its plural by NulSeparator
+// and prefixed by its context and EotSeparator.
+func moKey(e *PoEntry) string {
+	key := e.ID
+	if e.PluralID != "" {
+		key += NulSeparator + e.PluralID
+	}
+	if e.HasContext {
+		key = e.Context + EotSeparator + key
+	}
+
+	return key
+}
+
+// MarshalMo compiles the catalog into the GNU gettext .mo format, as msgfmt does:
+// obsolete and untranslated entries are left out, as are fuzzy ones unless useFuzzy is set.
+// The header is always kept. Entries are sorted by key and no hash table is written.
+func (f *PoFile) MarshalMo(useFuzzy bool) []byte {
+	type moEntry struct{ key, str string }
+
+	var entries []moEntry
+	for _, e := range f.Entries {
+		if e.Obsolete {
+			continue
+		}
+		if !e.IsHeader() && (!e.Translated() || (e.HasFlag("fuzzy") && !useFuzzy)) {
+			continue
+		}
+		if e.IsHeader() && len(e.Str) == 0 {
+			continue
+		}
+		entries = append(entries, moEntry{moKey(e), strings.Join(e.Str, NulSeparator)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	// Header, then the tables of the lengths and offsets of the msgids and msgstrs, then the strings.
+	const headerSize = 28
+	n := uint32(len(entries))
+	idTable := uint32(headerSize)
+	strTable := idTable + 8*n
+	offset := strTable + 8*n
+
+	var buf bytes.Buffer
+	for _, v := range []uint32{MoMagicLittleEndian, 0, n, idTable, strTable, 0, offset} {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+
+	// Strings are NUL terminated, as written by msgfmt, although their length doesn't count it.
+	var strs bytes.Buffer
+	table := func(s string) {
+		binary.Write(&buf, binary.LittleEndian, uint32(len(s)))
+		binary.Write(&buf, binary.LittleEndian, offset+uint32(strs.Len()))
+		strs.WriteString(s)
+		strs.WriteByte(0)
+	}
+	for _, e := range entries {
+		table(e.key)
+	}
+	for _, e := range entries {
+		table(e.str)
+	}
+	buf.Write(strs.Bytes())
+
+	return buf.Bytes()
+}
+
+// UnmarshalMo decompiles a file in the GNU gettext .mo format into a PoFile, as msgunfmt does.
+// Entries are kept in their order, the header being first. Unlike Mo.Parse, it fails on malformed files.
+func UnmarshalMo(data []byte) (*PoFile, error) {
+	if len(data) < 28 {
+		return nil, errors.New("mo: file too short")
+	}
+
+	var bo binary.ByteOrder
+	switch binary.LittleEndian.Uint32(data) {
+	case MoMagicLittleEndian:
+		bo = binary.LittleEndian
+	case MoMagicBigEndian:
+		bo = binary.BigEndian
+	default:
+		return nil, errors.New("mo: invalid magic number")
+	}
+	if major := bo.Uint16(data[4:]); major != 0 && major != 1 {
+		return nil, fmt.Errorf("mo: unsupported version %d", major)
+	}
+	n, idTable, strTable := bo.Uint32(data[8:]), bo.Uint32(data[12:]), bo.Uint32(data[16:])
+
+	// str returns the i-th string of the table at the given offset.
+	str := func(table, i uint32) (string, error) {
+		pos := uint64(table) + 8*uint64(i)
+		if pos+8 > uint64(len(data)) {
+			return "", errors.New("mo: string table out of range")
+		}
+		length, offset := uint64(bo.Uint32(data[pos:])), uint64(bo.Uint32(data[pos+4:]))
+		if offset+length > uint64(len(data)) {
+			return "", fmt.Errorf("mo: string %d out of range", i)
+		}
+
+		return string(data[offset : offset+length]), nil
+	}
+
+	f := new(PoFile)
+	for i := uint32(0); i < n; i++ {
+		key, err := str(idTable, i)
+		if err != nil {
+			return nil, err
+		}
+		msgstr, err := str(strTable, i)
+		if err != nil {
+			return nil, err
+		}
+
+		e := &PoEntry{Str: strings.Split(msgstr, NulSeparator)}
+		if i := strings.Index(key, EotSeparator); i >= 0 {
+			e.Context, e.HasContext, key = key[:i], true, key[i+1:]
+		}
+		if i := strings.Index(key, NulSeparator); i >= 0 {
+			e.ID, e.PluralID = key[:i], key[i+1:]
+		} else {
+			e.ID = key
+			e.Str = []string{msgstr}
+		}
+
+		if e.IsHeader() {
+			f.Entries = append([]*PoEntry{e}, f.Entries...)
+		} else {
+			f.Entries = append(f.Entries, e)
+		}
+	}
+
+	return f, nil
+}
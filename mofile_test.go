@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalMo(t *testing.T) {
+	data, err := ioutil.ReadFile("fixtures/fr/LC_MESSAGES/default.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := ParsePoFile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mo := NewMo()
+	mo.Parse(f.MarshalMo(false))
+	po := NewPo()
+	po.Parse(data)
+
+	if mo.Language != "fr" {
+		t.Errorf("Expected 'fr' but got '%s'", mo.Language)
+	}
+	tests := []struct{ mo, po string }{
+		{mo.Get("My text"), po.Get("My text")},
+		{mo.Get("language"), po.Get("language")},
+		{mo.GetN("One with var: %s", "Several with vars: %s", 2, "v"), po.GetN("One with var: %s", "Several with vars: %s", 2, "v")},
+		{mo.GetC("Some random in a context", "Ctx"), po.GetC("Some random in a context", "Ctx")},
+		{mo.GetNC("One with var: %s", "Several with vars: %s", 1, "Ctx", "v"), po.GetNC("One with var: %s", "Several with vars: %s", 1, "Ctx", "v")},
+	}
+	for _, test := range tests {
+		if test.mo != test.po {
+			t.Errorf("Expected '%s' but got '%s'", test.po, test.mo)
+		}
+	}
+}
+
+func TestMarshalMoFuzzy(t *testing.T) {
+	f, err := ParsePoFile([]byte(`msgid ""
+msgstr "Language: de\n"
+
+#, fuzzy
+msgid "Fuzzy"
+msgstr "Unscharf"
+
+msgid "Untranslated"
+msgstr ""
+
+#~ msgid "Obsolete"
+#~ msgstr "Veraltet"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mo := NewMo()
+	mo.Parse(f.MarshalMo(false))
+	if tr := mo.Get("Fuzzy"); tr != "Fuzzy" {
+		t.Errorf("Expected 'Fuzzy' but got '%s'", tr)
+	}
+	if tr := mo.Get("Obsolete"); tr != "Obsolete" {
+		t.Errorf("Expected 'Obsolete' but got '%s'", tr)
+	}
+
+	mo = NewMo()
+	mo.Parse(f.MarshalMo(true))
+	if tr := mo.Get("Fuzzy"); tr != "Unscharf" {
+		t.Errorf("Expected 'Unscharf' but got '%s'", tr)
+	}
+	if tr := mo.Get("Untranslated"); tr != "Untranslated" {
+		t.Errorf("Expected 'Untranslated' but got '%s'", tr)
+	}
+}
+
+func TestUnmarshalMo(t *testing.T) {
+	data, err := ioutil.ReadFile("fixtures/fr/LC_MESSAGES/default.mo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := UnmarshalMo(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Decompiled catalogs compile back to the same translations.
+	mo, po := NewMo(), NewPo()
+	mo.Parse(data)
+	po.Parse(f.Format(FormatOptions{}))
+	moEntries, poEntries := mo.GetDomain().load().entries, po.GetDomain().load().entries
+	if len(moEntries) != len(poEntries) {
+		t.Errorf("Expected %d entries but got %d", len(moEntries), len(poEntries))
+	}
+	for k, tr := range moEntries {
+		if poEntries[k] == nil || !reflect.DeepEqual(tr.Trs, poEntries[k].Trs) {
+			t.Errorf("Expected the decompiled catalog to hold the translations of %q", k.id)
+		}
+	}
+	if h := f.Header(); h == nil || f.Entries[0] != h {
+		t.Error("Expected the header to be the first entry")
+	}
+
+	e := f.Find("Ctx", "One with var: %s")
+	if e == nil || e.PluralID != "Several with vars: %s" || len(e.Str) != 2 {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+
+	for _, bad := range [][]byte{nil, []byte("not a mo file, but long enough to be one"), data[:40]} {
+		if _, err := UnmarshalMo(bad); err == nil {
+			t.Errorf("Expected an error decompiling %q", bad)
+		}
+	}
+}
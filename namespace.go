@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+/*
+Namespace is a Locale scoped to a prefix, so component libraries sharing catalogs avoid collisions without repeating
+long prefixes at every call site. The semantic keys looked up with TKey are prefixed, and the lookups of source strings
+are made in the context of the prefix, followed by the context given if any.
+
+Example:
+
+	settings := l.Namespace("settings.")
+
+	settings.TKey("title", "Settings")    // l.TKey("settings.title", "Settings")
+	settings.Get("Save")                  // l.GetC("Save", "settings.")
+	settings.GetC("Save", "dialog")       // l.GetC("Save", "settings.dialog")
+*/
+type Namespace struct {
+	locale *Locale
+	prefix string
+}
+
+// Namespace returns the Locale scoped to the prefix, i.e. "settings.".
+func (l *Locale) Namespace(prefix string) *Namespace {
+	return &Namespace{locale: l, prefix: prefix}
+}
+
+// Namespace returns the namespace nested in this one, its prefix following the namespace's.
+func (ns *Namespace) Namespace(prefix string) *Namespace {
+	return &Namespace{locale: ns.locale, prefix: ns.prefix + prefix}
+}
+
+// Prefix returns the prefix of the keys and contexts of the namespace.
+func (ns *Namespace) Prefix() string {
+	return ns.prefix
+}
+
+// Locale returns the Locale the namespace is scoped from.
+func (ns *Namespace) Locale() *Locale {
+	return ns.locale
+}
+
+// TKey returns the translation of the prefixed semantic key in the default domain, or def when it isn't translated.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (ns *Namespace) TKey(key, def string, vars ...interface{}) string {
+	return ns.locale.TKey(ns.prefix+key, def, vars...)
+}
+
+// TKeyD returns the translation of the prefixed semantic key in the given domain, or def when it isn't translated.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (ns *Namespace) TKeyD(dom, key, def string, vars ...interface{}) string {
+	return ns.locale.TKeyD(dom, ns.prefix+key, def, vars...)
+}
+
+// Get returns the Translation of the given string in the context of the prefix, in the default domain.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (ns *Namespace) Get(str string, vars ...interface{}) string {
+	return ns.locale.GetC(str, ns.prefix, vars...)
+}
+
+// GetN retrieves the (N)th plural form of Translation for the given string in the context of the prefix, in the
+// default domain.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (ns *Namespace) GetN(str, plural string, n int, vars ...interface{}) string {
+	return ns.locale.GetNC(str, plural, n, ns.prefix, vars...)
+}
+
+// GetD returns the Translation of the given string in the context of the prefix, in the given domain.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (ns *Namespace) GetD(dom, str string, vars ...interface{}) string {
+	return ns.locale.GetDC(dom, str, ns.prefix, vars...)
+}
+
+// GetND retrieves the (N)th plural form of Translation for the given string in the context of the prefix, in the
+// given domain.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (ns *Namespace) GetND(dom, str, plural string, n int, vars ...interface{}) string {
+	return ns.locale.GetNDC(dom, str, plural, n, ns.prefix, vars...)
+}
+
+// GetC returns the Translation of the given string in the prefixed context, in the default domain.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (ns *Namespace) GetC(str, ctx string, vars ...interface{}) string {
+	return ns.locale.GetC(str, ns.prefix+ctx, vars...)
+}
+
+// GetNC retrieves the (N)th plural form of Translation for the given string in the prefixed context, in the default
+// domain.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (ns *Namespace) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	return ns.locale.GetNC(str, plural, n, ns.prefix+ctx, vars...)
+}
+
+// GetDC returns the Translation of the given string in the prefixed context, in the given domain.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (ns *Namespace) GetDC(dom, str, ctx string, vars ...interface{}) string {
+	return ns.locale.GetDC(dom, str, ns.prefix+ctx, vars...)
+}
+
+// GetNDC retrieves the (N)th plural form of Translation for the given string in the prefixed context, in the given
+// domain.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (ns *Namespace) GetNDC(dom, str, plural string, n int, ctx string, vars ...interface{}) string {
+	return ns.locale.GetNDC(dom, str, plural, n, ns.prefix+ctx, vars...)
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestNamespace(t *testing.T) {
+	str := `msgid ""
+msgstr ""
+"Language: de\n"
+
+msgid "Save"
+msgstr "Speichern"
+
+msgctxt "settings."
+msgid "Save"
+msgstr "Einstellungen speichern"
+
+msgctxt "settings.dialog"
+msgid "Close"
+msgstr "Dialog schließen"
+
+msgctxt "settings.general."
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "Eine Datei"
+msgstr[1] "%d Dateien"
+
+msgid "settings.general.title"
+msgstr "Allgemein"
+`
+
+	// Create Locales directory
+	dirname := path.Join("/tmp", "gotextnamespace", "de", "LC_MESSAGES")
+	err := os.MkdirAll(dirname, os.ModePerm)
+	if err != nil {
+		t.Fatalf("Can't create test directory: %s", err.Error())
+	}
+
+	// Write PO content to file
+	err = ioutil.WriteFile(path.Join(dirname, "default.po"), []byte(str), 0644)
+	if err != nil {
+		t.Fatalf("Can't write to test file: %s", err.Error())
+	}
+
+	l := NewLocale("/tmp/gotextnamespace", "de")
+	l.AddDomain("default")
+
+	settings := l.Namespace("settings.")
+	general := settings.Namespace("general.")
+	if general.Prefix() != "settings.general." {
+		t.Errorf("Expected '%s' but got '%s'", "settings.general.", general.Prefix())
+	}
+
+	tests := []struct {
+		tr, expected string
+	}{
+		{l.Get("Save"), "Speichern"},
+		{settings.Get("Save"), "Einstellungen speichern"},
+		{settings.GetD("default", "Save"), "Einstellungen speichern"},
+		{settings.GetC("Close", "dialog"), "Dialog schließen"},
+		{settings.GetDC("default", "Close", "dialog"), "Dialog schließen"},
+		{general.GetN("One file", "%d files", 3, 3), "3 Dateien"},
+		{general.GetND("default", "One file", "%d files", 1), "Eine Datei"},
+		{general.TKey("title", "General"), "Allgemein"},
+		{general.TKeyD("default", "summary", "Summary"), "Summary"},
+		{settings.Get("Cancel"), "Cancel"},
+	}
+	for _, test := range tests {
+		if test.tr != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.tr)
+		}
+	}
+}
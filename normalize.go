@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "golang.org/x/text/unicode/norm"
+
+// nfcKey returns the key with its context and msgid in Unicode normalization form C, so composed and decomposed forms
+// of the same string, i.e. "é" as U+00E9 or as "e" followed by U+0301, have the same key.
+func nfcKey(k entryKey) entryKey {
+	return entryKey{ctx: norm.NFC.String(k.ctx), id: norm.NFC.String(k.id)}
+}
+
+// nfcIndex returns the entries whose key isn't in normalization form C by normalized key, nil when there is none, as
+// for most catalogs. When several entries share a normalized key, the one with the lowest key is kept.
+func nfcIndex(entries map[entryKey]*Translation) map[entryKey]*Translation {
+	var index map[entryKey]*Translation
+	exact := make(map[entryKey]entryKey)
+	for k, tr := range entries {
+		nk := nfcKey(k)
+		if nk == k {
+			continue
+		}
+		if prev, ok := exact[nk]; ok && keyLess(prev, k) {
+			continue
+		}
+		if index == nil {
+			index = make(map[entryKey]*Translation)
+		}
+		index[nk] = tr
+		exact[nk] = k
+	}
+
+	return index
+}
+
+// keyLess reports if the key a sorts before b, by context then msgid.
+func keyLess(a, b entryKey) bool {
+	if a.ctx != b.ctx {
+		return a.ctx < b.ctx
+	}
+	return a.id < b.id
+}
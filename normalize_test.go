@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "testing"
+
+func TestNormalizedLookup(t *testing.T) {
+	// "Café" composed in the catalog, decomposed in the context, and the other way around
+	str := "msgid \"Café\"\nmsgstr \"Coffee shop\"\n\n" +
+		"msgctxt \"résumé\"\nmsgid \"Name\"\nmsgstr \"Full name\"\n\n" +
+		"msgid \"Née\"\nmsgstr \"Born\"\n"
+
+	po := NewPo()
+	po.Parse([]byte(str))
+
+	tests := []struct {
+		tr, expected string
+	}{
+		{po.Get("Café"), "Coffee shop"},
+		{po.Get("Café"), "Coffee shop"},
+		{po.GetC("Name", "résumé"), "Full name"},
+		{po.Get("Née"), "Born"},
+		{po.Get("Née"), "Born"},
+		{po.Get("Cafe"), "Cafe"},
+	}
+	for _, test := range tests {
+		if test.tr != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.tr)
+		}
+	}
+
+	// Exact matches come first
+	po.Parse([]byte("msgid \"Café\"\nmsgstr \"Bistro\"\n"))
+	if tr := po.Get("Café"); tr != "Bistro" {
+		t.Errorf("Expected '%s' but got '%s'", "Bistro", tr)
+	}
+	if tr := po.Get("Café"); tr != "Coffee shop" {
+		t.Errorf("Expected '%s' but got '%s'", "Coffee shop", tr)
+	}
+
+	po.GetDomain().SetLooseLookup(true)
+	if tr := po.Get(" NÉE "); tr != "Born" {
+		t.Errorf("Expected '%s' but got '%s'", "Born", tr)
+	}
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReloadEvent describes a catalog loaded or replaced on a Locale.
+type ReloadEvent struct {
+	Language string `json:"language"`
+	Domain   string `json:"domain"`
+	Entries  int    `json:"entries"`
+}
+
+// NotifierKeepAlive is the interval between the keep-alive comments sent to idle Server-Sent Events clients.
+var NotifierKeepAlive = 30 * time.Second
+
+/*
+Notifier pushes catalog reload events to subscribers, so live-preview translation editing updates every screen
+without manual refreshes. It's an Observer to be set on the Locales to watch, forwarding all events to another
+Observer if given. It's also an http.Handler streaming the events to web clients as Server-Sent Events,
+which other service instances can follow with WatchReloads.
+
+Example:
+
+	n := gotext.NewNotifier(nil)
+	l.SetObserver(n)
+	http.Handle("/i18n/events", n)
+
+	// Browser
+	new EventSource("/i18n/events").addEventListener("reload", e => reloadTranslations(JSON.parse(e.data)))
+*/
+type Notifier struct {
+	next Observer
+
+	subscribers map[chan ReloadEvent]struct{}
+	mutex       sync.Mutex
+}
+
+var _ Observer = (*Notifier)(nil)
+
+// NewNotifier returns a Notifier forwarding the observed events to next, when not nil.
+func NewNotifier(next Observer) *Notifier {
+	return &Notifier{
+		next:        next,
+		subscribers: make(map[chan ReloadEvent]struct{}),
+	}
+}
+
+// ObserveLookup implements Observer, forwarding lookups to the next Observer.
+func (n *Notifier) ObserveLookup(lang, dom string, found, fuzzy bool) {
+	if n.next != nil {
+		n.next.ObserveLookup(lang, dom, found, fuzzy)
+	}
+}
+
+// ObserveLoad implements Observer, notifying subscribers of the reload.
+func (n *Notifier) ObserveLoad(lang, dom string, entries int) {
+	if n.next != nil {
+		n.next.ObserveLoad(lang, dom, entries)
+	}
+
+	n.Notify(ReloadEvent{Language: lang, Domain: dom, Entries: entries})
+}
+
+// Notify sends an event to all subscribers. Events are dropped for subscribers not keeping up.
+func (n *Notifier) Notify(ev ReloadEvent) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	for ch := range n.subscribers {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving the reload events, and a function to unsubscribe.
+func (n *Notifier) Subscribe() (<-chan ReloadEvent, func()) {
+	ch := make(chan ReloadEvent, 16)
+
+	n.mutex.Lock()
+	n.subscribers[ch] = struct{}{}
+	n.mutex.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			n.mutex.Lock()
+			delete(n.subscribers, ch)
+			n.mutex.Unlock()
+		})
+	}
+}
+
+// ServeHTTP streams the reload events as Server-Sent Events named "reload", with the JSON encoded ReloadEvent as data.
+func (n *Notifier) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events, unsubscribe := n.Subscribe()
+	defer unsubscribe()
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(NotifierKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case ev := <-events:
+			data, err := json.Marshal(ev)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "event: reload\ndata: %s\n\n", data)
+			flusher.Flush()
+
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// WatchReloads follows the Server-Sent Events stream served by a Notifier at url, i.e. on another service instance,
+// calling fn for each reload event until ctx is done or the stream ends.
+func WatchReloads(ctx context.Context, url string, fn func(ReloadEvent)) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The stream is long lived, so the client timeout doesn't apply
+	client := *HTTPClient
+	client.Timeout = 0
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("watching %s: %s", url, resp.Status)
+	}
+
+	event := ""
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			event = ""
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(line[len("event:"):])
+		case strings.HasPrefix(line, "data:") && event == "reload":
+			var ev ReloadEvent
+			if err := json.Unmarshal([]byte(strings.TrimSpace(line[len("data:"):])), &ev); err == nil {
+				fn(ev)
+			}
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return scanner.Err()
+}
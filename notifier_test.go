@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNotifier(t *testing.T) {
+	o := new(testObserver)
+	n := NewNotifier(o)
+
+	l := NewLocale("fixtures/", "en_US")
+	l.SetObserver(n)
+
+	events, unsubscribe := n.Subscribe()
+	l.AddDomain("default")
+
+	select {
+	case ev := <-events:
+		if ev.Language != "en_US" || ev.Domain != "default" || ev.Entries == 0 {
+			t.Errorf("Unexpected event %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected reload event")
+	}
+	unsubscribe()
+
+	if len(o.events) != 1 {
+		t.Errorf("Expected events to be forwarded but got %v", o.events)
+	}
+}
+
+func TestNotifierStream(t *testing.T) {
+	n := NewNotifier(nil)
+	srv := httptest.NewServer(n)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan ReloadEvent, 1)
+	go WatchReloads(ctx, srv.URL, func(ev ReloadEvent) {
+		received <- ev
+	})
+
+	// Wait for the client to subscribe
+	deadline := time.Now().Add(time.Second)
+	for {
+		n.mutex.Lock()
+		subscribed := len(n.subscribers) > 0
+		n.mutex.Unlock()
+		if subscribed || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	po := NewPo()
+	po.Parse([]byte("msgid \"Hello\"\nmsgstr \"Bonjour\"\n"))
+	l := NewLocale("", "fr")
+	l.SetObserver(n)
+	l.AddTranslator("greetings", po)
+
+	select {
+	case ev := <-received:
+		if ev != (ReloadEvent{Language: "fr", Domain: "greetings", Entries: 1}) {
+			t.Errorf("Unexpected event %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected streamed reload event")
+	}
+}
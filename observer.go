@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+// Observer receives translation events from a Locale, i.e. to export metrics and alert on untranslated strings.
+// Implementations must be safe for concurrent use, and must not call back into the Locale.
+// See the prommetrics module for a Prometheus implementation.
+type Observer interface {
+	// ObserveLookup is called on every lookup, reporting if a translation was found and if it's fuzzy.
+	ObserveLookup(lang, dom string, found, fuzzy bool)
+
+	// ObserveLoad is called when a domain catalog is loaded, replaced or removed, with its amount of entries.
+	// Backends not holding their entries in a Domain report 0 entries.
+	ObserveLoad(lang, dom string, entries int)
+}
+
+// SetObserver sets the Observer receiving the Locale translation events, nil removes it.
+// The catalogs already loaded are reported right away.
+func (l *Locale) SetObserver(o Observer) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.observer = o
+	l.publish()
+	for dom, tr := range l.Domains {
+		l.observeLoad(dom, tr)
+	}
+}
+
+// observeLookup reports a lookup to the observer, checking if the Translator holds a translation for the string.
+func (l *Locale) observeLookup(o Observer, dom, ctx, str string, tr Translator) {
+	var found, fuzzy bool
+	if tr != nil {
+		if t, ok := backendOf(tr).Lookup(ctx, str); ok && t.Trs[0] != "" {
+			found, fuzzy = true, t.Fuzzy
+		}
+	}
+
+	o.ObserveLookup(l.lang, dom, found, fuzzy)
+}
+
+// observeLoad reports a domain catalog change to the observer, it must be called holding the write lock.
+func (l *Locale) observeLoad(dom string, tr Translator) {
+	if l.observer == nil {
+		return
+	}
+
+	entries := 0
+	if tr != nil {
+		c := tr.GetDomain().load()
+		entries = len(c.entries)
+		if _, ok := c.entries[entryKey{}]; ok {
+			entries--
+		}
+	}
+
+	l.observer.ObserveLoad(l.lang, dom, entries)
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type testObserver struct {
+	sync.Mutex
+	events []string
+}
+
+func (o *testObserver) ObserveLookup(lang, dom string, found, fuzzy bool) {
+	o.Lock()
+	o.events = append(o.events, fmt.Sprintf("lookup %s %s %v %v", lang, dom, found, fuzzy))
+	o.Unlock()
+}
+
+func (o *testObserver) ObserveLoad(lang, dom string, entries int) {
+	o.Lock()
+	o.events = append(o.events, fmt.Sprintf("load %s %s %d", lang, dom, entries))
+	o.Unlock()
+}
+
+func TestLocaleObserver(t *testing.T) {
+	o := new(testObserver)
+
+	l := NewLocale("fixtures/", "en_US")
+	l.SetObserver(o)
+
+	po := NewPo()
+	po.Parse([]byte(`
+msgid ""
+msgstr "Language: en_US\n"
+
+msgid "Hello"
+msgstr "Hi"
+
+msgid "Bye"
+msgstr "Cheers"
+`))
+	l.AddTranslator("greetings", po)
+	l.GetD("greetings", "Hello")
+	l.GetD("greetings", "Missing")
+	l.GetND("other", "Missing", "Missing ones", 2)
+
+	l.SetMissingTranslator(MissingTranslatorFunc(func(lang, dom, ctx, str string) (string, error) {
+		return "draft", nil
+	}))
+	l.GetDC("greetings", "Missing", "Ctx")
+
+	expected := []string{
+		"load en_US greetings 2",
+		"lookup en_US greetings true false",
+		"lookup en_US greetings false false",
+		"lookup en_US other false false",
+		"lookup en_US greetings true true",
+	}
+	if !reflect.DeepEqual(o.events, expected) {
+		t.Errorf("Expected %v but got %v", expected, o.events)
+	}
+
+	l.SetObserver(nil)
+	l.Get("Hello")
+	if len(o.events) != len(expected) {
+		t.Errorf("Expected no events after removing the observer but got %v", o.events[len(expected):])
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+// Layer identifies the catalog layer satisfying a lookup. See Locale.ResolveLayer.
+type Layer int
+
+const (
+	// LayerNone means no layer holds a translation, so the msgid is returned.
+	LayerNone Layer = iota
+
+	// LayerOverride means the translation comes from the override catalog set with Locale.SetOverride.
+	LayerOverride
+
+	// LayerBase means the translation comes from the catalog loaded for the domain.
+	LayerBase
+
+	// LayerMissing means the translation is a draft provided by the MissingTranslator set with Locale.SetMissingTranslator.
+	LayerMissing
+
+	// LayerFallback means the translation comes from the catalog of another domain set with Locale.SetFallbackDomains.
+	LayerFallback
+)
+
+// String returns the name of the layer.
+func (ly Layer) String() string {
+	switch ly {
+	case LayerOverride:
+		return "override"
+	case LayerBase:
+		return "base"
+	case LayerMissing:
+		return "missing"
+	case LayerFallback:
+		return "fallback"
+	}
+	return "none"
+}
+
+/*
+SetOverride stacks an override catalog on top of a domain, i.e. for white-label customers renaming "Project" to "Workspace".
+Translations in the override take precedence over the domain catalog, which keeps resolving all the others,
+even if the domain is loaded or replaced after the override is set. A nil Translator removes the override.
+
+Example:
+
+	l := gotext.NewLocale("/path/to/i18n/dir", "en_US")
+	l.AddDomain("default")
+
+	overrides := gotext.NewPo()
+	overrides.ParseFile("/path/to/customers/acme/default.po")
+	l.SetOverride("default", overrides)
+
+	fmt.Println(l.Get("Project")) // Workspace
+*/
+func (l *Locale) SetOverride(dom string, tr Translator) {
+	l.Lock()
+	defer l.Unlock()
+
+	if tr == nil {
+		delete(l.overrides, dom)
+	} else {
+		if l.overrides == nil {
+			l.overrides = make(map[string]Translator)
+		}
+		if l.defaultDomain == "" {
+			l.defaultDomain = dom
+		}
+		l.overrides[dom] = tr
+	}
+	l.publish()
+}
+
+// GetOverride returns the override catalog set for a domain, or nil if there's none.
+func (l *Locale) GetOverride(dom string) Translator {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.overrides[dom]
+}
+
+// ResolveLayer reports which layer satisfies the lookup of a string in the given domain and context (empty for none).
+func (l *Locale) ResolveLayer(dom, str, ctx string) Layer {
+	snap := l.load()
+	tr, ok := snap.domains[dom]
+	if !ok {
+		return LayerNone
+	}
+
+	if layers, ok := snap.layers[dom]; ok {
+		if i := tr.(*ChainTranslator).Which(ctx, str); i >= 0 {
+			return layers[i]
+		}
+		return LayerNone
+	}
+
+	if t, ok := backendOf(tr).Lookup(ctx, str); ok && t.Trs[0] != "" {
+		return LayerBase
+	}
+	return LayerNone
+}
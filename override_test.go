@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "testing"
+
+func TestLocaleOverride(t *testing.T) {
+	overrides := NewPo()
+	overrides.Parse([]byte(`
+msgid "My text"
+msgstr "Overridden text"
+`))
+
+	l := NewLocale("fixtures/", "en_US")
+
+	// Overrides can be set before loading the domain
+	l.SetOverride("default", overrides)
+	if tr := l.Get("My text"); tr != "Overridden text" {
+		t.Errorf("Expected 'Overridden text' but got '%s'", tr)
+	}
+
+	l.AddDomain("default")
+	if l.GetOverride("default") != overrides {
+		t.Error("Expected override to be returned")
+	}
+
+	for _, test := range []struct {
+		str, ctx string
+		expected string
+		layer    Layer
+	}{
+		{"My text", "", "Overridden text", LayerOverride},
+		{"One with var: %s", "", "This one is the singular: %s", LayerBase},
+		{"Some random in a context", "Ctx", "Some random translation in a context", LayerBase},
+		{"Missing", "", "Missing", LayerNone},
+	} {
+		if tr := l.GetC(test.str, test.ctx); tr != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, tr)
+		}
+		if ly := l.ResolveLayer("default", test.str, test.ctx); ly != test.layer {
+			t.Errorf("Expected layer '%s' for '%s' but got '%s'", test.layer, test.str, ly)
+		}
+	}
+
+	l.SetOverride("default", nil)
+	if tr := l.Get("My text"); tr != translatedText {
+		t.Errorf("Expected '%s' but got '%s'", translatedText, tr)
+	}
+	if ly := l.ResolveLayer("default", "My text", ""); ly != LayerBase {
+		t.Errorf("Expected layer 'base' but got '%s'", ly)
+	}
+}
+
+func TestLocaleOverrideMarshal(t *testing.T) {
+	overrides := NewPo()
+	overrides.Parse([]byte(`
+msgid "My text"
+msgstr "Overridden text"
+`))
+
+	l := NewLocale("fixtures/", "en_US")
+	l.AddDomain("default")
+	l.SetOverride("default", overrides)
+	l.SetOverride("extra", overrides)
+
+	// Only the base catalogs are serialized
+	data, err := l.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := new(Locale)
+	if err = restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if tr := restored.Get("My text"); tr != translatedText {
+		t.Errorf("Expected '%s' but got '%s'", translatedText, tr)
+	}
+	if doms := restored.GetDomains(); len(doms) != 1 || doms[0] != "default" {
+		t.Errorf("Expected only the 'default' domain but got %v", doms)
+	}
+
+	restored.SetOverride("default", overrides)
+	if tr := restored.Get("My text"); tr != "Overridden text" {
+		t.Errorf("Expected 'Overridden text' but got '%s'", tr)
+	}
+}
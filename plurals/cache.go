@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package plurals
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// precomputed is the amount of n values evaluated ahead of time by CompileCached.
+const precomputed = 256
+
+// table is an Expression answering small values of n from values evaluated ahead of time.
+type table struct {
+	values []int
+	expr   Expression
+}
+
+// Eval implements the Expression interface.
+func (t *table) Eval(n uint32) int {
+	if n < uint32(len(t.values)) {
+		return t.values[n]
+	}
+	return t.expr.Eval(n)
+}
+
+type cacheEntry struct {
+	expr Expression
+	err  error
+}
+
+// cache stores compiled expressions by normalized source.
+var cache sync.Map
+
+// CompileCached works like Compile, but each distinct expression is compiled only once and the resulting
+// Expression is shared by all callers using the same formula (whitespace differences aside).
+// The returned Expression answers small values of n from a precomputed table,
+// so evaluating it is a single slice access in the common case.
+func CompileCached(s string) (Expression, error) {
+	key := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+
+	if e, ok := cache.Load(key); ok {
+		return e.(*cacheEntry).expr, e.(*cacheEntry).err
+	}
+
+	expr, err := Compile(key)
+	entry := &cacheEntry{err: err}
+	if err == nil {
+		entry.expr = precompute(expr)
+	}
+
+	e, _ := cache.LoadOrStore(key, entry)
+	return e.(*cacheEntry).expr, e.(*cacheEntry).err
+}
+
+// precompute returns an Expression evaluating expr with a lookup table for the first values of n.
+func precompute(expr Expression) Expression {
+	t := &table{
+		values: make([]int, precomputed),
+		expr:   expr,
+	}
+	for n := range t.values {
+		t.values[n] = expr.Eval(uint32(n))
+	}
+
+	return t
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package plurals
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestCompileCached(t *testing.T) {
+	f, err := os.Open("testdata/pluralforms.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := json.NewDecoder(f)
+	var fixtures []fixture
+	err = dec.Decode(&fixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, data := range fixtures {
+		expr, err := CompileCached(data.PluralForm)
+		if err != nil {
+			t.Errorf("'%s' triggered error: %s", data.PluralForm, err)
+			continue
+		}
+		for n, e := range data.Fixture {
+			if i := expr.Eval(uint32(n)); i != e {
+				t.Errorf("'%s' with n = %d, expected %d, got %d", data.PluralForm, n, e, i)
+			}
+		}
+
+		// Values after the precomputed table
+		plain, _ := Compile(data.PluralForm)
+		for _, n := range []uint32{precomputed, 1001, 1234567} {
+			if i, e := expr.Eval(n), plain.Eval(n); i != e {
+				t.Errorf("'%s' with n = %d, expected %d, got %d", data.PluralForm, n, e, i)
+			}
+		}
+	}
+}
+
+func TestCompileCachedShared(t *testing.T) {
+	a, err := CompileCached("(n != 1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := CompileCached(" (n != 1) ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a != b {
+		t.Error("Expected the same compiled expression to be shared")
+	}
+
+	if _, err := CompileCached("n ? ? 1"); err == nil {
+		t.Error("Expected error for invalid expression")
+	}
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package plurals
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GoSource compiles a plural form expression to the body of a Go function with the signature
+// func(n uint32) int, so generated code can select plural forms without compiling expressions at runtime.
+func GoSource(s string) (string, error) {
+	if strings.TrimSpace(s) == "" {
+		return "", fmt.Errorf("empty plural form expression")
+	}
+
+	expr, err := Compile(s)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err = writeExpression(&b, expr, "\t"); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// writeExpression writes the statements returning the value of expr.
+func writeExpression(b *strings.Builder, expr Expression, indent string) error {
+	switch e := expr.(type) {
+	case nil:
+		fmt.Fprintf(b, "%sreturn -1\n", indent)
+
+	case constValue:
+		fmt.Fprintf(b, "%sreturn %d\n", indent, e.value)
+
+	case ternary:
+		cond, err := testSource(e.test, "n")
+		if err != nil {
+			return err
+		}
+		switch e.test.(type) {
+		case and, or:
+			cond = cond[1 : len(cond)-1]
+		}
+		fmt.Fprintf(b, "%sif %s {\n", indent, cond)
+		if err = writeExpression(b, e.trueExpr, indent+"\t"); err != nil {
+			return err
+		}
+		fmt.Fprintf(b, "%s}\n", indent)
+		return writeExpression(b, e.falseExpr, indent)
+
+	default:
+		return fmt.Errorf("unsupported expression %T", expr)
+	}
+
+	return nil
+}
+
+// testSource returns the Go boolean expression of a test on the operand v.
+func testSource(t test, v string) (string, error) {
+	switch e := t.(type) {
+	case equal:
+		return fmt.Sprintf("%s == %d", v, e.value), nil
+	case notequal:
+		return fmt.Sprintf("%s != %d", v, e.value), nil
+	case gt:
+		return compareSource(v, ">", "<", e.value, e.flipped), nil
+	case lt:
+		return compareSource(v, "<", ">", e.value, e.flipped), nil
+	case gte:
+		return compareSource(v, ">=", "<=", e.value, e.flipped), nil
+	case lte:
+		return compareSource(v, "<=", ">=", e.value, e.flipped), nil
+
+	case and:
+		return logicSource(e.left, e.right, "&&", v)
+	case or:
+		return logicSource(e.left, e.right, "||", v)
+
+	case pipe:
+		m, ok := e.modifier.(mod)
+		if !ok {
+			return "", fmt.Errorf("unsupported operation %T", e.modifier)
+		}
+		return testSource(e.action, fmt.Sprintf("%s%%%d", v, m.value))
+	}
+
+	return "", fmt.Errorf("unsupported test %T", t)
+}
+
+// compareSource returns a comparison of v with value, flipped when the value was the left operand.
+func compareSource(v, op, flippedOp string, value uint32, flipped bool) string {
+	if flipped {
+		op = flippedOp
+	}
+
+	return fmt.Sprintf("%s %s %d", v, op, value)
+}
+
+// logicSource returns the parenthesized logic operation of two tests.
+func logicSource(left, right test, op, v string) (string, error) {
+	l, err := testSource(left, v)
+	if err != nil {
+		return "", err
+	}
+	r, err := testSource(right, v)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("(%s %s %s)", l, op, r), nil
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package plurals
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"os"
+	"testing"
+)
+
+func TestGoSource(t *testing.T) {
+	src, err := GoSource("n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "\tif n == 1 {\n\t\treturn 0\n\t}\n" +
+		"\tif n%10 >= 2 && (n%10 <= 4 && (n%100 < 10 || n%100 >= 20)) {\n\t\treturn 1\n\t}\n" +
+		"\treturn 2\n"
+	if src != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, src)
+	}
+
+	if _, err = GoSource(""); err == nil {
+		t.Error("Expected an error for an empty expression")
+	}
+}
+
+func TestGoSourceFixtures(t *testing.T) {
+	f, err := os.Open("testdata/pluralforms.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var fixtures []fixture
+	if err = json.NewDecoder(f).Decode(&fixtures); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, data := range fixtures {
+		src, err := GoSource(data.PluralForm)
+		if err != nil {
+			t.Errorf("'%s' failed to compile: %s", data.PluralForm, err)
+			continue
+		}
+
+		file := "package p\n\nfunc pluralForm(n uint32) int {\n" + src + "}\n"
+		if _, err = parser.ParseFile(token.NewFileSet(), "", file, 0); err != nil {
+			t.Errorf("'%s' generated invalid Go source: %s\n%s", data.PluralForm, err, src)
+		}
+	}
+}
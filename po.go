@@ -6,9 +6,12 @@
 package gotext
 
 import (
+	"fmt"
 	"net/textproto"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"unicode/utf8"
 )
 
 /*
@@ -42,6 +45,20 @@ type Po struct {
 	PluralForms string
 
 	domain *Domain
+
+	// Validation data recorded by the last Parse: source issues, lines of the entries,
+	// and the position of the parser.
+	issues    []Issue
+	lines     map[entryKey]int
+	line      int
+	entryLine int
+	hasCtx    bool
+
+	// Reverse index, built by Parse once enabled with EnableReverseIndex, with the source locations of the entries.
+	reverseEnabled bool
+	references     map[entryKey][]string
+	refBuffer      []string
+	reverse        atomic.Value
 }
 
 type parseState int
@@ -108,9 +125,10 @@ func (po *Po) Parse(buf []byte) {
 
 	// Lock while parsing
 	po.domain.trMutex.Lock()
-	po.domain.pluralMutex.Lock()
 	defer po.domain.trMutex.Unlock()
-	defer po.domain.pluralMutex.Unlock()
+
+	// Work on a copy of the storage, lookups keep using the published snapshot meanwhile
+	po.domain.beginUpdate()
 
 	// Get lines
 	lines := strings.Split(string(buf), "\n")
@@ -119,11 +137,30 @@ func (po *Po) Parse(buf []byte) {
 	po.domain.trBuffer = NewTranslation()
 	po.domain.ctxBuffer = ""
 
+	// Reset validation data
+	po.issues = nil
+	po.lines = make(map[entryKey]int)
+	po.hasCtx = false
+
 	state := head
-	for _, l := range lines {
+	for i, l := range lines {
+		po.line = i + 1
+
 		// Trim spaces
 		l = strings.TrimSpace(l)
 
+		// Flag comments start a new entry, buffer the fuzzy flag on it
+		if strings.HasPrefix(l, "#,") {
+			po.parseFlags(l)
+			continue
+		}
+
+		// Reference comments start a new entry too, buffer their source locations for the reverse index
+		if po.reverseEnabled && strings.HasPrefix(l, "#:") {
+			po.parseReferences(l)
+			continue
+		}
+
 		// Skip invalid lines
 		if !po.isValidLine(l) {
 			continue
@@ -171,6 +208,13 @@ func (po *Po) Parse(buf []byte) {
 	// Parse headers
 	po.domain.parseHeaders()
 
+	if po.reverseEnabled {
+		po.buildReverseIndex()
+	}
+
+	// Make new translations available to lookups
+	po.domain.publish()
+
 	// set values on this struct
 	// this is for backwards compatibility
 	po.Language = po.domain.Language
@@ -181,26 +225,63 @@ func (po *Po) Parse(buf []byte) {
 // saveBuffer takes the context and Translation buffers
 // and saves it on the translations collection
 func (po *Po) saveBuffer() {
-	// With no context...
-	if po.domain.ctxBuffer == "" {
-		po.domain.translations[po.domain.trBuffer.ID] = po.domain.trBuffer
+	// Skip empty buffers, so they can't override existing entries (i.e. headers) when parsing on top of previous data.
+	if po.domain.trBuffer.ID == "" && po.domain.trBuffer.PluralID == "" && len(po.domain.trBuffer.Trs) == 0 {
+		return
+	}
+
+	// Record source issues of the entry
+	key := entryKey{ctx: po.domain.ctxBuffer, id: po.domain.trBuffer.ID}
+	if first, ok := po.lines[key]; ok && key != (entryKey{}) {
+		po.addIssue(IssueDuplicate, po.entryLine, key, fmt.Sprintf("duplicate entry, first defined at line %d", first))
 	} else {
-		// With context...
-		if _, ok := po.domain.contexts[po.domain.ctxBuffer]; !ok {
-			po.domain.contexts[po.domain.ctxBuffer] = make(map[string]*Translation)
+		po.lines[key] = po.entryLine
+	}
+	if po.hasCtx && po.domain.ctxBuffer == "" {
+		po.addIssue(IssueEmptyContext, po.entryLine, key, "empty msgctxt, entries without context must omit it")
+	}
+	po.hasCtx = false
+
+	// Keep the source locations of the entry for the reverse index
+	if po.reverseEnabled {
+		if len(po.refBuffer) > 0 {
+			po.references[key] = po.refBuffer
+		} else {
+			delete(po.references, key)
 		}
-		po.domain.contexts[po.domain.ctxBuffer][po.domain.trBuffer.ID] = po.domain.trBuffer
+		po.refBuffer = nil
+	}
 
-		// Cleanup current context buffer if needed
-		if po.domain.trBuffer.ID != "" {
-			po.domain.ctxBuffer = ""
-		}
+	// Skip entries excluded by the domain filter
+	if po.domain.keep(po.domain.ctxBuffer, po.domain.trBuffer.ID) {
+		// Share repeated strings when interning is enabled
+		internTranslation(po.domain.trBuffer)
+		po.domain.ctxBuffer = intern(po.domain.ctxBuffer)
+
+		po.domain.entries[entryKey{ctx: po.domain.ctxBuffer, id: po.domain.trBuffer.ID}] = po.domain.trBuffer
+	}
+
+	// Cleanup current context buffer if needed
+	if po.domain.trBuffer.ID != "" {
+		po.domain.ctxBuffer = ""
 	}
 
 	// Flush Translation buffer
 	po.domain.trBuffer = NewTranslation()
 }
 
+// parseFlags takes a flags comment line starting with "#,", saves the current Translation buffer
+// and marks the next one as fuzzy when flagged so.
+func (po *Po) parseFlags(l string) {
+	po.saveBuffer()
+
+	for _, flag := range strings.Split(strings.TrimPrefix(l, "#,"), ",") {
+		if strings.TrimSpace(flag) == "fuzzy" {
+			po.domain.trBuffer.Fuzzy = true
+		}
+	}
+}
+
 // parseContext takes a line starting with "msgctxt",
 // saves the current Translation buffer and creates a new context.
 func (po *Po) parseContext(l string) {
@@ -208,7 +289,9 @@ func (po *Po) parseContext(l string) {
 	po.saveBuffer()
 
 	// Buffer context
-	po.domain.ctxBuffer, _ = strconv.Unquote(strings.TrimSpace(strings.TrimPrefix(l, "msgctxt")))
+	po.domain.ctxBuffer = po.unquote(strings.TrimSpace(strings.TrimPrefix(l, "msgctxt")))
+	po.hasCtx = true
+	po.entryLine = po.line
 }
 
 // parseID takes a line starting with "msgid",
@@ -218,12 +301,15 @@ func (po *Po) parseID(l string) {
 	po.saveBuffer()
 
 	// Set id
-	po.domain.trBuffer.ID, _ = strconv.Unquote(strings.TrimSpace(strings.TrimPrefix(l, "msgid")))
+	po.domain.trBuffer.ID = po.unquote(strings.TrimSpace(strings.TrimPrefix(l, "msgid")))
+	if !po.hasCtx {
+		po.entryLine = po.line
+	}
 }
 
 // parsePluralID saves the plural id buffer from a line starting with "msgid_plural"
 func (po *Po) parsePluralID(l string) {
-	po.domain.trBuffer.PluralID, _ = strconv.Unquote(strings.TrimSpace(strings.TrimPrefix(l, "msgid_plural")))
+	po.domain.trBuffer.PluralID = po.unquote(strings.TrimSpace(strings.TrimPrefix(l, "msgid_plural")))
 }
 
 // parseMessage takes a line starting with "msgstr" and saves it into the current buffer.
@@ -246,20 +332,36 @@ func (po *Po) parseMessage(l string) {
 		}
 
 		// Parse Translation string
-		po.domain.trBuffer.Trs[i], _ = strconv.Unquote(strings.TrimSpace(l[idx+1:]))
+		po.domain.trBuffer.Trs[i] = po.unquote(strings.TrimSpace(l[idx+1:]))
 
 		// Loop
 		return
 	}
 
 	// Save single Translation form under 0 index
-	po.domain.trBuffer.Trs[0], _ = strconv.Unquote(l)
+	po.domain.trBuffer.Trs[0] = po.unquote(l)
+}
+
+// unquote returns the content of a quoted string, or an empty string recording an issue when it's invalid.
+// Invalid UTF-8 bytes are replaced by U+FFFD, recording an issue too.
+func (po *Po) unquote(l string) string {
+	s, err := strconv.Unquote(l)
+	if err != nil {
+		po.addIssue(IssueInvalidEscape, po.line, entryKey{ctx: po.domain.ctxBuffer, id: po.domain.trBuffer.ID},
+			fmt.Sprintf("invalid string %s", l))
+	}
+	if !utf8.ValidString(l) {
+		po.addIssue(IssueEncoding, po.line, entryKey{ctx: po.domain.ctxBuffer, id: po.domain.trBuffer.ID},
+			"string isn't valid UTF-8")
+	}
+
+	return s
 }
 
 // parseString takes a well formatted string without prefix
 // and creates headers or attach multi-line strings when corresponding
 func (po *Po) parseString(l string, state parseState) {
-	clean, _ := strconv.Unquote(l)
+	clean := po.unquote(l)
 
 	switch state {
 	case msgStr:
@@ -589,3 +589,34 @@ func TestNewPoTranslatorRace(t *testing.T) {
 	<-pc
 	<-rc
 }
+
+func TestPoFuzzy(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`
+#, fuzzy
+msgid ""
+msgstr "Language: fr\n"
+
+#: main.go:10
+#, fuzzy, c-format
+msgctxt "menu"
+msgid "Open %s"
+msgstr "Ouvrir %s"
+
+#, c-format
+msgid "Close %s"
+msgstr "Fermer %s"
+`))
+
+	tr, ok := po.GetDomain().Lookup("menu", "Open %s")
+	if !ok || !tr.Fuzzy {
+		t.Errorf("Expected 'Open %%s' to be fuzzy, got %v", tr)
+	}
+	tr, ok = po.GetDomain().Lookup("", "Close %s")
+	if !ok || tr.Fuzzy {
+		t.Errorf("Expected 'Close %%s' not to be fuzzy, got %v", tr)
+	}
+	if po.Language != "fr" {
+		t.Errorf("Expected 'fr' but got '%s'", po.Language)
+	}
+}
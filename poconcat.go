@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"reflect"
+)
+
+// PoConflict is an entry found with different translations by ConcatPo.
+type PoConflict struct {
+	// Kept is the entry of the result, holding the first translation found.
+	Kept *PoEntry
+
+	// Dropped is the entry whose translation was discarded, and File the index of its catalog.
+	Dropped *PoEntry
+	File    int
+}
+
+// appendMissing appends to list the elements of add it doesn't hold yet.
+func appendMissing(list, add []string) []string {
+	for _, s := range add {
+		found := false
+		for _, l := range list {
+			if l == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, s)
+		}
+	}
+
+	return list
+}
+
+// copyEntry returns a copy of the entry that can be modified without changing it.
+func copyEntry(e *PoEntry) *PoEntry {
+	c := *e
+	c.TranslatorComments = append([]string(nil), e.TranslatorComments...)
+	c.ExtractedComments = append([]string(nil), e.ExtractedComments...)
+	c.References = append([]string(nil), e.References...)
+	c.Flags = append([]string(nil), e.Flags...)
+	c.Previous = append([]string(nil), e.Previous...)
+	c.OtherComments = append([]string(nil), e.OtherComments...)
+	c.Str = append([]string(nil), e.Str...)
+
+	return &c
+}
+
+/*
+ConcatPo combines catalogs into one, as GNU msgcat does, also collapsing the entries repeated in a catalog.
+Duplicate entries are merged: their comments, references and flags are combined and the first translation is kept,
+untranslated entries taking the translation of their duplicates. Duplicates with different translations are returned as conflicts.
+The header is the one of the first catalog having one.
+
+Example:
+
+	merged, conflicts := gotext.ConcatPo(accounts, billing)
+	for _, c := range conflicts {
+		log.Printf("msgid %q: %q conflicts with %q", c.Kept.ID, c.Kept.Str, c.Dropped.Str)
+	}
+*/
+func ConcatPo(files ...*PoFile) (*PoFile, []PoConflict) {
+	type key struct {
+		entryKey
+		obsolete bool
+	}
+
+	var (
+		merged    = new(PoFile)
+		conflicts []PoConflict
+		index     = make(map[key]*PoEntry)
+	)
+	for i, f := range files {
+		for _, e := range f.Entries {
+			k := key{entryKey{e.Context, e.ID}, e.Obsolete}
+			kept, ok := index[k]
+			if !ok {
+				kept = copyEntry(e)
+				index[k] = kept
+				merged.Entries = append(merged.Entries, kept)
+				continue
+			}
+			if e.IsHeader() {
+				continue
+			}
+
+			kept.TranslatorComments = appendMissing(kept.TranslatorComments, e.TranslatorComments)
+			kept.ExtractedComments = appendMissing(kept.ExtractedComments, e.ExtractedComments)
+			kept.References = appendMissing(kept.References, e.References)
+			kept.OtherComments = appendMissing(kept.OtherComments, e.OtherComments)
+
+			switch {
+			case !e.Translated() || reflect.DeepEqual(kept.Str, e.Str):
+				kept.Flags = appendMissing(kept.Flags, e.Flags)
+			case !kept.Translated():
+				kept.Str = append([]string(nil), e.Str...)
+				kept.PluralID = e.PluralID
+				kept.Flags = appendMissing(e.Flags[:len(e.Flags):len(e.Flags)], kept.Flags)
+				kept.SetFlag("fuzzy", e.HasFlag("fuzzy"))
+			default:
+				conflicts = append(conflicts, PoConflict{Kept: kept, Dropped: e, File: i})
+			}
+		}
+	}
+
+	return merged, conflicts
+}
+
+/*
+BuildCompendium combines the translations of catalogs of a language into a compendium, to translate new templates
+with MergeTemplate. Only translated entries are kept, without their references, fuzzy and obsolete ones being left out.
+Conflicting translations are returned as ConcatPo does, the first one being kept.
+
+Example:
+
+	compendium, _ := gotext.BuildCompendium(billing, accounts)
+	po := gotext.MergeTemplate(&gotext.PoFile{}, pot, gotext.MergeOptions{Compendium: compendium})
+*/
+func BuildCompendium(files ...*PoFile) (*PoFile, []PoConflict) {
+	translated := make([]*PoFile, len(files))
+	for i, f := range files {
+		translated[i] = new(PoFile)
+		for _, e := range f.Entries {
+			if e.Obsolete || (!e.IsHeader() && (!e.Translated() || e.HasFlag("fuzzy"))) {
+				continue
+			}
+			c := copyEntry(e)
+			c.References = nil
+			translated[i].Entries = append(translated[i].Entries, c)
+		}
+	}
+
+	return ConcatPo(translated...)
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConcatPo(t *testing.T) {
+	a, err := ParsePoFile([]byte(`msgid ""
+msgstr "Language: de\n"
+
+#: accounts.go:1
+msgid "Save"
+msgstr "Speichern"
+
+#: accounts.go:2
+msgid "Cancel"
+msgstr ""
+
+#: accounts.go:3
+msgid "Open"
+msgstr "Öffnen"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParsePoFile([]byte(`msgid ""
+msgstr "Language: fr\n"
+
+#. Button label
+#: billing.go:1
+#, fuzzy
+msgid "Save"
+msgstr "Speichern"
+
+#: billing.go:2
+#, fuzzy
+msgid "Cancel"
+msgstr "Abbrechen"
+
+#: billing.go:3
+msgid "Open"
+msgstr "Aufmachen"
+
+msgid "Pay"
+msgstr "Bezahlen"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, conflicts := ConcatPo(a, b)
+	if len(merged.Entries) != 5 {
+		t.Fatalf("Expected 5 entries but got %d", len(merged.Entries))
+	}
+	if lang := merged.Header().HeaderValue("Language"); lang != "de" {
+		t.Errorf("Expected 'de' but got '%s'", lang)
+	}
+
+	e := merged.Find("", "Save")
+	if !reflect.DeepEqual(e.References, []string{"accounts.go:1", "billing.go:1"}) || len(e.ExtractedComments) != 1 || !e.HasFlag("fuzzy") {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+
+	e = merged.Find("", "Cancel")
+	if e.Str[0] != "Abbrechen" || !e.HasFlag("fuzzy") {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+
+	if len(conflicts) != 1 || conflicts[0].Kept.ID != "Open" || conflicts[0].Kept.Str[0] != "Öffnen" ||
+		conflicts[0].Dropped.Str[0] != "Aufmachen" || conflicts[0].File != 1 {
+		t.Errorf("Unexpected conflicts %+v", conflicts)
+	}
+
+	// Sources are left unchanged.
+	if e = a.Find("", "Save"); len(e.References) != 1 || e.HasFlag("fuzzy") {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+}
+
+func TestConcatPoRepeated(t *testing.T) {
+	f, err := ParsePoFile([]byte(`#: a.go:1
+msgid "Save"
+msgstr ""
+
+#: b.go:1
+# Shown on the toolbar
+msgid "Save"
+msgstr "Enregistrer"
+
+#: c.go:1
+msgid "Save"
+msgstr "Sauvegarder"
+
+#~ msgid "Save"
+#~ msgstr "Sauver"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unique, conflicts := ConcatPo(f)
+	if len(unique.Entries) != 2 || !unique.Entries[1].Obsolete {
+		t.Fatalf("Expected an entry and an obsolete one but got %+v", unique.Entries)
+	}
+	e := unique.Entries[0]
+	if e.Str[0] != "Enregistrer" || !reflect.DeepEqual(e.References, []string{"a.go:1", "b.go:1", "c.go:1"}) || len(e.TranslatorComments) != 1 {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+	if len(conflicts) != 1 || conflicts[0].Dropped.Str[0] != "Sauvegarder" || conflicts[0].File != 0 {
+		t.Errorf("Unexpected conflicts %+v", conflicts)
+	}
+}
@@ -0,0 +1,527 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PoEntry is an entry of a PoFile, with its comments.
+// The header is the entry with an empty msgid and no context.
+type PoEntry struct {
+	// Comments, without their prefix: "# " for translator comments, "#." for extracted ones,
+	// "#:" for references, "#," for flags and "#|" for the previous msgid of fuzzy entries.
+	TranslatorComments []string
+	ExtractedComments  []string
+	References         []string
+	Flags              []string
+	Previous           []string
+
+	// OtherComments are the comments of unknown kinds, i.e. markers of translation management systems,
+	// without their "#" prefix, written back as they were.
+	OtherComments []string
+
+	Context    string
+	HasContext bool
+	ID         string
+	PluralID   string
+
+	// Str holds the msgstr, or the msgstr[i] forms of plural entries.
+	Str []string
+
+	// Obsolete entries are commented out with "#~".
+	Obsolete bool
+}
+
+// IsHeader reports if the entry is the catalog header.
+func (e *PoEntry) IsHeader() bool {
+	return e.ID == "" && !e.HasContext
+}
+
+// HasFlag reports if the entry has the given flag, i.e. "fuzzy".
+func (e *PoEntry) HasFlag(flag string) bool {
+	for _, f := range e.Flags {
+		if f == flag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetFlag adds or removes a flag of the entry.
+func (e *PoEntry) SetFlag(flag string, on bool) {
+	flags := e.Flags[:0:0]
+	for _, f := range e.Flags {
+		if f != flag {
+			flags = append(flags, f)
+		}
+	}
+	if on {
+		flags = append(flags, flag)
+	}
+	e.Flags = flags
+}
+
+// Translated reports if all of the msgstr forms of the entry are set.
+func (e *PoEntry) Translated() bool {
+	if len(e.Str) == 0 {
+		return false
+	}
+	for _, s := range e.Str {
+		if s == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PoStats counts the entries of a catalog by state, leaving out the header and obsolete entries.
+type PoStats struct {
+	Translated   int
+	Fuzzy        int
+	Untranslated int
+}
+
+// Total returns the number of entries.
+func (s PoStats) Total() int {
+	return s.Translated + s.Fuzzy + s.Untranslated
+}
+
+// Percent returns the percentage of translated entries, 100 for empty catalogs.
+func (s PoStats) Percent() float64 {
+	if s.Total() == 0 {
+		return 100
+	}
+
+	return 100 * float64(s.Translated) / float64(s.Total())
+}
+
+// Add returns the sum of the counts.
+func (s PoStats) Add(o PoStats) PoStats {
+	return PoStats{s.Translated + o.Translated, s.Fuzzy + o.Fuzzy, s.Untranslated + o.Untranslated}
+}
+
+// PoFile is a PO catalog as written in its source, keeping the comments and obsolete entries
+// discarded by Po, so tools can rewrite catalogs without losing information.
+type PoFile struct {
+	Entries []*PoEntry
+}
+
+// Header returns the header entry, or nil if there's none.
+func (f *PoFile) Header() *PoEntry {
+	for _, e := range f.Entries {
+		if e.IsHeader() && !e.Obsolete {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// Find returns the non obsolete entry with the given context and msgid, or nil if there's none.
+func (f *PoFile) Find(ctx, id string) *PoEntry {
+	for _, e := range f.Entries {
+		if !e.Obsolete && e.Context == ctx && e.ID == id {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// Stats counts the entries of the catalog by state. Fuzzy entries aren't counted as translated.
+func (f *PoFile) Stats() PoStats {
+	var s PoStats
+	for _, e := range f.Entries {
+		switch {
+		case e.Obsolete || e.IsHeader():
+		case e.HasFlag("fuzzy"):
+			s.Fuzzy++
+		case e.Translated():
+			s.Translated++
+		default:
+			s.Untranslated++
+		}
+	}
+
+	return s
+}
+
+// ParsePoFile parses the source of a PO catalog, keeping all of its comments.
+// It fails on malformed strings, reporting their line.
+func ParsePoFile(data []byte) (*PoFile, error) {
+	f := new(PoFile)
+
+	var (
+		cur     = new(PoEntry)
+		hasID   bool
+		field   *string
+		started bool
+	)
+	flush := func() {
+		if started {
+			f.Entries = append(f.Entries, cur)
+		}
+		cur, hasID, field, started = new(PoEntry), false, nil, false
+	}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+
+		obsolete := false
+		if strings.HasPrefix(line, "#~") {
+			obsolete = true
+			line = strings.TrimSpace(line[2:])
+			// GNU gettext writes the previous msgids of obsolete entries as "#~|" comments.
+			if strings.HasPrefix(line, "|") {
+				line = "#" + line
+			}
+			if hasID && !cur.Obsolete {
+				flush()
+			}
+		}
+
+		switch {
+		case line == "":
+			if hasID {
+				flush()
+			}
+			continue
+
+		case strings.HasPrefix(line, "#"):
+			if hasID && !(obsolete && strings.HasPrefix(line, "#|")) {
+				flush()
+			}
+			started = true
+			comment := line[1:]
+			switch {
+			case strings.HasPrefix(comment, "."):
+				cur.ExtractedComments = append(cur.ExtractedComments, strings.TrimSpace(comment[1:]))
+			case strings.HasPrefix(comment, ":"):
+				cur.References = append(cur.References, strings.TrimSpace(comment[1:]))
+			case strings.HasPrefix(comment, ","):
+				for _, flag := range strings.Split(comment[1:], ",") {
+					if flag = strings.TrimSpace(flag); flag != "" {
+						cur.Flags = append(cur.Flags, flag)
+					}
+				}
+			case strings.HasPrefix(comment, "|"):
+				cur.Previous = append(cur.Previous, strings.TrimSpace(comment[1:]))
+			case comment != "" && !strings.HasPrefix(comment, " ") && !strings.HasPrefix(comment, "\t"):
+				cur.OtherComments = append(cur.OtherComments, comment)
+			default:
+				cur.TranslatorComments = append(cur.TranslatorComments, strings.TrimPrefix(comment, " "))
+			}
+			continue
+		}
+
+		keyword, rest := line, ""
+		if idx := strings.IndexAny(line, " \t"); idx != -1 {
+			keyword, rest = line[:idx], strings.TrimSpace(line[idx:])
+		}
+		if strings.HasPrefix(line, "\"") {
+			keyword, rest = "", line
+		}
+
+		s, err := strconv.Unquote(rest)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid string %s", i+1, rest)
+		}
+
+		switch {
+		case keyword == "":
+			if field == nil {
+				return nil, fmt.Errorf("line %d: unexpected string %s", i+1, rest)
+			}
+			*field += s
+			continue
+
+		case keyword == "msgctxt":
+			if hasID {
+				flush()
+			}
+			cur.Context, cur.HasContext = s, true
+			field = &cur.Context
+
+		case keyword == "msgid":
+			if hasID {
+				flush()
+			}
+			cur.ID, hasID = s, true
+			field = &cur.ID
+
+		case keyword == "msgid_plural":
+			cur.PluralID = s
+			field = &cur.PluralID
+
+		case keyword == "msgstr":
+			cur.Str = append(cur.Str[:0], s)
+			field = &cur.Str[0]
+
+		case strings.HasPrefix(keyword, "msgstr["):
+			n, err := strconv.Atoi(strings.TrimSuffix(keyword[len("msgstr["):], "]"))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("line %d: invalid keyword %s", i+1, keyword)
+			}
+			for len(cur.Str) <= n {
+				cur.Str = append(cur.Str, "")
+			}
+			cur.Str[n] = s
+			field = &cur.Str[n]
+
+		default:
+			return nil, fmt.Errorf("line %d: unknown keyword %s", i+1, keyword)
+		}
+		started = true
+		cur.Obsolete = obsolete
+	}
+	if hasID {
+		flush()
+	}
+
+	return f, nil
+}
+
+// PoSort selects the order of the entries written by PoFile.Format.
+type PoSort int
+
+const (
+	// SortNone keeps the entries in their order.
+	SortNone PoSort = iota
+
+	// SortByID sorts the entries by msgid, then context.
+	SortByID
+
+	// SortByFile sorts the entries by their first reference, then msgid.
+	SortByFile
+)
+
+// DefaultPoWidth is the default width of the lines written by PoFile.Format, as used by GNU gettext.
+const DefaultPoWidth = 79
+
+// FormatOptions configures how catalogs are written by PoFile.Format.
+type FormatOptions struct {
+	// Width of the lines, DefaultPoWidth when zero. Long strings are wrapped at spaces.
+	Width int
+
+	// NoWrap disables wrapping, long strings are only split after newlines.
+	NoWrap bool
+
+	// Sort selects the order of the entries. The header is always first and obsolete entries last.
+	Sort PoSort
+}
+
+// Format writes the catalog in canonical gettext style: strings escaped and wrapped as GNU gettext tools do,
+// comments in their canonical order, the header first and obsolete entries last.
+func (f *PoFile) Format(opts FormatOptions) []byte {
+	if opts.Width <= 0 {
+		opts.Width = DefaultPoWidth
+	}
+
+	entries := append([]*PoEntry(nil), f.Entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Obsolete != b.Obsolete {
+			return b.Obsolete
+		}
+		if a.IsHeader() != b.IsHeader() {
+			return a.IsHeader()
+		}
+		switch opts.Sort {
+		case SortByFile:
+			ra, rb := firstReference(a), firstReference(b)
+			if ra != rb {
+				return ra < rb
+			}
+			fallthrough
+		case SortByID:
+			if a.ID != b.ID {
+				return a.ID < b.ID
+			}
+			return a.Context < b.Context
+		}
+		return false
+	})
+
+	var buf bytes.Buffer
+	for i, e := range entries {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		e.write(&buf, opts)
+	}
+
+	return buf.Bytes()
+}
+
+// firstReference returns the first source reference of an entry.
+func firstReference(e *PoEntry) string {
+	if len(e.References) == 0 {
+		return ""
+	}
+
+	return strings.Fields(e.References[0] + " ")[0]
+}
+
+// write writes the entry followed by a newline.
+func (e *PoEntry) write(buf *bytes.Buffer, opts FormatOptions) {
+	for _, c := range e.TranslatorComments {
+		buf.WriteString(strings.TrimRight("# "+c, " ") + "\n")
+	}
+	for _, c := range e.ExtractedComments {
+		buf.WriteString(strings.TrimRight("#. "+c, " ") + "\n")
+	}
+	for _, c := range e.References {
+		buf.WriteString("#: " + c + "\n")
+	}
+	if len(e.Flags) > 0 {
+		buf.WriteString("#, " + strings.Join(e.Flags, ", ") + "\n")
+	}
+	for _, c := range e.OtherComments {
+		buf.WriteString("#" + c + "\n")
+	}
+
+	prefix, previous := "", "#| "
+	if e.Obsolete {
+		prefix, previous = "#~ ", "#~| "
+	}
+	for _, c := range e.Previous {
+		buf.WriteString(previous + c + "\n")
+	}
+
+	if e.HasContext {
+		writePoString(buf, prefix, "msgctxt", e.Context, opts)
+	}
+	writePoString(buf, prefix, "msgid", e.ID, opts)
+	if e.PluralID != "" {
+		writePoString(buf, prefix, "msgid_plural", e.PluralID, opts)
+		strs := e.Str
+		if len(strs) == 0 {
+			strs = []string{"", ""}
+		}
+		for i, s := range strs {
+			writePoString(buf, prefix, fmt.Sprintf("msgstr[%d]", i), s, opts)
+		}
+		return
+	}
+
+	str := ""
+	if len(e.Str) > 0 {
+		str = e.Str[0]
+	}
+	writePoString(buf, prefix, "msgstr", str, opts)
+}
+
+// EscapePoString escapes s to be written between double quotes in a PO file.
+func EscapePoString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\a':
+			b.WriteString(`\a`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\v':
+			b.WriteString(`\v`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\%03o`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// writePoString writes a keyword with its string, split in several lines after newlines and,
+// unless disabled, wrapped at spaces to fit the width.
+func writePoString(buf *bytes.Buffer, prefix, keyword, s string, opts FormatOptions) {
+	head := prefix + keyword + " "
+
+	// Newlines only split strings when they aren't the last characters.
+	lines := strings.SplitAfter(s, "\n")
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if escaped := EscapePoString(s); len(lines) == 1 &&
+		(opts.NoWrap || utf8.RuneCountInString(head)+utf8.RuneCountInString(escaped)+2 <= opts.Width) {
+		buf.WriteString(head + `"` + escaped + `"` + "\n")
+		return
+	}
+
+	buf.WriteString(head + `""` + "\n")
+	for _, line := range lines {
+		escaped := EscapePoString(line)
+		if opts.NoWrap {
+			buf.WriteString(prefix + `"` + escaped + `"` + "\n")
+			continue
+		}
+		for _, part := range wrapPoLine(escaped, opts.Width-utf8.RuneCountInString(prefix)-2) {
+			buf.WriteString(prefix + `"` + part + `"` + "\n")
+		}
+	}
+}
+
+// wrapPoLine wraps an escaped line at spaces so each part fits in width characters when possible.
+func wrapPoLine(s string, width int) []string {
+	var parts []string
+	for utf8.RuneCountInString(s) > width {
+		// Break after the last space fitting in the width, or after the first one if none fits.
+		cut := -1
+		count := 0
+		for i, r := range s {
+			count++
+			if r == ' ' {
+				if count <= width || cut == -1 {
+					cut = i + 1
+				}
+				if count > width {
+					break
+				}
+			}
+		}
+		if cut == -1 || cut == len(s) {
+			break
+		}
+		parts = append(parts, s[:cut])
+		s = s[cut:]
+	}
+
+	return append(parts, s)
+}
+
+// FormatPo rewrites the source of a PO catalog in canonical gettext style, preserving its comments
+// and obsolete entries. See PoFile.Format.
+func FormatPo(data []byte, opts FormatOptions) ([]byte, error) {
+	f, err := ParsePoFile(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.Format(opts), nil
+}
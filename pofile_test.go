@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+const unformattedPo = `# Translator comment
+#
+#, fuzzy
+msgid ""
+msgstr ""
+"Language: fr\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+#~ msgid "Old"
+#~ msgstr "Vieux"
+
+#: b.go:10 b.go:12
+#. extracted
+#,c-format,fuzzy
+#| msgid "Old %s"
+msgctxt "ctx"
+msgid "A very long string that will certainly need to be wrapped because it is longer than seventy nine characters in total"
+msgstr "Une très longue chaîne\tqui"
+" sera coupée"
+msgid "One\nTwo\n"
+msgid_plural "Many\n"
+msgstr[0] "Un\nDeux\n"
+msgstr[1] ""
+`
+
+const formattedPo = `# Translator comment
+#
+#, fuzzy
+msgid ""
+msgstr ""
+"Language: fr\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+#. extracted
+#: b.go:10 b.go:12
+#, c-format, fuzzy
+#| msgid "Old %s"
+msgctxt "ctx"
+msgid ""
+"A very long string that will certainly need to be wrapped because it is "
+"longer than seventy nine characters in total"
+msgstr "Une très longue chaîne\tqui sera coupée"
+
+msgid ""
+"One\n"
+"Two\n"
+msgid_plural "Many\n"
+msgstr[0] ""
+"Un\n"
+"Deux\n"
+msgstr[1] ""
+
+#~ msgid "Old"
+#~ msgstr "Vieux"
+`
+
+func TestFormatPo(t *testing.T) {
+	out, err := FormatPo([]byte(unformattedPo), FormatOptions{Sort: SortByID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != formattedPo {
+		t.Errorf("Expected '%s' but got '%s'", formattedPo, out)
+	}
+
+	// Formatting is idempotent.
+	again, _ := FormatPo(out, FormatOptions{Sort: SortByID})
+	if string(again) != formattedPo {
+		t.Errorf("Expected '%s' but got '%s'", formattedPo, again)
+	}
+
+	// Formatted catalogs hold the same translations.
+	a, b := NewPo(), NewPo()
+	a.Parse([]byte(unformattedPo))
+	b.Parse(out)
+	if a.GetC("A very long string that will certainly need to be wrapped because it is longer than seventy nine characters in total", "ctx") !=
+		b.GetC("A very long string that will certainly need to be wrapped because it is longer than seventy nine characters in total", "ctx") {
+		t.Error("Expected the same translations")
+	}
+}
+
+func TestFormatPoNoWrap(t *testing.T) {
+	long := `msgid "A very long string that will certainly need to be wrapped because it is longer than seventy nine characters in total"
+msgstr ""
+`
+	out, err := FormatPo([]byte(long), FormatOptions{NoWrap: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != long {
+		t.Errorf("Expected '%s' but got '%s'", long, out)
+	}
+}
+
+func TestFormatPoFixtures(t *testing.T) {
+	data, err := ioutil.ReadFile("fixtures/de/default.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := FormatPo(data, FormatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := NewPo(), NewPo()
+	a.Parse(data)
+	b.Parse(out)
+	if !reflect.DeepEqual(a.GetDomain().load().entries, b.GetDomain().load().entries) {
+		t.Error("Expected the formatted catalog to hold the same entries")
+	}
+}
+
+func TestParsePoFile(t *testing.T) {
+	f, err := ParsePoFile([]byte(unformattedPo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Entries) != 4 {
+		t.Fatalf("Expected 4 entries but got %d", len(f.Entries))
+	}
+
+	e := f.Find("ctx", "A very long string that will certainly need to be wrapped because it is longer than seventy nine characters in total")
+	if e == nil || !e.HasFlag("fuzzy") || !e.HasFlag("c-format") || e.Str[0] != "Une très longue chaîne\tqui sera coupée" {
+		t.Fatalf("Unexpected entry %+v", e)
+	}
+	e.SetFlag("fuzzy", false)
+	if !reflect.DeepEqual(e.Flags, []string{"c-format"}) {
+		t.Errorf("Expected [c-format] but got %v", e.Flags)
+	}
+	if h := f.Header(); h == nil || !h.HasFlag("fuzzy") {
+		t.Errorf("Unexpected header %+v", h)
+	}
+	if !f.Entries[1].Obsolete || f.Find("", "Old") != nil {
+		t.Error("Expected the obsolete entry to be kept apart")
+	}
+
+	for _, bad := range []string{"msgid \"unterminated\n", "msgid \"a\"\nmsgstr[x] \"\"\n", "\"orphan\"\n", "msgfoo \"a\"\n"} {
+		if _, err := ParsePoFile([]byte(bad)); err == nil {
+			t.Errorf("Expected an error parsing '%s'", bad)
+		}
+	}
+}
+
+func TestEscapePoString(t *testing.T) {
+	expected := `a\\b\"c\n\td\001`
+	if got := EscapePoString("a\\b\"c\n\td\x01"); got != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, got)
+	}
+}
+
+func TestPoFileStats(t *testing.T) {
+	f, err := ParsePoFile([]byte(unformattedPo))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := f.Stats()
+	if stats != (PoStats{Translated: 0, Fuzzy: 1, Untranslated: 1}) {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	stats = stats.Add(PoStats{Translated: 2})
+	if stats.Total() != 4 || stats.Percent() != 50 {
+		t.Errorf("Expected 4 entries 50%% translated but got %d %v%%", stats.Total(), stats.Percent())
+	}
+	if p := (PoStats{}).Percent(); p != 100 {
+		t.Errorf("Expected 100 but got %v", p)
+	}
+}
+
+func TestParsePoFileObsoletePrevious(t *testing.T) {
+	data, err := ioutil.ReadFile("fixtures/fr/LC_MESSAGES/obsolete.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := ParsePoFile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// find returns the entry, obsolete or not
+	find := func(ctx, id string) *PoEntry {
+		for _, e := range f.Entries {
+			if e.Context == ctx && e.ID == id {
+				return e
+			}
+		}
+		return nil
+	}
+	for _, test := range []struct {
+		ctx, id  string
+		obsolete bool
+		previous []string
+	}{
+		{"", "Hello, %s!", false, []string{`msgid "Hello %s"`}},
+		{"", "Older", true, []string{`msgid "Old"`}},
+		{"menu", "%d file", true, []string{`msgctxt "menu"`, `msgid "%d old file"`, `msgid_plural "%d old files"`}},
+	} {
+		e := find(test.ctx, test.id)
+		if e == nil {
+			t.Errorf("Expected an entry for '%s'", test.id)
+			continue
+		}
+		if e.Obsolete != test.obsolete || !reflect.DeepEqual(e.Previous, test.previous) {
+			t.Errorf("Expected %v obsolete with previous %q for '%s' but got %v with %q", test.obsolete, test.previous, test.id, e.Obsolete, e.Previous)
+		}
+	}
+	if e := find("menu", "%d file"); e == nil || e.Str[1] != "%d fichiers" {
+		t.Errorf("Expected the translations of the obsolete entry but got %+v", e)
+	}
+
+	// The previous msgids of obsolete entries are written as GNU gettext does
+	out, err := FormatPo(data, FormatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != string(data) {
+		t.Errorf("Expected '%s' but got '%s'", data, out)
+	}
+}
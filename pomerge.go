@@ -0,0 +1,283 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FuzzyThreshold is the minimum similarity, between 0 and 1, for MergeTemplate to reuse
+// the translation of a changed msgid as a fuzzy one, as GNU msgmerge does.
+const FuzzyThreshold = 0.6
+
+// MergeOptions configures MergeTemplate.
+type MergeOptions struct {
+	// NoFuzzyMatching disables the reuse of the translations of similar msgids as fuzzy translations.
+	NoFuzzyMatching bool
+
+	// Previous keeps the previous msgid of fuzzy matched entries as "#|" comments, for translators to review the change.
+	Previous bool
+
+	// Compendium is a catalog of translations reused for the entries the translated catalog lacks,
+	// as the compendiums of GNU msgmerge. Its unused translations aren't kept as obsolete entries.
+	Compendium *PoFile
+}
+
+// HeaderValue returns the value of a field of the header entry, or an empty string if it's not set.
+func (e *PoEntry) HeaderValue(key string) string {
+	if len(e.Str) == 0 {
+		return ""
+	}
+	for _, line := range strings.Split(e.Str[0], "\n") {
+		if i := strings.Index(line, ":"); i > 0 && strings.EqualFold(strings.TrimSpace(line[:i]), key) {
+			return strings.TrimSpace(line[i+1:])
+		}
+	}
+
+	return ""
+}
+
+// SetHeaderValue sets the value of a field of the header entry, appending it if it's not set.
+func (e *PoEntry) SetHeaderValue(key, value string) {
+	if len(e.Str) == 0 {
+		e.Str = []string{""}
+	}
+
+	lines := strings.SplitAfter(e.Str[0], "\n")
+	for i, line := range lines {
+		if j := strings.Index(line, ":"); j > 0 && strings.EqualFold(strings.TrimSpace(line[:j]), key) {
+			lines[i] = line[:j] + ": " + value + "\n"
+			e.Str[0] = strings.Join(lines, "")
+			return
+		}
+	}
+
+	if e.Str[0] != "" && !strings.HasSuffix(e.Str[0], "\n") {
+		e.Str[0] += "\n"
+	}
+	e.Str[0] += key + ": " + value + "\n"
+}
+
+// nplurals returns the number of plural forms of the catalog, from its Plural-Forms header, 2 by default.
+func (f *PoFile) nplurals() int {
+	if h := f.Header(); h != nil {
+		for _, part := range strings.Split(h.HeaderValue("Plural-Forms"), ";") {
+			kv := strings.SplitN(part, "=", 2)
+			if len(kv) == 2 && strings.TrimSpace(kv[0]) == "nplurals" {
+				if n, err := strconv.Atoi(strings.TrimSpace(kv[1])); err == nil && n > 0 {
+					return n
+				}
+			}
+		}
+	}
+
+	return 2
+}
+
+// similarity returns the similarity of two strings, between 0 and 1, as the ratio of their longest common subsequence of runes.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra)+len(rb) == 0 {
+		return 1
+	}
+
+	prev, cur := make([]int, len(rb)+1), make([]int, len(rb)+1)
+	for i := range ra {
+		for j := range rb {
+			switch {
+			case ra[i] == rb[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] > cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	return 2 * float64(prev[len(rb)]) / float64(len(ra)+len(rb))
+}
+
+// fuzzyMatch returns the unused translated entry of the catalog whose msgid is the most similar to the one of e,
+// among those with the same context, or nil if none reaches FuzzyThreshold.
+func fuzzyMatch(e *PoEntry, candidates []*PoEntry, used map[*PoEntry]bool) *PoEntry {
+	var (
+		best  *PoEntry
+		score = FuzzyThreshold
+	)
+	n := len([]rune(e.ID))
+	for _, c := range candidates {
+		if used[c] || c.IsHeader() || c.Context != e.Context || !c.Translated() {
+			continue
+		}
+		// The ratio can't exceed the one of the lengths, skip the computation when it's too low already.
+		m := len([]rune(c.ID))
+		if min, max := float64(n), float64(m); 2*minFloat(min, max)/(min+max) < score {
+			continue
+		}
+		if s := similarity(e.ID, c.ID); s >= score {
+			best, score = c, s
+		}
+	}
+
+	return best
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+/*
+MergeTemplate updates the translations of po to a new template, as GNU msgmerge does:
+entries are those of the template, with their extracted comments, references and flags,
+keeping the translator comments and translations of po for the unchanged msgids.
+Translations of changed msgids are reused as fuzzy ones when they're similar enough,
+and those left unused are kept as obsolete entries. Translations missing in po are looked up in the Compendium of the options. The header of po is kept, with the POT-Creation-Date of the template.
+
+Example:
+
+	pot, _ := gotext.ParsePoFile(potData)
+	po, _ := gotext.ParsePoFile(poData)
+	merged := gotext.MergeTemplate(po, pot, gotext.MergeOptions{})
+	ioutil.WriteFile("locales/de/default.po", merged.Format(gotext.FormatOptions{}), 0644)
+*/
+func MergeTemplate(po, pot *PoFile, opts MergeOptions) *PoFile {
+	merged := new(PoFile)
+	nplurals := po.nplurals()
+
+	header := po.Header()
+	if header == nil {
+		header = pot.Header()
+	}
+	if header != nil {
+		h := *header
+		h.Str = append([]string(nil), header.Str...)
+		if tmpl := pot.Header(); tmpl != nil && header != tmpl {
+			if date := tmpl.HeaderValue("POT-Creation-Date"); date != "" {
+				h.SetHeaderValue("POT-Creation-Date", date)
+			}
+		}
+		merged.Entries = append(merged.Entries, &h)
+	}
+
+	// Exact matches, including obsolete entries brought back to life.
+	used := make(map[*PoEntry]bool)
+	exact := make(map[entryKey]*PoEntry)
+	for _, e := range po.Entries {
+		k := entryKey{e.Context, e.ID}
+		if old, ok := exact[k]; !e.IsHeader() && (!ok || old.Obsolete) {
+			exact[k] = e
+		}
+	}
+
+	compendium := make(map[entryKey]*PoEntry)
+	if opts.Compendium != nil {
+		for _, e := range opts.Compendium.Entries {
+			k := entryKey{e.Context, e.ID}
+			if compendium[k] == nil && !e.IsHeader() && !e.Obsolete && e.Translated() {
+				compendium[k] = e
+			}
+		}
+	}
+
+	for _, t := range pot.Entries {
+		if t.IsHeader() || t.Obsolete {
+			continue
+		}
+
+		e := &PoEntry{
+			ExtractedComments: t.ExtractedComments,
+			References:        t.References,
+			Context:           t.Context,
+			HasContext:        t.HasContext,
+			ID:                t.ID,
+			PluralID:          t.PluralID,
+		}
+		for _, flag := range t.Flags {
+			if flag != "fuzzy" {
+				e.Flags = append(e.Flags, flag)
+			}
+		}
+
+		old := exact[entryKey{t.Context, t.ID}]
+		if old == nil {
+			if c := compendium[entryKey{t.Context, t.ID}]; c != nil {
+				old = &PoEntry{ID: c.ID, PluralID: c.PluralID, Str: c.Str, Flags: c.Flags}
+			}
+		}
+		fuzzy := false
+		if old == nil && !opts.NoFuzzyMatching {
+			old = fuzzyMatch(t, po.Entries, used)
+			if old == nil && opts.Compendium != nil {
+				if old = fuzzyMatch(t, opts.Compendium.Entries, nil); old != nil {
+					old = &PoEntry{Context: old.Context, HasContext: old.HasContext, ID: old.ID, PluralID: old.PluralID, Str: old.Str}
+				}
+			}
+			if old != nil {
+				fuzzy = true
+				if opts.Previous {
+					if old.HasContext {
+						e.Previous = append(e.Previous, "msgctxt \""+EscapePoString(old.Context)+"\"")
+					}
+					e.Previous = append(e.Previous, "msgid \""+EscapePoString(old.ID)+"\"")
+					if old.PluralID != "" {
+						e.Previous = append(e.Previous, "msgid_plural \""+EscapePoString(old.PluralID)+"\"")
+					}
+				}
+			}
+		}
+
+		if old != nil {
+			used[old] = true
+			e.TranslatorComments = old.TranslatorComments
+			e.OtherComments = old.OtherComments
+			e.Str = append([]string(nil), old.Str...)
+			fuzzy = fuzzy || old.HasFlag("fuzzy")
+
+			// Translations of entries becoming plural or singular are kept for review.
+			if (old.PluralID == "") != (t.PluralID == "") {
+				fuzzy = true
+				if t.PluralID != "" {
+					for len(e.Str) < nplurals {
+						e.Str = append(e.Str, e.Str[0])
+					}
+				} else {
+					e.Str = e.Str[:1]
+				}
+			}
+		}
+
+		if len(e.Str) == 0 {
+			e.Str = []string{""}
+			if t.PluralID != "" {
+				e.Str = make([]string, nplurals)
+			}
+		}
+		if fuzzy {
+			e.SetFlag("fuzzy", true)
+		}
+		merged.Entries = append(merged.Entries, e)
+	}
+
+	// Unused translations are kept as obsolete entries, untranslated ones are dropped.
+	for _, e := range po.Entries {
+		if used[e] || e.IsHeader() || !(e.Translated() || e.Obsolete) {
+			continue
+		}
+		o := *e
+		o.Obsolete = true
+		o.References = nil
+		merged.Entries = append(merged.Entries, &o)
+	}
+
+	return merged
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"testing"
+)
+
+func TestMergeTemplate(t *testing.T) {
+	pot, err := ParsePoFile([]byte(`msgid ""
+msgstr ""
+"POT-Creation-Date: 2020-02-02 10:00+0000\n"
+
+#: main.go:10
+#, c-format
+msgid "Hello %s"
+msgstr ""
+
+#: main.go:11
+msgid "Delete all the selected files"
+msgstr ""
+
+#: main.go:12
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+
+#: main.go:13
+msgid "Brand new"
+msgstr ""
+
+#: main.go:14
+msgid "Revived"
+msgstr ""
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	po, err := ParsePoFile([]byte(`msgid ""
+msgstr ""
+"POT-Creation-Date: 2020-01-01 10:00+0000\n"
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+# Greeting
+#: old.go:1
+msgid "Hello %s"
+msgstr "Hallo %s"
+
+msgid "Delete the selected files"
+msgstr "Ausgewählte Dateien löschen"
+
+msgid "%d file"
+msgstr "%d Datei"
+
+msgid "Removed"
+msgstr "Entfernt"
+
+msgid "Untranslated"
+msgstr ""
+
+#~ msgid "Revived"
+#~ msgstr "Wiederbelebt"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged := MergeTemplate(po, pot, MergeOptions{Previous: true})
+
+	h := merged.Header()
+	if h == nil || h.HeaderValue("Language") != "de" || h.HeaderValue("POT-Creation-Date") != "2020-02-02 10:00+0000" {
+		t.Errorf("Unexpected header %+v", h)
+	}
+	if po.Header().HeaderValue("POT-Creation-Date") != "2020-01-01 10:00+0000" {
+		t.Error("Expected the header of the catalog to be left unchanged")
+	}
+
+	e := merged.Find("", "Hello %s")
+	if e == nil || e.Str[0] != "Hallo %s" || e.HasFlag("fuzzy") || !e.HasFlag("c-format") ||
+		len(e.TranslatorComments) != 1 || e.References[0] != "main.go:10" {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+
+	e = merged.Find("", "Delete all the selected files")
+	if e == nil || e.Str[0] != "Ausgewählte Dateien löschen" || !e.HasFlag("fuzzy") ||
+		len(e.Previous) != 1 || e.Previous[0] != `msgid "Delete the selected files"` {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+
+	e = merged.Find("", "%d file")
+	if e == nil || len(e.Str) != 2 || e.Str[1] != "%d Datei" || !e.HasFlag("fuzzy") {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+
+	e = merged.Find("", "Brand new")
+	if e == nil || e.Translated() || e.HasFlag("fuzzy") {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+
+	e = merged.Find("", "Revived")
+	if e == nil || e.Str[0] != "Wiederbelebt" {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+
+	var obsolete []string
+	for _, e := range merged.Entries {
+		if e.Obsolete {
+			obsolete = append(obsolete, e.ID)
+		}
+	}
+	if len(obsolete) != 1 || obsolete[0] != "Removed" {
+		t.Errorf("Expected [Removed] but got %v", obsolete)
+	}
+
+	merged = MergeTemplate(po, pot, MergeOptions{NoFuzzyMatching: true})
+	if e = merged.Find("", "Delete all the selected files"); e == nil || e.Translated() {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"abc", "abc", 1},
+		{"abc", "xyz", 0},
+		{"abcd", "abxd", 0.75},
+	}
+	for _, test := range tests {
+		if got := similarity(test.a, test.b); got != test.want {
+			t.Errorf("Expected %v for %q and %q but got %v", test.want, test.a, test.b, got)
+		}
+	}
+}
+
+func TestMergeTemplateCompendium(t *testing.T) {
+	compendium, conflicts := BuildCompendium(
+		mustParsePoFile(t, `#: billing.go:1
+msgid "Save"
+msgstr "Enregistrer"
+
+#, fuzzy
+msgid "Delete"
+msgstr "Supprimer"
+
+msgid "Send the invoice"
+msgstr "Envoyer la facture"
+`),
+		mustParsePoFile(t, `msgid "Save"
+msgstr "Sauver"
+
+msgid "Cancel"
+msgstr "Annuler"
+`))
+	if len(compendium.Entries) != 3 || len(conflicts) != 1 || len(compendium.Find("", "Save").References) != 0 {
+		t.Fatalf("Unexpected compendium %+v", compendium.Entries)
+	}
+
+	pot := mustParsePoFile(t, `msgid ""
+msgstr "Language: fr\n"
+
+msgid "Save"
+msgstr ""
+
+msgid "Delete"
+msgstr ""
+
+msgid "Send the invoices"
+msgstr ""
+
+msgid "Cancel"
+msgstr ""
+`)
+	po := mustParsePoFile(t, `msgid "Cancel"
+msgstr "Abandonner"
+`)
+	merged := MergeTemplate(po, pot, MergeOptions{Compendium: compendium})
+
+	tests := []struct {
+		id, str string
+		fuzzy   bool
+	}{
+		{"Save", "Enregistrer", false},
+		{"Delete", "", false},
+		{"Send the invoices", "Envoyer la facture", true},
+		{"Cancel", "Abandonner", false},
+	}
+	for _, test := range tests {
+		e := merged.Find("", test.id)
+		if e == nil || e.Str[0] != test.str || e.HasFlag("fuzzy") != test.fuzzy {
+			t.Errorf("Unexpected entry %+v", e)
+		}
+	}
+	for _, e := range merged.Entries {
+		if e.Obsolete {
+			t.Errorf("Unexpected obsolete entry %+v", e)
+		}
+	}
+}
+
+func mustParsePoFile(t *testing.T, src string) *PoFile {
+	f, err := ParsePoFile([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
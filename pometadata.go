@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "strings"
+
+// metadataField returns the name and value of an "X-Name: value" annotation comment.
+func metadataField(c string) (name, value string, ok bool) {
+	c = strings.TrimSpace(c)
+	idx := strings.Index(c, ":")
+	if !strings.HasPrefix(c, "X-") || idx == -1 || strings.ContainsAny(c[:idx], " \t") {
+		return "", "", false
+	}
+
+	return c[:idx], strings.TrimSpace(c[idx+1:]), true
+}
+
+/*
+Metadata returns the per-entry annotations of the entry, the translator and extracted comments written as
+"X-Name: value", i.e. string IDs or screenshot links of translation management systems, by name.
+The first annotation of a name wins.
+
+Example:
+
+	# X-String-Id: 4711
+	#. X-Screenshot: https://tms.example.com/screens/42.png
+	msgid "Save"
+	msgstr "Speichern"
+*/
+func (e *PoEntry) Metadata() map[string]string {
+	meta := make(map[string]string)
+	for _, list := range [][]string{e.TranslatorComments, e.ExtractedComments} {
+		for _, c := range list {
+			if name, value, ok := metadataField(c); ok {
+				if _, seen := meta[name]; !seen {
+					meta[name] = value
+				}
+			}
+		}
+	}
+
+	return meta
+}
+
+// SetMetadata sets an annotation of the entry, its name starting with "X-", where it is already written
+// or as a new translator comment. An empty value removes it.
+func (e *PoEntry) SetMetadata(name, value string) {
+	set := value == ""
+	update := func(list []string) []string {
+		kept := list[:0:0]
+		for _, c := range list {
+			if n, _, ok := metadataField(c); ok && n == name {
+				if set {
+					continue
+				}
+				c, set = name+": "+value, true
+			}
+			kept = append(kept, c)
+		}
+		return kept
+	}
+	e.TranslatorComments = update(e.TranslatorComments)
+	e.ExtractedComments = update(e.ExtractedComments)
+
+	if !set {
+		e.TranslatorComments = append(e.TranslatorComments, name+": "+value)
+	}
+}
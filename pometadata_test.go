@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "testing"
+
+const metadataPo = `# X-String-Id: 4711
+# Reviewed by the legal team
+#. X-Screenshot: https://tms.example.com/screens/42.png
+#: settings.go:12
+#@ tms-lock
+#=rev 3
+msgid "Save"
+msgstr "Speichern"
+`
+
+func TestPoEntryMetadata(t *testing.T) {
+	f, err := ParsePoFile([]byte(metadataPo))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Comments of unknown kinds are written back as they were
+	if out := string(f.Format(FormatOptions{})); out != metadataPo {
+		t.Errorf("Expected '%s' but got '%s'", metadataPo, out)
+	}
+
+	e := f.Find("", "Save")
+	meta := e.Metadata()
+	if len(meta) != 2 || meta["X-String-Id"] != "4711" || meta["X-Screenshot"] != "https://tms.example.com/screens/42.png" {
+		t.Errorf("Unexpected metadata %v", meta)
+	}
+
+	e.SetMetadata("X-String-Id", "4712")
+	e.SetMetadata("X-Screenshot", "")
+	e.SetMetadata("X-Owner", "billing")
+	e.Str[0] = "Sichern"
+
+	expected := `# X-String-Id: 4712
+# Reviewed by the legal team
+# X-Owner: billing
+#: settings.go:12
+#@ tms-lock
+#=rev 3
+msgid "Save"
+msgstr "Sichern"
+`
+	out := f.Format(FormatOptions{})
+	if string(out) != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, out)
+	}
+
+	f, err = ParsePoFile(out)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	meta = f.Find("", "Save").Metadata()
+	if len(meta) != 2 || meta["X-String-Id"] != "4712" || meta["X-Owner"] != "billing" {
+		t.Errorf("Unexpected metadata %v", meta)
+	}
+}
+
+func TestMergeTemplateKeepsOtherComments(t *testing.T) {
+	po, _ := ParsePoFile([]byte(metadataPo))
+	pot, _ := ParsePoFile([]byte("#: settings.go:14\nmsgid \"Save\"\nmsgstr \"\"\n"))
+
+	e := MergeTemplate(po, pot, MergeOptions{}).Find("", "Save")
+	if len(e.OtherComments) != 2 || e.OtherComments[0] != "@ tms-lock" {
+		t.Errorf("Unexpected comments %q", e.OtherComments)
+	}
+	if id := e.Metadata()["X-String-Id"]; id != "4711" {
+		t.Errorf("Expected '%s' but got '%s'", "4711", id)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"strconv"
+	"strings"
+)
+
+// IssueArgumentOrder reports a translation using the arguments of its msgid in another order without explicit
+// argument indexes, garbling the output. See IndexArguments.
+const IssueArgumentOrder IssueKind = "argument-order"
+
+// formatArg is a fmt verb of a format, with the argument it formats.
+type formatArg struct {
+	start, end int
+	indexed    bool
+	arg        int
+	verb       string
+}
+
+// formatArgs returns the fmt verbs of s, numbering their arguments as fmt does. Named verbs aren't included.
+func formatArgs(s string) []formatArg {
+	var args []formatArg
+	next := 1
+	for _, m := range formatVerbRe.FindAllStringSubmatchIndex(s, -1) {
+		verb := s[m[4]:m[5]]
+		if verb == "%" {
+			continue
+		}
+		a := formatArg{start: m[0], end: m[1], indexed: m[2] != -1, verb: verb}
+		if a.indexed {
+			next, _ = strconv.Atoi(s[m[2]:m[3]])
+		}
+		a.arg = next
+		next++
+		args = append(args, a)
+	}
+
+	return args
+}
+
+// indexArguments returns str with the arguments of its unindexed verbs matched to the ones of the msgid verbs
+// of the same kind, reporting if they're reordered, and false when a verb can't be matched.
+func indexArguments(msgid, str string) (indexed string, reordered, ok bool) {
+	source := formatArgs(msgid)
+	verbs := make(map[int]string)
+	for _, a := range source {
+		verbs[a.arg] = a.verb
+	}
+
+	var buf strings.Builder
+	last := 0
+	used := make(map[int]bool)
+	for _, a := range formatArgs(str) {
+		arg := a.arg
+		if !a.indexed && verbs[arg] != a.verb {
+			arg = 0
+			for _, s := range source {
+				if s.verb == a.verb && (arg == 0 || used[arg] && !used[s.arg]) {
+					arg = s.arg
+				}
+			}
+			if arg == 0 {
+				return str, false, false
+			}
+			reordered = true
+		}
+		used[arg] = true
+
+		buf.WriteString(str[last:a.start])
+		if a.indexed {
+			buf.WriteString(str[a.start:a.end])
+		} else {
+			buf.WriteString("%[" + strconv.Itoa(arg) + "]" + str[a.start+1:a.end])
+		}
+		last = a.end
+	}
+	buf.WriteString(str[last:])
+
+	return buf.String(), reordered, true
+}
+
+// ReordersArguments reports if the translation str uses the arguments of msgid in another order without explicit
+// argument indexes, as "%d Dateien in %s" for "%s holds %d files", which fmt would format with the wrong arguments.
+// Arguments are told apart by their verbs, so translations swapping arguments of the same verb can't be detected.
+func ReordersArguments(msgid, str string) bool {
+	_, reordered, _ := indexArguments(msgid, str)
+
+	return reordered
+}
+
+/*
+IndexArguments rewrites the verbs of the translation str to explicit argument indexes, matching each of them to
+the first unused argument of msgid with the same verb, so translations can change the order of the arguments.
+It reports false, returning str, when a verb of str has no match in msgid.
+
+Example:
+
+	str, _ := gotext.IndexArguments("%s holds %d files", "%d Dateien in %s")
+	// str is "%[2]d Dateien in %[1]s"
+*/
+func IndexArguments(msgid, str string) (string, bool) {
+	indexed, _, ok := indexArguments(msgid, str)
+
+	return indexed, ok
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestIndexArguments(t *testing.T) {
+	tests := []struct {
+		msgid, str, expected string
+		reordered, ok        bool
+	}{
+		{"%s holds %d files", "%d Dateien in %s", "%[2]d Dateien in %[1]s", true, true},
+		{"%s holds %d files", "%s enthält %d Dateien", "%[1]s enthält %[2]d Dateien", false, true},
+		{"%s of %s", "%s von %s", "%[1]s von %[2]s", false, true},
+		{"%s %d %s", "%s %s %d", "%[1]s %[3]s %[2]d", true, true},
+		{"%s: %.2f%%", "%.2f%% für %s", "%[2].2f%% für %[1]s", true, true},
+		{"%[2]s of %[1]s", "%[1]s: %[2]s", "%[1]s: %[2]s", false, true},
+		{"%(name)s has %d", "%d für %(name)s", "%[1]d für %(name)s", false, true},
+		{"Hello %s", "Hallo %d", "Hallo %d", false, false},
+	}
+	for _, test := range tests {
+		str, ok := IndexArguments(test.msgid, test.str)
+		if str != test.expected || ok != test.ok {
+			t.Errorf("Expected '%s' (%v) but got '%s' (%v)", test.expected, test.ok, str, ok)
+		}
+		if reordered := ReordersArguments(test.msgid, test.str); reordered != test.reordered {
+			t.Errorf("Expected %v but got %v for '%s'", test.reordered, reordered, test.str)
+		}
+	}
+
+	str, _ := IndexArguments("%s holds %d files", "%d Dateien in %s")
+	if out := fmt.Sprintf(str, "docs", 3); out != "3 Dateien in docs" {
+		t.Errorf("Expected '%s' but got '%s'", "3 Dateien in docs", out)
+	}
+}
+
+func TestValidateArgumentOrder(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "%s holds %d files"
+msgstr "%d Dateien in %s"
+
+msgid "%s has %d file"
+msgid_plural "%s has %d files"
+msgstr[0] "%s hat %d Datei"
+msgstr[1] "%d Dateien hat %s"
+`))
+
+	issues := po.Validate()
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues but got %v", issues)
+	}
+	expected := `msgstr reorders the arguments without indexes, use "%[2]d Dateien in %[1]s"`
+	if issues[0].Kind != IssueArgumentOrder || issues[0].Message != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, issues[0])
+	}
+	expected = `msgstr[1] reorders the arguments without indexes, use "%[2]d Dateien hat %[1]s"`
+	if issues[1].Kind != IssueArgumentOrder || issues[1].Message != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, issues[1])
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+/*
+Package prommetrics exports gotext translation metrics to Prometheus.
+
+It's a separate module, so the gotext package doesn't depend on the Prometheus client.
+
+Example:
+
+	m, err := prommetrics.New(prometheus.DefaultRegisterer)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	l := gotext.NewLocale("/path/to/i18n/dir", "de")
+	l.SetObserver(m)
+	l.AddDomain("default")
+
+Exported metrics, labeled by domain and language:
+
+	gotext_lookups_total          Translation lookups.
+	gotext_misses_total           Lookups without translation.
+	gotext_fuzzy_total            Lookups resolved with fuzzy translations.
+	gotext_loads_total            Catalog loads and reloads.
+	gotext_catalog_entries        Entries of the loaded catalogs.
+
+Alerting on the rate of gotext_misses_total after a deploy detects untranslated strings.
+*/
+package prommetrics
+
+import (
+	"github.com/leonelquinteros/gotext"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var labels = []string{"domain", "language"}
+
+// Metrics is a gotext.Observer exporting translation metrics to Prometheus.
+type Metrics struct {
+	lookups *prometheus.CounterVec
+	misses  *prometheus.CounterVec
+	fuzzy   *prometheus.CounterVec
+	loads   *prometheus.CounterVec
+	entries *prometheus.GaugeVec
+}
+
+var _ gotext.Observer = (*Metrics)(nil)
+
+// New creates the translation metrics and registers them on reg.
+func New(reg prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		lookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gotext_lookups_total",
+			Help: "Translation lookups.",
+		}, labels),
+		misses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gotext_misses_total",
+			Help: "Lookups without translation.",
+		}, labels),
+		fuzzy: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gotext_fuzzy_total",
+			Help: "Lookups resolved with fuzzy translations.",
+		}, labels),
+		loads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gotext_loads_total",
+			Help: "Catalog loads and reloads.",
+		}, labels),
+		entries: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "gotext_catalog_entries",
+			Help: "Entries of the loaded catalogs.",
+		}, labels),
+	}
+
+	for _, c := range []prometheus.Collector{m.lookups, m.misses, m.fuzzy, m.loads, m.entries} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// ObserveLookup implements gotext.Observer.
+func (m *Metrics) ObserveLookup(lang, dom string, found, fuzzy bool) {
+	m.lookups.WithLabelValues(dom, lang).Inc()
+	if !found {
+		m.misses.WithLabelValues(dom, lang).Inc()
+	}
+	if fuzzy {
+		m.fuzzy.WithLabelValues(dom, lang).Inc()
+	}
+}
+
+// ObserveLoad implements gotext.Observer.
+func (m *Metrics) ObserveLoad(lang, dom string, entries int) {
+	m.loads.WithLabelValues(dom, lang).Inc()
+	m.entries.WithLabelValues(dom, lang).Set(float64(entries))
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package prommetrics
+
+import (
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := New(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := gotext.NewLocale("../fixtures/", "en_US")
+	l.SetObserver(m)
+	l.AddDomain("default")
+
+	l.Get("My text")
+	l.Get("Missing")
+	l.Get("Missing")
+
+	for _, test := range []struct {
+		c        prometheus.Collector
+		expected float64
+	}{
+		{m.lookups.WithLabelValues("default", "en_US"), 3},
+		{m.misses.WithLabelValues("default", "en_US"), 2},
+		{m.fuzzy.WithLabelValues("default", "en_US"), 0},
+		{m.loads.WithLabelValues("default", "en_US"), 1},
+	} {
+		if got := testutil.ToFloat64(test.c); got != test.expected {
+			t.Errorf("Expected %v but got %v", test.expected, got)
+		}
+	}
+
+	if got := testutil.ToFloat64(m.entries.WithLabelValues("default", "en_US")); got == 0 {
+		t.Error("Expected catalog entries to be reported")
+	}
+
+	if _, err = New(reg); err == nil {
+		t.Error("Expected error registering metrics twice")
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// PseudoOptions configures the pseudo-localization of strings.
+type PseudoOptions struct {
+	// Expansion is the length added to strings, as a ratio of their length, i.e. 0.3 for 30% longer strings.
+	Expansion float64
+
+	// Accents replaces ASCII letters by accented ones.
+	Accents bool
+
+	// Open and Close are the markers wrapping strings, revealing truncated and concatenated ones.
+	Open, Close string
+}
+
+// DefaultPseudoOptions are the pseudo-localization options used by most tools:
+// strings 30% longer, accented, and wrapped in brackets.
+var DefaultPseudoOptions = PseudoOptions{Expansion: 0.3, Accents: true, Open: "[", Close: "]"}
+
+// pseudoAccents maps ASCII letters to accented ones.
+var pseudoAccents = map[rune]rune{
+	'a': 'á', 'b': 'ƀ', 'c': 'ç', 'd': 'ð', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ', 'h': 'ĥ', 'i': 'î', 'j': 'ĵ', 'k': 'ķ', 'l': 'ļ', 'm': 'ɱ',
+	'n': 'ñ', 'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ', 's': 'š', 't': 'ţ', 'u': 'û', 'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+	'A': 'Å', 'B': 'Ɓ', 'C': 'Ç', 'D': 'Ð', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ', 'H': 'Ĥ', 'I': 'Î', 'J': 'Ĵ', 'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ',
+	'N': 'Ñ', 'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ', 'S': 'Š', 'T': 'Ţ', 'U': 'Û', 'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+}
+
+// pseudoKeepRe matches the parts of strings kept by pseudo-localization: named and fmt placeholders,
+// template actions, markup tags and entities.
+var pseudoKeepRe = regexp.MustCompile(re.String() + `|` + formatVerbRe.String() + `|\{\{.*?\}\}|<[^>]*>|&#?[a-zA-Z0-9]+;`)
+
+// Pseudolocalize returns a pseudo-localized version of s, to test the localization of user interfaces without translations:
+// strings stay readable while revealing the ones left untranslated, truncated or concatenated.
+// Placeholders, template actions and markup are kept unchanged.
+func Pseudolocalize(s string, opts PseudoOptions) string {
+	if s == "" {
+		return s
+	}
+
+	var b strings.Builder
+	b.WriteString(opts.Open)
+
+	letters := 0
+	last := 0
+	accent := func(text string) {
+		for _, r := range text {
+			if a, ok := pseudoAccents[r]; ok && opts.Accents {
+				r = a
+			}
+			if r != ' ' && r != '\n' {
+				letters++
+			}
+			b.WriteRune(r)
+		}
+	}
+	for _, loc := range pseudoKeepRe.FindAllStringIndex(s, -1) {
+		accent(s[last:loc[0]])
+		b.WriteString(s[loc[0]:loc[1]])
+		letters += utf8.RuneCountInString(s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	accent(s[last:])
+
+	if pad := int(math.Ceil(float64(letters) * opts.Expansion)); pad > 0 {
+		b.WriteString(strings.Repeat("~", pad))
+	}
+	b.WriteString(opts.Close)
+
+	return b.String()
+}
+
+// Pseudolocalize fills the translations of all of the entries of the catalog with pseudo-localized msgids,
+// so QA builds can be produced from a template. Plural forms beyond the first one use the msgid_plural.
+func (f *PoFile) Pseudolocalize(opts PseudoOptions) {
+	nplurals := f.nplurals()
+	for _, e := range f.Entries {
+		if e.IsHeader() || e.Obsolete {
+			continue
+		}
+
+		e.SetFlag("fuzzy", false)
+		if e.PluralID == "" {
+			e.Str = []string{Pseudolocalize(e.ID, opts)}
+			continue
+		}
+		e.Str = make([]string, nplurals)
+		for i := range e.Str {
+			e.Str[i] = Pseudolocalize(e.PluralID, opts)
+		}
+		e.Str[0] = Pseudolocalize(e.ID, opts)
+	}
+}
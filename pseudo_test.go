@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"testing"
+)
+
+func TestPseudolocalize(t *testing.T) {
+	tests := []struct {
+		s    string
+		opts PseudoOptions
+		want string
+	}{
+		{"Save", DefaultPseudoOptions, "[Šáṽé~~]"},
+		{"Hello %s, you have %(count)d <b>new</b> messages", DefaultPseudoOptions,
+			"[Ĥéļļö %s, ýöû ĥáṽé %(count)d <b>ñéŵ</b> ɱéššáĝéš~~~~~~~~~~~~~]"},
+		{"{{.Name}} &amp; co", PseudoOptions{Accents: true}, "{{.Name}} &amp; çö"},
+		{"Save", PseudoOptions{Expansion: 0.5, Open: "«", Close: "»"}, "«Save~~»"},
+		{"", DefaultPseudoOptions, ""},
+	}
+	for _, test := range tests {
+		if got := Pseudolocalize(test.s, test.opts); got != test.want {
+			t.Errorf("Expected '%s' but got '%s'", test.want, got)
+		}
+	}
+}
+
+func TestPoFilePseudolocalize(t *testing.T) {
+	f, err := ParsePoFile([]byte(`msgid ""
+msgstr "Plural-Forms: nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+#, fuzzy
+msgid "Open"
+msgstr ""
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Pseudolocalize(PseudoOptions{Open: "[", Close: "]"})
+
+	po := NewPo()
+	po.Parse(f.Format(FormatOptions{}))
+	if tr := po.Get("Open"); tr != "[Open]" {
+		t.Errorf("Expected '[Open]' but got '%s'", tr)
+	}
+	for n, want := range map[int]string{1: "[1 file]", 3: "[3 files]", 5: "[5 files]"} {
+		if tr := po.GetN("%d file", "%d files", n, n); tr != want {
+			t.Errorf("Expected '%s' but got '%s'", want, tr)
+		}
+	}
+}
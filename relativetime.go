@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"math"
+	"time"
+)
+
+// RelativeTimeContext is the msgctxt of the strings looked up by Locale.FormatRelativeTime.
+const RelativeTimeContext = "relative time"
+
+// relativeUnit holds the strings of a unit of relative times, from the largest to the smallest.
+type relativeUnit struct {
+	d                    time.Duration
+	past, pastPlural     string
+	future, futurePlural string
+}
+
+var relativeUnits = []relativeUnit{
+	{365 * 24 * time.Hour, "%d year ago", "%d years ago", "in %d year", "in %d years"},
+	{30 * 24 * time.Hour, "%d month ago", "%d months ago", "in %d month", "in %d months"},
+	{7 * 24 * time.Hour, "%d week ago", "%d weeks ago", "in %d week", "in %d weeks"},
+	{24 * time.Hour, "%d day ago", "%d days ago", "in %d day", "in %d days"},
+	{time.Hour, "%d hour ago", "%d hours ago", "in %d hour", "in %d hours"},
+	{time.Minute, "%d minute ago", "%d minutes ago", "in %d minute", "in %d minutes"},
+	{time.Second, "%d second ago", "%d seconds ago", "in %d second", "in %d seconds"},
+}
+
+/*
+FormatRelativeTime returns a duration relative to now as "3 days ago" for negative durations or "in 2 hours"
+for positive ones, in the largest unit it holds, from years (365 days) and months (30 days) down to seconds,
+truncating the rest. Durations under a second are "now".
+
+The strings are looked up in the default domain of the Locale with the RelativeTimeContext, i.e.
+msgctxt "relative time" and msgid "%d day ago" with msgid_plural "%d days ago", so their plural forms follow
+the Plural-Forms of the catalog. Untranslated strings are in English.
+
+Example:
+
+	l.FormatRelativeTime(time.Until(invoice.Due)) // "in 3 days"
+	l.FormatRelativeTime(-90 * time.Minute)        // "1 hour ago"
+*/
+func (l *Locale) FormatRelativeTime(d time.Duration) string {
+	past := d < 0
+	if past {
+		// -math.MinInt64 overflows, the nanosecond less doesn't change the count of years
+		if d == math.MinInt64 {
+			d++
+		}
+		d = -d
+	}
+
+	for _, u := range relativeUnits {
+		if d < u.d {
+			continue
+		}
+		n := int(d / u.d)
+		if past {
+			return l.GetNC(u.past, u.pastPlural, n, RelativeTimeContext, n)
+		}
+		return l.GetNC(u.future, u.futurePlural, n, RelativeTimeContext, n)
+	}
+
+	return l.GetC("now", RelativeTimeContext)
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestFormatRelativeTime(t *testing.T) {
+	dirname := path.Join("/tmp", "relativetime")
+	if err := os.MkdirAll(path.Join(dirname, "pl", "LC_MESSAGES"), os.ModePerm); err != nil {
+		t.Fatalf("Can't create test directory: %s", err.Error())
+	}
+	defer os.RemoveAll(dirname)
+
+	// Polish has three plural forms
+	str := `msgid ""
+msgstr ""
+"Language: pl\n"
+"Plural-Forms: nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgctxt "relative time"
+msgid "%d day ago"
+msgid_plural "%d days ago"
+msgstr[0] "%d dzień temu"
+msgstr[1] "%d dni temu"
+msgstr[2] "%d dni temu"
+
+msgctxt "relative time"
+msgid "in %d hour"
+msgid_plural "in %d hours"
+msgstr[0] "za %d godzinę"
+msgstr[1] "za %d godziny"
+msgstr[2] "za %d godzin"
+
+msgctxt "relative time"
+msgid "now"
+msgstr "teraz"
+`
+	err := ioutil.WriteFile(path.Join(dirname, "pl", "LC_MESSAGES", "default.po"), []byte(str), os.ModePerm)
+	if err != nil {
+		t.Fatalf("Can't write test file: %s", err.Error())
+	}
+
+	l := NewLocale(dirname, "pl")
+	l.AddDomain("default")
+
+	tests := []struct {
+		tr, expected string
+	}{
+		{l.FormatRelativeTime(-3 * 24 * time.Hour), "3 dni temu"},
+		{l.FormatRelativeTime(-25 * time.Hour), "1 dzień temu"},
+		{l.FormatRelativeTime(2 * time.Hour), "za 2 godziny"},
+		{l.FormatRelativeTime(5*time.Hour + 59*time.Minute), "za 5 godzin"},
+		{l.FormatRelativeTime(time.Hour), "za 1 godzinę"},
+		{l.FormatRelativeTime(500 * time.Millisecond), "teraz"},
+		{l.FormatRelativeTime(-90 * time.Second), "1 minute ago"},
+		{l.FormatRelativeTime(-14 * 24 * time.Hour), "2 weeks ago"},
+		{l.FormatRelativeTime(400 * 24 * time.Hour), "in 1 year"},
+		{l.FormatRelativeTime(-60 * 24 * time.Hour), "2 months ago"},
+		{l.FormatRelativeTime(math.MinInt64), "292 years ago"},
+		{l.FormatRelativeTime(math.MaxInt64), "in 292 years"},
+	}
+	for _, test := range tests {
+		if test.tr != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.tr)
+		}
+	}
+}
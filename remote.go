@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// HTTPClient is the client used to fetch remote catalogs added with Locale.AddDomainURL.
+var HTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// remoteCatalog is a catalog fetched over HTTP, along with the validators used to revalidate it.
+type remoteCatalog struct {
+	url          string
+	file         string
+	etag         string
+	lastModified string
+}
+
+// AddDomainURL fetches a .po or .mo catalog over HTTP(S) and adds it as a domain named after the file,
+// so "https://example.com/i18n/de/default.po" is added as the "default" domain.
+// Catalogs can be revalidated later with RefreshURLs or WatchURLs to update translations without shipping new binaries.
+func (l *Locale) AddDomainURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return err
+	}
+
+	dom, file := domainFromPath(parsed.Path)
+	if dom == "" {
+		return fmt.Errorf("no catalog file in url %s", u)
+	}
+
+	rc := &remoteCatalog{url: u, file: file}
+
+	l.remotesMutex.Lock()
+	defer l.remotesMutex.Unlock()
+
+	if _, err = l.fetchRemote(dom, rc); err != nil {
+		return err
+	}
+
+	if l.remotes == nil {
+		l.remotes = make(map[string]*remoteCatalog)
+	}
+	l.remotes[dom] = rc
+
+	return nil
+}
+
+// RefreshURLs revalidates the catalogs added with AddDomainURL using conditional requests (ETag and If-Modified-Since),
+// replacing the domains that changed. All catalogs are refreshed even if some fail, and the first error is returned.
+func (l *Locale) RefreshURLs() error {
+	l.remotesMutex.Lock()
+	defer l.remotesMutex.Unlock()
+
+	var first error
+	for dom, rc := range l.remotes {
+		if _, err := l.fetchRemote(dom, rc); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// WatchURLs calls RefreshURLs every interval on a new goroutine, until the returned function is called.
+// Refresh errors are passed to onError, when not nil. Translations keep being served from the last fetched catalogs.
+func (l *Locale) WatchURLs(interval time.Duration, onError func(error)) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := l.RefreshURLs(); err != nil && onError != nil {
+					onError(err)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
+
+// fetchRemote downloads the catalog when it changed since the last fetch, and adds it to the Locale.
+// It reports if the catalog was updated. It must be called while holding remotesMutex.
+func (l *Locale) fetchRemote(dom string, rc *remoteCatalog) (bool, error) {
+	req, err := http.NewRequest(http.MethodGet, rc.url, nil)
+	if err != nil {
+		return false, err
+	}
+	if rc.etag != "" {
+		req.Header.Set("If-None-Match", rc.etag)
+	}
+	if rc.lastModified != "" {
+		req.Header.Set("If-Modified-Since", rc.lastModified)
+	}
+
+	resp, err := HTTPClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return false, nil
+	case http.StatusOK:
+	default:
+		return false, fmt.Errorf("fetching %s: %s", rc.url, resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	if len(data) == 0 {
+		return false, errors.New("empty catalog fetched from " + rc.url)
+	}
+
+	l.addCatalogData(dom, rc.file, data)
+
+	rc.etag = resp.Header.Get("ETag")
+	rc.lastModified = resp.Header.Get("Last-Modified")
+
+	return true, nil
+}
+
+// addCatalogData parses the contents of a catalog file (.po or .mo, by extension) and adds it as a domain.
+func (l *Locale) addCatalogData(dom, file string, data []byte) {
+	tr := newTranslator(file, NewDomain())
+	tr.Parse(data)
+	l.AddTranslator(dom, tr)
+}
+
+// domainFromPath returns the domain name for a catalog path, that is the file name without extension.
+func domainFromPath(p string) (dom, file string) {
+	file = path.Base(p)
+	dom = strings.TrimSuffix(file, path.Ext(file))
+	if dom == "." || dom == "/" {
+		dom = ""
+	}
+
+	return dom, file
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestLocaleAddDomainURL(t *testing.T) {
+	var mutex sync.Mutex
+	version := 1
+	fetches := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		etag := fmt.Sprintf(`"v%d"`, version)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if r.URL.Path != "/de/remote.po" {
+			http.NotFound(w, r)
+			return
+		}
+
+		fetches++
+		w.Header().Set("ETag", etag)
+		fmt.Fprintf(w, "msgid \"Hello\"\nmsgstr \"Hallo v%d\"\n", version)
+	}))
+	defer srv.Close()
+
+	l := NewLocale("", "de")
+	if err := l.AddDomainURL(srv.URL + "/de/remote.po"); err != nil {
+		t.Fatal(err)
+	}
+	if l.GetDomain() != "remote" {
+		t.Errorf("Expected 'remote' but got '%s'", l.GetDomain())
+	}
+	if tr := l.Get("Hello"); tr != "Hallo v1" {
+		t.Errorf("Expected 'Hallo v1' but got '%s'", tr)
+	}
+
+	// Unchanged catalogs aren't downloaded again
+	if err := l.RefreshURLs(); err != nil {
+		t.Error(err)
+	}
+	if fetches != 1 {
+		t.Errorf("Expected 1 fetch but got %d", fetches)
+	}
+
+	mutex.Lock()
+	version = 2
+	mutex.Unlock()
+
+	if err := l.RefreshURLs(); err != nil {
+		t.Error(err)
+	}
+	if tr := l.Get("Hello"); tr != "Hallo v2" {
+		t.Errorf("Expected 'Hallo v2' but got '%s'", tr)
+	}
+
+	if err := l.AddDomainURL(srv.URL + "/de/missing.po"); err == nil {
+		t.Error("Expected error fetching missing catalog")
+	}
+}
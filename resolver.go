@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Resolver returns the languages requested by an HTTP request according to some source (cookie, header...),
+// ordered by preference. It returns none when the source isn't present.
+type Resolver interface {
+	Resolve(r *http.Request) []string
+}
+
+// ResolverFunc adapts a function to the Resolver interface.
+type ResolverFunc func(r *http.Request) []string
+
+// Resolve calls f(r).
+func (f ResolverFunc) Resolve(r *http.Request) []string {
+	return f(r)
+}
+
+/*
+ResolverChain combines Resolvers by priority: the languages of the first Resolver come first,
+so they're preferred by Locale.Match, and later Resolvers provide the fallbacks.
+
+Example:
+
+	resolvers := gotext.ResolverChain{
+		gotext.PathPrefixResolver(),
+		gotext.QueryResolver("lang"),
+		gotext.CookieResolver("lang"),
+		gotext.UserResolver(func(r *http.Request) string { return currentUser(r).Language }),
+		gotext.AcceptLanguageResolver(),
+	}
+
+	http.ListenAndServe(":8080", gotext.Middleware(l, resolvers)(mux))
+*/
+type ResolverChain []Resolver
+
+// Resolve returns the languages of every Resolver, in order.
+func (rc ResolverChain) Resolve(r *http.Request) []string {
+	var langs []string
+	for _, res := range rc {
+		langs = append(langs, res.Resolve(r)...)
+	}
+
+	return langs
+}
+
+// AcceptLanguageResolver returns a Resolver reading the Accept-Language header.
+func AcceptLanguageResolver() Resolver {
+	return ResolverFunc(func(r *http.Request) []string {
+		var langs []string
+		for _, v := range r.Header["Accept-Language"] {
+			langs = append(langs, ParseAcceptLanguage(v)...)
+		}
+
+		return langs
+	})
+}
+
+// CookieResolver returns a Resolver reading the language from the cookie with the given name.
+func CookieResolver(name string) Resolver {
+	return ResolverFunc(func(r *http.Request) []string {
+		if c, err := r.Cookie(name); err == nil && c.Value != "" {
+			return []string{c.Value}
+		}
+
+		return nil
+	})
+}
+
+// QueryResolver returns a Resolver reading the language from the URL query parameter with the given name.
+func QueryResolver(param string) Resolver {
+	return ResolverFunc(func(r *http.Request) []string {
+		if v := r.URL.Query().Get(param); v != "" {
+			return []string{v}
+		}
+
+		return nil
+	})
+}
+
+// PathPrefixResolver returns a Resolver reading the language from the first segment of the URL path (i.e. "/fr/about").
+// Segments not looking like a language code are ignored.
+func PathPrefixResolver() Resolver {
+	return ResolverFunc(func(r *http.Request) []string {
+		seg := strings.TrimPrefix(r.URL.Path, "/")
+		if idx := strings.Index(seg, "/"); idx != -1 {
+			seg = seg[:idx]
+		}
+
+		// Language codes have 2 or 3 letters, optionally followed by other subtags
+		lang := SimplifiedLocale(seg)
+		base := lang
+		if idx := strings.Index(base, "_"); idx != -1 {
+			base = base[:idx]
+		}
+		if len(base) < 2 || len(base) > 3 || !isAlpha(base) || !isLanguageCode(lang) {
+			return nil
+		}
+
+		return []string{lang}
+	})
+}
+
+// UserResolver returns a Resolver reading the language from a callback, i.e. from the profile of the authenticated user.
+// The callback returns an empty string when there's no preference.
+func UserResolver(f func(r *http.Request) string) Resolver {
+	return ResolverFunc(func(r *http.Request) []string {
+		if lang := f(r); lang != "" {
+			return []string{lang}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestResolvers(t *testing.T) {
+	r := httptest.NewRequest("GET", "/de-de/about?lang=fr", nil)
+	r.Header.Set("Accept-Language", "en-AU, en;q=0.5")
+	r.AddCookie(&http.Cookie{Name: "lang", Value: "es"})
+
+	for _, test := range []struct {
+		res      Resolver
+		expected []string
+	}{
+		{AcceptLanguageResolver(), []string{"en_AU", "en"}},
+		{CookieResolver("lang"), []string{"es"}},
+		{CookieResolver("missing"), nil},
+		{QueryResolver("lang"), []string{"fr"}},
+		{QueryResolver("missing"), nil},
+		{PathPrefixResolver(), []string{"de_DE"}},
+		{UserResolver(func(r *http.Request) string { return "it" }), []string{"it"}},
+		{UserResolver(func(r *http.Request) string { return "" }), nil},
+		{ResolverChain{CookieResolver("lang"), QueryResolver("lang")}, []string{"es", "fr"}},
+	} {
+		if got := test.res.Resolve(r); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Expected %v but got %v", test.expected, got)
+		}
+	}
+
+	for _, p := range []string{"/about", "/", "/docs/v1", "/../x"} {
+		if got := PathPrefixResolver().Resolve(httptest.NewRequest("GET", p, nil)); got != nil {
+			t.Errorf("Expected no language for '%s' but got %v", p, got)
+		}
+	}
+}
+
+func TestRequestLocaleResolvers(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	l.AddDomain("default")
+
+	r := httptest.NewRequest("GET", "/xx/about?lang=fr", nil)
+	r.Header.Set("Accept-Language", "de")
+
+	// Unavailable languages fall through to the next Resolver
+	if got := RequestLocale(l, r, PathPrefixResolver(), QueryResolver("lang"), AcceptLanguageResolver()).GetLanguage(); got != "fr" {
+		t.Errorf("Expected 'fr' but got '%s'", got)
+	}
+	if got := RequestLocale(l, r, AcceptLanguageResolver(), QueryResolver("lang")).GetLanguage(); got != "de" {
+		t.Errorf("Expected 'de' but got '%s'", got)
+	}
+	if got := RequestLocale(l, r).GetLanguage(); got != "de" {
+		t.Errorf("Expected 'de' but got '%s'", got)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ReverseMatch is an entry whose translation matches the string looked up by Po.Lookup.
+type ReverseMatch struct {
+	// Context of the entry, empty for none.
+	Context string
+
+	ID       string
+	PluralID string
+
+	// Form is the index of the plural form matched, 0 for singular translations.
+	Form int
+
+	// References are the source locations of the entry, "file:line", as listed by its "#:" comments.
+	References []string
+}
+
+// reverseKey identifies a plural form of an entry.
+type reverseKey struct {
+	key  entryKey
+	form int
+}
+
+// reversePattern matches the strings formatted with a translation holding placeholders.
+type reversePattern struct {
+	re *regexp.Regexp
+	reverseKey
+}
+
+// reverseIndex maps translations back to their entries. It must never be modified after being built.
+type reverseIndex struct {
+	exact    map[string][]reverseKey
+	patterns []reversePattern
+	entries  map[entryKey]*Translation
+	refs     map[entryKey][]string
+}
+
+// reversePlaceholderRe matches the placeholders replaced by the values formatted with a translation.
+var reversePlaceholderRe = regexp.MustCompile(re.String() + `|` + formatVerbRe.String())
+
+// EnableReverseIndex makes the catalogs parsed afterwards indexed by translation, along with the source locations of
+// their entries, so Lookup can map a string a user saw back to its msgid and code location. It's disabled by default
+// as the index takes as much memory as the translations.
+func (po *Po) EnableReverseIndex() {
+	po.domain.trMutex.Lock()
+	po.reverseEnabled = true
+	if po.references == nil {
+		po.references = make(map[entryKey][]string)
+	}
+	po.domain.trMutex.Unlock()
+}
+
+// parseReferences takes a "#:" comment line starting a new entry, and buffers its source locations when the reverse
+// index is enabled.
+func (po *Po) parseReferences(l string) {
+	po.saveBuffer()
+	po.refBuffer = append(po.refBuffer, strings.Fields(strings.TrimPrefix(l, "#:"))...)
+}
+
+// buildReverseIndex indexes the translations of the domain, it must be called while holding trMutex.
+func (po *Po) buildReverseIndex() {
+	idx := &reverseIndex{
+		exact:   make(map[string][]reverseKey),
+		entries: po.domain.entries,
+		refs:    make(map[entryKey][]string, len(po.references)),
+	}
+	for k, refs := range po.references {
+		idx.refs[k] = refs
+	}
+
+	for k, t := range po.domain.entries {
+		if k.id == "" {
+			continue
+		}
+		for form, tr := range t.Trs {
+			if tr == "" {
+				continue
+			}
+			rk := reverseKey{key: k, form: form}
+			idx.exact[tr] = append(idx.exact[tr], rk)
+
+			if locs := reversePlaceholderRe.FindAllStringIndex(tr, -1); len(locs) > 0 {
+				idx.patterns = append(idx.patterns, reversePattern{re: placeholderPattern(tr, locs), reverseKey: rk})
+			}
+		}
+	}
+
+	po.reverse.Store(idx)
+}
+
+// placeholderPattern returns a regular expression matching the strings formatted with the translation, whose
+// placeholders are at the given locations.
+func placeholderPattern(tr string, locs [][]int) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, loc := range locs {
+		b.WriteString(regexp.QuoteMeta(tr[last:loc[0]]))
+		if tr[loc[0]:loc[1]] == "%%" {
+			b.WriteString("%")
+		} else {
+			b.WriteString("(?s:.+?)")
+		}
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(tr[last:]))
+	b.WriteString("$")
+
+	return regexp.MustCompile(b.String())
+}
+
+// Lookup returns the entries whose translation is the given string, as a user saw it, with their source locations,
+// so support tooling can map it back to its msgid and code. Strings formatted from translations with placeholders,
+// i.e. "Sie haben 3 Nachrichten" for "Sie haben %d Nachrichten", are matched when no translation is identical.
+// Matches are sorted by context, msgid and plural form.
+// The reverse index must be enabled with EnableReverseIndex before parsing, Lookup returns nil otherwise.
+func (po *Po) Lookup(translated string) []ReverseMatch {
+	idx, ok := po.reverse.Load().(*reverseIndex)
+	if !ok {
+		return nil
+	}
+
+	keys := idx.exact[translated]
+	if len(keys) == 0 {
+		for _, p := range idx.patterns {
+			if p.re.MatchString(translated) {
+				keys = append(keys, p.reverseKey)
+			}
+		}
+	}
+
+	matches := make([]ReverseMatch, 0, len(keys))
+	for _, rk := range keys {
+		t := idx.entries[rk.key]
+		matches = append(matches, ReverseMatch{
+			Context:    rk.key.ctx,
+			ID:         t.ID,
+			PluralID:   t.PluralID,
+			Form:       rk.form,
+			References: append([]string(nil), idx.refs[rk.key]...),
+		})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Context != b.Context {
+			return a.Context < b.Context
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Form < b.Form
+	})
+
+	return matches
+}
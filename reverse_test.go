@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPoLookup(t *testing.T) {
+	src := []byte(`msgid ""
+msgstr ""
+"Language: de\n"
+
+#: web/home.go:12 web/menu.go:40
+msgid "Home"
+msgstr "Startseite"
+
+#: web/menu.go:41
+#, fuzzy
+msgctxt "menu"
+msgid "Start"
+msgstr "Startseite"
+
+#: web/inbox.go:7
+msgid "You have one message"
+msgid_plural "You have %d messages"
+msgstr[0] "Sie haben eine Nachricht"
+msgstr[1] "Sie haben %d Nachrichten"
+
+msgid "Hello %(name)s, 100%% done"
+msgstr "Hallo %(name)s, 100%% fertig"
+`)
+
+	po := NewPo()
+	po.Parse(src)
+	if matches := po.Lookup("Startseite"); matches != nil {
+		t.Errorf("Expected no matches without reverse index but got %v", matches)
+	}
+
+	po = NewPo()
+	po.EnableReverseIndex()
+	po.Parse(src)
+
+	tests := []struct {
+		translated string
+		expected   []ReverseMatch
+	}{
+		{"Startseite", []ReverseMatch{
+			{ID: "Home", References: []string{"web/home.go:12", "web/menu.go:40"}},
+			{Context: "menu", ID: "Start", References: []string{"web/menu.go:41"}},
+		}},
+		{"Sie haben 3 Nachrichten", []ReverseMatch{
+			{ID: "You have one message", PluralID: "You have %d messages", Form: 1, References: []string{"web/inbox.go:7"}},
+		}},
+		{"Hallo Ana, 100% fertig", []ReverseMatch{
+			{ID: "Hello %(name)s, 100%% done"},
+		}},
+		{"Unbekannt", []ReverseMatch{}},
+	}
+	for _, test := range tests {
+		if matches := po.Lookup(test.translated); !reflect.DeepEqual(matches, test.expected) {
+			t.Errorf("Expected %v but got %v for '%s'", test.expected, matches, test.translated)
+		}
+	}
+
+	// The fuzzy flag following the references still applies
+	if tr, _ := po.GetDomain().Lookup("menu", "Start"); !tr.Fuzzy {
+		t.Errorf("Expected the entry to be fuzzy")
+	}
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+// lookup returns the translation found for the key, as find does, unless it's fuzzy and fuzzy translations are skipped.
+func (c *catalog) lookup(k entryKey) (*Translation, bool) {
+	tr, ok := c.find(k)
+	if ok && c.skipFuzzy && tr.Fuzzy {
+		return nil, false
+	}
+	return tr, ok
+}
+
+// SetSkipFuzzy makes lookups skip the fuzzy translations, the drafts needing review, as if they were missing: the
+// msgid is returned, or the translation of the next layer of the Locale. It's disabled by default, so fuzzy translations
+// are used. Entries are fuzzy when flagged so in the catalog, or when their Translation is marked Fuzzy, i.e. by
+// backends reading the review state of a translation management system.
+func (do *Domain) SetSkipFuzzy(skip bool) {
+	do.trMutex.Lock()
+	do.skipFuzzy = skip
+	do.publish()
+	do.trMutex.Unlock()
+}
+
+/*
+SetSkipFuzzy makes the domains loaded, and the ones loaded afterwards, skip their fuzzy translations, as
+Domain.SetSkipFuzzy, so production only shows reviewed translations while staging shows the drafts too.
+Domains added with AddTranslator, AddDomainURL or an Updater are loaded as well.
+
+Example:
+
+	l := gotext.NewLocale("/path/to/i18n/dir", "de")
+	l.SetSkipFuzzy(os.Getenv("APP_ENV") == "production")
+	l.AddDomain("default")
+*/
+func (l *Locale) SetSkipFuzzy(skip bool) {
+	l.Lock()
+	l.skipFuzzy = skip
+	for _, tr := range l.Domains {
+		if do := tr.GetDomain(); do != nil {
+			do.SetSkipFuzzy(skip)
+		}
+	}
+	l.Unlock()
+}
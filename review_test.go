@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+)
+
+const reviewPo = `msgid ""
+msgstr ""
+"Language: de\n"
+
+msgid "Save"
+msgstr "Speichern"
+
+#, fuzzy
+msgid "Delete"
+msgstr "Löschen"
+
+#, fuzzy, c-format
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "Eine Datei"
+msgstr[1] "%d Dateien"
+`
+
+func TestSkipFuzzy(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(reviewPo))
+
+	if tr := po.Get("Delete"); tr != "Löschen" {
+		t.Errorf("Expected '%s' but got '%s'", "Löschen", tr)
+	}
+
+	po.GetDomain().SetSkipFuzzy(true)
+	tests := []struct {
+		tr, expected string
+	}{
+		{po.Get("Save"), "Speichern"},
+		{po.Get("Delete"), "Delete"},
+		{po.GetN("One file", "%d files", 2, 2), "2 files"},
+	}
+	for _, test := range tests {
+		if test.tr != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.tr)
+		}
+	}
+
+	// Entries are still listed with their state
+	entries := po.Entries()
+	if len(entries) != 3 || !entries[0].Fuzzy || entries[0].ID != "Delete" {
+		t.Errorf("Unexpected entries %+v", entries)
+	}
+}
+
+func TestLocaleSkipFuzzy(t *testing.T) {
+	// Create Locales directory
+	dirname := path.Join("/tmp", "gotextreview", "de", "LC_MESSAGES")
+	err := os.MkdirAll(dirname, os.ModePerm)
+	if err != nil {
+		t.Fatalf("Can't create test directory: %s", err.Error())
+	}
+
+	// Write PO content to file
+	err = ioutil.WriteFile(path.Join(dirname, "default.po"), []byte(reviewPo), 0644)
+	if err != nil {
+		t.Fatalf("Can't write to test file: %s", err.Error())
+	}
+
+	staging := NewLocale("/tmp/gotextreview", "de")
+	staging.AddDomain("default")
+	if tr := staging.Get("Delete"); tr != "Löschen" {
+		t.Errorf("Expected '%s' but got '%s'", "Löschen", tr)
+	}
+
+	production := NewLocale("/tmp/gotextreview", "de")
+	production.SetSkipFuzzy(true)
+	production.AddDomain("default")
+	if tr := production.Get("Delete"); tr != "Delete" {
+		t.Errorf("Expected '%s' but got '%s'", "Delete", tr)
+	}
+	if ly := production.ResolveLayer("default", "Delete", ""); ly != LayerNone {
+		t.Errorf("Expected the %s layer but got %s", LayerNone, ly)
+	}
+
+	// Skipped translations fall through to the next layer
+	production.SetMissingTranslator(MissingTranslatorFunc(func(lang, dom, ctx, str string) (string, error) {
+		return "[" + str + "]", nil
+	}))
+	if tr := production.Get("Delete"); tr != "[Delete]" {
+		t.Errorf("Expected '%s' but got '%s'", "[Delete]", tr)
+	}
+}
+
+func TestLocaleSkipFuzzyAdded(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(reviewPo))
+	}))
+	defer srv.Close()
+
+	l := NewLocale("", "de")
+	l.SetSkipFuzzy(true)
+
+	// Remote domains, and translators added afterwards, skip their fuzzy translations too
+	if err := l.AddDomainURL(srv.URL + "/de/remote.po"); err != nil {
+		t.Fatal(err)
+	}
+	po := NewPo()
+	po.Parse([]byte(reviewPo))
+	l.AddTranslator("added", po)
+
+	for _, dom := range []string{"remote", "added"} {
+		if tr := l.GetD(dom, "Delete"); tr != "Delete" {
+			t.Errorf("Expected '%s' in %s but got '%s'", "Delete", dom, tr)
+		}
+		if tr := l.GetD(dom, "Save"); tr != "Speichern" {
+			t.Errorf("Expected '%s' in %s but got '%s'", "Speichern", dom, tr)
+		}
+	}
+	if err := l.RefreshURLs(); err != nil {
+		t.Fatal(err)
+	}
+	if tr := l.GetD("remote", "Delete"); tr != "Delete" {
+		t.Errorf("Expected '%s' after a refresh but got '%s'", "Delete", tr)
+	}
+}
@@ -0,0 +1,252 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// DefaultSQLQuery is the query used by SQLStore to load the translations of a domain and language.
+// It expects a table with the columns (domain, language, context, msgid, plural_index, msgstr).
+// The header entry (empty context and msgid) provides the Plural-Forms of the catalog, as in PO files.
+// Plural msgids aren't stored: lookups of plural forms without row return the msgid or plural msgid looked up.
+const DefaultSQLQuery = "SELECT context, msgid, plural_index, msgstr FROM translations WHERE domain = ? AND language = ?"
+
+// DefaultSQLErrorTTL is the time a SQLStore serves catalogs failing to load as empty ones before querying them again.
+const DefaultSQLErrorTTL = 5 * time.Second
+
+type sqlKey struct {
+	domain string
+	lang   string
+}
+
+// sqlLoad is a catalog being loaded, shared by the lookups waiting for it.
+type sqlLoad struct {
+	done chan struct{}
+	c    *catalog
+}
+
+/*
+SQLStore loads translations from a database table through database/sql, for products where translations are edited on an admin UI.
+Catalogs are loaded once per domain and language and cached in-process until invalidated.
+Queries run without blocking the lookups of other catalogs, and concurrent lookups of a catalog being loaded share its query.
+Catalogs failing to load are served empty for the error TTL, then queried again.
+
+Example:
+
+	store := gotext.NewSQLStore(db, gotext.DefaultSQLQuery)
+
+	l := gotext.NewLocale("", "de")
+	l.AddTranslator("default", store.Translator("default", "de"))
+
+	fmt.Println(l.Get("Translate this"))
+
+	// After translations are edited
+	store.Invalidate("default", "de")
+*/
+type SQLStore struct {
+	db    *sql.DB
+	query string
+
+	errorTTL time.Duration
+
+	catalogs map[sqlKey]*catalog
+	loading  map[sqlKey]*sqlLoad
+	failures map[sqlKey]time.Time
+	mutex    sync.RWMutex
+
+	// now returns the current time, replaced by tests.
+	now func() time.Time
+}
+
+// NewSQLStore returns a store loading translations with the given query.
+// The query receives the domain and language as arguments and must return the context (nullable), msgid,
+// plural index and msgstr columns. Placeholders have to match the database driver (i.e. "$1" and "$2" for PostgreSQL).
+func NewSQLStore(db *sql.DB, query string) *SQLStore {
+	if query == "" {
+		query = DefaultSQLQuery
+	}
+
+	return &SQLStore{
+		db:       db,
+		query:    query,
+		errorTTL: DefaultSQLErrorTTL,
+		catalogs: make(map[sqlKey]*catalog),
+		loading:  make(map[sqlKey]*sqlLoad),
+		failures: make(map[sqlKey]time.Time),
+		now:      time.Now,
+	}
+}
+
+// SetErrorTTL sets the time catalogs failing to load are served empty before being queried again,
+// DefaultSQLErrorTTL by default. Zero queries them again on every lookup.
+func (s *SQLStore) SetErrorTTL(ttl time.Duration) {
+	s.mutex.Lock()
+	s.errorTTL = ttl
+	s.mutex.Unlock()
+}
+
+// Backend returns a Backend serving the translations of the given domain and language.
+func (s *SQLStore) Backend(domain, lang string) Backend {
+	return &sqlBackend{
+		store: s,
+		key:   sqlKey{domain: domain, lang: SimplifiedLocale(lang)},
+	}
+}
+
+// Translator returns a Translator serving the translations of the given domain and language, to be added to a Locale.
+func (s *SQLStore) Translator(domain, lang string) *BackendTranslator {
+	return NewBackendTranslator(s.Backend(domain, lang))
+}
+
+// Load (re)loads the translations of the given domain and language from the database.
+// The cached catalog is only replaced when loading succeeds.
+// Lookups load catalogs on demand, so it's only needed to preload them or to check for errors.
+func (s *SQLStore) Load(domain, lang string) error {
+	key := sqlKey{domain: domain, lang: SimplifiedLocale(lang)}
+	c, err := s.fetch(key)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	s.catalogs[key] = c
+	delete(s.loading, key)
+	delete(s.failures, key)
+	s.mutex.Unlock()
+
+	return nil
+}
+
+// Invalidate drops the cached translations of the given domain and language, so they're loaded again on next lookup.
+func (s *SQLStore) Invalidate(domain, lang string) {
+	key := sqlKey{domain: domain, lang: SimplifiedLocale(lang)}
+
+	s.mutex.Lock()
+	delete(s.catalogs, key)
+	delete(s.loading, key)
+	delete(s.failures, key)
+	s.mutex.Unlock()
+}
+
+// InvalidateAll drops all cached translations.
+func (s *SQLStore) InvalidateAll() {
+	s.mutex.Lock()
+	s.catalogs = make(map[sqlKey]*catalog)
+	s.loading = make(map[sqlKey]*sqlLoad)
+	s.failures = make(map[sqlKey]time.Time)
+	s.mutex.Unlock()
+}
+
+// catalog returns the cached catalog for key, loading it if needed.
+// The query runs without holding the lock, once for all the lookups waiting for the catalog.
+// When loading fails, an empty catalog is served until the error TTL expires.
+func (s *SQLStore) catalog(key sqlKey) *catalog {
+	s.mutex.RLock()
+	c, ok := s.catalogs[key]
+	s.mutex.RUnlock()
+	if ok {
+		return c
+	}
+
+	s.mutex.Lock()
+	if c, ok = s.catalogs[key]; ok {
+		s.mutex.Unlock()
+		return c
+	}
+	if retry, ok := s.failures[key]; ok && s.now().Before(retry) {
+		s.mutex.Unlock()
+		return emptyCatalog
+	}
+	if load, ok := s.loading[key]; ok {
+		s.mutex.Unlock()
+		<-load.done
+		return load.c
+	}
+	load := &sqlLoad{done: make(chan struct{})}
+	s.loading[key] = load
+	s.mutex.Unlock()
+
+	c, err := s.fetch(key)
+	if err != nil {
+		c = emptyCatalog
+	}
+
+	s.mutex.Lock()
+	// Results of loads invalidated meanwhile aren't cached
+	if s.loading[key] == load {
+		delete(s.loading, key)
+		if err != nil {
+			s.failures[key] = s.now().Add(s.errorTTL)
+		} else {
+			s.catalogs[key] = c
+			delete(s.failures, key)
+		}
+	}
+	s.mutex.Unlock()
+
+	load.c = c
+	close(load.done)
+
+	return c
+}
+
+// fetch queries the database for the translations identified by key.
+func (s *SQLStore) fetch(key sqlKey) (*catalog, error) {
+	rows, err := s.db.Query(s.query, key.domain, key.lang)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	c := &catalog{entries: make(map[entryKey]*Translation)}
+	for rows.Next() {
+		var (
+			ctx        sql.NullString
+			id, msgstr string
+			index      int
+		)
+		if err = rows.Scan(&ctx, &id, &index, &msgstr); err != nil {
+			return nil, err
+		}
+
+		k := entryKey{ctx: ctx.String, id: id}
+		tr, ok := c.entries[k]
+		if !ok {
+			tr = NewTranslation()
+			tr.ID = id
+			c.entries[k] = tr
+		}
+		tr.Trs[index] = msgstr
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Plural forms are taken from the header entry
+	if h, ok := c.entries[entryKey{}]; ok {
+		c.pluralforms = headerPluralForms(h.Get())
+	}
+
+	return c, nil
+}
+
+// sqlBackend serves the translations of a domain and language stored on a SQLStore.
+type sqlBackend struct {
+	store *SQLStore
+	key   sqlKey
+}
+
+func (b *sqlBackend) Lookup(ctx, id string) (*Translation, bool) {
+	tr, ok := b.store.catalog(b.key).entries[entryKey{ctx: ctx, id: id}]
+	return tr, ok
+}
+
+func (b *sqlBackend) PluralForm(n int) int {
+	return b.store.catalog(b.key).pluralForm(n)
+}
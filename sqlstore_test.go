@@ -0,0 +1,253 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memDriver is a minimal database/sql driver serving rows of (domain, language, context, msgid, plural_index, msgstr)
+// from memory, filtered by the domain and language query arguments.
+type memDriver struct {
+	sync.Mutex
+	rows    [][]driver.Value
+	queries int
+
+	// Queries fail with err, and the ones of a language wait for its channel to be closed.
+	err     error
+	blocked map[string]chan struct{}
+}
+
+func (d *memDriver) Open(name string) (driver.Conn, error) { return &memConn{d}, nil }
+
+type memConn struct{ d *memDriver }
+
+func (c *memConn) Prepare(query string) (driver.Stmt, error) { return &memStmt{c.d}, nil }
+func (c *memConn) Close() error                              { return nil }
+func (c *memConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type memStmt struct{ d *memDriver }
+
+func (s *memStmt) Close() error  { return nil }
+func (s *memStmt) NumInput() int { return 2 }
+func (s *memStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s *memStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.Lock()
+	wait := s.d.blocked[args[1].(string)]
+	s.d.Unlock()
+	if wait != nil {
+		<-wait
+	}
+
+	s.d.Lock()
+	defer s.d.Unlock()
+
+	s.d.queries++
+	if s.d.err != nil {
+		return nil, s.d.err
+	}
+	rows := &memRows{}
+	for _, r := range s.d.rows {
+		if r[0] == args[0] && r[1] == args[1] {
+			rows.rows = append(rows.rows, r[2:])
+		}
+	}
+
+	return rows, nil
+}
+
+type memRows struct {
+	rows [][]driver.Value
+}
+
+func (r *memRows) Columns() []string {
+	return []string{"context", "msgid", "plural_index", "msgstr"}
+}
+func (r *memRows) Close() error { return nil }
+func (r *memRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+
+	return nil
+}
+
+var testSQLDriver = &memDriver{}
+
+func init() {
+	sql.Register("gotext-mem", testSQLDriver)
+}
+
+func TestSQLStore(t *testing.T) {
+	testSQLDriver.queries = 0
+	testSQLDriver.rows = [][]driver.Value{
+		{"default", "de", nil, "", int64(0), "Language: de\nPlural-Forms: nplurals=2; plural=(n != 1);\n"},
+		{"default", "de", nil, "Hello", int64(0), "Hallo"},
+		{"default", "de", "Menu", "Open", int64(0), "Öffnen"},
+		{"default", "de", nil, "%d file", int64(0), "%d Datei"},
+		{"default", "de", nil, "%d file", int64(1), "%d Dateien"},
+		{"default", "de", "Menu", "%d folder", int64(0), "%d Ordner"},
+		{"default", "fr", nil, "Hello", int64(0), "Bonjour"},
+	}
+
+	db, err := sql.Open("gotext-mem", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store := NewSQLStore(db, "")
+	l := NewLocale("", "de")
+	l.AddTranslator("default", store.Translator("default", "de"))
+
+	for _, test := range []struct{ expected, got string }{
+		{"Hallo", l.Get("Hello")},
+		{"Öffnen", l.GetC("Open", "Menu")},
+		{"1 Datei", l.GetN("%d file", "%d files", 1, 1)},
+		{"3 Dateien", l.GetN("%d file", "%d files", 3, 3)},
+		{"Untranslated", l.Get("Untranslated")},
+		// Rows hold no plural msgid, the one of the lookup is returned for missing forms
+		{"1 Ordner", l.GetNC("%d folder", "%d folders", 1, "Menu", 1)},
+		{"3 folders", l.GetNC("%d folder", "%d folders", 3, "Menu", 3)},
+	} {
+		if test.expected != test.got {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.got)
+		}
+	}
+
+	if testSQLDriver.queries != 1 {
+		t.Errorf("Expected 1 query but got %d", testSQLDriver.queries)
+	}
+
+	// Edit and invalidate
+	testSQLDriver.rows[1][5] = "Servus"
+	if tr := l.Get("Hello"); tr != "Hallo" {
+		t.Errorf("Expected cached 'Hallo' but got '%s'", tr)
+	}
+
+	store.Invalidate("default", "de")
+	if tr := l.Get("Hello"); tr != "Servus" {
+		t.Errorf("Expected 'Servus' but got '%s'", tr)
+	}
+
+	if err = store.Load("default", "fr"); err != nil {
+		t.Error(err)
+	}
+	if tr := store.Translator("default", "fr").Get("Hello"); tr != "Bonjour" {
+		t.Errorf("Expected 'Bonjour' but got '%s'", tr)
+	}
+}
+
+func TestSQLStoreErrors(t *testing.T) {
+	testSQLDriver.Lock()
+	testSQLDriver.queries = 0
+	testSQLDriver.err = errors.New("connection reset")
+	testSQLDriver.rows = [][]driver.Value{
+		{"default", "de", nil, "Hello", int64(0), "Hallo"},
+	}
+	testSQLDriver.Unlock()
+	defer func() {
+		testSQLDriver.err = nil
+	}()
+
+	db, err := sql.Open("gotext-mem", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	store := NewSQLStore(db, "")
+	store.now = func() time.Time { return now }
+	tr := store.Translator("default", "de")
+
+	// Failures are served empty for the error TTL only
+	for i := 0; i < 3; i++ {
+		if got := tr.Get("Hello"); got != "Hello" {
+			t.Errorf("Expected '%s' but got '%s'", "Hello", got)
+		}
+	}
+	if testSQLDriver.queries != 1 {
+		t.Errorf("Expected 1 query but got %d", testSQLDriver.queries)
+	}
+
+	testSQLDriver.Lock()
+	testSQLDriver.err = nil
+	testSQLDriver.Unlock()
+	now = now.Add(DefaultSQLErrorTTL)
+	if got := tr.Get("Hello"); got != "Hallo" {
+		t.Errorf("Expected '%s' but got '%s'", "Hallo", got)
+	}
+	if testSQLDriver.queries != 2 {
+		t.Errorf("Expected 2 queries but got %d", testSQLDriver.queries)
+	}
+}
+
+func TestSQLStoreConcurrentLoads(t *testing.T) {
+	wait := make(chan struct{})
+	testSQLDriver.Lock()
+	testSQLDriver.queries = 0
+	testSQLDriver.blocked = map[string]chan struct{}{"de": wait}
+	testSQLDriver.rows = [][]driver.Value{
+		{"default", "de", nil, "Hello", int64(0), "Hallo"},
+		{"default", "fr", nil, "Hello", int64(0), "Bonjour"},
+	}
+	testSQLDriver.Unlock()
+	defer func() {
+		testSQLDriver.blocked = nil
+	}()
+
+	db, err := sql.Open("gotext-mem", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store := NewSQLStore(db, "")
+	de := store.Translator("default", "de")
+
+	results := make(chan string)
+	for i := 0; i < 3; i++ {
+		go func() {
+			results <- de.Get("Hello")
+		}()
+	}
+
+	// A slow catalog doesn't block the lookups of the others
+	done := make(chan string)
+	go func() {
+		done <- store.Translator("default", "fr").Get("Hello")
+	}()
+	select {
+	case got := <-done:
+		if got != "Bonjour" {
+			t.Errorf("Expected '%s' but got '%s'", "Bonjour", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Lookup blocked by the query of another catalog")
+	}
+
+	close(wait)
+	for i := 0; i < 3; i++ {
+		if got := <-results; got != "Hallo" {
+			t.Errorf("Expected '%s' but got '%s'", "Hallo", got)
+		}
+	}
+	if testSQLDriver.queries != 2 {
+		t.Errorf("Expected 2 queries but got %d", testSQLDriver.queries)
+	}
+}
@@ -0,0 +1,18 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+// DetectSystemLocale returns the user's locale as configured in the operating system, simplified (see SimplifiedLocale).
+// Environment variables (LANGUAGE, LC_ALL, LC_MESSAGES and LANG) are honored on every platform when set.
+// Otherwise, it asks the Win32 user-locale API on Windows and the global AppleLocale preference on macOS.
+// It returns an empty string if the locale can't be detected.
+func DetectSystemLocale() string {
+	if langs := LanguagesFromEnv(); len(langs) > 0 {
+		return langs[0]
+	}
+
+	return SimplifiedLocale(systemLocale())
+}
@@ -0,0 +1,25 @@
+//go:build darwin
+// +build darwin
+
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// systemLocale reads the AppleLocale global preference (i.e. "en_US" or "en_US@rg=dezzzz") using the defaults tool,
+// which avoids linking against CoreFoundation through cgo.
+func systemLocale() string {
+	out, err := exec.Command("defaults", "read", "-g", "AppleLocale").Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,14 @@
+//go:build !windows && !darwin
+// +build !windows,!darwin
+
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+// systemLocale has no other source than the environment on these platforms.
+func systemLocale() string {
+	return ""
+}
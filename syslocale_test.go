@@ -0,0 +1,18 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import "testing"
+
+func TestDetectSystemLocale(t *testing.T) {
+	restore := setEnv(map[string]string{"LANGUAGE": "", "LC_ALL": "", "LC_MESSAGES": "de_AT.UTF-8", "LANG": "en_US.UTF-8"})
+	defer restore()
+
+	tr := DetectSystemLocale()
+	if tr != "de_AT" {
+		t.Errorf("Expected 'de_AT' but got '%s'", tr)
+	}
+}
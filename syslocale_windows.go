@@ -0,0 +1,34 @@
+//go:build windows
+// +build windows
+
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// localeNameMaxLength is LOCALE_NAME_MAX_LENGTH from winnls.h
+const localeNameMaxLength = 85
+
+var procGetUserDefaultLocaleName = syscall.NewLazyDLL("kernel32.dll").NewProc("GetUserDefaultLocaleName")
+
+// systemLocale reads the user default locale name (i.e. "en-US") from the Win32 API.
+func systemLocale() string {
+	if procGetUserDefaultLocaleName.Find() != nil {
+		return ""
+	}
+
+	buf := make([]uint16, localeNameMaxLength)
+	r, _, _ := procGetUserDefaultLocaleName.Call(uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	if r == 0 {
+		return ""
+	}
+
+	return syscall.UTF16ToString(buf)
+}
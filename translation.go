@@ -10,6 +10,9 @@ type Translation struct {
 	ID       string
 	PluralID string
 	Trs      map[int]string
+
+	// Fuzzy marks draft translations needing review, i.e. machine translated ones.
+	Fuzzy bool
 }
 
 // NewTranslation returns the Translation object and initialized it.
@@ -23,10 +26,8 @@ func NewTranslation() *Translation {
 // Get returns the string of the translation
 func (t *Translation) Get() string {
 	// Look for Translation index 0
-	if _, ok := t.Trs[0]; ok {
-		if t.Trs[0] != "" {
-			return t.Trs[0]
-		}
+	if tr := t.Trs[0]; tr != "" {
+		return tr
 	}
 
 	// Return untranslated id by default
@@ -36,10 +37,8 @@ func (t *Translation) Get() string {
 // GetN returns the string of the plural translation
 func (t *Translation) GetN(n int) string {
 	// Look for Translation index
-	if _, ok := t.Trs[n]; ok {
-		if t.Trs[n] != "" {
-			return t.Trs[n]
-		}
+	if tr := t.Trs[n]; tr != "" {
+		return tr
 	}
 
 	// Return untranslated singular if corresponding
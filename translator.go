@@ -10,6 +10,7 @@ import (
 	"io/ioutil"
 	"net/textproto"
 	"os"
+	"path"
 )
 
 // Translator interface is used by Locale and Po objects.Translator
@@ -60,13 +61,23 @@ func (te *TranslatorEncoding) GetTranslator() Translator {
 	po.domain.PluralForms = te.PluralForms
 	po.domain.nplurals = te.Nplurals
 	po.domain.plural = te.Plural
-	po.domain.translations = te.Translations
-	po.domain.contexts = te.Contexts
+	po.domain.setEncodedEntries(te.Translations, te.Contexts)
+
+	po.domain.pluralforms = compilePlural(te.Plural)
+	po.domain.publish()
 
 	return po
 }
 
-//getFileData reads a file and returns the byte slice after doing some basic sanity checking
+// newTranslator returns a Po or Mo Translator, depending on the file extension, that stores translations on the given Domain.
+func newTranslator(file string, domain *Domain) Translator {
+	if path.Ext(file) == ".mo" {
+		return &Mo{domain: domain}
+	}
+	return &Po{domain: domain}
+}
+
+// getFileData reads a file and returns the byte slice after doing some basic sanity checking
 func getFileData(f string) ([]byte, error) {
 	// Check if file exists
 	info, err := os.Stat(f)
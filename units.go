@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+// UnitContext is the msgctxt of the unit strings looked up by Locale.FormatUnit.
+const UnitContext = "unit"
+
+// Unit is a measurement unit, as the msgid and msgid_plural formatting a quantity of it with a %d verb.
+type Unit struct {
+	Singular, Plural string
+}
+
+// Units of length, mass, volume and data, in their English forms.
+var (
+	UnitMillimeter = Unit{"%d millimeter", "%d millimeters"}
+	UnitCentimeter = Unit{"%d centimeter", "%d centimeters"}
+	UnitMeter      = Unit{"%d meter", "%d meters"}
+	UnitKilometer  = Unit{"%d kilometer", "%d kilometers"}
+	UnitInch       = Unit{"%d inch", "%d inches"}
+	UnitFoot       = Unit{"%d foot", "%d feet"}
+	UnitYard       = Unit{"%d yard", "%d yards"}
+	UnitMile       = Unit{"%d mile", "%d miles"}
+
+	UnitGram     = Unit{"%d gram", "%d grams"}
+	UnitKilogram = Unit{"%d kilogram", "%d kilograms"}
+	UnitOunce    = Unit{"%d ounce", "%d ounces"}
+	UnitPound    = Unit{"%d pound", "%d pounds"}
+
+	UnitMilliliter = Unit{"%d milliliter", "%d milliliters"}
+	UnitLiter      = Unit{"%d liter", "%d liters"}
+	UnitGallon     = Unit{"%d gallon", "%d gallons"}
+
+	UnitByte     = Unit{"%d byte", "%d bytes"}
+	UnitKilobyte = Unit{"%d kilobyte", "%d kilobytes"}
+	UnitMegabyte = Unit{"%d megabyte", "%d megabytes"}
+	UnitGigabyte = Unit{"%d gigabyte", "%d gigabytes"}
+)
+
+/*
+FormatUnit formats a quantity of a unit with its localized name, "1 mile" or "2 miles", looking the unit up in
+the default domain of the Locale with the UnitContext, so the plural form is selected by the Plural-Forms of
+the catalog as GetN does. A German catalog would translate "%d kilometer" and "%d kilometers" into
+"%d Kilometer", giving "1 Kilometer" and "2 Kilometer". Untranslated units are in English.
+Units not provided by the package are declared as a Unit.
+
+Example:
+
+	l.FormatUnit(2, gotext.UnitMile) // "2 miles"
+
+	parsec := gotext.Unit{Singular: "%d parsec", Plural: "%d parsecs"}
+	l.FormatUnit(3, parsec) // "3 parsecs"
+*/
+func (l *Locale) FormatUnit(n int, u Unit) string {
+	return l.GetNC(u.Singular, u.Plural, n, UnitContext, n)
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestFormatUnit(t *testing.T) {
+	dirname := path.Join("/tmp", "formatunit")
+	if err := os.MkdirAll(path.Join(dirname, "de", "LC_MESSAGES"), os.ModePerm); err != nil {
+		t.Fatalf("Can't create test directory: %s", err.Error())
+	}
+	defer os.RemoveAll(dirname)
+
+	str := `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgctxt "unit"
+msgid "%d kilometer"
+msgid_plural "%d kilometers"
+msgstr[0] "%d Kilometer"
+msgstr[1] "%d Kilometer"
+
+msgctxt "unit"
+msgid "%d mile"
+msgid_plural "%d miles"
+msgstr[0] "%d Meile"
+msgstr[1] "%d Meilen"
+`
+	err := ioutil.WriteFile(path.Join(dirname, "de", "LC_MESSAGES", "default.po"), []byte(str), os.ModePerm)
+	if err != nil {
+		t.Fatalf("Can't write test file: %s", err.Error())
+	}
+
+	de := NewLocale(dirname, "de")
+	de.AddDomain("default")
+	en := NewLocale(dirname, "en")
+
+	tests := []struct {
+		tr, expected string
+	}{
+		{de.FormatUnit(1, UnitKilometer), "1 Kilometer"},
+		{de.FormatUnit(2, UnitKilometer), "2 Kilometer"},
+		{de.FormatUnit(1, UnitMile), "1 Meile"},
+		{de.FormatUnit(5, UnitMile), "5 Meilen"},
+		{de.FormatUnit(3, UnitLiter), "3 liters"},
+		{en.FormatUnit(1, UnitMile), "1 mile"},
+		{en.FormatUnit(2, UnitFoot), "2 feet"},
+		{en.FormatUnit(3, Unit{"%d parsec", "%d parsecs"}), "3 parsecs"},
+	}
+	for _, test := range tests {
+		if test.tr != test.expected {
+			t.Errorf("Expected '%s' but got '%s'", test.expected, test.tr)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidSignature is returned when a catalog bundle doesn't match its signature.
+	ErrInvalidSignature = errors.New("gotext: invalid catalog bundle signature")
+
+	// ErrNoRollback is returned by Updater.Rollback when there's no previous bundle to go back to.
+	ErrNoRollback = errors.New("gotext: no previous catalog bundle")
+)
+
+/*
+Updater downloads catalog bundles over the air, so translation hotfixes ship independently of releases.
+
+A bundle is a tar archive, optionally gzipped, holding .po and .mo files named after their domain (i.e. "default.po").
+It's only applied when its Ed25519 signature, stored next to it with a ".sig" suffix (raw or base64 encoded), is valid
+for the configured public key. All domains of a bundle are swapped in atomically, and bundles failing to parse aren't
+applied at all. Rollback restores the domains replaced by the last update.
+
+Bundles are fetched with the Fetcher registered for the location scheme (see RegisterFetcher), "http" and "https" by default.
+
+Example:
+
+	u := gotext.NewUpdater(l, "https://cdn.example.com/i18n/de.tar.gz", publicKey)
+	stop := u.Start(5*time.Minute, func(err error) { log.Println(err) })
+	defer stop()
+*/
+type Updater struct {
+	locale    *Locale
+	location  string
+	signature string
+	key       ed25519.PublicKey
+
+	// Checksum of the last applied bundle
+	sum [sha256.Size]byte
+
+	// Translators replaced by the last applied bundle
+	previous map[string]Translator
+
+	mutex sync.Mutex
+}
+
+// NewUpdater returns an Updater applying to l the bundles at location, signed with the private counterpart of key.
+func NewUpdater(l *Locale, location string, key ed25519.PublicKey) *Updater {
+	return &Updater{
+		locale:    l,
+		location:  location,
+		signature: location + ".sig",
+		key:       key,
+	}
+}
+
+// SetSignatureLocation sets the location of the bundle signature, when it isn't stored next to it.
+func (u *Updater) SetSignatureLocation(location string) {
+	u.mutex.Lock()
+	u.signature = location
+	u.mutex.Unlock()
+}
+
+// Update downloads the bundle and its signature, and applies the bundle when it's valid and changed since the last update.
+// It reports if the bundle was applied. The Locale is left untouched on any error.
+func (u *Updater) Update(ctx context.Context) (bool, error) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	bundle, err := fetchLocation(ctx, u.location)
+	if err != nil {
+		return false, err
+	}
+	sig, err := fetchLocation(ctx, u.signature)
+	if err != nil {
+		return false, err
+	}
+
+	if !verifyBundle(u.key, bundle, sig) {
+		return false, ErrInvalidSignature
+	}
+
+	sum := sha256.Sum256(bundle)
+	if sum == u.sum {
+		return false, nil
+	}
+
+	trs, err := ParseBundle(bundle)
+	if err != nil {
+		return false, err
+	}
+
+	u.previous = u.locale.swapTranslators(trs)
+	u.sum = sum
+
+	return true, nil
+}
+
+// Rollback restores the domains replaced by the last applied bundle.
+// The rolled back bundle isn't applied again by Update until it changes.
+func (u *Updater) Rollback() error {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if u.previous == nil {
+		return ErrNoRollback
+	}
+
+	u.locale.swapTranslators(u.previous)
+	u.previous = nil
+
+	return nil
+}
+
+// Start calls Update every interval on a new goroutine, until the returned function is called.
+// Update errors are passed to onError, when not nil.
+func (u *Updater) Start(interval time.Duration, onError func(error)) (stop func()) {
+	ticker := time.NewTicker(interval)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := u.Update(ctx); err != nil && onError != nil && ctx.Err() == nil {
+					onError(err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return cancel
+}
+
+// ParseBundle parses the .po and .mo files of a catalog bundle (tar archive, optionally gzipped), keyed by domain.
+// It fails if any catalog can't be parsed or holds no entries, or if several files hold the same domain
+// (i.e. "default.po" in two directories).
+func ParseBundle(data []byte) (map[string]Translator, error) {
+	var r io.Reader = bytes.NewReader(data)
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		r = gz
+	}
+
+	trs := make(map[string]Translator)
+	names := make(map[string]string)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ext := path.Ext(hdr.Name)
+		if hdr.Typeflag != tar.TypeReg || (ext != ".po" && ext != ".mo") {
+			continue
+		}
+
+		buf, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+
+		dom, file := domainFromPath(hdr.Name)
+		if name, ok := names[dom]; ok {
+			return nil, fmt.Errorf("gotext: %s and %s both hold domain %s", name, hdr.Name, dom)
+		}
+		names[dom] = hdr.Name
+
+		t, err := parseBundleFile(file, buf)
+		if err != nil {
+			return nil, err
+		}
+		trs[dom] = t
+	}
+
+	if len(trs) == 0 {
+		return nil, errors.New("gotext: no catalogs found in bundle")
+	}
+
+	return trs, nil
+}
+
+// parseBundleFile parses a catalog from a bundle, turning parser panics and empty results into errors.
+func parseBundleFile(file string, buf []byte) (t Translator, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("gotext: parsing %s: %v", file, r)
+		}
+	}()
+
+	t = newTranslator(file, NewDomain())
+	t.Parse(buf)
+	if len(t.GetDomain().load().entries) == 0 {
+		return nil, fmt.Errorf("gotext: parsing %s: no entries found", file)
+	}
+
+	return t, nil
+}
+
+// verifyBundle checks the raw or base64 encoded Ed25519 signature of a bundle.
+func verifyBundle(key ed25519.PublicKey, bundle, sig []byte) bool {
+	if len(key) != ed25519.PublicKeySize {
+		return false
+	}
+
+	if len(sig) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+		if err != nil {
+			return false
+		}
+		sig = decoded
+	}
+
+	return ed25519.Verify(key, bundle, sig)
+}
+
+// fetchLocation downloads the given location with the Fetcher registered for its scheme.
+func fetchLocation(ctx context.Context, location string) ([]byte, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+
+	return fetch(ctx, u)
+}
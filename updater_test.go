@@ -0,0 +1,147 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// makeBundle returns a gzipped tar archive with the given files.
+func makeBundle(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+
+	return buf.Bytes()
+}
+
+func TestUpdater(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects := make(map[string][]byte)
+	publish := func(bundle []byte, key ed25519.PrivateKey) {
+		objects["/de.tar.gz"] = bundle
+		objects["/de.tar.gz.sig"] = []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, bundle)))
+	}
+
+	RegisterFetcher("ota", FetcherFunc(func(ctx context.Context, u *url.URL) ([]byte, error) {
+		if data, ok := objects[u.Path]; ok {
+			return data, nil
+		}
+		return nil, errors.New("not found")
+	}))
+	defer RegisterFetcher("ota", nil)
+
+	l := NewLocale("", "de")
+	po := NewPo()
+	po.Parse([]byte("msgid \"Hello\"\nmsgstr \"Hallo\"\n"))
+	l.AddTranslator("default", po)
+
+	u := NewUpdater(l, "ota://cdn/de.tar.gz", pub)
+	if err = u.Rollback(); err != ErrNoRollback {
+		t.Errorf("Expected ErrNoRollback but got %v", err)
+	}
+
+	good := makeBundle(t, map[string]string{
+		"de/default.po": "msgid \"Hello\"\nmsgstr \"Servus\"\n",
+		"de/extra.po":   "msgid \"Bye\"\nmsgstr \"Tschüss\"\n",
+	})
+	publish(good, priv)
+
+	if ok, err := u.Update(context.Background()); !ok || err != nil {
+		t.Fatalf("Expected update but got %v, %v", ok, err)
+	}
+	if tr := l.Get("Hello"); tr != "Servus" {
+		t.Errorf("Expected 'Servus' but got '%s'", tr)
+	}
+	if tr := l.GetD("extra", "Bye"); tr != "Tschüss" {
+		t.Errorf("Expected 'Tschüss' but got '%s'", tr)
+	}
+
+	// Unchanged bundles aren't applied again
+	if ok, err := u.Update(context.Background()); ok || err != nil {
+		t.Errorf("Expected no update but got %v, %v", ok, err)
+	}
+
+	// Bundles signed with another key are rejected
+	_, other, _ := ed25519.GenerateKey(rand.Reader)
+	publish(makeBundle(t, map[string]string{"default.po": "msgid \"Hello\"\nmsgstr \"Evil\"\n"}), other)
+	if _, err := u.Update(context.Background()); err != ErrInvalidSignature {
+		t.Errorf("Expected ErrInvalidSignature but got %v", err)
+	}
+
+	// Broken bundles aren't applied
+	publish(makeBundle(t, map[string]string{"default.po": "msgid \"Hello\"\nmsgstr \"Hoi\"\n", "extra.mo": "garbage"}), priv)
+	if _, err := u.Update(context.Background()); err == nil {
+		t.Error("Expected error applying broken bundle")
+	}
+	if tr := l.Get("Hello"); tr != "Servus" {
+		t.Errorf("Expected 'Servus' but got '%s'", tr)
+	}
+
+	// Rollback restores the previous domains
+	if err = u.Rollback(); err != nil {
+		t.Error(err)
+	}
+	if tr := l.Get("Hello"); tr != "Hallo" {
+		t.Errorf("Expected 'Hallo' but got '%s'", tr)
+	}
+	if tr := l.GetD("extra", "Bye"); tr != "Bye" {
+		t.Errorf("Expected 'Bye' but got '%s'", tr)
+	}
+
+	// Rolled back bundles aren't applied again
+	publish(good, priv)
+	if ok, err := u.Update(context.Background()); ok || err != nil {
+		t.Errorf("Expected no update but got %v, %v", ok, err)
+	}
+}
+
+func TestParseBundle(t *testing.T) {
+	trs, err := ParseBundle(makeBundle(t, map[string]string{
+		"de/LC_MESSAGES/default.po": "msgid \"Hello\"\nmsgstr \"Hallo\"\n",
+		"de/LC_MESSAGES/admin.po":   "msgid \"Users\"\nmsgstr \"Benutzer\"\n",
+		"README":                    "not a catalog",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trs) != 2 || trs["default"].Get("Hello") != "Hallo" || trs["admin"].Get("Users") != "Benutzer" {
+		t.Errorf("Expected the default and admin domains but got %v", trs)
+	}
+
+	// Catalogs of the same domain would overwrite each other
+	_, err = ParseBundle(makeBundle(t, map[string]string{
+		"de/LC_MESSAGES/default.po": "msgid \"Hello\"\nmsgstr \"Hallo\"\n",
+		"fr/LC_MESSAGES/default.po": "msgid \"Hello\"\nmsgstr \"Bonjour\"\n",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "domain default") {
+		t.Errorf("Expected a duplicate domain error but got %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"fmt"
+	"mime"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IssueKind identifies the kind of an Issue found validating a catalog.
+type IssueKind string
+
+const (
+	// IssuePluralForms reports a Plural-Forms header that can't be compiled.
+	IssuePluralForms IssueKind = "plural-forms"
+
+	// IssuePluralCount reports a plural entry whose number of translations differs from nplurals.
+	IssuePluralCount IssueKind = "plural-count"
+
+	// IssuePlaceholders reports a translation whose format placeholders diverge from its msgid.
+	IssuePlaceholders IssueKind = "placeholders"
+
+	// IssueInvalidEscape reports a string that can't be unquoted, i.e. holding invalid escape sequences.
+	IssueInvalidEscape IssueKind = "invalid-escape"
+
+	// IssueEmptyContext reports an entry with an empty msgctxt, which can't be told apart from an entry without context.
+	IssueEmptyContext IssueKind = "empty-context"
+
+	// IssueDuplicate reports an entry defined more than once.
+	IssueDuplicate IssueKind = "duplicate"
+
+	// IssueHeader reports a header unusable by gettext tools: missing, declaring a charset other than UTF-8,
+	// or without Plural-Forms while the catalog holds plural entries.
+	IssueHeader IssueKind = "header"
+
+	// IssueEncoding reports a string that isn't valid UTF-8, whose invalid bytes are replaced by U+FFFD.
+	IssueEncoding IssueKind = "encoding"
+)
+
+// Issue is a problem found validating a catalog.
+type Issue struct {
+	Kind IssueKind
+
+	// Line of the entry in the parsed source, 0 when unknown.
+	Line int
+
+	// Context and Msgid of the entry, empty for catalog wide issues.
+	Context string
+	Msgid   string
+
+	Message string
+}
+
+// String returns the issue as "line: kind: message (msgid)".
+func (i Issue) String() string {
+	s := fmt.Sprintf("%d: %s: %s", i.Line, i.Kind, i.Message)
+	if i.Msgid != "" || i.Context != "" {
+		s += fmt.Sprintf(" (msgid %q", i.Msgid)
+		if i.Context != "" {
+			s += fmt.Sprintf(", msgctxt %q", i.Context)
+		}
+		s += ")"
+	}
+
+	return s
+}
+
+// addIssue records a source issue found while parsing.
+func (po *Po) addIssue(kind IssueKind, line int, key entryKey, msg string) {
+	po.issues = append(po.issues, Issue{Kind: kind, Line: line, Context: key.ctx, Msgid: key.id, Message: msg})
+}
+
+// formatVerbRe matches fmt verbs with their optional explicit argument index, flags, width and precision.
+var formatVerbRe = regexp.MustCompile(`%(?:\[(\d+)\])?[-+# 0]*(?:\d+)?(?:\.\d+)?([a-zA-Z%])`)
+
+// placeholders returns the format placeholders of s, as "index:verb" for positional verbs
+// and "name:verb" for named ones (see Sprintf).
+func placeholders(s string) map[string]bool {
+	ph := make(map[string]bool)
+	for _, m := range re.FindAllStringSubmatch(s, -1) {
+		ph[m[1]+":"+m[0][len(m[0])-1:]] = true
+	}
+
+	next := 1
+	for _, m := range formatVerbRe.FindAllStringSubmatch(re.ReplaceAllString(s, ""), -1) {
+		if m[2] == "%" {
+			continue
+		}
+		if m[1] != "" {
+			next, _ = strconv.Atoi(m[1])
+		}
+		ph[strconv.Itoa(next)+":"+m[2]] = true
+		next++
+	}
+
+	return ph
+}
+
+// describePlaceholders returns the sorted placeholders for messages.
+func describePlaceholders(ph map[string]bool) string {
+	if len(ph) == 0 {
+		return "none"
+	}
+
+	list := make([]string, 0, len(ph))
+	for p := range ph {
+		list = append(list, p)
+	}
+	sort.Strings(list)
+
+	return strings.Join(list, ", ")
+}
+
+// checkPlaceholders returns an issue message if the placeholders of a translation diverge from the source ones.
+// Plural forms may leave out placeholders, as long as they don't introduce any.
+func checkPlaceholders(source map[string]bool, str string, subset bool) string {
+	ph := placeholders(str)
+	for p := range ph {
+		if !source[p] {
+			return fmt.Sprintf("placeholders %s don't match the msgid ones: %s", describePlaceholders(ph), describePlaceholders(source))
+		}
+	}
+	if !subset && len(ph) != len(source) {
+		return fmt.Sprintf("placeholders %s don't match the msgid ones: %s", describePlaceholders(ph), describePlaceholders(source))
+	}
+
+	return ""
+}
+
+// formatIssue returns the kind and message of an issue if the verbs of a translation aren't compatible with the
+// placeholders of the source strings, or reorder the arguments of msgid without explicit indexes.
+func formatIssue(msgid string, source map[string]bool, str string, subset bool) (IssueKind, string) {
+	if fixed, reordered, _ := indexArguments(msgid, str); reordered {
+		return IssueArgumentOrder, fmt.Sprintf("reorders the arguments without indexes, use %q", fixed)
+	}
+	if msg := checkPlaceholders(source, str, subset); msg != "" {
+		return IssuePlaceholders, msg
+	}
+
+	return "", ""
+}
+
+/*
+CheckFormat checks a translation of msgid with the rules of Validate and the lint command, returning the issues
+of its verbs: placeholders diverging from the msgid ones, or arguments reordered without explicit indexes.
+Empty translations have no issues. The Line of the issues is 0, their Msgid is msgid.
+
+Example:
+
+	// Validate a translator submission before saving it
+	if issues := gotext.CheckFormat(msgid, submitted); len(issues) > 0 {
+		http.Error(w, issues[0].Message, http.StatusUnprocessableEntity)
+		return
+	}
+*/
+func CheckFormat(msgid, msgstr string) []Issue {
+	if msgstr == "" {
+		return nil
+	}
+	if kind, msg := formatIssue(msgid, placeholders(msgid), msgstr, false); msg != "" {
+		return []Issue{{Kind: kind, Msgid: msgid, Message: "msgstr " + msg}}
+	}
+
+	return nil
+}
+
+/*
+Validate checks the catalog, returning the issues found sorted by line:
+header and Plural-Forms errors, plural entries not matching nplurals, translations whose placeholders diverge from the msgid
+or reorder its arguments without explicit indexes,
+and, for the last parsed source, strings that aren't valid UTF-8, invalid escape sequences, empty contexts and duplicate entries.
+Untranslated strings aren't issues.
+
+Example:
+
+	po := gotext.NewPo()
+	po.ParseFile("locales/fr/default.po")
+	for _, issue := range po.Validate() {
+		fmt.Println(issue)
+	}
+*/
+func (po *Po) Validate() []Issue {
+	po.domain.trMutex.Lock()
+	issues := append([]Issue(nil), po.issues...)
+	lines := po.lines
+	nplurals, pluralforms, header := po.domain.nplurals, po.domain.pluralforms, po.domain.PluralForms
+	contentType := po.domain.Headers.Get("Content-Type")
+	po.domain.trMutex.Unlock()
+
+	if header != "" && pluralforms == nil {
+		issues = append(issues, Issue{Kind: IssuePluralForms, Message: fmt.Sprintf("invalid Plural-Forms header %q", header)})
+	}
+	if nplurals <= 0 {
+		nplurals = 2
+	}
+
+	entries := po.domain.load().entries
+	headerLine, hasHeader := lines[entryKey{}]
+	if _, ok := entries[entryKey{}]; !ok {
+		issues = append(issues, Issue{Kind: IssueHeader, Message: "missing header entry"})
+	} else if _, params, err := mime.ParseMediaType(contentType); contentType != "" && (err != nil || !strings.EqualFold(params["charset"], "UTF-8")) {
+		issues = append(issues, Issue{Kind: IssueHeader, Line: headerLine, Message: fmt.Sprintf("Content-Type %q doesn't declare the UTF-8 charset", contentType)})
+	}
+	hasPlurals := false
+
+	for k, tr := range entries {
+		if k.id == "" && k.ctx == "" {
+			continue
+		}
+		issue := Issue{Line: lines[k], Context: k.ctx, Msgid: k.id}
+
+		if tr.PluralID == "" {
+			for _, i := range CheckFormat(k.id, tr.Trs[0]) {
+				issue.Kind, issue.Message = i.Kind, i.Message
+				issues = append(issues, issue)
+			}
+			continue
+		}
+
+		hasPlurals = true
+		forms := 0
+		translated := false
+		for i, str := range tr.Trs {
+			if i+1 > forms {
+				forms = i + 1
+			}
+			translated = translated || str != ""
+		}
+		if translated && forms != nplurals {
+			issue.Kind, issue.Message = IssuePluralCount, fmt.Sprintf("%d plural forms, expected %d (nplurals)", forms, nplurals)
+			issues = append(issues, issue)
+		}
+
+		source := placeholders(k.id)
+		for p := range placeholders(tr.PluralID) {
+			source[p] = true
+		}
+		for i := 0; i < forms; i++ {
+			if str := tr.Trs[i]; str != "" {
+				if kind, msg := formatIssue(tr.PluralID, source, str, true); msg != "" {
+					issue.Kind, issue.Message = kind, fmt.Sprintf("msgstr[%d] %s", i, msg)
+					issues = append(issues, issue)
+				}
+			}
+		}
+	}
+
+	if hasPlurals && header == "" && hasHeader {
+		issues = append(issues, Issue{Kind: IssueHeader, Line: headerLine, Message: "missing Plural-Forms header, required by plural entries"})
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Context != b.Context {
+			return a.Context < b.Context
+		}
+		if a.Msgid != b.Msgid {
+			return a.Msgid < b.Msgid
+		}
+		return a.Message < b.Message
+	})
+
+	return issues
+}
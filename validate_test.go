@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2018 DeineAgentur UG https://www.deineagentur.com. All rights reserved.
+ * Licensed under the MIT License. See LICENSE file in the project root for full license information.
+ */
+
+package gotext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPoValidate(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`msgid ""
+msgstr ""
+"Plural-Forms: nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "Hello %s"
+msgstr "Witaj %d"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "Jeden plik"
+msgstr[1] "%d pliki"
+
+msgid "Bad escape"
+msgstr "\q"
+
+msgctxt ""
+msgid "No context"
+msgstr "Bez kontekstu"
+
+msgid "Twice"
+msgstr "Raz"
+
+msgid "Twice"
+msgstr "Dwa razy"
+
+msgid "%[2]s of %[1]s"
+msgstr "%[1]s: %[2]s"
+
+msgid "%(name)s joined"
+msgstr "%(user)s dołączył"
+
+msgid "Untranslated %s"
+msgstr ""
+`))
+
+	expected := []Issue{
+		{Kind: IssuePlaceholders, Line: 5, Msgid: "Hello %s", Message: "msgstr placeholders 1:d don't match the msgid ones: 1:s"},
+		{Kind: IssuePluralCount, Line: 8, Msgid: "%d file", Message: "2 plural forms, expected 3 (nplurals)"},
+		{Kind: IssueInvalidEscape, Line: 14, Msgid: "Bad escape", Message: `invalid string "\q"`},
+		{Kind: IssueEmptyContext, Line: 16, Msgid: "No context", Message: "empty msgctxt, entries without context must omit it"},
+		{Kind: IssueDuplicate, Line: 23, Msgid: "Twice", Message: "duplicate entry, first defined at line 20"},
+		{Kind: IssuePlaceholders, Line: 29, Msgid: "%(name)s joined", Message: "msgstr placeholders user:s don't match the msgid ones: name:s"},
+	}
+
+	issues := po.Validate()
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Expected\n%v\nbut got\n%v", expected, issues)
+	}
+}
+
+func TestPoValidateClean(t *testing.T) {
+	po := NewPo()
+	po.ParseFile("fixtures/de/default.po")
+
+	if issues := po.Validate(); len(issues) != 0 {
+		t.Errorf("Expected no issues but got %v", issues)
+	}
+
+	po = NewPo()
+	po.Parse([]byte(`msgid ""
+msgstr "Plural-Forms: nplurals=2; plural=n ? 0;\n"
+`))
+	issues := po.Validate()
+	if len(issues) != 1 || issues[0].Kind != IssuePluralForms {
+		t.Errorf("Expected a plural forms issue but got %v", issues)
+	}
+}
+
+func TestPoValidateHeader(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte("msgid \"\"\nmsgstr \"Content-Type: text/plain; charset=ISO-8859-1\\n\"\n\n" +
+		"msgid \"%d file\"\nmsgid_plural \"%d files\"\nmsgstr[0] \"%d fichier\"\nmsgstr[1] \"%d fichiers\"\n\n" +
+		"msgid \"Caf\xe9\"\nmsgstr \"Caf\xe9\"\n"))
+
+	expected := []Issue{
+		{Kind: IssueHeader, Line: 1, Message: `Content-Type "text/plain; charset=ISO-8859-1" doesn't declare the UTF-8 charset`},
+		{Kind: IssueHeader, Line: 1, Message: "missing Plural-Forms header, required by plural entries"},
+		{Kind: IssueEncoding, Line: 9, Message: "string isn't valid UTF-8"},
+		{Kind: IssueEncoding, Line: 10, Msgid: "Caf\uFFFD", Message: "string isn't valid UTF-8"},
+	}
+	issues := po.Validate()
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Expected\n%v\nbut got\n%v", expected, issues)
+	}
+
+	po = NewPo()
+	po.Parse([]byte("msgid \"Hello\"\nmsgstr \"Bonjour\"\n"))
+	issues = po.Validate()
+	if len(issues) != 1 || issues[0].Kind != IssueHeader || issues[0].Message != "missing header entry" {
+		t.Errorf("Expected a missing header issue but got %v", issues)
+	}
+}
+
+func TestIssueString(t *testing.T) {
+	issue := Issue{Kind: IssueDuplicate, Line: 3, Context: "menu", Msgid: "Open", Message: "duplicate entry, first defined at line 1"}
+	expected := `3: duplicate: duplicate entry, first defined at line 1 (msgid "Open", msgctxt "menu")`
+	if issue.String() != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, issue.String())
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	tests := []struct {
+		msgid, msgstr string
+		expected      []Issue
+	}{
+		{"Hello %s", "Hallo %s", nil},
+		{"Hello %s", "", nil},
+		{"Hello %s", "Hallo", []Issue{{Kind: IssuePlaceholders, Msgid: "Hello %s", Message: "msgstr placeholders none don't match the msgid ones: 1:s"}}},
+		{"%s holds %d files", "%d Dateien in %s", []Issue{{Kind: IssueArgumentOrder, Msgid: "%s holds %d files", Message: `msgstr reorders the arguments without indexes, use "%[2]d Dateien in %[1]s"`}}},
+		{"%s holds %d files", "%[2]d Dateien in %[1]s", nil},
+		{"%(name)s joined", "%(user)s dołączył", []Issue{{Kind: IssuePlaceholders, Msgid: "%(name)s joined", Message: "msgstr placeholders user:s don't match the msgid ones: name:s"}}},
+	}
+	for _, test := range tests {
+		if issues := CheckFormat(test.msgid, test.msgstr); !reflect.DeepEqual(issues, test.expected) {
+			t.Errorf("Expected %v but got %v", test.expected, issues)
+		}
+	}
+}